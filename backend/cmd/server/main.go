@@ -32,6 +32,9 @@ func main() {
 	shutdown := tracing.InitTracer("fifteen-thirty-one-go")
 	defer shutdown()
 
+	if cfg.DBDebug {
+		database.EnableQueryStats()
+	}
 	db, err := database.OpenAndMigrate(cfg.DatabasePath)
 	if err != nil {
 		log.Fatalf("db open/migrate: %v", err)
@@ -79,10 +82,24 @@ func main() {
 
 	handlers.SetWebSocketOriginPolicy(cfg.AppEnv == "development", cfg.DevWebSocketsAllowAll, cfg.WSAllowedOrigins)
 	handlers.SetHubProvider(hubRef.Get)
+	handlers.SetRuntimeConfig(cfg)
+
+	// Background maintenance jobs stop when the server shuts down.
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	go handlers.RunAccessLogCleanup(jobsCtx, db)
+	go handlers.RunStateFlusher(jobsCtx, db)
+	go handlers.RunStaleGameJanitor(jobsCtx, db)
+	go handlers.RunIdleLobbyKicker(jobsCtx, db)
+	go handlers.RunTurnTimer(jobsCtx, db)
+	go handlers.RunMoveArchiver(jobsCtx, db)
 
 	r := gin.Default()
 	r.Use(otelgin.Middleware("fifteen-thirty-one-go"))
 	r.Use(middleware.DevCORS(cfg))
+	if cfg.DBDebug {
+		r.Use(middleware.QueryStats(cfg))
+	}
 	r.GET("/healthz", func(c *gin.Context) { c.JSON(200, gin.H{"ok": true}) })
 
 	api := r.Group("/api")
@@ -93,6 +110,10 @@ func main() {
 	handlers.RegisterLobbyRoutes(protected, db)
 	handlers.RegisterGameRoutes(protected, db)
 
+	admin := protected.Group("/admin")
+	admin.Use(middleware.RequireAdmin(cfg))
+	handlers.RegisterAdminRoutes(admin, db)
+
 	// WebSocket endpoint is auth-gated via token query param or Authorization header.
 	r.GET("/ws", handlers.WebSocketHandler(hubRef.Get, db, cfg))
 
@@ -125,6 +146,7 @@ func main() {
 		log.Printf("server error: %v", err)
 	}
 
+	stopJobs()
 	if h, ok := hubRef.Get(); ok && h != nil {
 		h.Stop()
 	}
@@ -134,4 +156,6 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Printf("server shutdown error: %v", err)
 	}
+	// Persist any write-behind engine state before the DB closes.
+	handlers.FlushPendingState(db)
 }
@@ -1,7 +1,10 @@
 package auth
 
 import (
+	"errors"
 	"fmt"
+	"log"
+	"sync"
 	"time"
 
 	"fifteen-thirty-one-go/backend/internal/config"
@@ -36,25 +39,103 @@ func GenerateToken(userID int64, username string, cfg config.Config) (string, er
 	return tok.SignedString([]byte(cfg.JWTSecret))
 }
 
+// Token rejection categories reported by TokenFailureCounts. Clients always just see
+// "invalid token"; the category is for operators telling clock skew apart from forged tokens.
+const (
+	TokenExpired      = "expired"
+	TokenNotYetValid  = "not_yet_valid" // nbf or iat beyond the leeway: a fast client clock or a crafted token
+	TokenBadSignature = "bad_signature"
+	TokenBadIssuer    = "bad_issuer"
+	TokenMalformed    = "malformed"
+	TokenInvalid      = "invalid"
+)
+
+// tokenLeeway is the clock skew tolerated on exp, nbf and iat.
+const tokenLeeway = 30 * time.Second
+
+var tokenFailures = struct {
+	mu     sync.Mutex
+	counts map[string]int64
+}{counts: map[string]int64{}}
+
+// TokenFailureCounts returns how many tokens were rejected per category since startup.
+func TokenFailureCounts() map[string]int64 {
+	tokenFailures.mu.Lock()
+	defer tokenFailures.mu.Unlock()
+	out := make(map[string]int64, len(tokenFailures.counts))
+	for k, v := range tokenFailures.counts {
+		out[k] = v
+	}
+	return out
+}
+
+// tokenFailureCategory classifies a jwt parse error.
+func tokenFailureCategory(err error) string {
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return TokenExpired
+	case errors.Is(err, jwt.ErrTokenNotValidYet), errors.Is(err, jwt.ErrTokenUsedBeforeIssued):
+		return TokenNotYetValid
+	case errors.Is(err, jwt.ErrTokenSignatureInvalid), errors.Is(err, jwt.ErrTokenUnverifiable):
+		return TokenBadSignature
+	case errors.Is(err, jwt.ErrTokenInvalidIssuer):
+		return TokenBadIssuer
+	case errors.Is(err, jwt.ErrTokenMalformed):
+		return TokenMalformed
+	default:
+		return TokenInvalid
+	}
+}
+
+// recordTokenFailure counts and logs a rejected token. For tokens from the future it also logs
+// how far ahead they are, which tells a skewed clock (seconds to minutes) from a forgery.
+func recordTokenFailure(err error, claims *Claims) {
+	category := tokenFailureCategory(err)
+	tokenFailures.mu.Lock()
+	tokenFailures.counts[category]++
+	tokenFailures.mu.Unlock()
+
+	switch {
+	case category == TokenNotYetValid && claims != nil:
+		var ahead time.Duration
+		now := time.Now()
+		if claims.NotBefore != nil {
+			ahead = claims.NotBefore.Sub(now)
+		}
+		if claims.IssuedAt != nil && claims.IssuedAt.Sub(now) > ahead {
+			ahead = claims.IssuedAt.Sub(now)
+		}
+		log.Printf("auth: token rejected: category=%s user_id=%d ahead=%s", category, claims.UserID, ahead.Round(time.Second))
+	case category == TokenExpired && claims != nil:
+		log.Printf("auth: token rejected: category=%s user_id=%d", category, claims.UserID)
+	default:
+		// Claims of a token with a bad signature or issuer can't be trusted, so none are logged.
+		log.Printf("auth: token rejected: category=%s", category)
+	}
+}
+
 func ParseAndValidateToken(tokenString string, cfg config.Config) (*Claims, error) {
 	if cfg.JWTSecret == "" {
 		return nil, fmt.Errorf("JWT_SECRET is required")
 	}
 
-	tok, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(t *jwt.Token) (any, error) {
+	claims := &Claims{}
+	tok, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (any, error) {
 		if t.Method != jwt.SigningMethodHS256 {
 			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
 		}
 		return []byte(cfg.JWTSecret), nil
 	},
 		jwt.WithIssuer(cfg.JWTIssuer),
-		jwt.WithLeeway(30*time.Second),
+		jwt.WithLeeway(tokenLeeway),
+		jwt.WithIssuedAt(),
 	)
 	if err != nil {
+		recordTokenFailure(err, claims)
 		return nil, err
 	}
-	claims, ok := tok.Claims.(*Claims)
-	if !ok || !tok.Valid {
+	if !tok.Valid {
+		recordTokenFailure(err, nil)
 		return nil, fmt.Errorf("invalid token")
 	}
 	return claims, nil
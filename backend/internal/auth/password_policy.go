@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"bufio"
+	_ "embed"
+	"strings"
+	"unicode"
+)
+
+// Password policies selectable via config.PasswordPolicy.
+const (
+	PasswordPolicyBasic  = "basic"
+	PasswordPolicyStrict = "strict"
+
+	strictMinCharClasses = 3
+)
+
+//go:embed common_passwords.txt
+var commonPasswordsRaw string
+
+var commonPasswords = func() map[string]struct{} {
+	m := make(map[string]struct{})
+	sc := bufio.NewScanner(strings.NewReader(commonPasswordsRaw))
+	for sc.Scan() {
+		if w := strings.TrimSpace(sc.Text()); w != "" {
+			m[strings.ToLower(w)] = struct{}{}
+		}
+	}
+	return m
+}()
+
+// CheckPasswordPolicy applies the policy's strength rules on top of the length checks that
+// HashPassword always enforces. The basic policy adds nothing. Strict requires at least three
+// of lowercase, uppercase, digits and symbols, rejects common passwords, and rejects
+// passwords containing the username.
+func CheckPasswordPolicy(policy, plain, username string) error {
+	if policy != PasswordPolicyStrict {
+		return nil
+	}
+	var lower, upper, digit, other bool
+	for _, r := range plain {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			other = true
+		}
+	}
+	classes := 0
+	for _, ok := range []bool{lower, upper, digit, other} {
+		if ok {
+			classes++
+		}
+	}
+	if classes < strictMinCharClasses {
+		return PasswordValidationError{msg: "password must mix at least 3 of: lowercase letters, uppercase letters, digits, symbols"}
+	}
+	folded := strings.ToLower(plain)
+	if _, ok := commonPasswords[folded]; ok {
+		return PasswordValidationError{msg: "password is too common; choose something less guessable"}
+	}
+	if u := strings.ToLower(strings.TrimSpace(username)); u != "" && strings.Contains(folded, u) {
+		return PasswordValidationError{msg: "password must not contain your username"}
+	}
+	return nil
+}
@@ -15,11 +15,163 @@ type Config struct {
 	JWTSecret string
 	JWTIssuer string
 	JWTTTL    time.Duration
+	// PasswordPolicy is "basic" (length limits only) or "strict" (also character classes, a
+	// common-password list and no username). Defaults to strict outside development.
+	PasswordPolicy string
+	// UsernameChanges lets users rename themselves (PUT /api/users/username), at most once per
+	// UsernameChangeInterval; zero means no limit.
+	UsernameChanges        bool
+	UsernameChangeInterval time.Duration
 
 	AppEnv                string
 	WSAllowedOrigins      []string
 	WSAllowQueryTokens    bool
 	DevWebSocketsAllowAll bool
+	// WSSessionTTL is how long a dropped WebSocket session can be resumed via session_id.
+	WSSessionTTL time.Duration
+
+	// WebSocket sizing: WSReadLimit caps a single inbound message, the buffer sizes feed the
+	// Upgrader, and WSSendQueue is the per-client outbound channel capacity.
+	WSReadLimit       int64
+	WSReadBufferSize  int
+	WSWriteBufferSize int
+	WSSendQueue       int
+	// WSPingPeriod is how often the server pings each client; a client silent for WSPongWait
+	// is dropped. LoadFromEnv requires WSPingPeriod < WSPongWait.
+	WSPingPeriod time.Duration
+	WSPongWait   time.Duration
+
+	// StrictHandValidation rejects moves when a player's persisted hand diverges from the
+	// engine's hand for that seat (tamper/desync guard). Defaults to true.
+	StrictHandValidation bool
+
+	// ResignPolicy controls what happens when a player resigns:
+	// "bot_takeover" hands the seat to a bot when more than two humans remain,
+	// "end_game" always finishes the game for everyone.
+	ResignPolicy string
+	// ResignBotDifficulty is the bot level that plays a resigned seat out under "bot_takeover".
+	ResignBotDifficulty string
+	// ResignToSpectator makes a player whose seat a bot takes over on resign a spectator of the
+	// lobby, so they can keep watching the game they left. Defaults to true.
+	ResignToSpectator bool
+
+	// Disconnect bot takeover (casual play): when enabled, a human whose last game connection
+	// drops is replaced by a bot after DisconnectBotGrace, and may reclaim the seat on reconnect
+	// within DisconnectReclaimWindow. Off by default. Match games, the rated format, are left
+	// alone unless DisconnectBotTakeoverMatches is also set.
+	DisconnectBotTakeover        bool
+	DisconnectBotTakeoverMatches bool
+	DisconnectBotDifficulty      string
+	DisconnectBotGrace           time.Duration
+	DisconnectReclaimWindow      time.Duration
+
+	// StateDurability selects how engine state reaches SQLite: "durable" (default) writes
+	// state_json in every move's transaction; "batched" keeps moves and hands synchronous but
+	// writes state_json behind, every StateFlushInterval or once StateFlushMaxPending games are
+	// dirty. A crash in batched mode can lose up to one interval of engine state.
+	StateDurability      string
+	StateFlushInterval   time.Duration
+	StateFlushMaxPending int64
+
+	// MaxBotsPerGame caps host-added bots per game; at least one seat always stays human.
+	// AllowAllBotGames (debug only) lifts the human requirement so bots may fill every seat.
+	MaxBotsPerGame   int64
+	AllowAllBotGames bool
+	// BotAutoCount records final hand/crib counts for bots when a hand reaches counting, so
+	// the next-hand gate only ever waits on humans.
+	BotAutoCount bool
+	// PendingActionPrompts adds pending_action (what the game expects from you next) to
+	// per-user game snapshots so reconnecting players know where they stand.
+	PendingActionPrompts bool
+	// CountingOrderHints adds counting_order and next_to_count to snapshots during counting.
+	CountingOrderHints bool
+	// GoResolvedEvents broadcasts game:go_resolved when every player passes and a pegging
+	// sequence ends on a go.
+	GoResolvedEvents bool
+	// GameEvents streams engine events (deal, discard, play, go, ...) as game:event frames.
+	GameEvents bool
+	// MugginsWindow is how long after a final count opponents may call muggins on it (games
+	// created with the muggins rule only).
+	MugginsWindow time.Duration
+	// WhatIfCutTraining lets players score their kept hand against a hypothetical cut
+	// (POST /games/:id/whatif-cut) as soon as they have discarded, not only once hands are
+	// counted. Off by default: it is a training aid.
+	WhatIfCutTraining bool
+	// TurnTimeoutForfeitAfter forfeits a player whose turns ran out this many times in a row
+	// (games created with a turn timeout only).
+	TurnTimeoutForfeitAfter int
+
+	// IncognitoSpectate gates hidden spectating: "off", "admins" (default; there is no premium
+	// tier yet) or "everyone". HiddenWatcherCountForHosts lets a lobby host see how many hidden
+	// spectators are watching, without names.
+	IncognitoSpectate          string
+	HiddenWatcherCountForHosts bool
+	// SpectatorReviewWindow is how long after a game finishes its spectators may still fetch
+	// the final board (GET /games/:id).
+	SpectatorReviewWindow time.Duration
+
+	// GameStatsEnabled records per-game pacing metrics (game_stats) when a game is finalized.
+	GameStatsEnabled bool
+	// LobbySeries keeps a running win tally across a lobby's games and its rematch lobbies.
+	LobbySeries bool
+
+	// AdminUserIDs lists users allowed to call /api/admin endpoints (ADMIN_USER_IDS, comma-separated).
+	AdminUserIDs []int64
+	// LeaderboardExportTimeout bounds how long an admin leaderboard export may run.
+	LeaderboardExportTimeout time.Duration
+	// ServerNoticeInterval is the minimum gap between admin server:notice broadcasts.
+	ServerNoticeInterval time.Duration
+
+	// AccessLogEnabled persists origin/IP/user-agent for logins and WebSocket upgrades;
+	// rows older than AccessLogRetention are pruned periodically.
+	AccessLogEnabled   bool
+	AccessLogRetention time.Duration
+
+	// StaleGameJanitor closes games with no move or lobby chat for StaleGameTimeout: a forfeit
+	// when exactly one human has been active, otherwise abandoned.
+	StaleGameJanitor bool
+	StaleGameTimeout time.Duration
+
+	// LobbyIdleKick frees the seats of humans idle past their lobby's idle_kick_seconds while
+	// it is still filling. Lobbies opt in at creation; this switch turns the sweep off.
+	LobbyIdleKick bool
+
+	// LobbyMinPlayersToStart is how many seats must be filled before a host may start a game
+	// early (POST /api/lobbies/:id/start), playing at that table size. A full lobby always
+	// starts on its own; set it to 4 to only ever start full tables.
+	LobbyMinPlayersToStart int64
+
+	// MoveArchival prunes raw game_moves of games finished more than MoveArchiveAge ago, keeping
+	// a per-player move summary and the round history for replay.
+	MoveArchival   bool
+	MoveArchiveAge time.Duration
+
+	// BotEVMaxConcurrent caps hard-bot EV discard computations running at once; bots that find
+	// every slot busy discard with the medium heuristic instead.
+	BotEVMaxConcurrent int
+
+	// BotThinkTime is how long a medium bot waits before each discard, pegging play or count;
+	// easy bots take half of it and hard bots half again more. Zero plays bot turns instantly,
+	// inside the request that handed them the turn.
+	BotThinkTime time.Duration
+
+	// SimulateMaxGames caps the games in one admin bot simulation run; SimulateMaxConcurrent
+	// caps runs in flight at once.
+	SimulateMaxGames      int
+	SimulateMaxConcurrent int
+
+	// UsersBatchMax caps how many ids one POST /api/users/batch lookup may ask for.
+	UsersBatchMax int
+
+	// MaxActiveLobbies caps the lobbies not yet finished across the server; creating one past it
+	// gets a 503. Zero means no cap.
+	MaxActiveLobbies int64
+
+	// DBDebug counts SQL statements per HTTP request and logs requests that ran more than
+	// DBDebugMaxQueries statements or took longer than DBDebugSlowRequest (development/staging).
+	DBDebug            bool
+	DBDebugMaxQueries  int
+	DBDebugSlowRequest time.Duration
 }
 
 func isJWTSecretPlaceholder(secret string) bool {
@@ -42,6 +194,73 @@ func isJWTSecretPlaceholder(secret string) bool {
 	return false
 }
 
+// envBool parses a boolean env var, warning and falling back to def on invalid input.
+func envBool(key string, def bool) bool {
+	v := strings.TrimSpace(os.Getenv(key))
+	if v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "WARNING: invalid %s=%q, using default %t\n", key, v, def)
+		return def
+	}
+	return b
+}
+
+// envChoice reads a lowercase enum env var, warning and falling back to def when the
+// value is not one of allowed.
+func envChoice(key string, def string, allowed ...string) string {
+	v := strings.ToLower(strings.TrimSpace(os.Getenv(key)))
+	if v == "" {
+		return def
+	}
+	for _, a := range allowed {
+		if v == a {
+			return v
+		}
+	}
+	fmt.Fprintf(os.Stderr, "WARNING: invalid %s=%q, using default %s\n", key, v, def)
+	return def
+}
+
+// envPositiveInt reads a positive integer, warning and falling back to def on invalid input.
+func envPositiveInt(key string, def int64) int64 {
+	v := strings.TrimSpace(os.Getenv(key))
+	if v == "" {
+		return def
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n <= 0 {
+		fmt.Fprintf(os.Stderr, "WARNING: invalid %s=%q, using default %d\n", key, v, def)
+		return def
+	}
+	return n
+}
+
+// envIntInRange reads an integer in [lo, hi], warning and falling back to def otherwise.
+func envIntInRange(key string, def, lo, hi int64) int64 {
+	v := strings.TrimSpace(os.Getenv(key))
+	if v == "" {
+		return def
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "WARNING: invalid %s=%q, using default %d\n", key, v, def)
+		return def
+	}
+	if n < lo || n > hi {
+		fmt.Fprintf(os.Stderr, "WARNING: %s=%d out of range [%d, %d], using default %d\n", key, n, lo, hi, def)
+		return def
+	}
+	return n
+}
+
+// envSeconds reads a positive number of seconds, warning and falling back to def on invalid input.
+func envSeconds(key string, def time.Duration) time.Duration {
+	return time.Duration(envPositiveInt(key, int64(def/time.Second))) * time.Second
+}
+
 func LoadFromEnv() (Config, error) {
 	ttlMinutes := int64(1440) // 24 hours
 	if v := os.Getenv("JWT_TTL_MINUTES"); v != "" {
@@ -68,6 +287,13 @@ func LoadFromEnv() (Config, error) {
 	if cfg.AppEnv == "" {
 		cfg.AppEnv = "development"
 	}
+	defaultPolicy := "strict"
+	if cfg.AppEnv == "development" {
+		defaultPolicy = "basic"
+	}
+	cfg.PasswordPolicy = envChoice("PASSWORD_POLICY", defaultPolicy, "basic", "strict")
+	cfg.UsernameChanges = envBool("USERNAME_CHANGES", true)
+	cfg.UsernameChangeInterval = time.Duration(envIntInRange("USERNAME_CHANGE_INTERVAL_DAYS", 30, 0, 365)) * 24 * time.Hour
 
 	if v := os.Getenv("WS_ALLOWED_ORIGINS"); v != "" {
 		parts := strings.Split(v, ",")
@@ -94,6 +320,92 @@ func LoadFromEnv() (Config, error) {
 		}
 	}
 
+	cfg.WSSessionTTL = envSeconds("WS_SESSION_TTL_SECONDS", 60*time.Second)
+	cfg.WSReadLimit = envIntInRange("WS_READ_LIMIT_BYTES", 64*1024, 1024, 16*1024*1024)
+	cfg.WSReadBufferSize = int(envIntInRange("WS_READ_BUFFER_SIZE", 1024, 256, 1024*1024))
+	cfg.WSWriteBufferSize = int(envIntInRange("WS_WRITE_BUFFER_SIZE", 1024, 256, 1024*1024))
+	cfg.WSSendQueue = int(envIntInRange("WS_SEND_QUEUE", 256, 1, 65536))
+	cfg.WSPongWait = envSeconds("WS_PONG_WAIT_SECONDS", 60*time.Second)
+	cfg.WSPingPeriod = envSeconds("WS_PING_PERIOD_SECONDS", 54*time.Second)
+	if cfg.WSPingPeriod >= cfg.WSPongWait {
+		return Config{}, fmt.Errorf("WS_PING_PERIOD_SECONDS (%d) must be less than WS_PONG_WAIT_SECONDS (%d)",
+			int64(cfg.WSPingPeriod/time.Second), int64(cfg.WSPongWait/time.Second))
+	}
+
+	cfg.StrictHandValidation = envBool("STRICT_HAND_VALIDATION", true)
+
+	cfg.ResignPolicy = envChoice("RESIGN_POLICY", "bot_takeover", "bot_takeover", "end_game")
+	cfg.ResignBotDifficulty = envChoice("RESIGN_BOT_DIFFICULTY", "medium", "easy", "medium", "hard")
+	cfg.ResignToSpectator = envBool("RESIGN_TO_SPECTATOR", true)
+
+	cfg.DisconnectBotTakeover = envBool("DISCONNECT_BOT_TAKEOVER", false)
+	cfg.DisconnectBotTakeoverMatches = envBool("DISCONNECT_BOT_TAKEOVER_MATCHES", false)
+	cfg.DisconnectBotDifficulty = envChoice("DISCONNECT_BOT_DIFFICULTY", "medium", "easy", "medium", "hard")
+	cfg.DisconnectBotGrace = envSeconds("DISCONNECT_BOT_GRACE_SECONDS", 30*time.Second)
+	cfg.DisconnectReclaimWindow = envSeconds("DISCONNECT_RECLAIM_WINDOW_SECONDS", 10*time.Minute)
+
+	cfg.StateDurability = envChoice("STATE_DURABILITY", "durable", "durable", "batched")
+	cfg.StateFlushInterval = envSeconds("STATE_FLUSH_INTERVAL_SECONDS", 2*time.Second)
+	cfg.StateFlushMaxPending = envPositiveInt("STATE_FLUSH_MAX_PENDING", 64)
+
+	cfg.MaxBotsPerGame = envPositiveInt("MAX_BOTS_PER_GAME", 3)
+	cfg.AllowAllBotGames = envBool("ALLOW_ALL_BOT_GAMES", false)
+	cfg.BotAutoCount = envBool("BOT_AUTO_COUNT", true)
+	cfg.PendingActionPrompts = envBool("PENDING_ACTION_PROMPTS", true)
+	cfg.GoResolvedEvents = envBool("GO_RESOLVED_EVENTS", true)
+	cfg.CountingOrderHints = envBool("COUNTING_ORDER_HINTS", true)
+	cfg.GameEvents = envBool("GAME_EVENTS", true)
+	cfg.MugginsWindow = envSeconds("MUGGINS_WINDOW_SECONDS", 30*time.Second)
+	cfg.WhatIfCutTraining = envBool("WHATIF_CUT_TRAINING", false)
+	cfg.TurnTimeoutForfeitAfter = int(envIntInRange("TURN_TIMEOUT_FORFEIT_AFTER", 3, 1, 100))
+
+	cfg.IncognitoSpectate = envChoice("INCOGNITO_SPECTATE", "admins", "off", "admins", "everyone")
+	cfg.HiddenWatcherCountForHosts = envBool("HIDDEN_WATCHER_COUNT_FOR_HOSTS", false)
+	cfg.SpectatorReviewWindow = time.Duration(envIntInRange("SPECTATOR_REVIEW_MINUTES", 30, 1, 24*60)) * time.Minute
+	cfg.GameStatsEnabled = envBool("GAME_STATS_ENABLED", true)
+	cfg.LobbySeries = envBool("LOBBY_SERIES", true)
+
+	if v := os.Getenv("ADMIN_USER_IDS"); v != "" {
+		for _, p := range strings.Split(v, ",") {
+			p = strings.TrimSpace(p)
+			if p == "" {
+				continue
+			}
+			id, err := strconv.ParseInt(p, 10, 64)
+			if err != nil || id <= 0 {
+				fmt.Fprintf(os.Stderr, "WARNING: ignoring invalid ADMIN_USER_IDS entry %q\n", p)
+				continue
+			}
+			cfg.AdminUserIDs = append(cfg.AdminUserIDs, id)
+		}
+	}
+	cfg.LeaderboardExportTimeout = envSeconds("LEADERBOARD_EXPORT_TIMEOUT_SECONDS", 2*time.Minute)
+	cfg.ServerNoticeInterval = envSeconds("SERVER_NOTICE_MIN_INTERVAL_SECONDS", 30*time.Second)
+
+	cfg.AccessLogEnabled = envBool("ACCESS_LOG_ENABLED", false)
+	cfg.AccessLogRetention = time.Duration(envPositiveInt("ACCESS_LOG_RETENTION_DAYS", 30)) * 24 * time.Hour
+
+	cfg.StaleGameJanitor = envBool("STALE_GAME_JANITOR", true)
+	cfg.StaleGameTimeout = time.Duration(envPositiveInt("STALE_GAME_TIMEOUT_HOURS", 72)) * time.Hour
+
+	cfg.LobbyIdleKick = envBool("LOBBY_IDLE_KICK", true)
+	cfg.LobbyMinPlayersToStart = envIntInRange("LOBBY_MIN_PLAYERS_TO_START", 2, 2, 4)
+
+	cfg.MoveArchival = envBool("MOVE_ARCHIVAL", false)
+	cfg.MoveArchiveAge = time.Duration(envPositiveInt("MOVE_ARCHIVE_AFTER_DAYS", 90)) * 24 * time.Hour
+
+	cfg.BotEVMaxConcurrent = int(envPositiveInt("BOT_EV_MAX_CONCURRENT", 4))
+	cfg.BotThinkTime = time.Duration(envIntInRange("BOT_THINK_MS", 0, 0, 10000)) * time.Millisecond
+	cfg.SimulateMaxGames = int(envPositiveInt("SIMULATE_MAX_GAMES", 500))
+	cfg.SimulateMaxConcurrent = int(envPositiveInt("SIMULATE_MAX_CONCURRENT", 1))
+
+	cfg.UsersBatchMax = int(envIntInRange("USERS_BATCH_MAX", 100, 1, 1000))
+	cfg.MaxActiveLobbies = envIntInRange("MAX_ACTIVE_LOBBIES", 0, 0, 1_000_000)
+
+	cfg.DBDebug = envBool("DB_QUERY_DEBUG", false)
+	cfg.DBDebugMaxQueries = int(envPositiveInt("DB_QUERY_DEBUG_MAX_QUERIES", 20))
+	cfg.DBDebugSlowRequest = time.Duration(envPositiveInt("DB_QUERY_DEBUG_SLOW_MS", 250)) * time.Millisecond
+
 	// JWT secret validation:
 	// - must be present (and not a placeholder)
 	// - must be at least 32 bytes for HS256
@@ -138,3 +450,25 @@ func LoadFromEnv() (Config, error) {
 
 	return cfg, nil
 }
+
+// CanSpectateIncognito reports whether userID may spectate without being listed.
+func (c Config) CanSpectateIncognito(userID int64) bool {
+	switch c.IncognitoSpectate {
+	case "everyone":
+		return true
+	case "admins":
+		return c.IsAdmin(userID)
+	default:
+		return false
+	}
+}
+
+// IsAdmin reports whether userID is listed in AdminUserIDs.
+func (c Config) IsAdmin(userID int64) bool {
+	for _, id := range c.AdminUserIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
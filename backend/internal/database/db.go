@@ -29,7 +29,7 @@ func OpenAndMigrate(dbPath string) (*sql.DB, error) {
 		}
 	}
 
-	db, err := sql.Open("sqlite3", sqliteDSN(dbPath))
+	db, err := sql.Open(driverName, sqliteDSN(dbPath))
 	if err != nil {
 		return nil, fmt.Errorf("sql open: %w", err)
 	}
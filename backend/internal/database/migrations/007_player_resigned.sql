@@ -0,0 +1,3 @@
+-- Track players who resigned mid-game so final standings can rank them last
+-- even when a bot has taken over their seat.
+ALTER TABLE game_players ADD COLUMN resigned BOOLEAN NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+-- Record when a disconnected human's seat was handed to a bot, so the human can
+-- reclaim it on reconnect within the configured window.
+ALTER TABLE game_players ADD COLUMN bot_takeover_at TIMESTAMP;
@@ -0,0 +1,5 @@
+-- Quiet hours: suppress realtime "your turn" pings between start and end (HH:MM, local to timezone).
+-- NULL start/end means quiet hours are disabled.
+ALTER TABLE user_preferences ADD COLUMN quiet_hours_start TEXT;
+ALTER TABLE user_preferences ADD COLUMN quiet_hours_end TEXT;
+ALTER TABLE user_preferences ADD COLUMN timezone TEXT NOT NULL DEFAULT 'UTC';
@@ -0,0 +1,15 @@
+-- Security audit trail for logins and WebSocket upgrades (enabled via ACCESS_LOG_ENABLED).
+CREATE TABLE IF NOT EXISTS access_log (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  event TEXT NOT NULL, -- login|ws_upgrade
+  success BOOLEAN NOT NULL DEFAULT 1,
+  user_id INTEGER, -- NULL for failed logins of unknown users
+  username TEXT NOT NULL DEFAULT '',
+  ip TEXT NOT NULL DEFAULT '',
+  origin TEXT NOT NULL DEFAULT '',
+  user_agent TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_access_log_created_at ON access_log(created_at);
+CREATE INDEX IF NOT EXISTS idx_access_log_user_id_created_at ON access_log(user_id, created_at);
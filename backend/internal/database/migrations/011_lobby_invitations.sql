@@ -0,0 +1,16 @@
+-- Lobby invitations (used by "play again with same opponents" challenges).
+CREATE TABLE IF NOT EXISTS lobby_invitations (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  lobby_id INTEGER NOT NULL,
+  inviter_id INTEGER NOT NULL,
+  invitee_id INTEGER NOT NULL,
+  token TEXT NOT NULL UNIQUE,
+  status TEXT NOT NULL DEFAULT 'pending' CHECK(status IN ('pending', 'accepted', 'declined')),
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE(lobby_id, invitee_id),
+  FOREIGN KEY(lobby_id) REFERENCES lobbies(id) ON DELETE CASCADE ON UPDATE CASCADE,
+  FOREIGN KEY(inviter_id) REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+  FOREIGN KEY(invitee_id) REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_lobby_invitations_invitee_status ON lobby_invitations(invitee_id, status);
@@ -0,0 +1,13 @@
+-- A lobby owns exactly one active (waiting or in_progress) game; rematches create a new lobby.
+-- Every "pick the lobby's game" query already takes the newest row, so any older active
+-- duplicates were unreachable. Close them out before enforcing the invariant.
+UPDATE games
+SET status = 'finished', finished_at = COALESCE(finished_at, CURRENT_TIMESTAMP)
+WHERE status IN ('waiting', 'in_progress')
+  AND id < (
+    SELECT MAX(g2.id) FROM games g2
+    WHERE g2.lobby_id = games.lobby_id AND g2.status IN ('waiting', 'in_progress')
+  );
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_games_one_active_per_lobby
+  ON games(lobby_id) WHERE status IN ('waiting', 'in_progress');
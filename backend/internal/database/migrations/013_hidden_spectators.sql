@@ -0,0 +1,3 @@
+-- Incognito spectators keep their row (so access checks still pass) but are left out of the
+-- public spectator list and count.
+ALTER TABLE lobby_spectators ADD COLUMN hidden INTEGER NOT NULL DEFAULT 0;
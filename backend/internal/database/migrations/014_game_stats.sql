@@ -0,0 +1,13 @@
+-- Per-game pacing metrics derived from game_moves at finalize time.
+CREATE TABLE IF NOT EXISTS game_stats (
+  game_id INTEGER PRIMARY KEY,
+  duration_seconds INTEGER NOT NULL,
+  hands INTEGER NOT NULL,
+  turns INTEGER NOT NULL,
+  avg_turn_seconds REAL NOT NULL,
+  longest_turn_seconds INTEGER NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY(game_id) REFERENCES games(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_game_stats_created_at ON game_stats(created_at);
@@ -0,0 +1,3 @@
+-- Pegging points presentation: immediate (itemized per play) or sequence_end (running total,
+-- breakdown when the sequence ends).
+ALTER TABLE user_preferences ADD COLUMN pegging_reveal TEXT NOT NULL DEFAULT 'immediate';
@@ -0,0 +1,4 @@
+-- How a game's standings were decided: played to the end, forfeited by an idle player, or
+-- abandoned by everyone. Abandoned results are kept for history but excluded from win/played stats.
+ALTER TABLE scoreboard ADD COLUMN outcome TEXT NOT NULL DEFAULT 'completed'
+  CHECK(outcome IN ('completed', 'forfeit', 'abandoned'));
@@ -0,0 +1,13 @@
+-- Raw game_moves of long-finished games are pruned by the archival job; a per-player, per-type
+-- summary is kept instead and games.moves_archived_at marks the game as archived.
+ALTER TABLE games ADD COLUMN moves_archived_at TIMESTAMP;
+
+CREATE TABLE IF NOT EXISTS game_move_summaries (
+  game_id INTEGER NOT NULL,
+  player_id INTEGER NOT NULL,
+  move_type TEXT NOT NULL,
+  moves INTEGER NOT NULL,
+  points_verified INTEGER NOT NULL,
+  PRIMARY KEY(game_id, player_id, move_type),
+  FOREIGN KEY(game_id) REFERENCES games(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
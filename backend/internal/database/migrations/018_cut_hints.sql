@@ -0,0 +1,2 @@
+-- Opt-in discard coaching: how each possible discard's kept hand scores across the remaining cuts.
+ALTER TABLE user_preferences ADD COLUMN cut_hints INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,7 @@
+-- One standings row per player per game, so racing finalizations can't record a player twice.
+-- Drop any duplicates first, keeping the earliest row.
+DELETE FROM scoreboard
+WHERE id NOT IN (SELECT MIN(id) FROM scoreboard GROUP BY game_id, user_id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_scoreboard_game_id_user_id
+ON scoreboard(game_id, user_id);
@@ -0,0 +1,2 @@
+-- How much a "suggest" auto-count suggestion explains: score | summary | detailed.
+ALTER TABLE user_preferences ADD COLUMN suggest_verbosity TEXT NOT NULL DEFAULT 'summary';
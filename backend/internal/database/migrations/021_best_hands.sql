@@ -0,0 +1,17 @@
+-- Each player's highest-scoring counted hand per completed game, recorded at finalize time from
+-- the round history, for the "best hands ever" stat.
+CREATE TABLE IF NOT EXISTS best_hands (
+  game_id INTEGER NOT NULL,
+  user_id INTEGER NOT NULL,
+  round INTEGER NOT NULL,
+  cards TEXT NOT NULL, -- JSON array of card codes, e.g. ["5H","5S","5D","JC"]
+  cut TEXT NOT NULL,
+  points INTEGER NOT NULL,
+  breakdown TEXT NOT NULL, -- JSON ScoreBreakdown
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY(game_id, user_id),
+  FOREIGN KEY(game_id) REFERENCES games(id) ON DELETE CASCADE ON UPDATE CASCADE,
+  FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_best_hands_user_points ON best_hands(user_id, points DESC);
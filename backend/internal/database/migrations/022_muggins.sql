@@ -0,0 +1,5 @@
+-- Muggins moves reference the under-claimed count they punish; the partial unique index lets
+-- each count be called at most once, even when several opponents race.
+ALTER TABLE game_moves ADD COLUMN ref_move_id INTEGER;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_game_moves_muggins_ref ON game_moves(ref_move_id) WHERE move_type = 'muggins';
@@ -0,0 +1,28 @@
+-- Completed rounds, written in the same transaction as the move that finished the round, so
+-- round history survives without the engine state and per-player hand scores can be queried.
+CREATE TABLE IF NOT EXISTS game_rounds (
+  game_id INTEGER NOT NULL,
+  round INTEGER NOT NULL,
+  dealer_index INTEGER NOT NULL,
+  cut TEXT NOT NULL DEFAULT '',
+  crib_points INTEGER, -- NULL when the game ended before the crib was counted
+  summary TEXT NOT NULL, -- JSON RoundSummary, as in the engine history
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY(game_id, round),
+  FOREIGN KEY(game_id) REFERENCES games(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+-- One row per counted hand in a round.
+CREATE TABLE IF NOT EXISTS game_round_hands (
+  game_id INTEGER NOT NULL,
+  round INTEGER NOT NULL,
+  seat INTEGER NOT NULL,
+  user_id INTEGER NOT NULL,
+  points INTEGER NOT NULL,
+  breakdown TEXT NOT NULL, -- JSON ScoreBreakdown
+  PRIMARY KEY(game_id, round, seat),
+  FOREIGN KEY(game_id, round) REFERENCES game_rounds(game_id, round) ON DELETE CASCADE ON UPDATE CASCADE,
+  FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_game_round_hands_user ON game_round_hands(user_id, points DESC);
@@ -0,0 +1,3 @@
+-- How badly each losing player was beaten in a completed game: 0 none, 1 skunk (below the
+-- skunk line), 2 double skunk. Winners and forfeited or abandoned games are always 0.
+ALTER TABLE scoreboard ADD COLUMN skunk_level INTEGER NOT NULL DEFAULT 0;
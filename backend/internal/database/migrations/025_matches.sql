@@ -0,0 +1,29 @@
+-- Best-of-N matches: consecutive games in one lobby played to target_points game points (a win
+-- scores 1, a skunk 2, a double skunk 3).
+CREATE TABLE IF NOT EXISTS matches (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  lobby_id INTEGER NOT NULL UNIQUE,
+  target_points INTEGER NOT NULL,
+  status TEXT NOT NULL DEFAULT 'in_progress', -- in_progress|finished|forfeited|abandoned
+  winner_id INTEGER,
+  forfeited_by INTEGER,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  finished_at TIMESTAMP,
+  FOREIGN KEY(lobby_id) REFERENCES lobbies(id) ON DELETE CASCADE ON UPDATE CASCADE,
+  FOREIGN KEY(winner_id) REFERENCES users(id) ON DELETE SET NULL ON UPDATE CASCADE,
+  FOREIGN KEY(forfeited_by) REFERENCES users(id) ON DELETE SET NULL ON UPDATE CASCADE
+);
+
+-- The games of a match in order; winner_id is NULL until the game is finalized.
+CREATE TABLE IF NOT EXISTS match_games (
+  match_id INTEGER NOT NULL,
+  game_number INTEGER NOT NULL,
+  game_id INTEGER NOT NULL UNIQUE,
+  winner_id INTEGER,
+  skunk_level INTEGER NOT NULL DEFAULT 0,
+  points INTEGER NOT NULL DEFAULT 0,
+  PRIMARY KEY(match_id, game_number),
+  FOREIGN KEY(match_id) REFERENCES matches(id) ON DELETE CASCADE ON UPDATE CASCADE,
+  FOREIGN KEY(game_id) REFERENCES games(id) ON DELETE CASCADE ON UPDATE CASCADE,
+  FOREIGN KEY(winner_id) REFERENCES users(id) ON DELETE SET NULL ON UPDATE CASCADE
+);
@@ -0,0 +1,20 @@
+-- Casual series: a running tally of wins across a lobby's games and the rematch lobbies started
+-- from them. Lobbies in the same series share series_id; a lobby leaves its series (series_id
+-- back to NULL) when a player leaves mid-game.
+CREATE TABLE IF NOT EXISTS lobby_series (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  games_played INTEGER NOT NULL DEFAULT 0,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS lobby_series_wins (
+  series_id INTEGER NOT NULL,
+  user_id INTEGER NOT NULL,
+  wins INTEGER NOT NULL DEFAULT 0,
+  PRIMARY KEY(series_id, user_id),
+  FOREIGN KEY(series_id) REFERENCES lobby_series(id) ON DELETE CASCADE ON UPDATE CASCADE,
+  FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+ALTER TABLE lobbies ADD COLUMN series_id INTEGER REFERENCES lobby_series(id) ON DELETE SET NULL;
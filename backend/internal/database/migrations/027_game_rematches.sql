@@ -0,0 +1,13 @@
+-- One rematch per finished game. The row is claimed (lobby_id/game_id NULL) before the rematch
+-- lobby is built, so two players asking at once get the same lobby instead of two.
+CREATE TABLE IF NOT EXISTS game_rematches (
+  source_game_id INTEGER PRIMARY KEY,
+  lobby_id INTEGER,
+  game_id INTEGER,
+  created_by INTEGER NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY(source_game_id) REFERENCES games(id) ON DELETE CASCADE ON UPDATE CASCADE,
+  FOREIGN KEY(lobby_id) REFERENCES lobbies(id) ON DELETE SET NULL ON UPDATE CASCADE,
+  FOREIGN KEY(game_id) REFERENCES games(id) ON DELETE SET NULL ON UPDATE CASCADE,
+  FOREIGN KEY(created_by) REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
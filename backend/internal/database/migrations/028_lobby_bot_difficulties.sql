@@ -0,0 +1,3 @@
+-- Bot difficulties a lobby's host may add, comma-separated (e.g. "easy" for a beginners' table).
+-- NULL allows every difficulty.
+ALTER TABLE lobbies ADD COLUMN allowed_bot_difficulties TEXT;
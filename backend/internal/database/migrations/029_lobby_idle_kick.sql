@@ -0,0 +1,2 @@
+-- Seconds a human may sit idle in a waiting lobby before their seat is freed. NULL never kicks.
+ALTER TABLE lobbies ADD COLUMN idle_kick_seconds INTEGER;
@@ -0,0 +1,4 @@
+-- Messages the lobby host pinned (house rules, links). A lobby keeps at most a few pins.
+ALTER TABLE lobby_messages ADD COLUMN pinned INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_lobby_messages_pinned ON lobby_messages(lobby_id) WHERE pinned = 1;
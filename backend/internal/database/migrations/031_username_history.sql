@@ -0,0 +1,11 @@
+-- One row per username change, kept for moderation and so old names stay traceable.
+CREATE TABLE IF NOT EXISTS username_history (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id INTEGER NOT NULL,
+  old_username TEXT NOT NULL,
+  new_username TEXT NOT NULL,
+  changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_username_history_user_id_changed_at ON username_history(user_id, changed_at DESC);
@@ -0,0 +1,5 @@
+-- One-time backfill for users created before registration seeded preferences (see
+-- models.InitDefaultPreferences). The column defaults are the preference defaults.
+INSERT INTO user_preferences(user_id)
+SELECT u.id FROM users u
+WHERE NOT EXISTS (SELECT 1 FROM user_preferences p WHERE p.user_id = u.id);
@@ -0,0 +1,10 @@
+-- The games counted towards each series, so a game is tallied once however many times its
+-- finalization runs.
+CREATE TABLE IF NOT EXISTS lobby_series_games (
+  game_id INTEGER PRIMARY KEY,
+  series_id INTEGER NOT NULL,
+  winner_id INTEGER NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY(game_id) REFERENCES games(id) ON DELETE CASCADE ON UPDATE CASCADE,
+  FOREIGN KEY(series_id) REFERENCES lobby_series(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
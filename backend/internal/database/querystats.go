@@ -0,0 +1,91 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// statsDriverName is the sqlite3 driver wrapped to count statements (see EnableQueryStats).
+const statsDriverName = "sqlite3-querystats"
+
+var (
+	driverName       = "sqlite3"
+	registerStatsDrv sync.Once
+
+	queryCount atomic.Int64
+	queryNanos atomic.Int64
+)
+
+// EnableQueryStats makes OpenAndMigrate use a driver that counts every statement and its
+// execution time, for the per-request query logger. Call it before OpenAndMigrate.
+func EnableQueryStats() {
+	registerStatsDrv.Do(func() {
+		sql.Register(statsDriverName, statsDriver{&sqlite3.SQLiteDriver{}})
+	})
+	driverName = statsDriverName
+}
+
+// QueryStats returns the statements executed process-wide since startup and the time spent
+// executing them. Callers diff two readings to measure a window. Without EnableQueryStats both
+// stay zero.
+func QueryStats() (int64, time.Duration) {
+	return queryCount.Load(), time.Duration(queryNanos.Load())
+}
+
+func recordQuery(start time.Time) {
+	queryCount.Add(1)
+	queryNanos.Add(int64(time.Since(start)))
+}
+
+type statsDriver struct{ driver.Driver }
+
+func (d statsDriver) Open(name string) (driver.Conn, error) {
+	c, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &statsConn{c.(*sqlite3.SQLiteConn)}, nil
+}
+
+// statsConn forwards to the sqlite3 connection, counting statements on the way through.
+type statsConn struct{ *sqlite3.SQLiteConn }
+
+func (c *statsConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	defer recordQuery(time.Now())
+	return c.SQLiteConn.ExecContext(ctx, query, args)
+}
+
+func (c *statsConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	defer recordQuery(time.Now())
+	return c.SQLiteConn.QueryContext(ctx, query, args)
+}
+
+func (c *statsConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	s, err := c.SQLiteConn.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &statsStmt{s.(*sqlite3.SQLiteStmt)}, nil
+}
+
+func (c *statsConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+type statsStmt struct{ *sqlite3.SQLiteStmt }
+
+func (s *statsStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	defer recordQuery(time.Now())
+	return s.SQLiteStmt.ExecContext(ctx, args)
+}
+
+func (s *statsStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	defer recordQuery(time.Now())
+	return s.SQLiteStmt.QueryContext(ctx, args)
+}
@@ -85,3 +85,21 @@ func (c Card) Value15() int {
 	}
 	return int(c.Rank)
 }
+
+// SameCards reports whether a and b contain the same cards, ignoring order.
+func SameCards(a, b []Card) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := make(map[Card]int, len(a))
+	for _, c := range a {
+		counts[c]++
+	}
+	for _, c := range b {
+		if counts[c] == 0 {
+			return false
+		}
+		counts[c]--
+	}
+	return true
+}
@@ -2,8 +2,10 @@ package cribbage
 
 import (
 	"errors"
+	"fmt"
 	"math/rand"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -18,14 +20,28 @@ const (
 	BotHard   BotDifficulty = "hard"
 )
 
+var ErrInvalidBotDifficulty = errors.New("invalid bot difficulty")
+
+// ParseBotDifficulty normalizes s (case and surrounding space) to a supported difficulty.
+func ParseBotDifficulty(s string) (BotDifficulty, error) {
+	switch d := BotDifficulty(strings.ToLower(strings.TrimSpace(s))); d {
+	case BotEasy, BotMedium, BotHard:
+		return d, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrInvalidBotDifficulty, s)
+}
+
 var botRandMu sync.Mutex
 var botRand = rand.New(rand.NewSource(time.Now().UnixNano()))
 
+// ChooseDiscard picks a two-card discard into an opponent's crib.
 func ChooseDiscard(hand []common.Card, difficulty BotDifficulty) ([]common.Card, error) {
-	return ChooseDiscardN(hand, 2, difficulty)
+	return ChooseDiscardN(hand, 2, difficulty, false)
 }
 
-func ChooseDiscardN(hand []common.Card, discardCount int, difficulty BotDifficulty) ([]common.Card, error) {
+// ChooseDiscardN picks discardCount cards to throw to the crib; ownCrib is true when the bot is
+// the dealer. Only the hard bot weighs the crib.
+func ChooseDiscardN(hand []common.Card, discardCount int, difficulty BotDifficulty, ownCrib bool) ([]common.Card, error) {
 	if discardCount <= 0 {
 		return nil, errors.New("invalid discard count")
 	}
@@ -42,7 +58,28 @@ func ChooseDiscardN(hand []common.Card, discardCount int, difficulty BotDifficul
 		botRand.Shuffle(len(cards), func(i, j int) { cards[i], cards[j] = cards[j], cards[i] })
 		botRandMu.Unlock()
 		return cards[:discardCount], nil
-	case BotMedium, BotHard:
+	case BotHard:
+		// Keep the split with the best expected hand score over all cuts (see DiscardOptions),
+		// plus what the discards should add to the bot's own crib or minus what they hand an
+		// opponent's. The first best split wins ties, so the choice is deterministic.
+		opts := DiscardOptions(cards, discardCount)
+		if len(opts) == 0 {
+			return ChooseDiscardN(hand, discardCount, BotMedium, ownCrib)
+		}
+		best, bestValue := 0, 0.0
+		for i, o := range opts {
+			v := o.EV
+			if crib := discardCribEV(o.Discard, cards); ownCrib {
+				v += crib
+			} else {
+				v -= crib
+			}
+			if i == 0 || v > bestValue {
+				best, bestValue = i, v
+			}
+		}
+		return opts[best].Discard, nil
+	case BotMedium:
 		// Simple heuristic: discard two lowest Value15 cards, breaking ties by rank.
 		sort.Slice(cards, func(i, j int) bool {
 			vi, vj := cards[i].Value15(), cards[j].Value15()
@@ -56,10 +93,34 @@ func ChooseDiscardN(hand []common.Card, discardCount int, difficulty BotDifficul
 		})
 		return cards[:discardCount], nil
 	default:
-		return ChooseDiscardN(hand, discardCount, BotEasy)
+		return ChooseDiscardN(hand, discardCount, BotEasy, ownCrib)
 	}
 }
 
+// discardCribEV estimates what discard is worth to the crib: the fifteens, pairs and runs among
+// the discarded cards and the cut, plus nobs for a discarded jack, averaged over every cut not in
+// hand. The other crib cards are unknown and left out.
+func discardCribEV(discard, hand []common.Card) float64 {
+	inHand := map[common.Card]bool{}
+	for _, c := range hand {
+		inHand[c] = true
+	}
+	cards := append(append([]common.Card(nil), discard...), common.Card{})
+	total, cuts := 0, 0
+	for _, cut := range common.NewStandardDeck() {
+		if inHand[cut] {
+			continue
+		}
+		cards[len(cards)-1] = cut
+		total += scoreFifteens(cards) + scorePairs(cards) + scoreRuns(cards) + scoreNobs(discard, cut)
+		cuts++
+	}
+	if cuts == 0 {
+		return 0
+	}
+	return float64(total) / float64(cuts)
+}
+
 // ChoosePeggingPlay returns either a card to play, or go=true if no legal play exists.
 func ChoosePeggingPlay(hand []common.Card, peggingTotal int, peggingSeq []common.Card, difficulty BotDifficulty) (card *common.Card, goPlay bool) {
 	var legal []common.Card
@@ -80,38 +141,76 @@ func ChoosePeggingPlay(hand []common.Card, peggingTotal int, peggingSeq []common
 		pick := legal[botRand.Intn(len(legal))]
 		return &pick, false
 	case BotMedium:
-		return bestImmediatePegging(legal, peggingTotal, peggingSeq, false)
+		return bestImmediatePegging(legal, peggingTotal, peggingSeq)
 	case BotHard:
-		return bestImmediatePegging(legal, peggingTotal, peggingSeq, true)
+		return bestDefensivePegging(hand, legal, peggingTotal, peggingSeq)
 	default:
 		pick := legal[botRand.Intn(len(legal))]
 		return &pick, false
 	}
 }
 
-func bestImmediatePegging(legal []common.Card, peggingTotal int, peggingSeq []common.Card, applyRiskPenalty bool) (*common.Card, bool) {
+func bestImmediatePegging(legal []common.Card, peggingTotal int, peggingSeq []common.Card) (*common.Card, bool) {
 	bestIdx := 0
 	bestScore := -999999
 	for i, c := range legal {
-		points, newTotal, _ := PeggingScore(peggingSeq, c, peggingTotal)
+		points, _, _ := PeggingScore(peggingSeq, c, peggingTotal)
 		score := points * 100
 
 		// Prefer lower cards when no points are gained (keeps flexibility).
 		score -= c.Value15()
 
-		// Hard: avoid leaving an easy 15/31 setup when possible.
-		if applyRiskPenalty {
-			need15 := 15 - newTotal
-			need31 := 31 - newTotal
-			if need15 >= 1 && need15 <= 10 {
-				score -= 3
-			}
-			if need31 >= 1 && need31 <= 10 {
-				score -= 3
-			}
+		if score > bestScore {
+			bestScore = score
+			bestIdx = i
 		}
+	}
+	pick := legal[bestIdx]
+	return &pick, false
+}
 
-		if score > bestScore {
+// bestDefensivePegging is the hard bot's pegging: each legal card is worth what it pegs now
+// minus what the next player can expect to peg in reply. Replies are weighted by how many of
+// each rank are still unseen, i.e. not in the bot's hand or already played in this count, so
+// a card that leaves 21 with plenty of tens out is avoided, and a 4 (no single card makes 15)
+// is preferred over a 5 as a lead. Ties keep hand order, so the choice is deterministic.
+func bestDefensivePegging(hand, legal []common.Card, peggingTotal int, peggingSeq []common.Card) (*common.Card, bool) {
+	unseen := map[common.Rank]int{}
+	for r := common.Ace; r <= common.King; r++ {
+		unseen[r] = 4
+	}
+	for _, c := range hand {
+		unseen[c.Rank]--
+	}
+	for _, c := range peggingSeq {
+		unseen[c.Rank]--
+	}
+	totalUnseen := 0
+	for _, n := range unseen {
+		totalUnseen += max(n, 0)
+	}
+
+	bestIdx := 0
+	bestScore := 0.0
+	for i, c := range legal {
+		points, newTotal, _ := PeggingScore(peggingSeq, c, peggingTotal)
+		seq := append(append([]common.Card(nil), peggingSeq...), c)
+		risk := 0.0
+		if totalUnseen > 0 && newTotal < 31 {
+			for r := common.Ace; r <= common.King; r++ {
+				n := unseen[r]
+				reply := common.Card{Rank: r}
+				if n <= 0 || newTotal+reply.Value15() > 31 {
+					continue
+				}
+				// Suits don't matter to pegging scores, so any card of the rank stands in.
+				replyPoints, _, _ := PeggingScore(seq, reply, newTotal)
+				risk += float64(n*replyPoints) / float64(totalUnseen)
+			}
+		}
+		// Prefer lower cards when the rest is equal (keeps flexibility).
+		score := float64(points) - risk - float64(c.Value15())/100
+		if i == 0 || score > bestScore {
 			bestScore = score
 			bestIdx = i
 		}
@@ -0,0 +1,80 @@
+package cribbage
+
+import (
+	"slices"
+	"testing"
+
+	"fifteen-thirty-one-go/backend/internal/game/common"
+)
+
+// sameCards reports whether a and b hold the same cards in any order.
+func sameCards(a, b []common.Card) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, c := range a {
+		if !slices.Contains(b, c) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestHardBotKeepsMadeHands(t *testing.T) {
+	tests := []struct {
+		name    string
+		hand    string
+		discard string
+	}{
+		{"run of four", "7H 8D 9S 10C KH 2D", "KH 2D"},
+		{"fifteens and a pair", "5H 5D JS QC AH 2S", "AH 2S"},
+		{"two pairs of fifteens", "7H 8D 7S 8C KH 2D", "KH 2D"},
+		{"double run", "4H 5D 6S 6C KH", "KH"}, // three or four players throw one
+	}
+	for _, tt := range tests {
+		hand, want := cards(t, tt.hand), cards(t, tt.discard)
+		for _, ownCrib := range []bool{false, true} {
+			got, err := ChooseDiscardN(hand, len(want), BotHard, ownCrib)
+			if err != nil {
+				t.Fatalf("%s (own crib %t): %v", tt.name, ownCrib, err)
+			}
+			if !sameCards(got, want) {
+				t.Errorf("%s (own crib %t): discarded %v, want %v", tt.name, ownCrib, got, want)
+			}
+		}
+	}
+}
+
+func TestHardBotPegsDefensively(t *testing.T) {
+	tests := []struct {
+		name      string
+		seq, hand string
+		want      string
+	}{
+		// A 6 leaves 21 for any of the unseen tens to make 31; a 9 leaves 24, which only a 7
+		// reaches. Neither scores now, and the medium bot would play the lower 6.
+		{"avoids leaving 21", "KS 5C", "6H 9D", "9D"},
+		{"avoids leaving 21, other hand order", "KS 5C", "9D 6H", "9D"},
+		// 21 is only a risk when the bot can't make the 31 itself.
+		{"takes the 31", "KS 5C 6H", "10H 3D", "10H"},
+		// No single card makes 15 off a 4, while any ten does off a 5.
+		{"leads a 4 over a 5", "", "5D 4H", "4H"},
+	}
+	for _, tt := range tests {
+		seq, hand := cards(t, tt.seq), cards(t, tt.hand)
+		total := 0
+		for _, c := range seq {
+			total += c.Value15()
+		}
+		got, goPlay := ChoosePeggingPlay(hand, total, seq, BotHard)
+		if goPlay || got == nil || *got != cards(t, tt.want)[0] {
+			t.Errorf("%s: played %v (go %t), want %s", tt.name, got, goPlay, tt.want)
+		}
+	}
+
+	// The medium bot only looks at what a card pegs now, so it walks into the 21.
+	seq := cards(t, "KS 5C")
+	if got, _ := ChoosePeggingPlay(cards(t, "6H 9D"), 15, seq, BotMedium); *got != cards(t, "6H")[0] {
+		t.Errorf("medium bot played %v at 15, want the lower 6H", got)
+	}
+}
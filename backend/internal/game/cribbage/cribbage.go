@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"fifteen-thirty-one-go/backend/internal/game/common"
 	"fifteen-thirty-one-go/backend/internal/models"
@@ -31,10 +32,14 @@ type State struct {
 	KeptHands [][]common.Card `json:"kept_hands"` // 4-card hands used for counting (set after discards)
 	Crib      []common.Card   `json:"crib"`
 
+	// Discards holds what each seat threw to the crib this hand. Views reveal it only once the
+	// hand is counted, and only under Rules.RevealHands.
+	Discards [][]common.Card `json:"discards,omitempty"`
+
 	PeggingTotal     int           `json:"pegging_total"`
 	PeggingSeq       []common.Card `json:"pegging_seq"`
 	PeggingPassed    []bool        `json:"pegging_passed"`
-	DiscardCompleted []bool        `json:"discard_completed"`
+	DiscardCompleted []bool        `json:"discard_completed"` // per player; non-secret, drives "waiting for X to discard"
 
 	Scores []int  `json:"scores"`
 	Stage  string `json:"stage"` // dealing|discard|pegging|counting|finished
@@ -50,6 +55,58 @@ type State struct {
 	// History is an append-only record of completed counting phases.
 	// It intentionally contains only information that is no longer secret (past kept hands, crib, cut, breakdown).
 	History []RoundSummary `json:"history,omitempty"`
+
+	// SequencePegs lists the scoring events of the pegging sequence in progress; when the
+	// sequence ends they move to LastSequencePegs. Handlers use them to reveal pegging points
+	// at sequence end for players who prefer that.
+	SequencePegs     []PegEvent `json:"sequence_pegs,omitempty"`
+	LastSequencePegs []PegEvent `json:"last_sequence_pegs,omitempty"`
+	// HandPegging is what each seat has pegged this hand over the sequences that have ended; the
+	// count and round summaries carry it.
+	HandPegging []int `json:"hand_pegging,omitempty"`
+
+	// Events is the narrative of the game (see Event). Only the previous and current hands are
+	// kept; EventBase is the seq of the last event dropped before them.
+	Events    []Event `json:"events,omitempty"`
+	EventBase int     `json:"event_base,omitempty"`
+
+	// TurnDeadline is when the current turn runs out under Rules.TurnTimeoutSeconds. During
+	// discard one deadline covers everyone still discarding. Nil when turns are untimed or the
+	// game is not waiting on a discard or pegging play.
+	TurnDeadline *time.Time `json:"turn_deadline,omitempty"`
+	// TurnTimeouts counts each seat's consecutive timed-out turns (see RecordTurnTimeout).
+	TurnTimeouts []int `json:"turn_timeouts,omitempty"`
+
+	// PeggingUndo lets the player who made the last pegging play take it back (see
+	// UndoPeggingPlay). Server-side only; views omit it.
+	PeggingUndo *PeggingUndo `json:"pegging_undo,omitempty"`
+}
+
+// PegEvent is one scoring event during pegging.
+type PegEvent struct {
+	Player  int      `json:"player"`
+	Points  int      `json:"points"`
+	Reasons []string `json:"reasons"`
+}
+
+// LastCardReason labels the last-card point in PegEvent reasons.
+const LastCardReason = "last card"
+
+// A jack turned as the starter scores "his heels" for the dealer.
+const (
+	HeelsPoints = 2
+	HeelsReason = "his heels"
+)
+
+// GoResolution describes a pegging sequence that ended because every player passed.
+type GoResolution struct {
+	// Passed lists the seats that said go in the sequence, in seat order.
+	Passed []int `json:"passed"`
+	// LastCardPlayer is the seat awarded the last-card point, or -1 if nobody was.
+	LastCardPlayer int `json:"last_card_player"`
+	Points         int `json:"points"`
+	// NextLeader is the seat to lead the next sequence, or -1 if pegging is over.
+	NextLeader int `json:"next_leader"`
 }
 
 // CountSummary summarizes scoring results for the counting phase of a cribbage round.
@@ -61,6 +118,8 @@ type CountSummary struct {
 	Order []int                  `json:"order"`
 	Hands map[int]ScoreBreakdown `json:"hands,omitempty"` // playerIndex -> breakdown
 	Crib  *ScoreBreakdown        `json:"crib,omitempty"`
+	// Pegging is what each seat pegged this hand (per seat, last card included).
+	Pegging []int `json:"pegging,omitempty"`
 }
 
 // RoundSummary is an append-only record of a completed counting phase.
@@ -71,12 +130,23 @@ type RoundSummary struct {
 	Cut          *common.Card           `json:"cut,omitempty"`
 	Hands        map[int]ScoreBreakdown `json:"hands,omitempty"` // playerIndex -> breakdown
 	Crib         *ScoreBreakdown        `json:"crib,omitempty"`
+	Kept         map[int][]common.Card  `json:"kept,omitempty"` // playerIndex -> kept hand
+	CribCards    []common.Card          `json:"crib_cards,omitempty"`
 	ScoresBefore []int                  `json:"scores_before,omitempty"`
 	ScoresAfter  []int                  `json:"scores_after,omitempty"`
+	Pegging      []int                  `json:"pegging,omitempty"` // per seat, points pegged this hand
+	// ScoredZero flags counted hands that scored nothing (playerIndex -> flag) so clients can
+	// collapse them; CribScoredZero does the same for the crib.
+	ScoredZero     map[int]bool `json:"scored_zero,omitempty"`
+	CribScoredZero bool         `json:"crib_scored_zero,omitempty"`
 }
 
 func NewState(players int) *State {
-	r := DefaultRules(players)
+	return NewStateWithRules(DefaultRules(players))
+}
+
+// NewStateWithRules creates a fresh state for already-validated rules.
+func NewStateWithRules(r Rules) *State {
 	st := &State{
 		Rules:         r,
 		DealerIndex:   0,
@@ -126,18 +196,25 @@ func (s *State) Deal() error {
 	s.ReadyNextHand = nil
 	s.Stage = "discard"
 	s.KeptHands = make([][]common.Card, s.Rules.MaxPlayers)
+	s.Discards = make([][]common.Card, s.Rules.MaxPlayers)
 	s.PeggingPassed = make([]bool, s.Rules.MaxPlayers)
 	s.PeggingSeq = nil
 	s.PeggingTotal = 0
 	s.LastPlayIndex = -1
 	s.DiscardCompleted = make([]bool, s.Rules.MaxPlayers)
+	s.SequencePegs = nil
+	s.LastSequencePegs = nil
+	s.HandPegging = make([]int, s.Rules.MaxPlayers)
 
 	// Next player after dealer starts discarding in UI flows; pegging starts left of dealer.
 	s.CurrentIndex = (s.DealerIndex + 1) % s.Rules.MaxPlayers
+	s.emit(Event{Type: EventDeal, Player: s.DealerIndex})
+	s.RestartTurnClock()
 	return nil
 }
 
-func (s *State) Discard(player int, cards []common.Card) error {
+func (s *State) Discard(player int, cards []common.Card) (err error) {
+	defer s.verifyScores("discard", &err)
 	if s.Stage != "discard" {
 		return models.ErrNotInDiscardStage
 	}
@@ -150,11 +227,30 @@ func (s *State) Discard(player int, cards []common.Card) error {
 	if len(cards) != s.Rules.DiscardCount() {
 		return models.ErrInvalidDiscardCount
 	}
-	// remove cards from hand
+	// Crib contributions: 2 players give two cards each; 3 players one each plus one from the
+	// deck; 4 players one each. Either way the finished crib must hold exactly CribSize cards.
+	remaining := 0
+	for i, done := range s.DiscardCompleted {
+		if !done && i != player {
+			remaining++
+		}
+	}
+	if remaining == 0 {
+		finalCrib := len(s.Crib) + len(cards)
+		if s.Rules.MaxPlayers == 3 {
+			finalCrib++
+		}
+		if finalCrib != s.Rules.CribSize() {
+			return models.ErrInvalidCribSize
+		}
+	}
+	// Match every card to its own slot in the hand before touching anything, so a repeated
+	// card (or one not held) is rejected with the hand intact.
+	taken := make([]bool, len(s.Hands[player]))
 	for _, dc := range cards {
 		found := -1
 		for i, hc := range s.Hands[player] {
-			if hc.Rank == dc.Rank && hc.Suit == dc.Suit {
+			if !taken[i] && hc.Rank == dc.Rank && hc.Suit == dc.Suit {
 				found = i
 				break
 			}
@@ -162,17 +258,26 @@ func (s *State) Discard(player int, cards []common.Card) error {
 		if found < 0 {
 			return models.ErrDiscardCardNotInHand
 		}
-		s.Hands[player] = append(s.Hands[player][:found], s.Hands[player][found+1:]...)
-		s.Crib = append(s.Crib, dc)
+		taken[found] = true
+	}
+	kept := make([]common.Card, 0, len(s.Hands[player])-len(cards))
+	for i, hc := range s.Hands[player] {
+		if !taken[i] {
+			kept = append(kept, hc)
+		}
+	}
+	s.Hands[player] = kept
+	s.Crib = append(s.Crib, cards...)
+	if player < len(s.Discards) {
+		s.Discards[player] = append(s.Discards[player], cards...)
 	}
 
-	neededDiscards := s.Rules.MaxPlayers * s.Rules.DiscardCount()
 	s.DiscardCompleted[player] = true
-	allDone := len(s.Crib) == neededDiscards
+	s.emit(Event{Type: EventDiscard, Player: player, Count: len(cards)})
 
-	if allDone && len(s.Crib) == neededDiscards {
+	if remaining == 0 {
 		// 3-player cribbage: add one random card from deck to the crib to make 4.
-		if s.Rules.MaxPlayers == 3 && len(s.Crib) < s.Rules.CribSize() {
+		if s.Rules.MaxPlayers == 3 {
 			c, err := s.pop()
 			if err != nil {
 				return err
@@ -180,11 +285,19 @@ func (s *State) Discard(player int, cards []common.Card) error {
 			s.Crib = append(s.Crib, c)
 		}
 		// When crib is complete, cut and start pegging.
-		cut, err := s.pop()
+		cut, rest, err := Cut(s.Deck)
 		if err != nil {
 			return err
 		}
+		s.Deck = rest
 		s.Cut = &cut
+		cutEvent := Event{Type: EventCut, Player: s.DealerIndex, Card: &cut}
+		if cut.Rank == common.Jack {
+			s.Scores[s.DealerIndex] += HeelsPoints
+			cutEvent.Points = HeelsPoints
+			cutEvent.Reasons = []string{HeelsReason}
+		}
+		s.emit(cutEvent)
 		s.Stage = "pegging"
 		s.CountSummary = nil
 		s.PeggingTotal = 0
@@ -197,11 +310,18 @@ func (s *State) Discard(player int, cards []common.Card) error {
 			s.KeptHands[i] = append([]common.Card(nil), s.Hands[i]...)
 		}
 		s.CurrentIndex = (s.DealerIndex + 1) % s.Rules.MaxPlayers
+		// Heels can carry the dealer over the line before a card is played.
+		if s.Scores[s.DealerIndex] >= s.Rules.WinningScore() {
+			s.finish(s.DealerIndex)
+		}
+		s.RestartTurnClock()
 	}
 	return nil
 }
 
 func (s *State) PlayPeggingCard(player int, card common.Card) (score int, reasons []string, err error) {
+	defer s.verifyScores("play", &err)
+	defer s.turnMoved(&err)
 	if s.Stage != "pegging" {
 		return 0, nil, models.ErrNotInPeggingStage
 	}
@@ -223,18 +343,34 @@ func (s *State) PlayPeggingCard(player int, card common.Card) (score int, reason
 		return 0, nil, models.ErrWouldExceed31
 	}
 
-	points, newTotal, reasons := PeggingScore(s.PeggingSeq, card, s.PeggingTotal)
+	undo := s.newPeggingUndo(player, card, found)
+	s.PeggingUndo = nil
+	points, newTotal, reasons := PeggingScoreRules(s.PeggingSeq, card, s.PeggingTotal, s.Rules)
 	s.PeggingTotal = newTotal
 	s.PeggingSeq = append(s.PeggingSeq, card)
 	s.Scores[player] += points
+	if points > 0 {
+		s.SequencePegs = append(s.SequencePegs, PegEvent{Player: player, Points: points, Reasons: reasons})
+	}
 	s.LastPlayIndex = player
 	s.PeggingPassed[player] = false
+	played := card
+	s.emit(Event{Type: EventPlay, Player: player, Card: &played, Points: points, Reasons: reasons, Total: newTotal})
 
 	// remove from hand
 	s.Hands[player] = append(s.Hands[player][:found], s.Hands[player][found+1:]...)
 
+	// Pegging out wins on the spot; nothing after this card is played or counted.
+	if s.Scores[player] >= s.Rules.WinningScore() {
+		s.finish(player)
+		return points, reasons, nil
+	}
+
 	// Reset on 31: next player leads.
 	if s.PeggingTotal == 31 {
+		// The 31 replaces the last-card point; the reset clears the last player so
+		// maybeFinishRound can't award it again when this was the final card.
+		s.emit(Event{Type: EventSequenceReset, Player: -1})
 		s.resetPeggingAfterSequenceEnd((player + 1) % s.Rules.MaxPlayers)
 		s.advanceToNextPlayableOrGo()
 	} else {
@@ -245,22 +381,48 @@ func (s *State) PlayPeggingCard(player int, card common.Card) (score int, reason
 	if err := s.maybeFinishRound(); err != nil {
 		return points, reasons, err
 	}
+	if s.Stage == "pegging" && len(s.PeggingSeq) > 0 {
+		s.PeggingUndo = undo
+	}
 
 	return points, reasons, nil
 }
 
+// GoTurnError reports why player may not say "go" right now, or nil if it is their turn.
+// A player who already passed in the current sequence gets ErrTurnAlreadyAdvanced: that is
+// almost always a duplicate or raced request, not an attempt to act out of turn.
+func (s *State) GoTurnError(player int) error {
+	if player == s.CurrentIndex {
+		return nil
+	}
+	if player >= 0 && player < len(s.PeggingPassed) && s.PeggingPassed[player] {
+		return models.ErrTurnAlreadyAdvanced
+	}
+	return models.ErrNotYourTurn
+}
+
 func (s *State) Go(player int) (awarded int, err error) {
+	awarded, _, err = s.GoWithResolution(player)
+	return awarded, err
+}
+
+// GoWithResolution is Go that also reports how the sequence was resolved when the go ended
+// it; res is nil while the sequence continues.
+func (s *State) GoWithResolution(player int) (awarded int, res *GoResolution, err error) {
+	defer s.verifyScores("go", &err)
+	defer s.turnMoved(&err)
 	if s.Stage != "pegging" {
-		return 0, models.ErrNotInPeggingStage
+		return 0, nil, models.ErrNotInPeggingStage
 	}
-	if player != s.CurrentIndex {
-		return 0, models.ErrNotYourTurn
+	if err := s.GoTurnError(player); err != nil {
+		return 0, nil, err
 	}
 	if s.canPlay(player) {
-		return 0, models.ErrHasLegalPlay
+		return 0, nil, models.ErrHasLegalPlay
 	}
 	s.PeggingPassed[player] = true
 	s.CurrentIndex = (s.CurrentIndex + 1) % s.Rules.MaxPlayers
+	s.emit(Event{Type: EventGo, Player: player})
 
 	// If everyone has passed (or nobody can play), end the sequence.
 	allPassed := true
@@ -271,12 +433,20 @@ func (s *State) Go(player int) (awarded int, err error) {
 		}
 	}
 	if allPassed {
-		// Last card point only if we didn't hit 31.
+		// Last card points only if we didn't hit 31.
 		awardLast := s.PeggingTotal != 31
 		lastPlay := s.LastPlayIndex
+		res = &GoResolution{LastCardPlayer: -1}
+		for i, passed := range s.PeggingPassed {
+			if passed {
+				res.Passed = append(res.Passed, i)
+			}
+		}
 		if awardLast && s.LastPlayIndex >= 0 {
-			s.Scores[s.LastPlayIndex] += 1
-			awarded = 1
+			res.LastCardPlayer = s.LastPlayIndex
+			awarded = s.Rules.LastCardValue()
+			s.Scores[s.LastPlayIndex] += awarded
+			s.SequencePegs = append(s.SequencePegs, PegEvent{Player: s.LastPlayIndex, Points: awarded, Reasons: []string{LastCardReason}})
 			// Prevent a second award when the round finishes.
 			s.LastPlayIndex = -1
 		}
@@ -284,19 +454,58 @@ func (s *State) Go(player int) (awarded int, err error) {
 		if lastPlay < 0 {
 			nextLead = (s.DealerIndex + 1) % s.Rules.MaxPlayers
 		}
+		reset := Event{Type: EventSequenceReset, Player: res.LastCardPlayer, Points: awarded}
+		if awarded > 0 {
+			reset.Reasons = []string{LastCardReason}
+		}
+		s.emit(reset)
+		if awarded > 0 && s.Scores[res.LastCardPlayer] >= s.Rules.WinningScore() {
+			res.Points = awarded
+			res.NextLeader = -1
+			s.finish(res.LastCardPlayer)
+			return awarded, res, nil
+		}
 		s.resetPeggingAfterSequenceEnd(nextLead)
 		s.advanceToNextPlayableOrGo()
+		res.Points = awarded
 	} else {
 		s.advanceToNextPlayableOrGo()
 	}
 
 	if err := s.maybeFinishRound(); err != nil {
-		return awarded, err
+		return awarded, res, err
+	}
+	if res != nil {
+		res.NextLeader = -1
+		if s.Stage == "pegging" {
+			res.NextLeader = s.CurrentIndex
+		}
 	}
-	return awarded, nil
+	return awarded, res, nil
 }
 
+// endPeggingSequence closes the scoring log of the sequence that just ended and adds it to
+// HandPegging. Undo never reaches past a sequence end, so these points are final.
+func (s *State) endPeggingSequence() {
+	if len(s.HandPegging) < s.Rules.MaxPlayers {
+		// States saved before HandPegging existed.
+		s.HandPegging = append(s.HandPegging, make([]int, s.Rules.MaxPlayers-len(s.HandPegging))...)
+	}
+	for _, p := range s.SequencePegs {
+		if p.Player >= 0 && p.Player < len(s.HandPegging) {
+			s.HandPegging[p.Player] += p.Points
+		}
+	}
+	s.LastSequencePegs = s.SequencePegs
+	s.SequencePegs = nil
+}
+
+// resetPeggingAfterSequenceEnd starts a new sequence led by nextLead, the seat after whoever
+// played the sequence's last card; callers then skip to the first seat still holding cards.
+// The last player is cleared with the count: their point (or the 31) is already scored.
 func (s *State) resetPeggingAfterSequenceEnd(nextLead int) {
+	s.endPeggingSequence()
+	s.LastPlayIndex = -1
 	s.PeggingTotal = 0
 	s.PeggingSeq = nil
 	for i := range s.PeggingPassed {
@@ -305,6 +514,11 @@ func (s *State) resetPeggingAfterSequenceEnd(nextLead int) {
 	s.CurrentIndex = nextLead
 }
 
+// CanPlay reports whether player holds a card that fits under 31 in the current sequence.
+func (s *State) CanPlay(player int) bool {
+	return s.canPlay(player)
+}
+
 func (s *State) canPlay(player int) bool {
 	if player < 0 || player >= s.Rules.MaxPlayers {
 		return false
@@ -319,7 +533,7 @@ func (s *State) canPlay(player int) bool {
 
 func (s *State) advanceToNextPlayableOrGo() {
 	// Move CurrentIndex forward until we find a player who can play,
-	// or fall back to current (UI can call Go()).
+	// or fall back to a player who must say go (UI can call Go()).
 	for i := 0; i < s.Rules.MaxPlayers; i++ {
 		p := (s.CurrentIndex + i) % s.Rules.MaxPlayers
 		if s.canPlay(p) {
@@ -327,6 +541,16 @@ func (s *State) advanceToNextPlayableOrGo() {
 			return
 		}
 	}
+	// Nobody can play (e.g. the count is 28 and only high cards remain). Never leave an
+	// empty-handed seat on the clock: the go should come from someone still holding cards.
+	// Once they have all said go, Go() ends the sequence and play resumes from zero.
+	for i := 0; i < s.Rules.MaxPlayers; i++ {
+		p := (s.CurrentIndex + i) % s.Rules.MaxPlayers
+		if p < len(s.Hands) && len(s.Hands[p]) > 0 {
+			s.CurrentIndex = p
+			return
+		}
+	}
 }
 
 func (s *State) maybeFinishRound() error {
@@ -342,14 +566,25 @@ func (s *State) maybeFinishRound() error {
 		return nil
 	}
 
-	// Award last card point if the last sequence didn't end on 31.
+	// Award last card points if the last sequence didn't end on 31.
 	if s.PeggingTotal != 31 && s.LastPlayIndex >= 0 {
-		s.Scores[s.LastPlayIndex] += 1
+		last := s.LastPlayIndex
+		s.Scores[last] += s.Rules.LastCardValue()
+		s.SequencePegs = append(s.SequencePegs, PegEvent{Player: last, Points: s.Rules.LastCardValue(), Reasons: []string{LastCardReason}})
+		s.emit(Event{Type: EventSequenceReset, Player: last, Points: s.Rules.LastCardValue(), Reasons: []string{LastCardReason}})
 		s.LastPlayIndex = -1
+		if s.Scores[last] >= s.Rules.WinningScore() {
+			s.endPeggingSequence()
+			s.finish(last)
+			return nil
+		}
+	}
+	if len(s.PeggingSeq) > 0 {
+		s.endPeggingSequence()
 	}
 
 	s.Stage = "counting"
-	s.CountSummary = &CountSummary{Order: []int{}, Hands: map[int]ScoreBreakdown{}}
+	s.CountSummary = &CountSummary{Order: []int{}, Hands: map[int]ScoreBreakdown{}, Pegging: append([]int(nil), s.HandPegging...)}
 	s.ReadyNextHand = make([]bool, s.Rules.MaxPlayers)
 	if s.Cut == nil {
 		// Should not happen, but avoid panic.
@@ -363,7 +598,7 @@ func (s *State) maybeFinishRound() error {
 	// 3) Dealer's crib
 	//
 	// We must check for a winner immediately after each hand/crib is counted so
-	// the first player to reach 121 wins (no "overcount" by later hands).
+	// the first player to reach the target (121 by default) wins (no "overcount" by later hands).
 	scoresBefore := append([]int(nil), s.Scores...)
 	for off := 1; off < s.Rules.MaxPlayers; off++ {
 		i := (s.DealerIndex + off) % s.Rules.MaxPlayers
@@ -371,9 +606,10 @@ func (s *State) maybeFinishRound() error {
 		s.CountSummary.Order = append(s.CountSummary.Order, i)
 		s.CountSummary.Hands[i] = b
 		s.Scores[i] += b.Total
-		if s.Scores[i] >= 121 {
+		s.emitHandCounted(i, s.KeptHands[i], b, false)
+		if s.Scores[i] >= s.Rules.WinningScore() {
 			s.appendRoundSummary(scoresBefore)
-			s.Stage = "finished"
+			s.finish(i)
 			return nil
 		}
 	}
@@ -384,20 +620,22 @@ func (s *State) maybeFinishRound() error {
 		s.CountSummary.Order = append(s.CountSummary.Order, i)
 		s.CountSummary.Hands[i] = b
 		s.Scores[i] += b.Total
-		if s.Scores[i] >= 121 {
+		s.emitHandCounted(i, s.KeptHands[i], b, false)
+		if s.Scores[i] >= s.Rules.WinningScore() {
 			s.appendRoundSummary(scoresBefore)
-			s.Stage = "finished"
+			s.finish(i)
 			return nil
 		}
 	}
 	// Crib (dealer)
 	{
-		crib := ScoreHand(s.Crib, *s.Cut, true)
+		crib := ScoreHandRules(s.Crib, *s.Cut, true, s.Rules)
 		s.CountSummary.Crib = &crib
 		s.Scores[s.DealerIndex] += crib.Total
-		if s.Scores[s.DealerIndex] >= 121 {
+		s.emitHandCounted(s.DealerIndex, s.Crib, crib, true)
+		if s.Scores[s.DealerIndex] >= s.Rules.WinningScore() {
 			s.appendRoundSummary(scoresBefore)
-			s.Stage = "finished"
+			s.finish(s.DealerIndex)
 			return nil
 		}
 	}
@@ -421,17 +659,26 @@ func (s *State) appendRoundSummary(scoresBefore []int) {
 		c := *s.Cut
 		rs.Cut = &c
 	}
+	rs.Kept = map[int][]common.Card{}
+	for i, h := range s.KeptHands {
+		rs.Kept[i] = append([]common.Card(nil), h...)
+	}
+	rs.CribCards = append([]common.Card(nil), s.Crib...)
+	rs.Pegging = append([]int(nil), s.HandPegging...)
 	if s.CountSummary != nil {
 		// Deep copy breakdowns so future mutations can't affect history.
 		if s.CountSummary.Hands != nil {
 			rs.Hands = map[int]ScoreBreakdown{}
+			rs.ScoredZero = map[int]bool{}
 			for k, v := range s.CountSummary.Hands {
 				rs.Hands[k] = v
+				rs.ScoredZero[k] = v.Total == 0
 			}
 		}
 		if s.CountSummary.Crib != nil {
 			c := *s.CountSummary.Crib
 			rs.Crib = &c
+			rs.CribScoredZero = c.Total == 0
 		}
 	}
 	s.History = append(s.History, rs)
@@ -0,0 +1,367 @@
+package cribbage
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"fifteen-thirty-one-go/backend/internal/models"
+)
+
+// peggingState returns a state at the start of pegging: seat 0 deals, seat 1 leads, and hands
+// holds each seat's cards in the form cards accepts (they are also the kept hands).
+func peggingState(t testing.TB, r Rules, cut string, hands ...string) *State {
+	t.Helper()
+	st := NewStateWithRules(r)
+	st.Stage = "pegging"
+	c := cards(t, cut)[0]
+	st.Cut = &c
+	for i, h := range hands {
+		st.Hands[i] = cards(t, h)
+		st.KeptHands[i] = cards(t, h)
+	}
+	st.PeggingPassed = make([]bool, r.MaxPlayers)
+	st.HandPegging = make([]int, r.MaxPlayers)
+	st.CurrentIndex = 1
+	return st
+}
+
+// play plays card for player and returns the points it pegged.
+func play(t testing.TB, st *State, player int, card string) int {
+	t.Helper()
+	pts, _, err := st.PlayPeggingCard(player, cards(t, card)[0])
+	if err != nil {
+		t.Fatalf("seat %d plays %s: %v", player, card, err)
+	}
+	return pts
+}
+
+// sayGo says go for player and returns the last-card points it awarded.
+func sayGo(t testing.TB, st *State, player int) int {
+	t.Helper()
+	pts, err := st.Go(player)
+	if err != nil {
+		t.Fatalf("seat %d says go: %v", player, err)
+	}
+	return pts
+}
+
+func TestDiscardEndsWithFourCardCrib(t *testing.T) {
+	for _, players := range []int{2, 3, 4} {
+		st := NewState(players)
+		if err := st.Deal(); err != nil {
+			t.Fatalf("%d players: deal: %v", players, err)
+		}
+		for p := 0; p < players; p++ {
+			if got, want := len(st.Hands[p]), st.Rules.HandSize(); got != want {
+				t.Fatalf("%d players: seat %d dealt %d cards, want %d", players, p, got, want)
+			}
+			if err := st.Discard(p, st.Hands[p][:st.Rules.DiscardCount()]); err != nil {
+				t.Fatalf("%d players: seat %d discard: %v", players, p, err)
+			}
+		}
+		if len(st.Crib) != 4 {
+			t.Errorf("%d players: crib has %d cards, want 4", players, len(st.Crib))
+		}
+		if st.Stage != "pegging" || st.Cut == nil {
+			t.Errorf("%d players: stage %q cut %v after the last discard, want pegging with a cut", players, st.Stage, st.Cut)
+		}
+		for p := 0; p < players; p++ {
+			if len(st.KeptHands[p]) != 4 {
+				t.Errorf("%d players: seat %d kept %d cards, want 4", players, p, len(st.KeptHands[p]))
+			}
+		}
+	}
+}
+
+func TestDiscardRejectsWrongCount(t *testing.T) {
+	for _, players := range []int{2, 3, 4} {
+		st := NewState(players)
+		if err := st.Deal(); err != nil {
+			t.Fatalf("%d players: deal: %v", players, err)
+		}
+		err := st.Discard(0, st.Hands[0][:st.Rules.DiscardCount()+1])
+		if !errors.Is(err, models.ErrInvalidDiscardCount) {
+			t.Errorf("%d players: discarding %d cards: err = %v, want ErrInvalidDiscardCount", players, st.Rules.DiscardCount()+1, err)
+		}
+		if len(st.Crib) != 0 || st.DiscardCompleted[0] {
+			t.Errorf("%d players: rejected discard changed the state", players)
+		}
+	}
+}
+
+func TestDiscardRejectsRepeatedCard(t *testing.T) {
+	st := NewState(2)
+	if err := st.Deal(); err != nil {
+		t.Fatalf("deal: %v", err)
+	}
+	st.Hands[0] = cards(t, "5H 6D 7C 8S 9H 10D")
+	before := slices.Clone(st.Hands[0])
+	// One 5H held, two thrown: the second has no card left to match.
+	err := st.Discard(0, cards(t, "5H 5H"))
+	if !errors.Is(err, models.ErrDiscardCardNotInHand) {
+		t.Errorf("discarding 5H twice: err = %v, want ErrDiscardCardNotInHand", err)
+	}
+	if !slices.Equal(st.Hands[0], before) {
+		t.Errorf("hand %v after the rejected discard, want %v unchanged", st.Hands[0], before)
+	}
+	if len(st.Crib) != 0 || st.DiscardCompleted[0] {
+		t.Errorf("rejected discard changed the crib (%v) or marked seat 0 done", st.Crib)
+	}
+}
+
+func TestLastCardPoints(t *testing.T) {
+	for _, tt := range []struct {
+		setting int
+		want    int
+	}{
+		{0, 1}, // unset states keep the standard point
+		{1, 1},
+		{2, 2},
+	} {
+		r := DefaultRules(2)
+		r.LastCardPoints = tt.setting
+		if err := r.Validate(); err != nil {
+			t.Fatalf("LastCardPoints %d: %v", tt.setting, err)
+		}
+
+		// Awarded by Go: seat 0 cannot go over 31, so seat 1 takes the last card of the
+		// sequence; seat 0 then leads 5D alone and takes the last card of the hand.
+		st := peggingState(t, r, "2C", "10H 5D", "KS QS")
+		play(t, st, 1, "KS")
+		play(t, st, 0, "10H")
+		play(t, st, 1, "QS")
+		if st.CurrentIndex != 0 {
+			t.Fatalf("LastCardPoints %d: seat %d on the clock at 30, want seat 0 to say go", tt.setting, st.CurrentIndex)
+		}
+		if got := sayGo(t, st, 0); got != tt.want {
+			t.Errorf("LastCardPoints %d: go awarded %d, want %d", tt.setting, got, tt.want)
+		}
+		play(t, st, 0, "5D")
+		if st.Stage != "counting" {
+			t.Fatalf("LastCardPoints %d: stage %q after the last card, want counting", tt.setting, st.Stage)
+		}
+		if got := st.CountSummary.Pegging; got[0] != tt.want || got[1] != tt.want {
+			t.Errorf("LastCardPoints %d: pegged %v, want %d each for the last cards", tt.setting, got, tt.want)
+		}
+
+		// A sequence that ends on 31 scores the 31 and no last card.
+		st = peggingState(t, r, "2C", "10H 6D", "KS 5C")
+		play(t, st, 1, "KS")
+		play(t, st, 0, "10H")
+		play(t, st, 1, "5C")
+		if got := play(t, st, 0, "6D"); got != 2 {
+			t.Errorf("LastCardPoints %d: 31 pegged %d, want 2", tt.setting, got)
+		}
+		if got := st.CountSummary.Pegging; got[0] != 2 || got[1] != 0 {
+			t.Errorf("LastCardPoints %d: pegged %v after a closing 31, want [2 0]", tt.setting, got)
+		}
+	}
+}
+
+func TestLastCardPointsValidation(t *testing.T) {
+	for _, v := range []int{-1, 3} {
+		r := DefaultRules(2)
+		r.LastCardPoints = v
+		if err := r.Validate(); !errors.Is(err, ErrInvalidRules) {
+			t.Errorf("LastCardPoints %d: Validate() = %v, want ErrInvalidRules", v, err)
+		}
+	}
+}
+
+func TestStuckAt28ResetsAndResumes(t *testing.T) {
+	// Both seats still hold cards but nothing fits under 31: the seat on the clock says go, the
+	// last card scores, and the next sequence plays out the stuck cards from zero.
+	st := peggingState(t, DefaultRules(2), "2C", "8H QD 9D", "KS 10C JS")
+	play(t, st, 1, "KS")
+	play(t, st, 0, "8H")
+	play(t, st, 1, "10C")
+	if st.PeggingTotal != 28 || st.CanPlay(0) || st.CanPlay(1) {
+		t.Fatalf("count %d, can play %t/%t, want 28 with nobody able to play", st.PeggingTotal, st.CanPlay(0), st.CanPlay(1))
+	}
+	if st.CurrentIndex != 0 {
+		t.Fatalf("seat %d on the clock, want seat 0 to say go", st.CurrentIndex)
+	}
+	if got := sayGo(t, st, 0); got != 1 {
+		t.Errorf("go awarded %d, want the last-card point for seat 1", got)
+	}
+	if st.PeggingTotal != 0 || len(st.PeggingSeq) != 0 || st.CurrentIndex != 0 {
+		t.Fatalf("after the go: count %d seq %v seat %d, want a fresh sequence led by seat 0", st.PeggingTotal, st.PeggingSeq, st.CurrentIndex)
+	}
+	play(t, st, 0, "QD")
+	play(t, st, 1, "JS")
+	play(t, st, 0, "9D")
+	if st.Stage != "counting" {
+		t.Fatalf("stage %q after every card was played, want counting", st.Stage)
+	}
+	if got := st.CountSummary.Pegging; got[0] != 1 || got[1] != 1 {
+		t.Errorf("pegged %v, want a last-card point each", got)
+	}
+}
+
+func TestStuckAt28SkipsEmptyHandedSeat(t *testing.T) {
+	// Seat 0 is out of cards; the go must come from seat 1, who still holds an unplayable jack.
+	st := peggingState(t, DefaultRules(2), "2C", "8H", "KS 10C JS")
+	play(t, st, 1, "KS")
+	play(t, st, 0, "8H")
+	play(t, st, 1, "10C")
+	if st.CurrentIndex != 1 {
+		t.Fatalf("seat %d on the clock at 28, want seat 1 (seat 0 has no cards)", st.CurrentIndex)
+	}
+	sayGo(t, st, 1)
+	if st.Stage != "pegging" || st.CurrentIndex != 1 || st.PeggingTotal != 0 {
+		t.Fatalf("after the go: stage %q seat %d count %d, want seat 1 to lead a new sequence", st.Stage, st.CurrentIndex, st.PeggingTotal)
+	}
+	play(t, st, 1, "JS")
+	if st.Stage != "counting" {
+		t.Errorf("stage %q after the jack, want counting", st.Stage)
+	}
+}
+
+func TestThirtyOneResetsWithMorePlayers(t *testing.T) {
+	type move struct {
+		seat int
+		card string
+	}
+	tests := []struct {
+		name   string
+		hands  []string
+		moves  []move
+		pegged []int
+	}{
+		{
+			// The hand's final card makes 31: the 31 scores and no last-card point follows.
+			name:   "3 players, final 31",
+			hands:  []string{"6D", "KS 5C", "QS"},
+			moves:  []move{{1, "KS"}, {2, "QS"}, {0, "6D"}, {1, "5C"}},
+			pegged: []int{0, 2, 0},
+		},
+		{
+			// A 31 mid-hand: seat 1 leads the next sequence, which ends with a last card.
+			name:   "4 players, mid-hand 31",
+			hands:  []string{"AD 9D", "KS 2C", "10S 3C", "KH 4C"},
+			moves:  []move{{1, "KS"}, {2, "10S"}, {3, "KH"}, {0, "AD"}, {1, "2C"}, {2, "3C"}, {3, "4C"}, {0, "9D"}},
+			pegged: []int{3, 0, 0, 3},
+		},
+	}
+	for _, tt := range tests {
+		st := peggingState(t, DefaultRules(len(tt.hands)), "2H", tt.hands...)
+		for _, m := range tt.moves {
+			play(t, st, m.seat, m.card)
+			if st.Stage == "pegging" && len(st.PeggingSeq) == 0 {
+				// The card made 31: the count starts over with the next seat and nobody holds
+				// the last card.
+				if st.LastPlayIndex != -1 || st.PeggingTotal != 0 {
+					t.Errorf("%s: after 31 last play %d count %d, want -1 and 0", tt.name, st.LastPlayIndex, st.PeggingTotal)
+				}
+				if want := (m.seat + 1) % len(tt.hands); st.CurrentIndex != want {
+					t.Errorf("%s: seat %d leads after the 31, want seat %d", tt.name, st.CurrentIndex, want)
+				}
+			}
+		}
+		if st.Stage != "counting" {
+			t.Fatalf("%s: stage %q after every card was played, want counting", tt.name, st.Stage)
+		}
+		for seat, want := range tt.pegged {
+			if got := st.CountSummary.Pegging[seat]; got != want {
+				t.Errorf("%s: seat %d pegged %d, want %d (all %v)", tt.name, seat, got, want, st.CountSummary.Pegging)
+			}
+		}
+	}
+}
+
+func TestSequenceResetWithMorePlayers(t *testing.T) {
+	type step struct {
+		seat int
+		card string // "" says go
+		lead int    // when the step ends the sequence: the seat that leads the next one; else -1
+	}
+	tests := []struct {
+		name   string
+		hands  []string
+		steps  []step
+		pegged []int
+	}{
+		{
+			// At 25 neither seat 1 nor seat 2 can follow, so seat 0 plays on alone; once nobody
+			// can play, seat 1's go gives seat 0 the last card and seat 1 leads from zero.
+			name:  "3 players, everyone passes but one",
+			hands: []string{"5D AD", "KS 9C", "QS 8C"},
+			steps: []step{
+				{1, "KS", -1}, {2, "QS", -1}, {0, "5D", -1}, {0, "AD", -1}, {1, "", 1},
+				{1, "9C", -1}, {2, "8C", -1},
+			},
+			pegged: []int{1, 0, 1},
+		},
+		{
+			name:  "4 players, everyone passes but one",
+			hands: []string{"5D AD", "KS 9C", "QS 8C", "3S 7C"},
+			steps: []step{
+				{1, "KS", -1}, {2, "QS", -1}, {3, "3S", -1}, {0, "5D", -1}, {0, "AD", -1}, {1, "", 1},
+				{1, "9C", -1}, {2, "8C", -1}, {3, "7C", -1},
+			},
+			pegged: []int{1, 0, 0, 4}, // seat 3's 9-8-7 run and the last card
+		},
+		{
+			// Seat 0 makes 31; seat 1, next in line, has no cards left, so seat 2 leads.
+			name:  "4 players, 31 mid-hand",
+			hands: []string{"AD 9D", "KS", "10S 3C", "KH 4C"},
+			steps: []step{
+				{1, "KS", -1}, {2, "10S", -1}, {3, "KH", -1}, {0, "AD", 2},
+				{2, "3C", -1}, {3, "4C", -1}, {0, "9D", -1},
+			},
+			pegged: []int{3, 0, 0, 0},
+		},
+		{
+			// Seat 1's ace makes 31 and seat 2, next in line, leads from zero.
+			name:  "3 players, 31 mid-hand",
+			hands: []string{"JD 2D", "KS AC", "10H 4C"},
+			steps: []step{
+				{1, "KS", -1}, {2, "10H", -1}, {0, "JD", -1}, {1, "AC", 2},
+				{2, "4C", -1}, {0, "2D", -1},
+			},
+			pegged: []int{1, 2, 0},
+		},
+		{
+			// The hand runs out at 14, below 31: the last card scores and nobody leads again.
+			name:   "3 players, hand ends below 31",
+			hands:  []string{"2D", "3C", "9S"},
+			steps:  []step{{1, "3C", -1}, {2, "9S", -1}, {0, "2D", -1}},
+			pegged: []int{1, 0, 0},
+		},
+		{
+			name:   "4 players, hand ends below 31",
+			hands:  []string{"2D", "3C", "9S", "4H"},
+			steps:  []step{{1, "3C", -1}, {2, "9S", -1}, {3, "4H", -1}, {0, "2D", -1}},
+			pegged: []int{1, 0, 0, 0},
+		},
+	}
+	for _, tt := range tests {
+		st := peggingState(t, DefaultRules(len(tt.hands)), "2H", tt.hands...)
+		for i, s := range tt.steps {
+			if s.card == "" {
+				sayGo(t, st, s.seat)
+			} else {
+				play(t, st, s.seat, s.card)
+			}
+			if s.lead < 0 {
+				continue
+			}
+			if st.PeggingTotal != 0 || len(st.PeggingSeq) != 0 || st.LastPlayIndex != -1 || slices.Contains(st.PeggingPassed, true) {
+				t.Errorf("%s: after step %d count %d seq %v last %d passed %v, want a fresh sequence",
+					tt.name, i+1, st.PeggingTotal, st.PeggingSeq, st.LastPlayIndex, st.PeggingPassed)
+			}
+			if st.CurrentIndex != s.lead {
+				t.Errorf("%s: after step %d seat %d leads, want seat %d", tt.name, i+1, st.CurrentIndex, s.lead)
+			}
+		}
+		if st.Stage != "counting" {
+			t.Fatalf("%s: stage %q after every card was played, want counting", tt.name, st.Stage)
+		}
+		for seat, want := range tt.pegged {
+			if got := st.CountSummary.Pegging[seat]; got != want {
+				t.Errorf("%s: seat %d pegged %d, want %d (all %v)", tt.name, seat, got, want, st.CountSummary.Pegging)
+			}
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package cribbage
+
+import (
+	"errors"
+	"fmt"
+
+	"fifteen-thirty-one-go/backend/internal/game/common"
+)
+
+// ErrDeckExhausted is returned when a cut needs a card and the deck is empty.
+var ErrDeckExhausted = errors.New("deck exhausted")
+
+// Cut tie policies for cutting for deal.
+const (
+	// CutTieRecut has the tied low cutters cut again until one card is lowest (standard).
+	CutTieRecut = "recut"
+	// CutTieSuit breaks ties by suit, clubs lowest, then diamonds, hearts, spades.
+	CutTieSuit = "suit"
+)
+
+// Cut draws the top card of an already-shuffled deck and returns it with the remaining deck.
+// Both the starter cut and cutting for deal go through here.
+func Cut(deck []common.Card) (common.Card, []common.Card, error) {
+	if len(deck) == 0 {
+		return common.Card{}, deck, ErrDeckExhausted
+	}
+	return deck[0], deck[1:], nil
+}
+
+var suitOrder = map[common.Suit]int{common.Clubs: 0, common.Diamonds: 1, common.Hearts: 2, common.Spades: 3}
+
+// CutForDeal has each of the given seats cut from deck; the lowest card (aces low) deals.
+// Ties for lowest are settled by rules.CutTieRule(). It returns the dealer seat and the
+// remaining deck, or ErrDeckExhausted if re-cuts run the deck out.
+func CutForDeal(deck []common.Card, seats []int, rules Rules) (int, []common.Card, error) {
+	if len(seats) == 0 {
+		return 0, deck, fmt.Errorf("cut for deal: no seats")
+	}
+	contenders := append([]int(nil), seats...)
+	for {
+		cards := make([]common.Card, len(contenders))
+		for i := range contenders {
+			var err error
+			cards[i], deck, err = Cut(deck)
+			if err != nil {
+				return 0, deck, err
+			}
+		}
+		low := cards[0].Rank
+		for _, c := range cards[1:] {
+			low = min(low, c.Rank)
+		}
+		var tied []int
+		best := -1
+		for i, c := range cards {
+			if c.Rank != low {
+				continue
+			}
+			tied = append(tied, contenders[i])
+			if best < 0 || suitOrder[c.Suit] < suitOrder[cards[best].Suit] {
+				best = i
+			}
+		}
+		if len(tied) == 1 {
+			return tied[0], deck, nil
+		}
+		if rules.CutTieRule() == CutTieSuit {
+			return contenders[best], deck, nil
+		}
+		contenders = tied
+	}
+}
+
+// CutForFirstDeal has every seat cut from a freshly shuffled deck and makes the winner the
+// dealer of the next Deal. A cut that runs the deck out on re-cuts starts over with a new deck.
+func (s *State) CutForFirstDeal() error {
+	seats := make([]int, s.Rules.MaxPlayers)
+	for i := range seats {
+		seats[i] = i
+	}
+	for {
+		deck := common.NewStandardDeck()
+		if err := common.Shuffle(deck); err != nil {
+			return err
+		}
+		dealer, _, err := CutForDeal(deck, seats, s.Rules)
+		if errors.Is(err, ErrDeckExhausted) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		s.DealerIndex = dealer
+		return nil
+	}
+}
@@ -0,0 +1,68 @@
+package cribbage
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCutForDeal(t *testing.T) {
+	tests := []struct {
+		name   string
+		deck   string
+		seats  []int
+		policy string
+		dealer int
+		left   int
+	}{
+		{"lowest deals", "9C 2D KH", []int{0, 1}, "", 1, 1},
+		{"aces low", "AS 2C", []int{0, 1}, "", 0, 0},
+		{"tie re-cuts", "5H 5S 9C 2D", []int{0, 1}, CutTieRecut, 1, 0},
+		{"only tied seats re-cut", "AH 3S AD KC 2D", []int{0, 1, 2}, "", 2, 0},
+		{"tie by suit", "5H 5S 9C", []int{0, 1}, CutTieSuit, 0, 1},
+		{"suit order clubs lowest", "7S 7C 7D", []int{0, 1, 2}, CutTieSuit, 1, 0},
+	}
+	for _, tt := range tests {
+		r := DefaultRules(len(tt.seats))
+		r.CutTiePolicy = tt.policy
+		dealer, rest, err := CutForDeal(cards(t, tt.deck), tt.seats, r)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if dealer != tt.dealer || len(rest) != tt.left {
+			t.Errorf("%s: dealer %d with %d cards left, want %d with %d", tt.name, dealer, len(rest), tt.dealer, tt.left)
+		}
+	}
+}
+
+func TestCutForDealDeckExhausted(t *testing.T) {
+	for _, deck := range []string{"", "5H", "5H 5S 7C"} {
+		if _, _, err := CutForDeal(cards(t, deck), []int{0, 1}, DefaultRules(2)); !errors.Is(err, ErrDeckExhausted) {
+			t.Errorf("deck %q: err = %v, want ErrDeckExhausted", deck, err)
+		}
+	}
+	if _, _, err := CutForDeal(cards(t, "5H"), nil, DefaultRules(2)); err == nil {
+		t.Error("no seats: want an error")
+	}
+}
+
+func TestCutForFirstDealPicksEverySeat(t *testing.T) {
+	for _, players := range []int{2, 3, 4} {
+		seen := make([]bool, players)
+		for i := 0; i < 500; i++ {
+			st := NewState(players)
+			if err := st.CutForFirstDeal(); err != nil {
+				t.Fatalf("%d players: %v", players, err)
+			}
+			if st.DealerIndex < 0 || st.DealerIndex >= players {
+				t.Fatalf("%d players: dealer %d out of range", players, st.DealerIndex)
+			}
+			seen[st.DealerIndex] = true
+		}
+		for seat, ok := range seen {
+			if !ok {
+				t.Errorf("%d players: seat %d never won the cut in 500 tries", players, seat)
+			}
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package cribbage
+
+// maxPeggingPerCard bounds what one pegging play can score: fifteen or thirty-one (2) plus
+// the larger of four of a kind (12) and a seven-card run (7). Pairs and runs can't both score
+// on the same play, since a run window always includes the previous card.
+const maxPeggingPerCard = 2 + 12
+
+// Outlook is derived, read-only endgame data for one seat.
+type Outlook struct {
+	Position int `json:"position"`
+	// Needed is how many points the seat still needs to reach the target score.
+	Needed int `json:"needed"`
+	// MaxThisHand is a conservative upper bound on what the seat can still score this hand.
+	MaxThisHand int `json:"max_this_hand"`
+	// CanWinThisHand reports whether MaxThisHand covers Needed.
+	CanWinThisHand bool `json:"can_win_this_hand"`
+}
+
+// Outlook returns per-seat endgame figures. The bound deliberately ignores hidden cards (every
+// uncounted hand or crib is assumed to be a 29), so it is identical for every viewer and leaks
+// nothing about anyone's hand.
+func (s *State) Outlook() []Outlook {
+	n := s.Rules.MaxPlayers
+	out := make([]Outlook, 0, n)
+	target := s.Rules.WinningScore()
+	for i := 0; i < n && i < len(s.Scores); i++ {
+		o := Outlook{Position: i, Needed: max(target-s.Scores[i], 0)}
+		o.MaxThisHand = s.maxRemainingThisHand(i)
+		o.CanWinThisHand = o.Needed > 0 && o.MaxThisHand >= o.Needed
+		out = append(out, o)
+	}
+	return out
+}
+
+func (s *State) maxRemainingThisHand(player int) int {
+	var cardsToPeg int
+	switch s.Stage {
+	case "dealing", "discard":
+		cardsToPeg = s.Rules.HandSize() - s.Rules.DiscardCount()
+	case "pegging":
+		if player < len(s.Hands) {
+			cardsToPeg = len(s.Hands[player])
+		}
+	default:
+		// Counting applies all hand and crib scores at once; nothing is left this hand.
+		return 0
+	}
+	total := cardsToPeg*(maxPeggingPerCard+s.Rules.LastCardValue()) + MaxHandScore
+	if player == s.DealerIndex {
+		total += MaxHandScore // crib
+	}
+	return total
+}
@@ -0,0 +1,80 @@
+package cribbage
+
+import (
+	"sort"
+
+	"fifteen-thirty-one-go/backend/internal/game/common"
+)
+
+// DiscardOption is one way to split a dealt hand, scored over every possible cut.
+type DiscardOption struct {
+	Keep    []common.Card
+	Discard []common.Card
+	EV      float64 // mean kept-hand score over the possible cuts
+	Best    int     // highest kept-hand score over the possible cuts
+	Worst   int     // lowest kept-hand score over the possible cuts
+}
+
+// DiscardOptions enumerates every way to discard discardCount cards from hand and scores the
+// kept hand against each card not in hand as the cut. Options are ordered by EV, best first;
+// ties keep enumeration order. This is the expensive part of the hard bot: a six-card deal is
+// 15 splits x 46 cuts.
+func DiscardOptions(hand []common.Card, discardCount int) []DiscardOption {
+	if discardCount <= 0 || discardCount >= len(hand) {
+		return nil
+	}
+	inHand := map[common.Card]bool{}
+	for _, c := range hand {
+		inHand[c] = true
+	}
+	var cuts []common.Card
+	for _, c := range common.NewStandardDeck() {
+		if !inHand[c] {
+			cuts = append(cuts, c)
+		}
+	}
+
+	var out []DiscardOption
+	forEachCombination(len(hand), discardCount, func(idx []int) {
+		opt := DiscardOption{Worst: MaxHandScore}
+		skip := map[int]bool{}
+		for _, i := range idx {
+			skip[i] = true
+			opt.Discard = append(opt.Discard, hand[i])
+		}
+		for i, c := range hand {
+			if !skip[i] {
+				opt.Keep = append(opt.Keep, c)
+			}
+		}
+		total := 0
+		for _, cut := range cuts {
+			pts := ScoreHand(opt.Keep, cut, false).Total
+			total += pts
+			opt.Best = max(opt.Best, pts)
+			opt.Worst = min(opt.Worst, pts)
+		}
+		opt.EV = float64(total) / float64(len(cuts))
+		out = append(out, opt)
+	})
+	sort.SliceStable(out, func(i, j int) bool { return out[i].EV > out[j].EV })
+	return out
+}
+
+// forEachCombination calls fn with each k-subset of [0, n) in lexicographic order. The slice is
+// reused between calls.
+func forEachCombination(n, k int, fn func([]int)) {
+	idx := make([]int, k)
+	var rec func(start, depth int)
+	rec = func(start, depth int) {
+		if depth == k {
+			fn(idx)
+			return
+		}
+		for i := start; i <= n-(k-depth); i++ {
+			idx[depth] = i
+			rec(i+1, depth+1)
+		}
+	}
+	rec(0, 0)
+}
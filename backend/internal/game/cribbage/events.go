@@ -0,0 +1,112 @@
+package cribbage
+
+import "fifteen-thirty-one-go/backend/internal/game/common"
+
+// Game event types, in the order they typically occur within a hand.
+const (
+	EventDeal          = "deal"
+	EventDiscard       = "discard"
+	EventCut           = "cut"
+	EventPlay          = "play"
+	EventGo            = "go"
+	EventUndo          = "undo"
+	EventSequenceReset = "sequence_reset"
+	EventHandCounted   = "hand_counted"
+	EventMuggins       = "muggins"
+	EventGameOver      = "game_over"
+)
+
+// Event is one entry of the game's chronological narrative. Events carry only information that
+// is public when they happen: a discard records how many cards were thrown, not which; counted
+// hands are revealed at counting time. Seq numbers start at 1 and are assigned by the engine,
+// so replaying the same moves yields the same log.
+type Event struct {
+	Seq     int           `json:"seq"`
+	Type    string        `json:"type"`
+	Player  int           `json:"player"` // seat; -1 when the event isn't tied to one
+	Card    *common.Card  `json:"card,omitempty"`
+	Cards   []common.Card `json:"cards,omitempty"` // hand_counted: the counted hand or crib
+	Count   int           `json:"count,omitempty"` // discard: cards thrown to the crib
+	Points  int           `json:"points,omitempty"`
+	Reasons []string      `json:"reasons,omitempty"`
+	Total   int           `json:"total,omitempty"` // play: pegging total after the card; undo: the restored total
+	Crib    bool          `json:"crib,omitempty"`  // hand_counted: the dealer's crib
+	From    *int          `json:"from,omitempty"`  // muggins: the seat whose missed points moved
+}
+
+func (s *State) emit(e Event) {
+	if e.Type == EventDeal {
+		// A new hand: drop everything before the previous hand's deal so the log stays bounded.
+		for i := len(s.Events) - 1; i >= 0; i-- {
+			if s.Events[i].Type == EventDeal {
+				s.EventBase += i
+				s.Events = append([]Event(nil), s.Events[i:]...)
+				break
+			}
+		}
+	}
+	e.Seq = s.LastEventSeq() + 1
+	s.Events = append(s.Events, e)
+}
+
+// LastEventSeq returns the seq of the newest event, or 0 before the first.
+func (s *State) LastEventSeq() int {
+	return s.EventBase + len(s.Events)
+}
+
+// EventsSince returns the retained events after seq (0 for all). Events older than the previous
+// hand are no longer kept; a caller asking from before them gets what is left and can tell from
+// the first seq that it missed some.
+func (s *State) EventsSince(seq int) []Event {
+	i := seq - s.EventBase
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(s.Events) {
+		return nil
+	}
+	return append([]Event(nil), s.Events[i:]...)
+}
+
+// HandEvents returns the events of the current hand, from its deal onward.
+func (s *State) HandEvents() []Event {
+	for i := len(s.Events) - 1; i >= 0; i-- {
+		if s.Events[i].Type == EventDeal {
+			return append([]Event(nil), s.Events[i:]...)
+		}
+	}
+	return append([]Event(nil), s.Events...)
+}
+
+// OpenSequenceSeq returns the seq after which plays belong to the pegging sequence still in
+// progress, whose scoring players with the sequence_end reveal have not seen yet. ok is false
+// when no sequence is open.
+func (s *State) OpenSequenceSeq() (seq int, ok bool) {
+	if s.Stage != "pegging" {
+		return 0, false
+	}
+	for i := len(s.Events) - 1; i >= 0; i-- {
+		switch s.Events[i].Type {
+		case EventCut, EventSequenceReset:
+			return s.Events[i].Seq, true
+		}
+	}
+	return s.EventBase, true
+}
+
+func (s *State) emitHandCounted(player int, cards []common.Card, b ScoreBreakdown, crib bool) {
+	reasons := make([]string, 0, len(b.Reasons))
+	for _, r := range []string{"fifteens", "pairs", "runs", "flush", "nobs"} {
+		if b.Reasons[r] > 0 {
+			reasons = append(reasons, r)
+		}
+	}
+	s.emit(Event{Type: EventHandCounted, Player: player, Cards: append([]common.Card(nil), cards...), Points: b.Total, Reasons: reasons, Crib: crib})
+}
+
+// finish ends the game with winner reaching the target score.
+func (s *State) finish(winner int) {
+	s.Stage = "finished"
+	s.TurnDeadline = nil
+	s.emit(Event{Type: EventGameOver, Player: winner, Points: s.Scores[winner]})
+}
@@ -0,0 +1,57 @@
+package cribbage
+
+import "testing"
+
+func TestEventLogKeepsPreviousAndCurrentHand(t *testing.T) {
+	st, _, err := SimulateGame(DefaultRules(2), []BotDifficulty{BotEasy, BotEasy}, 0)
+	if err != nil {
+		t.Fatalf("simulate: %v", err)
+	}
+	deals := 0
+	for i, e := range st.Events {
+		if e.Seq != st.EventBase+i+1 {
+			t.Fatalf("event %d has seq %d, want %d", i, e.Seq, st.EventBase+i+1)
+		}
+		if e.Type == EventDeal {
+			deals++
+		}
+	}
+	if deals != 2 || st.Events[0].Type != EventDeal {
+		t.Errorf("kept %d deals starting with %q, want the previous and current hands", deals, st.Events[0].Type)
+	}
+	if st.EventBase == 0 {
+		t.Errorf("a full game dropped no events")
+	}
+	if got := st.EventsSince(0); len(got) != len(st.Events) {
+		t.Errorf("EventsSince(0) = %d events, want the %d kept", len(got), len(st.Events))
+	}
+	last := st.LastEventSeq()
+	if got := st.EventsSince(last - 1); len(got) != 1 || got[0].Seq != last {
+		t.Errorf("EventsSince(%d) = %v, want only seq %d", last-1, got, last)
+	}
+}
+
+func TestOpenSequenceSeq(t *testing.T) {
+	st := peggingState(t, DefaultRules(2), "2C", "10H 6D 5S", "KS 5C 4D")
+	st.emit(Event{Type: EventCut, Player: 0})
+	cut := st.LastEventSeq()
+	play(t, st, 1, "KS")
+	play(t, st, 0, "10H")
+	if seq, ok := st.OpenSequenceSeq(); !ok || seq != cut {
+		t.Fatalf("OpenSequenceSeq() = %d, %t mid-sequence, want %d, true", seq, ok, cut)
+	}
+	play(t, st, 1, "5C")
+	play(t, st, 0, "6D") // 31 ends the sequence
+	reset := st.LastEventSeq()
+	if seq, ok := st.OpenSequenceSeq(); !ok || seq != reset || st.Events[len(st.Events)-1].Type != EventSequenceReset {
+		t.Fatalf("OpenSequenceSeq() = %d, %t after the 31, want the reset at %d", seq, ok, reset)
+	}
+	play(t, st, 1, "4D")
+	play(t, st, 0, "5S")
+	if st.Stage != "counting" {
+		t.Fatalf("stage %q after every card was played, want counting", st.Stage)
+	}
+	if _, ok := st.OpenSequenceSeq(); ok {
+		t.Errorf("OpenSequenceSeq() reports an open sequence during counting")
+	}
+}
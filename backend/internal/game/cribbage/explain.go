@@ -0,0 +1,66 @@
+package cribbage
+
+import (
+	"sort"
+
+	"fifteen-thirty-one-go/backend/internal/game/common"
+)
+
+// ScoreItem is one scoring combination in a counted hand, e.g. a fifteen made by 7H and 8C.
+type ScoreItem struct {
+	Kind   string        `json:"kind"` // fifteen|pair|run|flush|nobs, matching ScoreBreakdown's fields
+	Cards  []common.Card `json:"cards"`
+	Points int           `json:"points"`
+}
+
+// ExplainHand itemizes the combinations ScoreHand counts, in the order players usually count
+// them: fifteens, pairs, runs, flush, nobs. The items' points sum to ScoreHandRules' total.
+func ExplainHand(hand []common.Card, cut common.Card, isCrib bool, r Rules) []ScoreItem {
+	all := make([]common.Card, 0, len(hand)+1)
+	all = append(all, hand...)
+	all = append(all, cut)
+
+	var fifteens, pairs, runs []ScoreItem
+	bestRun := 0
+	for mask := 1; mask < 1<<len(all); mask++ {
+		var cards []common.Card
+		sum := 0
+		for i, c := range all {
+			if mask&(1<<i) != 0 {
+				cards = append(cards, c)
+				sum += c.Value15()
+			}
+		}
+		if sum == 15 {
+			fifteens = append(fifteens, ScoreItem{Kind: "fifteen", Cards: cards, Points: 2})
+		}
+		if len(cards) == 2 && cards[0].Rank == cards[1].Rank {
+			pairs = append(pairs, ScoreItem{Kind: "pair", Cards: cards, Points: 2})
+		}
+		if len(cards) >= 3 && len(cards) >= bestRun && isRun(cards) {
+			if len(cards) > bestRun {
+				bestRun = len(cards)
+				runs = runs[:0]
+			}
+			runs = append(runs, ScoreItem{Kind: "run", Cards: cards, Points: len(cards)})
+		}
+	}
+	// Subsets come out in bitmask order; list each kind smallest first for readability.
+	sort.SliceStable(fifteens, func(i, j int) bool { return len(fifteens[i].Cards) < len(fifteens[j].Cards) })
+
+	items := append(append(fifteens, pairs...), runs...)
+	if pts := scoreFlush(hand, cut, isCrib && r.CribFlushRequiresFive()); pts > 0 {
+		cards := append([]common.Card(nil), hand...)
+		if pts == len(hand)+1 {
+			cards = append(cards, cut)
+		}
+		items = append(items, ScoreItem{Kind: "flush", Cards: cards, Points: pts})
+	}
+	for _, c := range hand {
+		if c.Rank == common.Jack && c.Suit == cut.Suit {
+			items = append(items, ScoreItem{Kind: "nobs", Cards: []common.Card{c}, Points: 1})
+			break
+		}
+	}
+	return items
+}
@@ -0,0 +1,73 @@
+package cribbage
+
+import (
+	"fmt"
+	"log"
+)
+
+// ScoreInvariantError reports scores no valid game can reach: a negative score, or a score at
+// or past the target while the game is still being played (the winner was never declared).
+type ScoreInvariantError struct {
+	Player int
+	Score  int
+	Target int
+	Stage  string
+}
+
+func (e *ScoreInvariantError) Error() string {
+	if e.Score < 0 {
+		return fmt.Sprintf("score invariant: seat %d has negative score %d (stage %s)", e.Player, e.Score, e.Stage)
+	}
+	return fmt.Sprintf("score invariant: seat %d has %d of %d but the game is still in %s", e.Player, e.Score, e.Target, e.Stage)
+}
+
+// CheckScores returns the first score invariant the state violates, or nil.
+func (s *State) CheckScores() error {
+	target := s.Rules.WinningScore()
+	for i, sc := range s.Scores {
+		if sc < 0 || (sc >= target && s.Stage != "finished") {
+			return &ScoreInvariantError{Player: i, Score: sc, Target: target, Stage: s.Stage}
+		}
+	}
+	return nil
+}
+
+// verifyScores runs CheckScores when op succeeded, logging a violation and returning it through
+// err so the caller discards the move instead of persisting a corrupt board.
+func (s *State) verifyScores(op string, err *error) {
+	if *err != nil {
+		return
+	}
+	if v := s.CheckScores(); v != nil {
+		log.Printf("cribbage: %s left an invalid board: %v scores=%v", op, v, s.Scores)
+		*err = v
+	}
+}
+
+// RepairScores fixes a state restored with broken scores: negative scores are raised to zero
+// and a game left running with a score at or past the target is finished for the highest
+// scorer (the lowest seat on ties). It returns the first violation found, or nil when the
+// state was already valid.
+func (s *State) RepairScores() error {
+	found := s.CheckScores()
+	if found == nil {
+		return nil
+	}
+	for i := range s.Scores {
+		if s.Scores[i] < 0 {
+			s.Scores[i] = 0
+		}
+	}
+	if s.Stage != "finished" {
+		winner := -1
+		for i, sc := range s.Scores {
+			if sc >= s.Rules.WinningScore() && (winner < 0 || sc > s.Scores[winner]) {
+				winner = i
+			}
+		}
+		if winner >= 0 {
+			s.finish(winner)
+		}
+	}
+	return found
+}
@@ -0,0 +1,91 @@
+package cribbage
+
+import (
+	"errors"
+	"testing"
+
+	"fifteen-thirty-one-go/backend/internal/models"
+)
+
+func TestScoreInvariantHoldsThroughFullGames(t *testing.T) {
+	for _, players := range []int{2, 3, 4} {
+		for _, target := range []int{61, 121} {
+			r := DefaultRules(players)
+			r.TargetScore = target
+			bots := make([]BotDifficulty, players)
+			for i := range bots {
+				bots[i] = BotMedium
+			}
+			for game := 0; game < 20; game++ {
+				// Every move checks the board; SimulateGame fails on the first violation.
+				st, winner, err := SimulateGame(r, bots, game%players)
+				if err != nil {
+					t.Fatalf("%d players to %d: %v", players, target, err)
+				}
+				if err := st.CheckScores(); err != nil {
+					t.Fatalf("%d players to %d: final board: %v", players, target, err)
+				}
+				for i, sc := range st.Scores {
+					if i == winner && sc < target {
+						t.Errorf("%d players to %d: winner seat %d finished on %d", players, target, i, sc)
+					}
+					if i != winner && sc >= target {
+						t.Errorf("%d players to %d: seat %d reached %d but seat %d won", players, target, i, sc, winner)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestPeggingOutEndsTheGame(t *testing.T) {
+	st := peggingState(t, DefaultRules(2), "2C", "5D 9C", "10S 5H")
+	st.Scores = []int{100, 119}
+	play(t, st, 1, "10S")
+	play(t, st, 0, "5D") // fifteen: seat 0 to 102
+	if got := play(t, st, 1, "5H"); got != 2 {
+		t.Fatalf("pair pegged %d, want 2", got)
+	}
+	if st.Stage != "finished" {
+		t.Fatalf("stage %q after seat 1 pegged to %d, want finished", st.Stage, st.Scores[1])
+	}
+	if e := st.Events[len(st.Events)-1]; e.Type != EventGameOver || e.Player != 1 || e.Points != 121 {
+		t.Errorf("last event %+v, want game_over for seat 1 on 121", e)
+	}
+	// Nothing after the winning card is played or counted.
+	if st.Scores[0] != 102 || st.CountSummary != nil {
+		t.Errorf("scores %v count %v after pegging out, want [102 121] and no count", st.Scores, st.CountSummary)
+	}
+	if _, _, err := st.PlayPeggingCard(0, cards(t, "9C")[0]); !errors.Is(err, models.ErrNotInPeggingStage) {
+		t.Errorf("play after the game ended: err = %v, want ErrNotInPeggingStage", err)
+	}
+	if err := st.CheckScores(); err != nil {
+		t.Errorf("board after pegging out: %v", err)
+	}
+}
+
+func TestPeggingOutOnTheLastCardPoint(t *testing.T) {
+	st := peggingState(t, DefaultRules(2), "2C", "9C", "10S")
+	st.Scores = []int{120, 100}
+	play(t, st, 1, "10S")
+	play(t, st, 0, "9C") // 19 with every card played: seat 0 takes the last card
+	if st.Stage != "finished" || st.Scores[0] != 121 || st.CountSummary != nil {
+		t.Errorf("stage %q scores %v, want seat 0 to peg out on the last card before any count", st.Stage, st.Scores)
+	}
+}
+
+func TestCorruptBoardRejectsMoves(t *testing.T) {
+	st := peggingState(t, DefaultRules(2), "2C", "9C", "10S 4D")
+	st.Scores = []int{125, 0} // past the target with the game still running
+	_, _, err := st.PlayPeggingCard(1, cards(t, "10S")[0])
+	var inv *ScoreInvariantError
+	if !errors.As(err, &inv) || inv.Player != 0 {
+		t.Fatalf("play on a corrupt board: err = %v, want a score invariant error for seat 0", err)
+	}
+	if err := st.RepairScores(); err == nil || st.Stage != "finished" {
+		t.Errorf("RepairScores() = %v with stage %q, want the violation reported and the game finished", err, st.Stage)
+	}
+	if err := st.CheckScores(); err != nil {
+		t.Errorf("board after repair: %v", err)
+	}
+}
@@ -0,0 +1,31 @@
+package cribbage
+
+import "fifteen-thirty-one-go/backend/internal/models"
+
+// Muggins moves points that claimant missed when counting to caller. Counting already credited
+// the claimant with the verified total, so the missed points are taken back from them, though
+// never below zero; moved is what actually changed hands. Caller wins if this carries them to
+// the target. Only valid during the counting stage of a game played with Rules.Muggins.
+func (s *State) Muggins(caller, claimant, points int) (moved int, err error) {
+	defer s.verifyScores("muggins", &err)
+	if !s.Rules.Muggins {
+		return 0, models.ErrMugginsDisabled
+	}
+	if s.Stage != "counting" {
+		return 0, models.ErrNotInCountingStage
+	}
+	if caller < 0 || caller >= s.Rules.MaxPlayers || claimant < 0 || claimant >= s.Rules.MaxPlayers || caller == claimant || points <= 0 {
+		return 0, models.ErrInvalidPlayer
+	}
+	if points > s.Scores[claimant] {
+		points = s.Scores[claimant]
+	}
+	s.Scores[claimant] -= points
+	s.Scores[caller] += points
+	from := claimant
+	s.emit(Event{Type: EventMuggins, Player: caller, Points: points, From: &from})
+	if s.Scores[caller] >= s.Rules.WinningScore() {
+		s.finish(caller)
+	}
+	return points, nil
+}
@@ -0,0 +1,30 @@
+package cribbage
+
+import "testing"
+
+func TestMugginsCapsAtClaimantScore(t *testing.T) {
+	tests := []struct {
+		claimant, missed, moved int
+	}{
+		{10, 4, 4},
+		{3, 6, 3},
+		{0, 2, 0},
+	}
+	for _, tt := range tests {
+		r := DefaultRules(2)
+		r.Muggins = true
+		st := NewStateWithRules(r)
+		st.Stage = "counting"
+		st.Scores = []int{tt.claimant, 50}
+		moved, err := st.Muggins(1, 0, tt.missed)
+		if err != nil {
+			t.Fatalf("claimant on %d, missed %d: %v", tt.claimant, tt.missed, err)
+		}
+		if moved != tt.moved || st.Scores[0] != tt.claimant-tt.moved || st.Scores[1] != 50+tt.moved {
+			t.Errorf("claimant on %d, missed %d: moved %d, scores %v; want %d moved", tt.claimant, tt.missed, moved, st.Scores, tt.moved)
+		}
+		if e := st.Events[len(st.Events)-1]; e.Type != EventMuggins || e.Points != tt.moved {
+			t.Errorf("claimant on %d, missed %d: last event %+v, want muggins for %d", tt.claimant, tt.missed, e, tt.moved)
+		}
+	}
+}
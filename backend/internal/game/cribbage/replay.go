@@ -0,0 +1,72 @@
+package cribbage
+
+import (
+	"errors"
+
+	"fifteen-thirty-one-go/backend/internal/game/common"
+	"fifteen-thirty-one-go/backend/internal/models"
+)
+
+// ReplayHand returns a state at the start of a recorded hand, for stepping through it with the
+// recorded moves. Discards are not recorded card by card, so each seat is dealt the cards it
+// kept; the crib and cut are revealed by ReplayDiscard once everyone has discarded. Replays
+// are untimed.
+func ReplayHand(r Rules, dealer int, scores []int, kept [][]common.Card) *State {
+	r.TurnTimeoutSeconds = 0
+	s := NewStateWithRules(r)
+	s.DealerIndex = dealer
+	copy(s.Scores, scores)
+	for i := range s.Hands {
+		s.Hands[i] = []common.Card{}
+		if i < len(kept) {
+			s.Hands[i] = append(s.Hands[i], kept[i]...)
+		}
+	}
+	s.Stage = "discard"
+	s.PeggingPassed = make([]bool, r.MaxPlayers)
+	s.LastPlayIndex = -1
+	s.CurrentIndex = (dealer + 1) % r.MaxPlayers
+	return s
+}
+
+// ReplayDiscard marks player's recorded discard. When the last seat has discarded, the crib and
+// cut are revealed and pegging starts as in Discard. Heels are not scored here; the recorded
+// heels move carries them (see ReplayHeels).
+func (s *State) ReplayDiscard(player int, crib []common.Card, cut *common.Card) error {
+	if s.Stage != "discard" {
+		return models.ErrNotInDiscardStage
+	}
+	if player < 0 || player >= s.Rules.MaxPlayers {
+		return models.ErrInvalidPlayer
+	}
+	if s.DiscardCompleted[player] {
+		return models.ErrDiscardAlreadyCompleted
+	}
+	s.DiscardCompleted[player] = true
+	for _, done := range s.DiscardCompleted {
+		if !done {
+			return nil
+		}
+	}
+	if cut == nil {
+		return errors.New("replay: missing cut card")
+	}
+	c := *cut
+	s.Cut = &c
+	s.Crib = append([]common.Card(nil), crib...)
+	s.Stage = "pegging"
+	s.DiscardCompleted = make([]bool, s.Rules.MaxPlayers)
+	for i := range s.Hands {
+		s.KeptHands[i] = append([]common.Card(nil), s.Hands[i]...)
+	}
+	s.CurrentIndex = (s.DealerIndex + 1) % s.Rules.MaxPlayers
+	return nil
+}
+
+// ReplayHeels credits the dealer with recorded heels points.
+func (s *State) ReplayHeels(points int) {
+	s.Scores[s.DealerIndex] += points
+	if s.Scores[s.DealerIndex] >= s.Rules.WinningScore() {
+		s.finish(s.DealerIndex)
+	}
+}
@@ -1,10 +1,61 @@
 package cribbage
 
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidRules wraps every Rules.Validate failure; the wrapped message is safe to show users.
+var ErrInvalidRules = errors.New("invalid rules")
+
 // Rules captures configurable cribbage rules for 2-4 players.
 type Rules struct {
 	MaxPlayers int `json:"max_players"` // 2-4
+	// TargetScore is the winning score: 121 (standard) or 61 (short game). Zero means 121 so
+	// states persisted before this field existed keep their original target.
+	TargetScore int `json:"target_score,omitempty"`
+	// LastCardPoints is awarded for the last card of a pegging sequence that doesn't reach 31.
+	// Zero means the standard 1 point.
+	LastCardPoints int `json:"last_card_points,omitempty"`
+	// CutTiePolicy settles ties when cutting for deal: CutTieRecut (default when empty) or
+	// CutTieSuit.
+	CutTiePolicy string `json:"cut_tie_policy,omitempty"`
+	// CribFourCardFlush lets the crib score a flush on its own four cards, like a hand. The
+	// standard rule (false) requires the cut to match as well.
+	CribFourCardFlush bool `json:"crib_four_card_flush,omitempty"`
+	// Muggins lets opponents claim points a player missed when counting (see State.Muggins).
+	Muggins bool `json:"muggins,omitempty"`
+	// TurnTimeoutSeconds limits each discard and pegging turn; when it runs out the server plays
+	// for the player (see State.TurnDeadline). Zero means turns are untimed.
+	TurnTimeoutSeconds int `json:"turn_timeout_seconds,omitempty"`
+	// RevealHands is a casual option that also shows every seat's discards while a hand is
+	// being counted, so players can review the full dealt hands before the next deal.
+	RevealHands bool `json:"reveal_hands,omitempty"`
+	// PeggingFlush is a house variant that scores same-suit cards played in a row during
+	// pegging: one point per card once MinPeggingFlush are in a row. Standard play (false)
+	// scores no flushes until the count.
+	PeggingFlush bool `json:"pegging_flush,omitempty"`
+	// Variant picks the deal: VariantStandard (the default when empty) or VariantFiveCard, the
+	// older two-player game dealt five cards and played to 61.
+	Variant string `json:"variant,omitempty"`
 }
 
+const (
+	StandardTargetScore = 121
+	ShortTargetScore    = 61
+
+	StandardLastCardPoints = 1
+	MaxLastCardPoints      = 2
+)
+
+// Rule variants (see Rules.Variant).
+const (
+	VariantStandard = "standard"
+	// VariantFiveCard deals two players five cards each; both throw two to the crib, peg and
+	// count three-card hands, and play to 61.
+	VariantFiveCard = "five_card"
+)
+
 func DefaultRules(players int) Rules {
 	if players < 2 {
 		players = 2
@@ -15,7 +66,125 @@ func DefaultRules(players int) Rules {
 	return Rules{MaxPlayers: players}
 }
 
+// VariantName returns the effective variant.
+func (r Rules) VariantName() string {
+	if r.Variant == "" {
+		return VariantStandard
+	}
+	return r.Variant
+}
+
+// FiveCard reports whether this is the five-card game.
+func (r Rules) FiveCard() bool {
+	return r.Variant == VariantFiveCard
+}
+
+// WinningScore returns the score that ends the game.
+func (r Rules) WinningScore() int {
+	if r.TargetScore == 0 {
+		if r.FiveCard() {
+			return ShortTargetScore
+		}
+		return StandardTargetScore
+	}
+	return r.TargetScore
+}
+
+// SkunkLine is the score a loser must reach to avoid a skunk: 30 points short of the target.
+func (r Rules) SkunkLine() int {
+	return r.WinningScore() - 30
+}
+
+// DoubleSkunkLine is the score a loser must reach to avoid a double skunk, 60 short of the
+// target. Short games have no double skunk and return 0.
+func (r Rules) DoubleSkunkLine() int {
+	if r.WinningScore() == ShortTargetScore {
+		return 0
+	}
+	return r.WinningScore() - 60
+}
+
+// Skunk levels for a losing score; a skunk is worth two games and a double skunk three.
+const (
+	NoSkunk     = 0
+	Skunk       = 1
+	DoubleSkunk = 2
+)
+
+// SkunkLevel classifies a losing player's final score against the skunk lines.
+func (r Rules) SkunkLevel(score int) int {
+	switch {
+	case r.DoubleSkunkLine() > 0 && score < r.DoubleSkunkLine():
+		return DoubleSkunk
+	case score < r.SkunkLine():
+		return Skunk
+	default:
+		return NoSkunk
+	}
+}
+
+// LastCardValue returns the points for playing the last card of a sequence short of 31.
+func (r Rules) LastCardValue() int {
+	if r.LastCardPoints == 0 {
+		return StandardLastCardPoints
+	}
+	return r.LastCardPoints
+}
+
+// CribFlushRequiresFive reports whether a crib flush needs the cut to match its suit (standard).
+func (r Rules) CribFlushRequiresFive() bool {
+	return !r.CribFourCardFlush
+}
+
+// CutTieRule returns the effective cut tie policy.
+func (r Rules) CutTieRule() string {
+	if r.CutTiePolicy == "" {
+		return CutTieRecut
+	}
+	return r.CutTiePolicy
+}
+
+// Validate checks that the rule combination is playable. It is used when creating lobbies
+// and when restoring persisted engine state.
+func (r Rules) Validate() error {
+	if r.MaxPlayers < 2 || r.MaxPlayers > 4 {
+		return fmt.Errorf("%w: max_players must be 2-4", ErrInvalidRules)
+	}
+	switch r.TargetScore {
+	case 0, StandardTargetScore, ShortTargetScore:
+	default:
+		return fmt.Errorf("%w: target_score must be %d or %d", ErrInvalidRules, ShortTargetScore, StandardTargetScore)
+	}
+	if r.LastCardPoints < 0 || r.LastCardPoints > MaxLastCardPoints {
+		return fmt.Errorf("%w: last_card_points must be %d-%d", ErrInvalidRules, StandardLastCardPoints, MaxLastCardPoints)
+	}
+	switch r.CutTiePolicy {
+	case "", CutTieRecut, CutTieSuit:
+	default:
+		return fmt.Errorf("%w: cut_tie_policy must be %s or %s", ErrInvalidRules, CutTieRecut, CutTieSuit)
+	}
+	if r.TurnTimeoutSeconds != 0 && (r.TurnTimeoutSeconds < MinTurnTimeoutSeconds || r.TurnTimeoutSeconds > MaxTurnTimeoutSeconds) {
+		return fmt.Errorf("%w: turn_timeout_seconds must be 0 (off) or %d-%d", ErrInvalidRules, MinTurnTimeoutSeconds, MaxTurnTimeoutSeconds)
+	}
+	switch r.Variant {
+	case "", VariantStandard:
+	case VariantFiveCard:
+		if r.MaxPlayers != 2 {
+			return fmt.Errorf("%w: the %s variant is for 2 players", ErrInvalidRules, VariantFiveCard)
+		}
+		if r.TargetScore != 0 && r.TargetScore != ShortTargetScore {
+			return fmt.Errorf("%w: the %s variant plays to %d", ErrInvalidRules, VariantFiveCard, ShortTargetScore)
+		}
+	default:
+		return fmt.Errorf("%w: variant must be %s or %s", ErrInvalidRules, VariantStandard, VariantFiveCard)
+	}
+	return nil
+}
+
 func (r Rules) HandSize() int {
+	if r.FiveCard() {
+		return 5
+	}
 	switch r.MaxPlayers {
 	case 2:
 		return 6
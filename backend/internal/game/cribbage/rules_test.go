@@ -0,0 +1,98 @@
+package cribbage
+
+import (
+	"errors"
+	"testing"
+
+	"fifteen-thirty-one-go/backend/internal/models"
+)
+
+func fiveCardRules() Rules {
+	r := DefaultRules(2)
+	r.Variant = VariantFiveCard
+	return r
+}
+
+func TestFiveCardDealsFiveAndThrowsTwo(t *testing.T) {
+	st := NewStateWithRules(fiveCardRules())
+	if err := st.Deal(); err != nil {
+		t.Fatalf("deal: %v", err)
+	}
+	for p := 0; p < 2; p++ {
+		if len(st.Hands[p]) != 5 {
+			t.Fatalf("seat %d dealt %d cards, want 5", p, len(st.Hands[p]))
+		}
+	}
+	if err := st.Discard(0, st.Hands[0][:3]); !errors.Is(err, models.ErrInvalidDiscardCount) {
+		t.Errorf("throwing 3 cards: err = %v, want ErrInvalidDiscardCount", err)
+	}
+	for p := 0; p < 2; p++ {
+		if err := st.Discard(p, st.Hands[p][:2]); err != nil {
+			t.Fatalf("seat %d throws 2: %v", p, err)
+		}
+	}
+	if len(st.Crib) != 4 {
+		t.Errorf("crib has %d cards, want 4", len(st.Crib))
+	}
+	if st.Stage != "pegging" {
+		t.Errorf("stage %q after both discards, want pegging", st.Stage)
+	}
+	for p := 0; p < 2; p++ {
+		if len(st.KeptHands[p]) != 3 {
+			t.Errorf("seat %d kept %d cards, want 3", p, len(st.KeptHands[p]))
+		}
+	}
+}
+
+func TestFiveCardHeelsCanWinAt61(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		dealer int
+		stage  string
+	}{
+		{"early", 10, "pegging"},
+		{"on the line", 59, "finished"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			st := NewStateWithRules(fiveCardRules())
+			if err := st.Deal(); err != nil {
+				t.Fatalf("deal: %v", err)
+			}
+			st.Scores[st.DealerIndex] = tc.dealer
+			// The cut is the top of the deck.
+			st.Deck[0] = cards(t, "JH")[0]
+			for p := 0; p < 2; p++ {
+				if err := st.Discard(p, st.Hands[p][:2]); err != nil {
+					t.Fatalf("seat %d throws 2: %v", p, err)
+				}
+			}
+			if got := st.Scores[st.DealerIndex]; got != tc.dealer+HeelsPoints {
+				t.Errorf("dealer has %d after a jack cut, want %d", got, tc.dealer+HeelsPoints)
+			}
+			if st.Stage != tc.stage {
+				t.Errorf("stage %q, want %q", st.Stage, tc.stage)
+			}
+		})
+	}
+}
+
+func TestFiveCardValidation(t *testing.T) {
+	if err := fiveCardRules().Validate(); err != nil {
+		t.Fatalf("two-player five-card rules: %v", err)
+	}
+	if got := fiveCardRules().WinningScore(); got != ShortTargetScore {
+		t.Errorf("five-card game plays to %d, want %d", got, ShortTargetScore)
+	}
+	for _, players := range []int{3, 4} {
+		r := DefaultRules(players)
+		r.Variant = VariantFiveCard
+		if err := r.Validate(); !errors.Is(err, ErrInvalidRules) {
+			t.Errorf("%d-player five-card game: err = %v, want ErrInvalidRules", players, err)
+		}
+	}
+	r := fiveCardRules()
+	r.TargetScore = StandardTargetScore
+	if err := r.Validate(); !errors.Is(err, ErrInvalidRules) {
+		t.Errorf("five-card game to %d: err = %v, want ErrInvalidRules", StandardTargetScore, err)
+	}
+}
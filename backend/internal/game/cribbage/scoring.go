@@ -6,6 +6,10 @@ import (
 	"fifteen-thirty-one-go/backend/internal/game/common"
 )
 
+// MaxHandScore is the highest possible score for a hand or crib (three fives and the
+// jack of the fourth suit, with a five cut).
+const MaxHandScore = 29
+
 type ScoreBreakdown struct {
 	Total    int            `json:"total"`
 	Fifteens int            `json:"fifteens"`
@@ -17,7 +21,13 @@ type ScoreBreakdown struct {
 }
 
 // ScoreHand scores a cribbage hand: 4 hand cards + cut card. (Pass the 4-card hand as hand.)
+// It applies the standard rules; use ScoreHandRules to count a crib under a game's variant.
 func ScoreHand(hand []common.Card, cut common.Card, isCrib bool) ScoreBreakdown {
+	return ScoreHandRules(hand, cut, isCrib, Rules{})
+}
+
+// ScoreHandRules is ScoreHand under the given rules (see Rules.CribFlushRequiresFive).
+func ScoreHandRules(hand []common.Card, cut common.Card, isCrib bool, r Rules) ScoreBreakdown {
 	all := make([]common.Card, 0, len(hand)+1)
 	all = append(all, hand...)
 	all = append(all, cut)
@@ -27,7 +37,7 @@ func ScoreHand(hand []common.Card, cut common.Card, isCrib bool) ScoreBreakdown
 	sb.Fifteens = scoreFifteens(all)
 	sb.Pairs = scorePairs(all)
 	sb.Runs = scoreRuns(all)
-	sb.Flush = scoreFlush(hand, cut, isCrib)
+	sb.Flush = scoreFlush(hand, cut, isCrib && r.CribFlushRequiresFive())
 	sb.Nobs = scoreNobs(hand, cut)
 
 	sb.Total = sb.Fifteens + sb.Pairs + sb.Runs + sb.Flush + sb.Nobs
@@ -54,20 +64,18 @@ func ScoreHand(hand []common.Card, cut common.Card, isCrib bool) ScoreBreakdown
 
 func scoreFifteens(cards []common.Card) int {
 	// Count all subsets that sum to 15, each worth 2 points.
-	n := len(cards)
-	points := 0
-	for mask := 1; mask < (1 << n); mask++ {
-		sum := 0
-		for i := 0; i < n; i++ {
-			if mask&(1<<i) != 0 {
-				sum += cards[i].Value15()
-			}
-		}
-		if sum == 15 {
-			points += 2
+	// ways[s] is the number of subsets of the cards seen so far summing to s; sums above
+	// 15 can never come back down, so they are dropped. This is O(n*15) instead of O(2^n),
+	// which matters for callers that score thousands of hand/cut combinations.
+	var ways [16]int
+	ways[0] = 1
+	for _, c := range cards {
+		v := c.Value15()
+		for s := 15; s >= v; s-- {
+			ways[s] += ways[s-v]
 		}
 	}
-	return points
+	return ways[15] * 2
 }
 
 func scorePairs(cards []common.Card) int {
@@ -130,27 +138,26 @@ func scoreRuns(cards []common.Card) int {
 	return bestLen * bestMult
 }
 
-func scoreFlush(hand []common.Card, cut common.Card, isCrib bool) int {
-	if len(hand) != 4 {
+// scoreFlush scores a hand of one suit a point per card, plus one when the cut matches: 4 or 5
+// for the usual four cards, 3 or 4 for a five-card game's three-card hand. With needsCut (the
+// standard crib rule) only a flush that includes the cut counts.
+func scoreFlush(hand []common.Card, cut common.Card, needsCut bool) int {
+	if len(hand) != 3 && len(hand) != 4 {
 		return 0
 	}
 	s := hand[0].Suit
-	for i := 1; i < 4; i++ {
-		if hand[i].Suit != s {
+	for _, c := range hand[1:] {
+		if c.Suit != s {
 			return 0
 		}
 	}
-	// Hand flush: 4, plus cut makes 5.
-	if isCrib {
-		if cut.Suit == s {
-			return 5
-		}
-		return 0
-	}
 	if cut.Suit == s {
-		return 5
+		return len(hand) + 1
 	}
-	return 4
+	if needsCut {
+		return 0
+	}
+	return len(hand)
 }
 
 func scoreNobs(hand []common.Card, cut common.Card) int {
@@ -165,7 +172,13 @@ func scoreNobs(hand []common.Card, cut common.Card) int {
 // PeggingScore computes points for a pegging play.
 // playSeq are the cards in the current count since the last reset (oldest->newest).
 // currentTotal is the total before playing newCard.
+// It applies the standard rules, which score no flushes in pegging; see PeggingScoreRules.
 func PeggingScore(playSeq []common.Card, newCard common.Card, currentTotal int) (points int, newTotal int, reasons []string) {
+	return PeggingScoreRules(playSeq, newCard, currentTotal, Rules{})
+}
+
+// PeggingScoreRules is PeggingScore under the given rules (see Rules.PeggingFlush).
+func PeggingScoreRules(playSeq []common.Card, newCard common.Card, currentTotal int, r Rules) (points int, newTotal int, reasons []string) {
 	newTotal = currentTotal + newCard.Value15()
 	reasons = []string{}
 
@@ -214,9 +227,29 @@ func PeggingScore(playSeq []common.Card, newCard common.Card, currentTotal int)
 		}
 	}
 
+	if r.PeggingFlush {
+		if n := peggingFlushLen(playSeq, newCard); n >= MinPeggingFlush {
+			points += n
+			reasons = append(reasons, "flush")
+		}
+	}
+
 	return points, newTotal, reasons
 }
 
+// MinPeggingFlush is the fewest same-suit cards in a row that score under Rules.PeggingFlush.
+const MinPeggingFlush = 3
+
+// peggingFlushLen counts the cards of newCard's suit played in a row at the end of the count,
+// newCard included.
+func peggingFlushLen(playSeq []common.Card, newCard common.Card) int {
+	n := 1
+	for i := len(playSeq) - 1; i >= 0 && playSeq[i].Suit == newCard.Suit; i-- {
+		n++
+	}
+	return n
+}
+
 func isRun(cards []common.Card) bool {
 	seen := map[int]bool{}
 	min := 99
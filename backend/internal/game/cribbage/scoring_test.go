@@ -0,0 +1,194 @@
+package cribbage
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"fifteen-thirty-one-go/backend/internal/game/common"
+)
+
+// cards parses a space-separated list such as "5H 10S JD".
+func cards(t testing.TB, s string) []common.Card {
+	t.Helper()
+	var out []common.Card
+	for _, f := range strings.Fields(s) {
+		c, err := common.ParseCard(f)
+		if err != nil {
+			t.Fatalf("parse %q: %v", f, err)
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// scoreFifteensBitmask is the original subset enumeration scoreFifteens replaced; it is kept
+// here as the reference the DP must agree with.
+func scoreFifteensBitmask(cards []common.Card) int {
+	n := len(cards)
+	points := 0
+	for mask := 1; mask < (1 << n); mask++ {
+		sum := 0
+		for i := 0; i < n; i++ {
+			if mask&(1<<i) != 0 {
+				sum += cards[i].Value15()
+			}
+		}
+		if sum == 15 {
+			points += 2
+		}
+	}
+	return points
+}
+
+func TestScoreFifteensMatchesBitmaskForEveryHand(t *testing.T) {
+	deck := common.NewStandardDeck()
+	hand := make([]common.Card, 5)
+	hands := 0
+	for a := 0; a < len(deck); a++ {
+		for b := a + 1; b < len(deck); b++ {
+			for c := b + 1; c < len(deck); c++ {
+				for d := c + 1; d < len(deck); d++ {
+					for e := d + 1; e < len(deck); e++ {
+						hand[0], hand[1], hand[2], hand[3], hand[4] = deck[a], deck[b], deck[c], deck[d], deck[e]
+						if got, want := scoreFifteens(hand), scoreFifteensBitmask(hand); got != want {
+							t.Fatalf("scoreFifteens(%v) = %d, bitmask = %d", hand, got, want)
+						}
+						hands++
+					}
+				}
+			}
+		}
+	}
+	if hands != 2598960 {
+		t.Fatalf("checked %d hands, want every 5-card hand (2598960)", hands)
+	}
+}
+
+func TestScoreFifteens(t *testing.T) {
+	tests := []struct {
+		hand string
+		want int
+	}{
+		{"5H 5S 5D JC 5C", 16},
+		{"5H 10S", 2},
+		{"AH 2S 3D 4C 6H", 2},
+		{"7H 8S 7D 8C", 8},
+		{"AH AS AD AC", 0},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		if got := scoreFifteens(cards(t, tt.hand)); got != tt.want {
+			t.Errorf("scoreFifteens(%s) = %d, want %d", tt.hand, got, tt.want)
+		}
+	}
+}
+
+var benchHands = [][]common.Card{
+	{{Rank: 5, Suit: common.Hearts}, {Rank: 5, Suit: common.Spades}, {Rank: 5, Suit: common.Diamonds}, {Rank: common.Jack, Suit: common.Clubs}, {Rank: 5, Suit: common.Clubs}},
+	{{Rank: 7, Suit: common.Hearts}, {Rank: 8, Suit: common.Spades}, {Rank: 7, Suit: common.Diamonds}, {Rank: 8, Suit: common.Clubs}, {Rank: 2, Suit: common.Clubs}},
+	{{Rank: 1, Suit: common.Hearts}, {Rank: 3, Suit: common.Spades}, {Rank: 9, Suit: common.Diamonds}, {Rank: common.King, Suit: common.Clubs}, {Rank: 6, Suit: common.Clubs}},
+}
+
+func BenchmarkScoreFifteens(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		scoreFifteens(benchHands[i%len(benchHands)])
+	}
+}
+
+func BenchmarkScoreFifteensBitmask(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		scoreFifteensBitmask(benchHands[i%len(benchHands)])
+	}
+}
+
+func TestCribFlush(t *testing.T) {
+	fourCard := DefaultRules(2)
+	fourCard.CribFourCardFlush = true
+	tests := []struct {
+		name   string
+		cut    string
+		isCrib bool
+		rules  Rules
+		want   int
+	}{
+		{"hand, cut off suit", "KS", false, DefaultRules(2), 4},
+		{"hand, cut matches", "KH", false, DefaultRules(2), 5},
+		{"standard crib, cut off suit", "KS", true, DefaultRules(2), 0},
+		{"standard crib, cut matches", "KH", true, DefaultRules(2), 5},
+		{"four-card crib, cut off suit", "KS", true, fourCard, 4},
+		{"four-card crib, cut matches", "KH", true, fourCard, 5},
+	}
+	// No fifteens, pairs, runs or nobs: the flush is all these score.
+	hand := cards(t, "2H 4H 6H QH")
+	for _, tt := range tests {
+		cut := cards(t, tt.cut)[0]
+		b := ScoreHandRules(hand, cut, tt.isCrib, tt.rules)
+		if b.Flush != tt.want || b.Total != tt.want {
+			t.Errorf("%s: flush %d total %d, want %d", tt.name, b.Flush, b.Total, tt.want)
+		}
+	}
+	if got := ScoreHand(hand, cards(t, "KS")[0], true).Flush; got != 0 {
+		t.Errorf("ScoreHand crib with the cut off suit: flush %d, want the standard 0", got)
+	}
+}
+
+func TestPeggingFlushOffScoresStandard(t *testing.T) {
+	flush := DefaultRules(2)
+	flush.PeggingFlush = true
+	tests := []struct {
+		seq, card string
+		standard  int // flag off, and PeggingScore
+		withFlush int
+	}{
+		{"", "5H", 0, 0},
+		{"2H 4H", "9H", 2, 5},        // fifteen, three hearts in a row
+		{"AH 2H", "3H", 3, 6},        // run of three, three hearts
+		{"4H 6H 8H", "10H", 0, 4},    // nothing but four hearts
+		{"5S 5H", "5D", 8, 8},        // fifteen and three of a kind, no suit run
+		{"2H 4S", "6H", 0, 0},        // hearts broken by a spade
+		{"KH 5H", "AD", 0, 0},        // a flush needs the last cards to match
+		{"3C 4C 5C 6C", "7C", 5, 10}, // run of five, five clubs
+	}
+	for _, tt := range tests {
+		seq := cards(t, tt.seq)
+		card := cards(t, tt.card)[0]
+		total := 0
+		for _, c := range seq {
+			total += c.Value15()
+		}
+		for _, r := range []Rules{{}, DefaultRules(2)} {
+			pts, _, reasons := PeggingScoreRules(seq, card, total, r)
+			if pts != tt.standard {
+				t.Errorf("%s then %s, flag off: %d points, want %d", tt.seq, tt.card, pts, tt.standard)
+			}
+			if slices.Contains(reasons, "flush") {
+				t.Errorf("%s then %s, flag off: reasons %v include a flush", tt.seq, tt.card, reasons)
+			}
+		}
+		if pts, _, _ := PeggingScore(seq, card, total); pts != tt.standard {
+			t.Errorf("%s then %s: PeggingScore %d points, want %d", tt.seq, tt.card, pts, tt.standard)
+		}
+		if pts, _, _ := PeggingScoreRules(seq, card, total, flush); pts != tt.withFlush {
+			t.Errorf("%s then %s, flag on: %d points, want %d", tt.seq, tt.card, pts, tt.withFlush)
+		}
+	}
+}
+
+func TestPeggingFlushOffInPlay(t *testing.T) {
+	// Four hearts in a row peg nothing in a standard game and four under the house rule.
+	for _, tt := range []struct {
+		flush bool
+		want  int
+	}{{false, 0}, {true, 4}} {
+		r := DefaultRules(2)
+		r.PeggingFlush = tt.flush
+		st := peggingState(t, r, "KS", "6H 10H", "4H 8H")
+		play(t, st, 1, "4H")
+		play(t, st, 0, "6H")
+		play(t, st, 1, "8H")
+		if got := play(t, st, 0, "10H"); got != tt.want {
+			t.Errorf("PeggingFlush %t: fourth heart pegged %d, want %d", tt.flush, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package cribbage
+
+import (
+	"errors"
+	"fmt"
+)
+
+// maxSimulatedHands bounds SimulateGame; a real game to 121 takes well under 30 hands.
+const maxSimulatedHands = 200
+
+// SimulateGame plays one complete game between bots entirely in memory, seat i thinking at
+// bots[i], with dealer dealing the first hand. It drives the same Deal, Discard,
+// PlayPeggingCard, Go and counting paths as live games and returns the finished state and the
+// winning seat.
+func SimulateGame(rules Rules, bots []BotDifficulty, dealer int) (*State, int, error) {
+	if err := rules.Validate(); err != nil {
+		return nil, -1, err
+	}
+	if len(bots) != rules.MaxPlayers {
+		return nil, -1, fmt.Errorf("simulate: %d bots for %d seats", len(bots), rules.MaxPlayers)
+	}
+	if dealer < 0 || dealer >= rules.MaxPlayers {
+		return nil, -1, fmt.Errorf("simulate: invalid dealer %d", dealer)
+	}
+	s := NewStateWithRules(rules)
+	s.DealerIndex = dealer
+	for hand := 0; hand < maxSimulatedHands; hand++ {
+		if hand > 0 {
+			s.DealerIndex = (s.DealerIndex + 1) % rules.MaxPlayers
+		}
+		if err := s.Deal(); err != nil {
+			return nil, -1, err
+		}
+		for p := 0; p < rules.MaxPlayers; p++ {
+			discards, err := ChooseDiscardN(s.Hands[p], rules.DiscardCount(), bots[p], p == s.DealerIndex)
+			if err != nil {
+				return nil, -1, err
+			}
+			if err := s.Discard(p, discards); err != nil {
+				return nil, -1, err
+			}
+		}
+		for s.Stage == "pegging" {
+			p := s.CurrentIndex
+			card, goPlay := ChoosePeggingPlay(s.Hands[p], s.PeggingTotal, s.PeggingSeq, bots[p])
+			var err error
+			if goPlay {
+				_, err = s.Go(p)
+			} else {
+				_, _, err = s.PlayPeggingCard(p, *card)
+			}
+			if err != nil {
+				return nil, -1, err
+			}
+		}
+		// The game ended during pegging (pegging out), or maybeFinishRound counted the hands
+		// and crib when the last card was played.
+		if s.Stage == "finished" {
+			for i := len(s.Events) - 1; i >= 0; i-- {
+				if s.Events[i].Type == EventGameOver {
+					return s, s.Events[i].Player, nil
+				}
+			}
+			return nil, -1, errors.New("simulate: finished without a winner")
+		}
+		if s.Stage != "counting" {
+			return nil, -1, fmt.Errorf("simulate: unexpected stage %q after pegging", s.Stage)
+		}
+	}
+	return nil, -1, fmt.Errorf("simulate: no winner after %d hands", maxSimulatedHands)
+}
@@ -0,0 +1,59 @@
+package cribbage
+
+import "time"
+
+// Bounds for Rules.TurnTimeoutSeconds when the turn timer is on.
+const (
+	MinTurnTimeoutSeconds = 15
+	MaxTurnTimeoutSeconds = 600
+)
+
+// TurnTimeout is how long a player has for a discard or a pegging play; 0 means turns are
+// untimed.
+func (r Rules) TurnTimeout() time.Duration {
+	return time.Duration(r.TurnTimeoutSeconds) * time.Second
+}
+
+// RestartTurnClock starts a fresh TurnDeadline when the game is waiting on a discard or a
+// pegging play, and clears it otherwise. The engine calls it whenever the turn moves: a new
+// deal, the cut that starts pegging, and every pegging play or go.
+func (s *State) RestartTurnClock() {
+	if s.Rules.TurnTimeoutSeconds <= 0 || (s.Stage != "discard" && s.Stage != "pegging") {
+		s.TurnDeadline = nil
+		return
+	}
+	d := time.Now().Add(s.Rules.TurnTimeout()).UTC()
+	s.TurnDeadline = &d
+}
+
+// turnMoved restarts the turn clock after a successful pegging move; deferred by the move.
+func (s *State) turnMoved(err *error) {
+	if *err == nil {
+		s.RestartTurnClock()
+	}
+}
+
+// TurnExpired reports whether the player(s) the game is waiting on ran out of time by now.
+func (s *State) TurnExpired(now time.Time) bool {
+	return s.TurnDeadline != nil && !now.Before(*s.TurnDeadline)
+}
+
+// RecordTurnTimeout counts a turn played for player because their time ran out and returns
+// how many turns in a row they have now timed out.
+func (s *State) RecordTurnTimeout(player int) int {
+	if player < 0 || player >= s.Rules.MaxPlayers {
+		return 0
+	}
+	if len(s.TurnTimeouts) != s.Rules.MaxPlayers {
+		s.TurnTimeouts = make([]int, s.Rules.MaxPlayers)
+	}
+	s.TurnTimeouts[player]++
+	return s.TurnTimeouts[player]
+}
+
+// ClearTurnTimeouts resets player's run of timed-out turns once they act themselves.
+func (s *State) ClearTurnTimeouts(player int) {
+	if player >= 0 && player < len(s.TurnTimeouts) {
+		s.TurnTimeouts[player] = 0
+	}
+}
@@ -0,0 +1,77 @@
+package cribbage
+
+import (
+	"fifteen-thirty-one-go/backend/internal/game/common"
+	"fifteen-thirty-one-go/backend/internal/models"
+)
+
+// PeggingUndo is what UndoPeggingPlay needs to take back the last pegging play. PlayPeggingCard
+// records it only when the play left the sequence open: a 31, the end of pegging or a win
+// can't be taken back. Version is the state version right after the play, so any later move
+// (which bumps the version) makes the undo stale.
+type PeggingUndo struct {
+	Player    int         `json:"player"`
+	Card      common.Card `json:"card"`
+	HandIndex int         `json:"hand_index"`
+	Version   int64       `json:"version"`
+
+	PeggingTotal  int   `json:"pegging_total"`
+	Scores        []int `json:"scores"`
+	CurrentIndex  int   `json:"current_index"`
+	LastPlayIndex int   `json:"last_play_index"`
+	Passed        bool  `json:"passed"`
+	SequencePegs  int   `json:"sequence_pegs"` // len(SequencePegs) before the play
+}
+
+// newPeggingUndo captures the state PlayPeggingCard is about to change.
+func (s *State) newPeggingUndo(player int, card common.Card, handIndex int) *PeggingUndo {
+	return &PeggingUndo{
+		Player:        player,
+		Card:          card,
+		HandIndex:     handIndex,
+		Version:       s.Version + 1,
+		PeggingTotal:  s.PeggingTotal,
+		Scores:        append([]int(nil), s.Scores...),
+		CurrentIndex:  s.CurrentIndex,
+		LastPlayIndex: s.LastPlayIndex,
+		Passed:        s.PeggingPassed[player],
+		SequencePegs:  len(s.SequencePegs),
+	}
+}
+
+// UndoPeggingPlay puts player's last pegging card back in their hand and restores the count,
+// scores and turn from before it. It fails with ErrUndoUnavailable unless that play is the
+// latest change to the state, and with ErrUndoNotYourPlay when someone else made it.
+func (s *State) UndoPeggingPlay(player int) error {
+	u := s.PeggingUndo
+	if u == nil || s.Stage != "pegging" || u.Version != s.Version || len(s.PeggingSeq) == 0 {
+		return models.ErrUndoUnavailable
+	}
+	if u.Player != player {
+		return models.ErrUndoNotYourPlay
+	}
+	hand := s.Hands[player]
+	i := u.HandIndex
+	if i < 0 || i > len(hand) {
+		i = len(hand)
+	}
+	restored := make([]common.Card, 0, len(hand)+1)
+	restored = append(restored, hand[:i]...)
+	restored = append(restored, u.Card)
+	s.Hands[player] = append(restored, hand[i:]...)
+
+	s.PeggingSeq = s.PeggingSeq[:len(s.PeggingSeq)-1]
+	s.PeggingTotal = u.PeggingTotal
+	copy(s.Scores, u.Scores)
+	s.CurrentIndex = u.CurrentIndex
+	s.LastPlayIndex = u.LastPlayIndex
+	s.PeggingPassed[player] = u.Passed
+	if u.SequencePegs <= len(s.SequencePegs) {
+		s.SequencePegs = s.SequencePegs[:u.SequencePegs]
+	}
+	s.PeggingUndo = nil
+	card := u.Card
+	s.emit(Event{Type: EventUndo, Player: player, Card: &card, Total: s.PeggingTotal})
+	s.RestartTurnClock()
+	return nil
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"fifteen-thirty-one-go/backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// recordAccess writes a security audit row when ACCESS_LOG_ENABLED is set. Failures are logged
+// and never affect the request being audited.
+func recordAccess(db *sql.DB, c *gin.Context, event string, success bool, userID *int64, username string) {
+	if !currentConfig().AccessLogEnabled {
+		return
+	}
+	if err := models.InsertAccessLog(db, models.AccessLogEntry{
+		Event:     event,
+		Success:   success,
+		UserID:    userID,
+		Username:  username,
+		IP:        c.ClientIP(),
+		Origin:    c.Request.Header.Get("Origin"),
+		UserAgent: c.Request.UserAgent(),
+	}); err != nil {
+		log.Printf("recordAccess: event=%s err=%v", event, err)
+	}
+}
+
+// RunAccessLogCleanup prunes access_log rows past the configured retention every hour until
+// ctx is cancelled. It is a no-op when access logging is disabled.
+func RunAccessLogCleanup(ctx context.Context, db *sql.DB) {
+	cfg := currentConfig()
+	if !cfg.AccessLogEnabled {
+		return
+	}
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		n, err := models.DeleteAccessLogOlderThan(db, cfg.AccessLogRetention)
+		if err != nil {
+			log.Printf("RunAccessLogCleanup: err=%v", err)
+		} else if n > 0 {
+			log.Printf("RunAccessLogCleanup: pruned %d rows", n)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"fifteen-thirty-one-go/backend/internal/auth"
+	"fifteen-thirty-one-go/backend/internal/models"
+	"fifteen-thirty-one-go/backend/internal/tracing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterAdminRoutes wires admin-only endpoints. The caller is responsible for gating rg
+// with middleware.RequireAdmin.
+func RegisterAdminRoutes(rg *gin.RouterGroup, db *sql.DB) {
+	rg.GET("/leaderboard/export", LeaderboardExportHandler(db))
+	rg.GET("/rooms/:room/clients", RoomClientsHandler())
+	rg.POST("/notice", ServerNoticeHandler())
+	rg.POST("/simulate", SimulateHandler())
+	rg.GET("/auth/token-failures", TokenFailuresHandler())
+}
+
+type serverNoticeRequest struct {
+	Message  string `json:"message"`
+	Severity string `json:"severity"` // info|warning|critical (default info)
+}
+
+// lastServerNotice rate-limits ServerNoticeHandler server-wide.
+var lastServerNotice struct {
+	mu   sync.Mutex
+	sent time.Time
+}
+
+// ServerNoticeHandler broadcasts a server:notice event (e.g. "maintenance in 10 minutes") to
+// every connected client. Notices are limited to one per ServerNoticeInterval.
+func ServerNoticeHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, span := tracing.StartSpan(c.Request.Context(), "handlers.ServerNoticeHandler")
+		defer span.End()
+
+		var req serverNoticeRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid json"})
+			return
+		}
+		msg := strings.TrimSpace(req.Message)
+		if msg == "" || len(msg) > 500 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "message must be 1-500 characters"})
+			return
+		}
+		severity := strings.ToLower(strings.TrimSpace(req.Severity))
+		switch severity {
+		case "":
+			severity = "info"
+		case "info", "warning", "critical":
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid severity (expected info, warning or critical)"})
+			return
+		}
+		hub, ok := getHubProvider()
+		if !ok || hub == nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "websocket hub unavailable"})
+			return
+		}
+
+		interval := currentConfig().ServerNoticeInterval
+		lastServerNotice.mu.Lock()
+		if wait := interval - time.Since(lastServerNotice.sent); wait > 0 {
+			lastServerNotice.mu.Unlock()
+			c.Header("Retry-After", strconv.Itoa(int(wait.Seconds())+1))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "a notice was sent recently; try again later"})
+			return
+		}
+		lastServerNotice.sent = time.Now()
+		lastServerNotice.mu.Unlock()
+
+		adminID, _ := userIDFromContext(c)
+		log.Printf("ServerNoticeHandler: admin_id=%d severity=%s message=%q", adminID, severity, msg)
+		hub.BroadcastAll("server:notice", gin.H{"message": msg, "severity": severity})
+		c.JSON(http.StatusOK, gin.H{"sent": true})
+	}
+}
+
+// TokenFailuresHandler reports rejected auth tokens per category since startup (see
+// auth.TokenFailureCounts), e.g. to spot clients with skewed clocks.
+func TokenFailuresHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, span := tracing.StartSpan(c.Request.Context(), "handlers.TokenFailuresHandler")
+		defer span.End()
+
+		c.JSON(http.StatusOK, gin.H{"failures": auth.TokenFailureCounts()})
+	}
+}
+
+// RoomClientsHandler lists the users connected to a WebSocket room (e.g. "game:12" or
+// "lobby:3") with per-user connection counts, for diagnosing missed broadcasts.
+func RoomClientsHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, span := tracing.StartSpan(c.Request.Context(), "handlers.RoomClientsHandler")
+		defer span.End()
+
+		room := strings.TrimSpace(c.Param("room"))
+		if room == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid room"})
+			return
+		}
+		hub, ok := getHubProvider()
+		if !ok || hub == nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "websocket hub unavailable"})
+			return
+		}
+		ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+		defer cancel()
+		snap, err := hub.RoomClients(ctx, room)
+		if err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "websocket hub unavailable"})
+			return
+		}
+		c.JSON(http.StatusOK, snap)
+	}
+}
+
+// LeaderboardExportHandler streams every player's totals and daily series for a window.
+// Query parameters: days (normalized like /leaderboard) and format=csv|json (default csv).
+// CSV has one row per player per day; JSON mirrors the /leaderboard response shape but
+// lists players in username order since rows are written as they are built.
+func LeaderboardExportHandler(db *sql.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, span := tracing.StartSpan(c.Request.Context(), "handlers.LeaderboardExportHandler")
+		defer span.End()
+
+		days := int64(30)
+		if s := c.Query("days"); s != "" {
+			if v, err := strconv.ParseInt(s, 10, 64); err == nil {
+				days = v
+			}
+		}
+		days = models.NormalizeLeaderboardDays(days)
+		format := c.DefaultQuery("format", "csv")
+		if format != "csv" && format != "json" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format"})
+			return
+		}
+
+		timeout := currentConfig().LeaderboardExportTimeout
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		// The server-wide WriteTimeout is sized for small JSON responses; extend it for this stream.
+		if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Now().Add(timeout)); err != nil {
+			log.Printf("LeaderboardExportHandler: SetWriteDeadline failed: %v", err)
+		}
+
+		filename := "leaderboard-" + strconv.FormatInt(days, 10) + "d." + format
+		c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+
+		var err error
+		if format == "csv" {
+			err = streamLeaderboardCSV(ctx, c, db, days)
+		} else {
+			err = streamLeaderboardJSON(ctx, c, db, days)
+		}
+		if err != nil {
+			log.Printf("LeaderboardExportHandler: days=%d format=%s err=%v", days, format, err)
+			if !c.Writer.Written() {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			}
+			// Once streaming has started the status is already sent; the truncated body is
+			// the only signal we can give the client.
+		}
+	}
+}
+
+// leaderboardFlushEvery is how many players the export writes between flushes to the client.
+const leaderboardFlushEvery = 50
+
+func streamLeaderboardCSV(ctx context.Context, c *gin.Context, db *sql.DB, days int64) error {
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	w := csv.NewWriter(c.Writer)
+	header := []string{
+		"user_id", "username", "games_played", "games_won", "win_rate",
+		"date", "day_games_played", "day_games_won", "day_win_rate",
+	}
+	wroteHeader := false
+	n := 0
+	err := models.StreamLeaderboard(ctx, db, days, func(p models.LeaderboardPlayer) error {
+		if !wroteHeader {
+			if err := w.Write(header); err != nil {
+				return err
+			}
+			wroteHeader = true
+		}
+		for _, d := range p.Series {
+			if err := w.Write([]string{
+				strconv.FormatInt(p.UserID, 10),
+				p.Username,
+				strconv.FormatInt(p.GamesPlayed, 10),
+				strconv.FormatInt(p.GamesWon, 10),
+				strconv.FormatFloat(p.WinRate, 'f', 4, 64),
+				d.Date,
+				strconv.FormatInt(d.GamesPlayed, 10),
+				strconv.FormatInt(d.GamesWon, 10),
+				strconv.FormatFloat(d.WinRate, 'f', 4, 64),
+			}); err != nil {
+				return err
+			}
+		}
+		if n++; n%leaderboardFlushEvery == 0 {
+			w.Flush()
+			c.Writer.Flush()
+		}
+		return w.Error()
+	})
+	if err != nil {
+		return err
+	}
+	if !wroteHeader {
+		if err := w.Write(header); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func streamLeaderboardJSON(ctx context.Context, c *gin.Context, db *sql.DB, days int64) error {
+	c.Header("Content-Type", "application/json; charset=utf-8")
+	prefix := `{"days":` + strconv.FormatInt(days, 10) + `,"items":[`
+	n := 0
+	err := models.StreamLeaderboard(ctx, db, days, func(p models.LeaderboardPlayer) error {
+		b, err := json.Marshal(p)
+		if err != nil {
+			return err
+		}
+		sep := ","
+		if n == 0 {
+			sep = prefix
+		}
+		n++
+		if _, err := c.Writer.WriteString(sep); err != nil {
+			return err
+		}
+		if _, err := c.Writer.Write(b); err != nil {
+			return err
+		}
+		if n%leaderboardFlushEvery == 0 {
+			c.Writer.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		if _, err := c.Writer.WriteString(prefix); err != nil {
+			return err
+		}
+	}
+	_, err = c.Writer.WriteString("]}")
+	return err
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"fifteen-thirty-one-go/backend/internal/game/cribbage"
+	"fifteen-thirty-one-go/backend/internal/tracing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type simulateRequest struct {
+	Games          int    `json:"games"`
+	A              string `json:"a"` // bot difficulty for seat 0
+	B              string `json:"b"` // bot difficulty for seat 1
+	TargetScore    int    `json:"target_score,omitempty"`
+	LastCardPoints int    `json:"last_card_points,omitempty"`
+}
+
+// SimulateSide is one bot's record over a simulation run.
+type SimulateSide struct {
+	Difficulty cribbage.BotDifficulty `json:"difficulty"`
+	Wins       int                    `json:"wins"`
+	WinRate    float64                `json:"win_rate"`   // rounded to three decimals
+	AvgMargin  float64                `json:"avg_margin"` // mean points won by, over this side's wins
+}
+
+// simulateSlots bounds concurrent simulation runs server-wide; it is sized from
+// SimulateMaxConcurrent on first use.
+var (
+	simulateOnce  sync.Once
+	simulateSlots chan struct{}
+)
+
+// SimulateHandler plays a batch of complete two-player games between two bot difficulties in
+// memory (no database, hub or HTTP round-trips) and reports win rates and average margins, for
+// tuning bot play. The first deal alternates between the seats so neither side always has the
+// first crib. Runs are capped at SimulateMaxGames games and SimulateMaxConcurrent at once.
+func SimulateHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, span := tracing.StartSpan(c.Request.Context(), "handlers.SimulateHandler")
+		defer span.End()
+
+		var req simulateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid json"})
+			return
+		}
+		cfg := currentConfig()
+		if req.Games <= 0 || req.Games > cfg.SimulateMaxGames {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("games must be 1-%d", cfg.SimulateMaxGames)})
+			return
+		}
+		a, err := cribbage.ParseBotDifficulty(req.A)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid difficulty for a (expected easy, medium or hard)"})
+			return
+		}
+		b, err := cribbage.ParseBotDifficulty(req.B)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid difficulty for b (expected easy, medium or hard)"})
+			return
+		}
+		rules := cribbage.Rules{MaxPlayers: 2, TargetScore: req.TargetScore, LastCardPoints: req.LastCardPoints}
+		if err := rules.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		simulateOnce.Do(func() {
+			simulateSlots = make(chan struct{}, cfg.SimulateMaxConcurrent)
+		})
+		select {
+		case simulateSlots <- struct{}{}:
+			defer func() { <-simulateSlots }()
+		default:
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "too many simulations running; try again later"})
+			return
+		}
+
+		start := time.Now()
+		bots := []cribbage.BotDifficulty{a, b}
+		sides := []SimulateSide{{Difficulty: a}, {Difficulty: b}}
+		margins := make([]int, 2)
+		hands := 0
+		for g := 0; g < req.Games; g++ {
+			if ctx.Err() != nil {
+				// Client went away; nobody is left to read the result.
+				return
+			}
+			st, winner, err := cribbage.SimulateGame(rules, bots, g%2)
+			if err != nil {
+				log.Printf("SimulateHandler: game %d failed: a=%s b=%s err=%v", g, a, b, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "simulation failed"})
+				return
+			}
+			sides[winner].Wins++
+			margins[winner] += st.Scores[winner] - st.Scores[1-winner]
+			hands += len(st.History)
+		}
+		for i := range sides {
+			sides[i].WinRate = math.Round(float64(sides[i].Wins)/float64(req.Games)*1000) / 1000
+			if sides[i].Wins > 0 {
+				sides[i].AvgMargin = math.Round(float64(margins[i])/float64(sides[i].Wins)*10) / 10
+			}
+		}
+		elapsed := time.Since(start)
+		adminID, _ := userIDFromContext(c)
+		log.Printf("SimulateHandler: admin_id=%d games=%d a=%s b=%s a_wins=%d b_wins=%d elapsed=%s", adminID, req.Games, a, b, sides[0].Wins, sides[1].Wins, elapsed)
+		c.JSON(http.StatusOK, gin.H{
+			"games":      req.Games,
+			"a":          sides[0],
+			"b":          sides[1],
+			"avg_hands":  math.Round(float64(hands)/float64(req.Games)*10) / 10,
+			"elapsed_ms": elapsed.Milliseconds(),
+		})
+	}
+}
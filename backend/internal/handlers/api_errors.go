@@ -36,6 +36,9 @@ func writeAPIError(c *gin.Context, err error) {
 	case errors.Is(err, models.ErrNotAPlayer):
 		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "not a player"})
 		return
+	case errors.Is(err, models.ErrTurnAlreadyAdvanced):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "the turn already moved on", "code": "turn_already_advanced"})
+		return
 	case errors.Is(err, models.ErrNotYourTurn):
 		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "not your turn"})
 		return
@@ -48,6 +51,9 @@ func writeAPIError(c *gin.Context, err error) {
 	case errors.Is(err, models.ErrCardNotInHand):
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "card not in hand"})
 		return
+	case errors.Is(err, models.ErrGameNotStarted):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "game has not started", "code": "game_not_started"})
+		return
 	case errors.Is(err, models.ErrNotInDiscardStage):
 		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "not in discard stage"})
 		return
@@ -60,6 +66,9 @@ func writeAPIError(c *gin.Context, err error) {
 	case errors.Is(err, models.ErrInvalidDiscardCount):
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid discard count"})
 		return
+	case errors.Is(err, models.ErrInvalidCribSize):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "crib would not have exactly 4 cards"})
+		return
 	case errors.Is(err, models.ErrInvalidPlayer):
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid player"})
 		return
@@ -69,18 +78,45 @@ func writeAPIError(c *gin.Context, err error) {
 	case errors.Is(err, models.ErrUnknownMoveType):
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "unknown move type"})
 		return
+	case errors.Is(err, models.ErrNotInCountingStage):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "not in counting stage"})
+		return
+	case errors.Is(err, models.ErrMugginsDisabled):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "muggins is not enabled for this game", "code": "muggins_disabled"})
+		return
+	case errors.Is(err, models.ErrMugginsAlreadyCalled):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "muggins was already called on that count", "code": "muggins_already_called"})
+		return
+	case errors.Is(err, models.ErrUndoUnavailable):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "there is no play to undo", "code": "undo_unavailable"})
+		return
+	case errors.Is(err, models.ErrUndoNotYourPlay):
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "only the player who made the play can undo it", "code": "undo_not_your_play"})
+		return
 	case errors.Is(err, models.ErrHasLegalPlay):
 		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "you have a legal play"})
 		return
 	case errors.Is(err, models.ErrGameStateMissing):
 		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "game state unavailable; recreate lobby"})
 		return
+	case errors.Is(err, models.ErrGameFinished):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "game already finished"})
+		return
 	case errors.Is(err, models.ErrGameStateConflict):
 		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "game state changed; retry"})
 		return
+	case errors.Is(err, models.ErrHandStateMismatch):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "hand out of sync; reload game"})
+		return
+	case errors.Is(err, models.ErrPlayerResigned):
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "you resigned from this game"})
+		return
 	case errors.Is(err, models.ErrLobbyFull):
 		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "lobby full"})
 		return
+	case errors.Is(err, models.ErrLobbyHasActiveGame):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "lobby already has an active game", "code": "lobby_has_active_game"})
+		return
 	case errors.Is(err, models.ErrLobbyNotJoinable):
 		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "lobby not joinable"})
 		return
@@ -45,26 +45,30 @@ func RegisterHandler(db *sql.DB, cfg config.Config) gin.HandlerFunc {
 			return
 		}
 
-		req.Username = strings.TrimSpace(req.Username)
-		uLen := utf8.RuneCountInString(req.Username)
-		if uLen < 3 || uLen > 32 {
+		username, ok := normalizeUsername(req.Username)
+		if !ok {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "username must be 3-32 characters"})
 			return
 		}
+		req.Username = username
 		// Do not TrimSpace passwords: leading/trailing spaces are valid characters.
 		if utf8.RuneCountInString(req.Password) < 8 {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "password must be at least 8 characters"})
 			return
 		}
 
-		if _, err := models.GetUserByUsername(db, req.Username); err == nil {
-			c.JSON(http.StatusConflict, gin.H{"error": "username already taken"})
-			return
-		} else if !errors.Is(err, models.ErrNotFound) {
+		if taken, err := models.UsernameTaken(db, req.Username, 0); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
 			return
+		} else if taken {
+			c.JSON(http.StatusConflict, gin.H{"error": "username already taken"})
+			return
 		}
 
+		if err := auth.CheckPasswordPolicy(cfg.PasswordPolicy, req.Password, req.Username); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		hash, err := auth.HashPassword(req.Password)
 		if err != nil {
 			if auth.IsPasswordValidationError(err) {
@@ -84,8 +88,10 @@ func RegisterHandler(db *sql.DB, cfg config.Config) gin.HandlerFunc {
 			return
 		}
 
-		// Create default preferences (best-effort).
-		_ = models.SetUserAutoCountMode(db, u.ID, "suggest")
+		// Seed default preferences (best-effort; reads fall back to the same defaults).
+		if err := models.InitDefaultPreferences(db, u.ID); err != nil {
+			log.Printf("RegisterHandler: InitDefaultPreferences failed: user_id=%d err=%v", u.ID, err)
+		}
 
 		token, err := auth.GenerateToken(u.ID, u.Username, cfg)
 		if err != nil {
@@ -98,6 +104,14 @@ func RegisterHandler(db *sql.DB, cfg config.Config) gin.HandlerFunc {
 	}
 }
 
+// normalizeUsername trims raw and reports whether the result is a valid username (3-32
+// characters).
+func normalizeUsername(raw string) (string, bool) {
+	username := strings.TrimSpace(raw)
+	n := utf8.RuneCountInString(username)
+	return username, n >= 3 && n <= 32
+}
+
 func LoginHandler(db *sql.DB, cfg config.Config) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		_, span := tracing.StartSpan(c.Request.Context(), "handlers.LoginHandler")
@@ -134,6 +148,11 @@ func LoginHandler(db *sql.DB, cfg config.Config) gin.HandlerFunc {
 		// Always run bcrypt comparison exactly once per request to normalize timing.
 		// Return 401 only for invalid credentials (including user-not-found after timing-normalized compare).
 		if cmpErr := auth.ComparePasswordHash(pwHash, req.Password); cmpErr != nil || !userFound {
+			var uid *int64
+			if userFound {
+				uid = &u.ID
+			}
+			recordAccess(db, c, "login", false, uid, req.Username)
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
 			return
 		}
@@ -144,6 +163,7 @@ func LoginHandler(db *sql.DB, cfg config.Config) gin.HandlerFunc {
 			return
 		}
 		setAuthCookie(c, cfg, token)
+		recordAccess(db, c, "login", true, &u.ID, u.Username)
 		c.JSON(http.StatusOK, authResponse{Token: token, User: u})
 	}
 }
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"fifteen-thirty-one-go/backend/internal/game/cribbage"
+	"fifteen-thirty-one-go/backend/internal/models"
+	"fifteen-thirty-one-go/backend/internal/tracing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bestHandForSeat picks the seat's highest-scoring counted hand (not crib) from the round
+// history; the earliest round wins ties. ok is false if the seat never had a hand counted.
+func bestHandForSeat(gameID, userID int64, seat int, history []cribbage.RoundSummary) (models.BestHand, bool) {
+	var best models.BestHand
+	found := false
+	for _, round := range history {
+		b, counted := round.Hands[seat]
+		cards := round.Kept[seat]
+		if !counted || round.Cut == nil || len(cards) == 0 {
+			continue
+		}
+		if found && int64(b.Total) <= best.Points {
+			continue
+		}
+		breakdown, err := json.Marshal(b)
+		if err != nil {
+			continue
+		}
+		best = models.BestHand{
+			GameID:    gameID,
+			UserID:    userID,
+			Round:     round.Round,
+			Cards:     cardCodes(cards),
+			Cut:       round.Cut.String(),
+			Points:    int64(b.Total),
+			Breakdown: breakdown,
+		}
+		found = true
+	}
+	return best, found
+}
+
+// BestHandsHandler returns a user's highest-scoring hands across completed games, best first.
+// ?limit=N selects how many (default 5, max 50).
+func BestHandsHandler(db *sql.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, span := tracing.StartSpan(c.Request.Context(), "handlers.BestHandsHandler")
+		defer span.End()
+
+		userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || userID <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+			return
+		}
+		limit := 5
+		if v := c.Query("limit"); v != "" {
+			limit, err = strconv.Atoi(v)
+			if err != nil || limit <= 0 || limit > 50 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be 1-50"})
+				return
+			}
+		}
+		if _, err := models.GetUserByID(db, userID); err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		hands, err := models.ListBestHands(ctx, db, userID, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"user_id": userID, "hands": hands})
+	}
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"log"
+	"sync"
+
+	"fifteen-thirty-one-go/backend/internal/game/cribbage"
+)
+
+// heavyBotSlots bounds concurrent hard-bot EV discards server-wide. It is sized from
+// BotEVMaxConcurrent and rebuilt when a new runtime config changes that limit; bots already
+// thinking release into the pool they took a slot from.
+var (
+	heavyBotMu    sync.Mutex
+	heavyBotSlots chan struct{}
+)
+
+// heavyBotPool returns the slot pool for the current BotEVMaxConcurrent.
+func heavyBotPool() chan struct{} {
+	n := currentConfig().BotEVMaxConcurrent
+	heavyBotMu.Lock()
+	defer heavyBotMu.Unlock()
+	if heavyBotSlots == nil || cap(heavyBotSlots) != n {
+		heavyBotSlots = make(chan struct{}, n)
+	}
+	return heavyBotSlots
+}
+
+// acquireBotDifficulty returns the difficulty a bot should think at and a release func. Hard
+// bots take a slot for their EV computation; when every slot is busy they fall back to the
+// medium heuristic rather than queueing, so a burst of bot games can't monopolize the CPU.
+func acquireBotDifficulty(gameID int64, d cribbage.BotDifficulty) (cribbage.BotDifficulty, func()) {
+	if d != cribbage.BotHard {
+		return d, func() {}
+	}
+	slots := heavyBotPool()
+	select {
+	case slots <- struct{}{}:
+		return d, func() { <-slots }
+	default:
+		log.Printf("bot EV limit reached (%d in flight); falling back to medium: game_id=%d", cap(slots), gameID)
+		return cribbage.BotMedium, func() {}
+	}
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"testing"
+
+	"fifteen-thirty-one-go/backend/internal/config"
+	"fifteen-thirty-one-go/backend/internal/game/cribbage"
+)
+
+func TestBotEVLimitFollowsConfigReload(t *testing.T) {
+	prev := currentConfig()
+	t.Cleanup(func() { SetRuntimeConfig(prev) })
+	setTestConfig(t, func(c *config.Config) { c.BotEVMaxConcurrent = 1 })
+
+	d, release := acquireBotDifficulty(1, cribbage.BotHard)
+	if d != cribbage.BotHard {
+		t.Fatalf("first hard bot thinks at %v, want hard", d)
+	}
+	if d, _ := acquireBotDifficulty(2, cribbage.BotHard); d != cribbage.BotMedium {
+		t.Fatalf("second hard bot with one slot thinks at %v, want medium", d)
+	}
+
+	setTestConfig(t, func(c *config.Config) { c.BotEVMaxConcurrent = 2 })
+	d2, release2 := acquireBotDifficulty(3, cribbage.BotHard)
+	d3, release3 := acquireBotDifficulty(4, cribbage.BotHard)
+	if d2 != cribbage.BotHard || d3 != cribbage.BotHard {
+		t.Fatalf("after raising the limit to 2, hard bots think at %v/%v, want hard/hard", d2, d3)
+	}
+	if d, _ := acquireBotDifficulty(5, cribbage.BotHard); d != cribbage.BotMedium {
+		t.Errorf("third hard bot with two slots thinks at %v, want medium", d)
+	}
+	// The bot from before the reload releases into its old pool without touching the new one.
+	release()
+	if d, _ := acquireBotDifficulty(6, cribbage.BotHard); d != cribbage.BotMedium {
+		t.Errorf("old-pool release freed a new slot: thinks at %v, want medium", d)
+	}
+	release2()
+	release3()
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+
+	"fifteen-thirty-one-go/backend/internal/game/common"
+	"fifteen-thirty-one-go/backend/internal/game/cribbage"
+	"fifteen-thirty-one-go/backend/internal/models"
+)
+
+// handStartMoveID returns the move id that opens the current hand's counting window. Every hand
+// starts with discards, so anything recorded after the latest discard belongs to this hand.
+func handStartMoveID(db *sql.DB, gameID int64) (int64, error) {
+	return models.LatestMoveIDOfType(db, gameID, "discard")
+}
+
+// finalCountMoves lists the final count move types a seat owes in the counting stage.
+func finalCountMoves(pos, dealerIndex int) []string {
+	if pos == dealerIndex {
+		return []string{"count_hand_final", "count_crib_final"}
+	}
+	return []string{"count_hand_final"}
+}
+
+// missingFinalCounts returns the final count move types the player has not yet recorded this hand.
+func missingFinalCounts(db *sql.DB, gameID, playerID int64, pos, dealerIndex int) ([]string, error) {
+	since, err := handStartMoveID(db, gameID)
+	if err != nil {
+		return nil, err
+	}
+	var missing []string
+	for _, mt := range finalCountMoves(pos, dealerIndex) {
+		done, err := models.HasUncorrectedMoveTypeSince(db, gameID, playerID, mt, since)
+		if err != nil {
+			return nil, err
+		}
+		if !done {
+			missing = append(missing, mt)
+		}
+	}
+	return missing, nil
+}
+
+// autoCountBots records correct final counts for every bot seat that still owes one this hand.
+// The engine already applied hand and crib scores when the round finished; these moves only
+// complete the move log so bots never hold up counting.
+func autoCountBots(db *sql.DB, gameID int64, players []models.GamePlayer) error {
+	if !currentConfig().BotAutoCount {
+		return nil
+	}
+	st, unlock, ok := defaultGameManager.GetLocked(gameID)
+	if !ok {
+		return nil
+	}
+	if st.Stage != "counting" || st.Cut == nil {
+		unlock()
+		return nil
+	}
+	cut := *st.Cut
+	dealerIndex := st.DealerIndex
+	rules := st.Rules
+	keptHands := make([][]common.Card, len(st.KeptHands))
+	for i := range st.KeptHands {
+		keptHands[i] = append([]common.Card(nil), st.KeptHands[i]...)
+	}
+	crib := append([]common.Card(nil), st.Crib...)
+	unlock()
+
+	for _, p := range players {
+		if !p.IsBot {
+			continue
+		}
+		pos := int(p.Position)
+		if pos < 0 || pos >= len(keptHands) {
+			continue
+		}
+		missing, err := missingFinalCounts(db, gameID, p.UserID, pos, dealerIndex)
+		if err != nil {
+			return err
+		}
+		for _, mt := range missing {
+			var verified int64
+			if mt == "count_crib_final" {
+				verified = int64(cribbage.ScoreHandRules(crib, cut, true, rules).Total)
+			} else {
+				verified = int64(cribbage.ScoreHand(keptHands[pos], cut, false).Total)
+			}
+			claim := verified
+			if _, err := models.InsertMove(db, models.GameMove{
+				GameID:        gameID,
+				PlayerID:      p.UserID,
+				MoveType:      mt,
+				ScoreClaimed:  &claim,
+				ScoreVerified: &verified,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// CountDuty is one final count a player owes in the counting stage.
+type CountDuty struct {
+	Kind      string `json:"kind"` // hand|crib
+	Finalized bool   `json:"finalized"`
+	Claimed   *int64 `json:"claimed,omitempty"`
+	Verified  *int64 `json:"verified,omitempty"`
+}
+
+// CountProgress summarizes a player's counting for the current hand from the move log, which
+// (unlike the engine state) records which final counts are already in. Reconnecting clients
+// use it to skip counts the player already submitted, including across server restarts.
+type CountProgress struct {
+	// Manual is false when the player's counts are recorded for them (auto-count or bot);
+	// their duties are then informational and never block the next hand.
+	Manual bool        `json:"manual"`
+	Duties []CountDuty `json:"duties"`
+	Done   bool        `json:"done"`
+}
+
+// countProgress builds the player's CountProgress for the hand in counting.
+func countProgress(db *sql.DB, gameID, userID int64, pos, dealerIndex int, manual bool) (*CountProgress, error) {
+	since, err := handStartMoveID(db, gameID)
+	if err != nil {
+		return nil, err
+	}
+	p := &CountProgress{Manual: manual, Done: true}
+	for _, mt := range finalCountMoves(pos, dealerIndex) {
+		d := CountDuty{Kind: "hand"}
+		if mt == "count_crib_final" {
+			d.Kind = "crib"
+		}
+		m, err := models.LatestUncorrectedMoveSince(db, gameID, userID, mt, since)
+		switch {
+		case err == nil:
+			d.Finalized = true
+			d.Claimed = m.ScoreClaimed
+			d.Verified = m.ScoreVerified
+		case errors.Is(err, models.ErrNotFound):
+			p.Done = false
+		default:
+			return nil, err
+		}
+		p.Duties = append(p.Duties, d)
+	}
+	return p, nil
+}
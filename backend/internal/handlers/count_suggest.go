@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"fifteen-thirty-one-go/backend/internal/game/common"
+	"fifteen-thirty-one-go/backend/internal/game/cribbage"
+	"fifteen-thirty-one-go/backend/internal/models"
+)
+
+// CountSuggestion is the engine's count offered to players in "suggest" auto-count mode. It is
+// advice only: the player still submits, and is credited with, their own final claim.
+type CountSuggestion struct {
+	Score        int64  `json:"score"`
+	ClaimMatches bool   `json:"claim_matches"`
+	Explanation  string `json:"explanation,omitempty"` // summary and detailed verbosity
+	// Items lists every scoring combination (detailed verbosity only).
+	Items []SuggestionItem `json:"items,omitempty"`
+}
+
+// SuggestionItem is one scoring combination, e.g. a fifteen made by 7H and 8C.
+type SuggestionItem struct {
+	Kind   string   `json:"kind"` // fifteen|pair|run|flush|nobs
+	Cards  []string `json:"cards"`
+	Points int      `json:"points"`
+}
+
+// countSuggestion builds the suggestion for a counted hand or crib at the given verbosity
+// (models.SuggestVerbosity*); unknown values fall back to the summary.
+func countSuggestion(verbosity string, cards []common.Card, cut common.Card, isCrib bool, rules cribbage.Rules, b cribbage.ScoreBreakdown, claim int64) *CountSuggestion {
+	s := &CountSuggestion{Score: int64(b.Total), ClaimMatches: claim == int64(b.Total)}
+	if verbosity == models.SuggestVerbosityScore {
+		return s
+	}
+	s.Explanation = breakdownSummary(b)
+	if verbosity == models.SuggestVerbosityDetailed {
+		for _, it := range cribbage.ExplainHand(cards, cut, isCrib, rules) {
+			s.Items = append(s.Items, SuggestionItem{Kind: it.Kind, Cards: cardCodes(it.Cards), Points: it.Points})
+		}
+	}
+	return s
+}
+
+// breakdownSummary renders a breakdown as e.g. "fifteens 4, pairs 2, runs 3 = 9".
+func breakdownSummary(b cribbage.ScoreBreakdown) string {
+	if b.Total == 0 {
+		return "nothing scores = 0"
+	}
+	var parts []string
+	for _, p := range []struct {
+		name   string
+		points int
+	}{{"fifteens", b.Fifteens}, {"pairs", b.Pairs}, {"runs", b.Runs}, {"flush", b.Flush}, {"nobs", b.Nobs}} {
+		if p.points > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", p.name, p.points))
+		}
+	}
+	return fmt.Sprintf("%s = %d", strings.Join(parts, ", "), b.Total)
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"database/sql"
+	"log"
+
+	"fifteen-thirty-one-go/backend/internal/models"
+)
+
+// CountingTurn names the next count the table is waiting on.
+type CountingTurn struct {
+	UserID int64  `json:"user_id"`
+	Kind   string `json:"kind"` // hand|crib
+}
+
+// countingOrder returns the seats' user ids in official counting order (left of the dealer
+// round to the dealer, whose crib is counted last) and the first count still owed. Only humans
+// who count manually owe counts; everyone else's hand is scored by the engine, so they are
+// never next. next is nil once every owed count is in.
+func countingOrder(db *sql.DB, gameID int64, players []models.GamePlayer, dealerIndex int) ([]int64, *CountingTurn) {
+	bySeat := map[int]models.GamePlayer{}
+	for _, p := range players {
+		bySeat[int(p.Position)] = p
+	}
+	n := len(players)
+	order := make([]int64, 0, n)
+	var next *CountingTurn
+	for off := 1; off <= n; off++ {
+		pos := (dealerIndex + off) % n
+		p, ok := bySeat[pos]
+		if !ok {
+			continue
+		}
+		order = append(order, p.UserID)
+		if next != nil || p.IsBot {
+			continue
+		}
+		prefs, err := models.GetUserPreferences(db, p.UserID)
+		if err != nil {
+			log.Printf("countingOrder: GetUserPreferences failed: user_id=%d err=%v", p.UserID, err)
+			continue
+		}
+		if prefs.AutoCountMode != "off" {
+			continue
+		}
+		missing, err := missingFinalCounts(db, gameID, p.UserID, pos, dealerIndex)
+		if err != nil {
+			log.Printf("countingOrder: missingFinalCounts failed: game_id=%d user_id=%d err=%v", gameID, p.UserID, err)
+			continue
+		}
+		// Hands are counted before the crib, so a dealer owing both counts their hand first.
+		for _, mt := range missing {
+			kind := "hand"
+			if mt == "count_crib_final" {
+				kind = "crib"
+			}
+			next = &CountingTurn{UserID: p.UserID, Kind: kind}
+			break
+		}
+	}
+	return order, next
+}
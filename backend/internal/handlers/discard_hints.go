@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+
+	"fifteen-thirty-one-go/backend/internal/game/common"
+	"fifteen-thirty-one-go/backend/internal/game/cribbage"
+	"fifteen-thirty-one-go/backend/internal/models"
+	"fifteen-thirty-one-go/backend/internal/tracing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DiscardHint describes one possible discard and how the kept hand scores across the cuts
+// still unseen by the player.
+type DiscardHint struct {
+	Keep    []string `json:"keep"`
+	Discard []string `json:"discard"`
+	Average float64  `json:"average"` // rounded to one decimal
+	Best    int      `json:"best"`
+	Worst   int      `json:"worst"`
+	Summary string   `json:"summary"` // e.g. "best cut gives 12, average 6.2"
+}
+
+// DiscardHintsHandler returns, best first, every way the caller could discard from their dealt
+// hand with the kept hand's score range over the remaining cut cards. Hints are private to the
+// requesting player, only offered before they discard, and only to players who enabled the
+// cut_hints preference.
+func DiscardHintsHandler(db *sql.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, span := tracing.StartSpan(c.Request.Context(), "handlers.DiscardHintsHandler")
+		defer span.End()
+
+		gameID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || gameID <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid game id"})
+			return
+		}
+		userID, ok := userIDFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		prefs, err := models.GetUserPreferences(db, userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		if !prefs.CutHints {
+			c.JSON(http.StatusForbidden, gin.H{"error": "discard hints are off; enable cut_hints in preferences"})
+			return
+		}
+
+		players, err := models.ListGamePlayersByGame(db, gameID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		pos := -1
+		for _, p := range players {
+			if p.UserID == userID {
+				pos = int(p.Position)
+			}
+		}
+		if pos < 0 {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+		st, unlock, err := ensureGameStateLocked(db, gameID, players)
+		if err != nil {
+			if errors.Is(err, models.ErrGameStateMissing) {
+				c.JSON(http.StatusConflict, gin.H{"error": "game state unavailable; recreate lobby"})
+				return
+			}
+			log.Printf("DiscardHintsHandler: ensureGameStateLocked failed: game_id=%d err=%v", gameID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		if st.Stage != "discard" || pos >= len(st.Hands) || (pos < len(st.DiscardCompleted) && st.DiscardCompleted[pos]) {
+			unlock()
+			c.JSON(http.StatusConflict, gin.H{"error": "discard hints are only available before you discard"})
+			return
+		}
+		hand := append([]common.Card(nil), st.Hands[pos]...)
+		discardCount := st.Rules.DiscardCount()
+		unlock()
+
+		// The enumeration runs outside the state lock.
+		opts := cribbage.DiscardOptions(hand, discardCount)
+		hints := make([]DiscardHint, 0, len(opts))
+		for _, o := range opts {
+			avg := math.Round(o.EV*10) / 10
+			hints = append(hints, DiscardHint{
+				Keep:    cardCodes(o.Keep),
+				Discard: cardCodes(o.Discard),
+				Average: avg,
+				Best:    o.Best,
+				Worst:   o.Worst,
+				Summary: fmt.Sprintf("best cut gives %d, average %.1f", o.Best, avg),
+			})
+		}
+		c.JSON(http.StatusOK, gin.H{"game_id": gameID, "hints": hints})
+	}
+}
@@ -1,13 +1,17 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"fifteen-thirty-one-go/backend/internal/game/common"
 	"fifteen-thirty-one-go/backend/internal/game/cribbage"
@@ -24,8 +28,15 @@ type moveRequest struct {
 	// play_card: card
 	Cards []string `json:"cards,omitempty"`
 	Card  string   `json:"card,omitempty"`
+
+	// timedOut marks a move the turn timer made for a player whose time ran out; it can't be
+	// set by clients.
+	timedOut bool
 }
 
+// GetGameHandler returns the game snapshot. Players get their own view; spectators get the
+// public one, and after the game ends only for SpectatorReviewWindow, so they can go over the
+// final board that the finished stage reveals.
 func GetGameHandler(db *sql.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		_, span := tracing.StartSpan(c.Request.Context(), "handlers.GetGameHandler")
@@ -41,7 +52,20 @@ func GetGameHandler(db *sql.DB) gin.HandlerFunc {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 			return
 		}
-		snap, err := BuildGameSnapshotForUser(db, gameID, userID)
+		inGame, err := models.IsUserInGame(db, userID, gameID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		var snap *GameSnapshot
+		if inGame {
+			snap, err = BuildGameSnapshotForUser(db, gameID, userID)
+		} else {
+			if !allowSpectatorView(c, db, userID, gameID) {
+				return
+			}
+			snap, err = BuildGameSnapshotPublic(db, gameID)
+		}
 		if err != nil {
 			if errors.Is(err, models.ErrNotFound) {
 				c.JSON(http.StatusNotFound, gin.H{"error": "game not found"})
@@ -51,14 +75,41 @@ func GetGameHandler(db *sql.DB) gin.HandlerFunc {
 				c.JSON(http.StatusConflict, gin.H{"error": "game state unavailable; recreate lobby"})
 				return
 			}
-			log.Printf("GetGameHandler BuildGameSnapshotForUser failed: game_id=%d user_id=%d err=%v", gameID, userID, err)
+			log.Printf("GetGameHandler: build snapshot failed: game_id=%d user_id=%d err=%v", gameID, userID, err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
 			return
 		}
+		if !inGame && !applySpectatorFollow(c, snap) {
+			return
+		}
 		c.JSON(http.StatusOK, snap)
 	}
 }
 
+// allowSpectatorView reports whether a non-player may load the game: they must spectate it and,
+// once it is finished, be within SpectatorReviewWindow of the end. Otherwise it writes the error.
+func allowSpectatorView(c *gin.Context, db *sql.DB, userID, gameID int64) bool {
+	spectating, err := models.IsUserSpectatingGame(db, userID, gameID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+		return false
+	}
+	if !spectating {
+		c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		return false
+	}
+	g, err := models.GetGameByID(db, gameID)
+	if err != nil {
+		writeAPIError(c, err)
+		return false
+	}
+	if g.Status == "finished" && (g.FinishedAt == nil || time.Since(*g.FinishedAt) > currentConfig().SpectatorReviewWindow) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "spectator review window has closed", "code": "review_window_closed"})
+		return false
+	}
+	return true
+}
+
 func MoveHandler(db *sql.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		_, span := tracing.StartSpan(c.Request.Context(), "handlers.MoveHandler")
@@ -100,12 +151,32 @@ func MoveHandler(db *sql.DB) gin.HandlerFunc {
 	}
 }
 
+// countRequest deliberately has no player field: a hand count is always attributed to the
+// requester's own seat (and a crib count to the dealer's crib), and bindCountRequest rejects
+// bodies that try to name anyone else.
 type countRequest struct {
 	Kind  string `json:"kind"` // hand|crib
 	Claim int64  `json:"claim"`
 	Final bool   `json:"final"`
 }
 
+// errCountUnknownField reports a count body carrying fields countRequest doesn't define.
+var errCountUnknownField = errors.New("unknown field in count request")
+
+// bindCountRequest decodes a count body strictly, so fields such as a target player or seat
+// are refused rather than silently ignored.
+func bindCountRequest(r io.Reader, req *countRequest) error {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(req); err != nil {
+		if strings.HasPrefix(err.Error(), "json: unknown field") {
+			return fmt.Errorf("%w: %v", errCountUnknownField, err)
+		}
+		return err
+	}
+	return nil
+}
+
 func CountHandler(db *sql.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		_, span := tracing.StartSpan(c.Request.Context(), "handlers.CountHandler")
@@ -123,10 +194,19 @@ func CountHandler(db *sql.DB) gin.HandlerFunc {
 		}
 
 		var req countRequest
-		if err := c.ShouldBindJSON(&req); err != nil {
+		if err := bindCountRequest(c.Request.Body, &req); err != nil {
+			if errors.Is(err, errCountUnknownField) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "counts are always for your own hand (or your crib as dealer)", "code": "count_unknown_field"})
+				return
+			}
 			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid json"})
 			return
 		}
+		// Hand and crib share the same ceiling; reject garbage claims before they reach game_moves.
+		if req.Claim < 0 || req.Claim > cribbage.MaxHandScore {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "claim out of range", "code": "claim_out_of_range"})
+			return
+		}
 
 		st, unlock, ok := defaultGameManager.GetLocked(gameID)
 		if !ok {
@@ -146,6 +226,7 @@ func CountHandler(db *sql.DB) gin.HandlerFunc {
 		// Copy the minimal read-only fields we need, then release the lock before DB work.
 		cut := *st.Cut
 		dealerIndex := st.DealerIndex
+		rules := st.Rules
 		keptHands := make([][]common.Card, len(st.KeptHands))
 		for i := range st.KeptHands {
 			keptHands[i] = append([]common.Card(nil), st.KeptHands[i]...)
@@ -170,8 +251,7 @@ func CountHandler(db *sql.DB) gin.HandlerFunc {
 			return
 		}
 
-		var verified int64
-		var breakdown any
+		var counted []common.Card
 		switch req.Kind {
 		case "hand":
 			posIdx := int(pos)
@@ -179,21 +259,20 @@ func CountHandler(db *sql.DB) gin.HandlerFunc {
 				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid player position"})
 				return
 			}
-			bd := cribbage.ScoreHand(keptHands[posIdx], cut, false)
-			verified = int64(bd.Total)
-			breakdown = bd
+			counted = keptHands[posIdx]
 		case "crib":
 			if int(pos) != dealerIndex {
 				c.JSON(http.StatusForbidden, gin.H{"error": "only dealer counts crib"})
 				return
 			}
-			bd := cribbage.ScoreHand(crib, cut, true)
-			verified = int64(bd.Total)
-			breakdown = bd
+			counted = crib
 		default:
 			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid kind"})
 			return
 		}
+		isCrib := req.Kind == "crib"
+		breakdown := cribbage.ScoreHandRules(counted, cut, isCrib, rules)
+		verified := int64(breakdown.Total)
 
 		claim := req.Claim
 		mt := "count_" + req.Kind
@@ -201,9 +280,14 @@ func CountHandler(db *sql.DB) gin.HandlerFunc {
 			mt = mt + "_final"
 		}
 		if req.Final {
-			// Prevent duplicate final submissions for the same player/game/type.
+			// Prevent duplicate final submissions for the same player/hand/type.
 			// Corrections should use the correction flow which marks the original as corrected.
-			exists, err := models.HasUncorrectedMoveType(db, gameID, userID, mt)
+			since, err := handStartMoveID(db, gameID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+				return
+			}
+			exists, err := models.HasUncorrectedMoveTypeSince(db, gameID, userID, mt, since)
 			if err != nil {
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
 				return
@@ -230,7 +314,11 @@ func CountHandler(db *sql.DB) gin.HandlerFunc {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
 			return
 		}
-		c.JSON(http.StatusOK, gin.H{"verified": verified, "breakdown": breakdown, "auto_count_mode": prefs.AutoCountMode})
+		resp := gin.H{"verified": verified, "breakdown": breakdown, "auto_count_mode": prefs.AutoCountMode}
+		if prefs.AutoCountMode == "suggest" {
+			resp["suggestion"] = countSuggestion(prefs.SuggestVerbosity, counted, cut, isCrib, rules, breakdown, claim)
+		}
+		c.JSON(http.StatusOK, resp)
 	}
 }
 
@@ -270,11 +358,24 @@ func QuitGameHandler(db *sql.DB) gin.HandlerFunc {
 			return
 		}
 
+		// Quitting a match game walks away from the whole match, and from the lobby's series.
+		if g.Status != "finished" {
+			if err := NewMatchManager(db).Forfeit(gameID, userID); err != nil {
+				log.Printf("QuitGameHandler: match forfeit failed: game_id=%d user_id=%d err=%v", gameID, userID, err)
+			}
+			if err := models.LeaveLobbySeries(db, g.LobbyID); err != nil {
+				log.Printf("QuitGameHandler: %v", err)
+			}
+		}
 		// Best-effort: mark game and lobby finished. This gives the UI a clean terminal state.
 		_ = models.SetGameStatus(db, gameID, "finished")
 		_ = models.SetLobbyStatus(db, g.LobbyID, "finished")
+		activeLobbies.invalidate()
 
 		// Drop in-memory runtime state so a future game doesn't accidentally reuse it.
+		if err := flushGameState(db, gameID); err != nil {
+			log.Printf("QuitGameHandler: flushGameState failed: game_id=%d err=%v", gameID, err)
+		}
 		defaultGameManager.Delete(gameID)
 
 		broadcastGameUpdate(db, gameID)
@@ -282,6 +383,165 @@ func QuitGameHandler(db *sql.DB) gin.HandlerFunc {
 	}
 }
 
+// ResignGameHandler lets a player resign. Depending on the configured resign policy, the seat is
+// handed to a bot of ResignBotDifficulty so the remaining players can continue (when more than two
+// humans remain), or the game ends for everyone as the resigner's forfeit, with the standings
+// recorded at the current scores. Resigned players always rank last.
+func ResignGameHandler(db *sql.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, span := tracing.StartSpan(c.Request.Context(), "handlers.ResignGameHandler")
+		defer span.End()
+
+		gameID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || gameID <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid game id"})
+			return
+		}
+		userID, ok := userIDFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		g, err := models.GetGameByID(db, gameID)
+		if err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "game not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		if g.Status == "finished" {
+			c.JSON(http.StatusConflict, gin.H{"error": "game already finished"})
+			return
+		}
+
+		players, err := models.ListGamePlayersByGame(db, gameID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		var me *models.GamePlayer
+		humans := 0
+		for i := range players {
+			if players[i].UserID == userID {
+				me = &players[i]
+			}
+			if (!players[i].IsBot || players[i].BotTakeover) && !players[i].Resigned {
+				humans++
+			}
+		}
+		if me == nil || (me.IsBot && !me.Resigned && !me.BotTakeover) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "not a player"})
+			return
+		}
+		if me.Resigned {
+			c.JSON(http.StatusConflict, gin.H{"error": "already resigned"})
+			return
+		}
+
+		// Seats are dealt for a fixed player count, so rather than reshaping the engine state
+		// we keep the seat and let a bot play it out.
+		cfg := currentConfig()
+		takeover := cfg.ResignPolicy == "bot_takeover" && humans > 2
+
+		var botDiff *string
+		if takeover {
+			d := cfg.ResignBotDifficulty
+			botDiff = &d
+		} else {
+			// The forfeit writes the scoreboard; keep maybeFinalizeGame from racing it.
+			defer lockFinalize(gameID)()
+		}
+		if err := resignSeat(c.Request.Context(), db, g, userID, players, botDiff); err != nil {
+			writeAPIError(c, err)
+			return
+		}
+
+		spectating := false
+		if takeover {
+			if cfg.ResignToSpectator {
+				spectating = spectateAfterResign(c.Request.Context(), db, g.LobbyID, gameID, userID)
+			}
+			// The new bot may be on turn (or still owe a discard).
+			if err := maybeRunBotTurns(db, gameID); err != nil {
+				log.Printf("maybeRunBotTurns failed after resign: game_id=%d err=%v", gameID, err)
+			}
+			if err := maybeFinalizeGame(c.Request.Context(), db, gameID); err != nil {
+				log.Printf("maybeFinalizeGame failed after resign: game_id=%d err=%v", gameID, err)
+			}
+		} else {
+			activeLobbies.invalidate()
+			// Drop in-memory runtime state so a future game doesn't accidentally reuse it.
+			if err := flushGameState(db, gameID); err != nil {
+				log.Printf("ResignGameHandler: flushGameState failed: game_id=%d err=%v", gameID, err)
+			}
+			defaultGameManager.Delete(gameID)
+		}
+
+		broadcastGameUpdate(db, gameID)
+		c.JSON(http.StatusOK, gin.H{"resigned": true, "game_continues": takeover, "spectating": spectating})
+	}
+}
+
+// resignSeat records userID's resignation, handing the seat to a bot at botDiff or, when botDiff
+// is nil, finishing the game as their forfeit. It commits against the engine state version like a
+// move does, so a move in flight for the seat retries and finds the player resigned instead of
+// landing on a seat that already belongs to a bot. A game the engine already finished is left to
+// maybeFinalizeGame and reported as ErrGameFinished.
+func resignSeat(ctx context.Context, db *sql.DB, g *models.Game, userID int64, players []models.GamePlayer, botDiff *string) error {
+	const maxAttempts = 3
+
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		st, unlock, err := ensureGameStateLocked(db, g.ID, players)
+		if err != nil {
+			return err
+		}
+		baseVersion := st.Version
+		working := cloneStateDeep(st)
+		working.Version = baseVersion
+		unlock()
+		if working.Stage == "finished" {
+			return models.ErrGameFinished
+		}
+
+		applied, err := func() (bool, error) {
+			tx, err := db.BeginTx(ctx, nil)
+			if err != nil {
+				return false, err
+			}
+			defer tx.Rollback()
+			if err := models.ResignPlayerTx(tx, g.ID, userID, botDiff); err != nil {
+				return false, err
+			}
+			// A player leaving resets the lobby's series even when a bot plays their seat out.
+			if err := models.LeaveLobbySeriesTx(tx, g.LobbyID); err != nil {
+				return false, err
+			}
+			if botDiff == nil {
+				if err := recordStandingsTx(ctx, tx, g.ID, players, working.Scores, map[int64]bool{userID: true}, models.OutcomeForfeit); err != nil {
+					return false, err
+				}
+				if err := models.SetGameStatusTx(tx, g.ID, "finished"); err != nil {
+					return false, err
+				}
+				if err := models.SetLobbyStatusTx(tx, g.LobbyID, "finished"); err != nil {
+					return false, err
+				}
+				if err := NewMatchManager(db).ForfeitTx(tx, g.ID, userID, players); err != nil {
+					return false, fmt.Errorf("match forfeit: %w", err)
+				}
+			}
+			return commitStateTx(db, tx, g.ID, baseVersion, &working)
+		}()
+		if err != nil || applied {
+			return err
+		}
+	}
+	return models.ErrGameStateConflict
+}
+
 func NextHandHandler(db *sql.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		_, span := tracing.StartSpan(c.Request.Context(), "handlers.NextHandHandler")
@@ -306,12 +566,39 @@ func NextHandHandler(db *sql.DB) gin.HandlerFunc {
 			c.JSON(http.StatusForbidden, gin.H{"error": "not a player"})
 			return
 		}
+		if err := flushGameState(db, gameID); err != nil {
+			log.Printf("NextHandHandler: flushGameState failed: game_id=%d err=%v", gameID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
 
 		players, err := models.ListGamePlayersByGame(db, gameID)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
 			return
 		}
+		// Players who count manually must record their final counts before readying up. Look
+		// up both possible duties now so no DB work happens under the state lock.
+		var countedHand, countedCrib bool
+		prefs, err := models.GetUserPreferences(db, userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		manualCount := prefs.AutoCountMode == "off"
+		if manualCount {
+			since, err := handStartMoveID(db, gameID)
+			if err == nil {
+				countedHand, err = models.HasUncorrectedMoveTypeSince(db, gameID, userID, "count_hand_final", since)
+			}
+			if err == nil {
+				countedCrib, err = models.HasUncorrectedMoveTypeSince(db, gameID, userID, "count_crib_final", since)
+			}
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+				return
+			}
+		}
 		st, unlock, err := ensureGameStateLocked(db, gameID, players)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "game not ready"})
@@ -340,6 +627,20 @@ func NextHandHandler(db *sql.DB) gin.HandlerFunc {
 		if st.ReadyNextHand == nil || len(st.ReadyNextHand) != st.Rules.MaxPlayers {
 			st.ReadyNextHand = make([]bool, st.Rules.MaxPlayers)
 		}
+		if manualCount && !st.ReadyNextHand[myPos] {
+			var missing []string
+			if !countedHand {
+				missing = append(missing, "hand")
+			}
+			if myPos == st.DealerIndex && !countedCrib {
+				missing = append(missing, "crib")
+			}
+			if len(missing) > 0 {
+				unlock()
+				c.JSON(http.StatusConflict, gin.H{"error": "submit your final counts first", "code": "counts_required", "missing": missing})
+				return
+			}
+		}
 		// Toggle readiness so users can un-ready if clicked accidentally.
 		st.ReadyNextHand[myPos] = !st.ReadyNextHand[myPos]
 		for _, p := range players {
@@ -363,6 +664,7 @@ func NextHandHandler(db *sql.DB) gin.HandlerFunc {
 		}
 
 		baseVersion := st.Version
+		eventsBefore := st.LastEventSeq()
 		working := cloneStateDeep(st)
 		working.Version = baseVersion
 		unlock()
@@ -427,6 +729,7 @@ func NextHandHandler(db *sql.DB) gin.HandlerFunc {
 			unlock2()
 		}
 
+		broadcastGameEvents(db, gameID, newGameEventBatch(&working, eventsBefore))
 		broadcastGameUpdate(db, gameID)
 		c.Status(http.StatusNoContent)
 	}
@@ -476,7 +779,8 @@ func CorrectHandler(db *sql.DB) gin.HandlerFunc {
 		}
 		isHost := false
 		var hostID int64
-		if err := db.QueryRow(`SELECT l.host_id FROM games g JOIN lobbies l ON l.id = g.lobby_id WHERE g.id = ?`, gameID).Scan(&hostID); err != nil {
+		var gameStatus string
+		if err := db.QueryRow(`SELECT l.host_id, g.status FROM games g JOIN lobbies l ON l.id = g.lobby_id WHERE g.id = ?`, gameID).Scan(&hostID, &gameStatus); err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				c.JSON(http.StatusNotFound, gin.H{"error": "game not found"})
 				return
@@ -506,6 +810,48 @@ func CorrectHandler(db *sql.DB) gin.HandlerFunc {
 			return
 		}
 
+		// Corrections only make sense while the hand is being counted or once the game is over.
+		// In a running game the checks below hold for the engine at baseVersion, and the
+		// correction commits against that version, so a deal landing in between is a conflict.
+		var working *cribbage.State
+		var baseVersion int64
+		if gameStatus != "finished" {
+			// The engine may not be cached yet (e.g. after a restart); load it like the other
+			// game handlers do rather than refusing the correction.
+			players, err := models.ListGamePlayersByGame(db, gameID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+				return
+			}
+			st, unlock, err := ensureGameStateLocked(db, gameID, players)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "game not ready"})
+				return
+			}
+			stage := st.Stage
+			baseVersion = st.Version
+			snapshot := cloneStateDeep(st)
+			snapshot.Version = baseVersion
+			working = &snapshot
+			unlock()
+			if stage != "counting" {
+				c.JSON(http.StatusConflict, gin.H{"error": "corrections are only allowed during counting or after the game", "code": "correction_wrong_stage"})
+				return
+			}
+		}
+		// A discard recorded after the move means a later hand has been dealt; fixing older
+		// hands is a historical correction only the host may make.
+		historical, err := models.HasMoveTypeAfter(db, gameID, "discard", prev.ID)
+		if err != nil {
+			log.Printf("HasMoveTypeAfter failed: game_id=%d move_id=%d err=%v", gameID, prev.ID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		if historical && !isHost {
+			c.JSON(http.StatusForbidden, gin.H{"error": "only the host can correct moves from a previous hand", "code": "correction_previous_hand"})
+			return
+		}
+
 		// Mark original move as corrected before inserting the correction (atomic via tx).
 		tx, err := db.Begin()
 		if err != nil {
@@ -530,12 +876,24 @@ func CorrectHandler(db *sql.DB) gin.HandlerFunc {
 			ScoreClaimed:  &newClaim,
 			ScoreVerified: &verified,
 			IsCorrected:   false,
+			RefMoveID:     &prev.ID,
 		}); err != nil {
 			log.Printf("InsertMoveTx (correction) failed: game_id=%d move_id=%d err=%v", gameID, req.MoveID, err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
 			return
 		}
-		if err := tx.Commit(); err != nil {
+		if working != nil {
+			applied, err := commitStateTx(db, tx, gameID, baseVersion, working)
+			if err != nil {
+				log.Printf("CorrectHandler commit failed: move_id=%d err=%v", req.MoveID, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+				return
+			}
+			if !applied {
+				writeAPIError(c, models.ErrGameStateConflict)
+				return
+			}
+		} else if err := tx.Commit(); err != nil {
 			log.Printf("CorrectHandler commit failed: move_id=%d err=%v", req.MoveID, err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
 			return
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"fifteen-thirty-one-go/backend/internal/game/cribbage"
+	"fifteen-thirty-one-go/backend/internal/models"
+	"fifteen-thirty-one-go/backend/internal/tracing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// gameEventBatch is a run of newly appended engine events plus, from the state they were taken
+// from, the seq after which plays are still in the open pegging sequence (-1 when none is open).
+type gameEventBatch struct {
+	events  []cribbage.Event
+	openSeq int
+}
+
+// newGameEventBatch collects st's events after seq.
+func newGameEventBatch(st *cribbage.State, seq int) *gameEventBatch {
+	b := &gameEventBatch{events: st.EventsSince(seq), openSeq: -1}
+	if open, ok := st.OpenSequenceSeq(); ok {
+		b.openSeq = open
+	}
+	return b
+}
+
+// eventsForViewer shapes events by the viewer's pegging_reveal mode (see withholdOpenSequence).
+func eventsForViewer(events []cribbage.Event, openSeq int, mode string) []cribbage.Event {
+	if mode != models.PeggingRevealSequenceEnd {
+		return events
+	}
+	events, _ = withholdOpenSequence(events, openSeq)
+	return events
+}
+
+// withholdOpenSequence strips the points and reasons of plays after openSeq, the scoring that
+// sequence_end viewers have not seen yet; notifyPegging sends them the sequence's breakdown
+// when it ends, and later reads of the log show the plays in full. changed reports whether any
+// play was stripped; events itself is never modified.
+func withholdOpenSequence(events []cribbage.Event, openSeq int) (out []cribbage.Event, changed bool) {
+	if openSeq < 0 {
+		return events, false
+	}
+	for i, e := range events {
+		if e.Type != cribbage.EventPlay || e.Seq <= openSeq || (e.Points == 0 && len(e.Reasons) == 0) {
+			continue
+		}
+		if !changed {
+			out = append([]cribbage.Event(nil), events...)
+			changed = true
+		}
+		out[i].Points = 0
+		out[i].Reasons = nil
+	}
+	if !changed {
+		return events, false
+	}
+	return out, true
+}
+
+// broadcastGameEvents streams newly appended engine events to the game room as game:event
+// frames, in sequence order. Viewers whose pegging_reveal is immediate get the plays' scoring
+// as it happens; everyone else, including clients joining mid-broadcast, gets it withheld until
+// the sequence ends.
+func broadcastGameEvents(db *sql.DB, gameID int64, b *gameEventBatch) {
+	if b == nil || len(b.events) == 0 || !currentConfig().GameEvents || hubProvider == nil {
+		return
+	}
+	hub, ok := hubProvider()
+	if !ok || hub == nil {
+		return
+	}
+	room := "game:" + strconv.FormatInt(gameID, 10)
+	withheld, changed := withholdOpenSequence(b.events, b.openSeq)
+	var immediate []int64
+	if changed {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		snap, err := hub.RoomClients(ctx, room)
+		cancel()
+		if err != nil {
+			log.Printf("broadcastGameEvents: RoomClients failed: game_id=%d err=%v", gameID, err)
+		}
+		for _, u := range snap.Users {
+			if pegRevealMode(db, u.UserID) != models.PeggingRevealSequenceEnd {
+				immediate = append(immediate, u.UserID)
+			}
+		}
+	}
+	for i, e := range withheld {
+		frame := map[string]any{"game_id": gameID, "event": e}
+		if len(immediate) == 0 {
+			hub.Broadcast(room, "game:event", frame)
+			continue
+		}
+		full := map[string]any{"game_id": gameID, "event": b.events[i]}
+		byUser := make(map[int64]any, len(immediate))
+		for _, id := range immediate {
+			byUser[id] = full
+		}
+		hub.BroadcastTailored(room, "game:event", frame, byUser)
+	}
+}
+
+// gameEvents returns the game's retained events after seq from the in-memory engine when
+// loaded, otherwise from the persisted state.
+func gameEvents(db *sql.DB, gameID int64, seq int) (*gameEventBatch, error) {
+	if st, unlock, ok := defaultGameManager.GetLocked(gameID); ok {
+		b := newGameEventBatch(st, seq)
+		unlock()
+		return b, nil
+	}
+	raw, _, ok, err := models.GetGameStateJSON(db, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, models.ErrGameStateMissing
+	}
+	var persisted cribbage.State
+	if err := json.Unmarshal([]byte(raw), &persisted); err != nil {
+		return nil, err
+	}
+	return newGameEventBatch(&persisted, seq), nil
+}
+
+// GameEventsHandler returns the game's retained event log (the previous and current hands), or
+// the events after ?since=seq for clients catching up on missed game:event frames.
+func GameEventsHandler(db *sql.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, span := tracing.StartSpan(c.Request.Context(), "handlers.GameEventsHandler")
+		defer span.End()
+
+		gameID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || gameID <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid game id"})
+			return
+		}
+		since := 0
+		if v := c.Query("since"); v != "" {
+			since, err = strconv.Atoi(v)
+			if err != nil || since < 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid since"})
+				return
+			}
+		}
+		userID, ok := userIDFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		allowed, err := canViewGame(db, userID, gameID)
+		if err != nil {
+			log.Printf("GameEventsHandler: authorization check failed: game_id=%d user_id=%d err=%v", gameID, userID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		if !allowed {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+
+		batch, err := gameEvents(db, gameID, since)
+		if err != nil {
+			if errors.Is(err, models.ErrGameStateMissing) {
+				c.JSON(http.StatusConflict, gin.H{"error": "game state unavailable; recreate lobby"})
+				return
+			}
+			log.Printf("GameEventsHandler: load events failed: game_id=%d err=%v", gameID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		events := eventsForViewer(batch.events, batch.openSeq, pegRevealMode(db, userID))
+		if events == nil {
+			events = []cribbage.Event{}
+		}
+		c.JSON(http.StatusOK, gin.H{"game_id": gameID, "events": events})
+	}
+}
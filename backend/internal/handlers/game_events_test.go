@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"fifteen-thirty-one-go/backend/internal/game/common"
+	"fifteen-thirty-one-go/backend/internal/game/cribbage"
+	"fifteen-thirty-one-go/backend/internal/models"
+)
+
+// pegPair discards for everyone, then has the leader play 5H and the next seat pair it with 5S,
+// leaving that sequence open at 10. It returns the pair's seq.
+func pegPair(t *testing.T, gameID int64) int {
+	t.Helper()
+	st, unlock, ok := defaultGameManager.GetLocked(gameID)
+	if !ok {
+		t.Fatalf("game %d has no engine state", gameID)
+	}
+	defer unlock()
+	for p := range st.Hands {
+		if err := st.Discard(p, st.Hands[p][:st.Rules.DiscardCount()]); err != nil {
+			t.Fatalf("seat %d discard: %v", p, err)
+		}
+	}
+	lead := st.CurrentIndex
+	next := (lead + 1) % st.Rules.MaxPlayers
+	st.Hands[lead] = []common.Card{{Rank: 5, Suit: common.Hearts}, {Rank: common.King, Suit: common.Diamonds}}
+	st.Hands[next] = []common.Card{{Rank: 5, Suit: common.Spades}, {Rank: common.Queen, Suit: common.Diamonds}}
+	if _, _, err := st.PlayPeggingCard(lead, st.Hands[lead][0]); err != nil {
+		t.Fatalf("lead: %v", err)
+	}
+	pts, _, err := st.PlayPeggingCard(next, st.Hands[next][0])
+	if err != nil || pts != 2 {
+		t.Fatalf("pair: %d points, err %v; want 2", pts, err)
+	}
+	return st.LastEventSeq()
+}
+
+func TestEventsWithholdOpenSequenceScoring(t *testing.T) {
+	db := newTestDB(t)
+	gameID, users := newTestGame(t, db, "alice", "bob")
+	seqEnd := models.PeggingRevealSequenceEnd
+	if _, err := models.UpdateUserPreferencesTx(db, users[0], nil, nil, false, &seqEnd, nil, nil); err != nil {
+		t.Fatalf("set pegging_reveal: %v", err)
+	}
+	pair := pegPair(t, gameID)
+
+	pairPoints := func(events []cribbage.Event) int {
+		for _, e := range events {
+			if e.Seq == pair {
+				return e.Points
+			}
+		}
+		t.Fatalf("events %v have no seq %d", events, pair)
+		return 0
+	}
+	getEvents := func(userID int64) []cribbage.Event {
+		var out struct {
+			Events []cribbage.Event `json:"events"`
+		}
+		path := fmt.Sprintf("/games/%d/events", gameID)
+		if code := doRequest(t, GameEventsHandler(db), http.MethodGet, "/games/:id/events", path, userID, nil, &out); code != http.StatusOK {
+			t.Fatalf("GET %s as user %d: status %d", path, userID, code)
+		}
+		return out.Events
+	}
+	snapshot := func(userID int64) []cribbage.Event {
+		snap, err := BuildGameSnapshotForUser(db, gameID, userID)
+		if err != nil {
+			t.Fatalf("snapshot for user %d: %v", userID, err)
+		}
+		return snap.State.Events
+	}
+
+	if got := pairPoints(getEvents(users[0])); got != 0 {
+		t.Errorf("sequence_end player sees the open pair's %d points in the log", got)
+	}
+	if got := pairPoints(snapshot(users[0])); got != 0 {
+		t.Errorf("sequence_end player sees the open pair's %d points in their snapshot", got)
+	}
+	if got := pairPoints(getEvents(users[1])); got != 2 {
+		t.Errorf("immediate player sees %d points for the pair in the log, want 2", got)
+	}
+	if got := pairPoints(snapshot(users[1])); got != 2 {
+		t.Errorf("immediate player sees %d points for the pair in their snapshot, want 2", got)
+	}
+	public, err := BuildGameSnapshotPublic(db, gameID)
+	if err != nil {
+		t.Fatalf("public snapshot: %v", err)
+	}
+	if got := pairPoints(public.State.Events); got != 0 {
+		t.Errorf("room-wide snapshot shows the open pair's %d points", got)
+	}
+
+	// Once the sequence ends everyone sees the pair.
+	endSequence(t, gameID)
+	if got := pairPoints(getEvents(users[0])); got != 2 {
+		t.Errorf("sequence_end player sees %d points for the pair after the sequence ended, want 2", got)
+	}
+}
+
+// endSequence plays out the hand pegPair left open, ending its sequence.
+func endSequence(t *testing.T, gameID int64) {
+	t.Helper()
+	st, unlock, _ := defaultGameManager.GetLocked(gameID)
+	defer unlock()
+	for st.Stage == "pegging" {
+		p := st.CurrentIndex
+		if !st.CanPlay(p) {
+			if _, err := st.Go(p); err != nil {
+				t.Fatalf("seat %d go: %v", p, err)
+			}
+			continue
+		}
+		if _, _, err := st.PlayPeggingCard(p, st.Hands[p][0]); err != nil {
+			t.Fatalf("seat %d play: %v", p, err)
+		}
+	}
+}
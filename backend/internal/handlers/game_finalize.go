@@ -5,14 +5,54 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"log"
 	"sort"
+	"sync"
 
+	"fifteen-thirty-one-go/backend/internal/game/cribbage"
 	"fifteen-thirty-one-go/backend/internal/models"
 )
 
+// finalizing serializes maybeFinalizeGame per game. Two SQLite transactions that both read the
+// scoreboard before writing deadlock on the write lock, and one fails with "database is
+// locked" instead of seeing the other's rows.
+var finalizing = struct {
+	mu    sync.Mutex
+	games map[int64]*finalizeLock
+}{games: map[int64]*finalizeLock{}}
+
+type finalizeLock struct {
+	sync.Mutex
+	waiters int
+}
+
+// lockFinalize takes gameID's finalize lock and returns its release.
+func lockFinalize(gameID int64) func() {
+	finalizing.mu.Lock()
+	l := finalizing.games[gameID]
+	if l == nil {
+		l = &finalizeLock{}
+		finalizing.games[gameID] = l
+	}
+	l.waiters++
+	finalizing.mu.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+		finalizing.mu.Lock()
+		if l.waiters--; l.waiters == 0 {
+			delete(finalizing.games, gameID)
+		}
+		finalizing.mu.Unlock()
+	}
+}
+
 // maybeFinalizeGame persists immutable end-of-game results once the engine reaches stage "finished".
-// It is safe to call multiple times (idempotent per game_id).
+// It is safe to call multiple times, including concurrently (idempotent per game_id).
 func maybeFinalizeGame(ctx context.Context, db *sql.DB, gameID int64) error {
+	defer lockFinalize(gameID)()
+
 	players, err := models.ListGamePlayersByGameContext(ctx, db, gameID)
 	if err != nil {
 		return fmt.Errorf("maybeFinalizeGame: ListGamePlayersByGameContext failed (game_id=%d): %w", gameID, err)
@@ -35,6 +75,8 @@ func maybeFinalizeGame(ctx context.Context, db *sql.DB, gameID int64) error {
 
 	// Copy what we need while holding the lock.
 	scores := append([]int(nil), st.Scores...)
+	history := append([]cribbage.RoundSummary(nil), st.History...)
+	rules := st.Rules
 	unlock()
 
 	type row struct {
@@ -42,6 +84,7 @@ func maybeFinalizeGame(ctx context.Context, db *sql.DB, gameID int64) error {
 		pos      int64
 		score    int64
 		username string
+		resigned bool
 	}
 	rows := make([]row, 0, len(players))
 	for _, p := range players {
@@ -50,9 +93,13 @@ func maybeFinalizeGame(ctx context.Context, db *sql.DB, gameID int64) error {
 		if pos >= 0 && pos < len(scores) {
 			sc = int64(scores[pos])
 		}
-		rows = append(rows, row{userID: p.UserID, pos: p.Position, score: sc, username: p.Username})
+		rows = append(rows, row{userID: p.UserID, pos: p.Position, score: sc, username: p.Username, resigned: p.Resigned})
 	}
 	sort.SliceStable(rows, func(i, j int) bool {
+		// Resigned players always rank below everyone who stayed, even if a bot kept scoring for them.
+		if rows[i].resigned != rows[j].resigned {
+			return !rows[i].resigned
+		}
 		if rows[i].score != rows[j].score {
 			return rows[i].score > rows[j].score
 		}
@@ -62,6 +109,8 @@ func maybeFinalizeGame(ctx context.Context, db *sql.DB, gameID int64) error {
 		return nil
 	}
 	winnerID := rows[0].userID
+	// Skunks only apply when the game was won on the board, not ended early by resignations.
+	skunkable := !rows[0].resigned && rows[0].score >= int64(rules.WinningScore())
 
 	var lobbyID int64
 	if err := db.QueryRowContext(ctx, `SELECT lobby_id FROM games WHERE id = ?`, gameID).Scan(&lobbyID); err != nil {
@@ -95,32 +144,88 @@ func maybeFinalizeGame(ctx context.Context, db *sql.DB, gameID int64) error {
 		return nil
 	}
 
+	// The check above is only a fast path: a finalization in another process can pass it too. The
+	// scoreboard's unique indexes settle the race, and stats only move for rows this call inserted.
+	gameSkunk := cribbage.NoSkunk
 	for i, r := range rows {
 		rank := int64(i + 1)
-		if _, err := tx.ExecContext(
-			ctx,
-			`INSERT INTO scoreboard(user_id, game_id, final_score, position) VALUES (?, ?, ?, ?)`,
-			r.userID, gameID, r.score, rank,
-		); err != nil {
+		skunk := cribbage.NoSkunk
+		if skunkable && i > 0 {
+			skunk = rules.SkunkLevel(int(r.score))
+		}
+		gameSkunk = max(gameSkunk, skunk)
+		inserted, err := models.InsertScoreboardRowTx(ctx, tx, r.userID, gameID, r.score, rank, models.OutcomeCompleted, skunk)
+		if err != nil {
 			return fmt.Errorf("maybeFinalizeGame: insert scoreboard row (game_id=%d user_id=%d rank=%d): %w", gameID, r.userID, rank, err)
 		}
+		if !inserted {
+			log.Printf("maybeFinalizeGame: scoreboard row already recorded, skipping stats: game_id=%d user_id=%d", gameID, r.userID)
+			continue
+		}
 		if _, err := tx.ExecContext(ctx, `UPDATE users SET games_played = games_played + 1 WHERE id = ?`, r.userID); err != nil {
 			return fmt.Errorf("maybeFinalizeGame: update games_played (user_id=%d game_id=%d): %w", r.userID, gameID, err)
 		}
+		if r.userID == winnerID {
+			if _, err := tx.ExecContext(ctx, `UPDATE users SET games_won = games_won + 1 WHERE id = ?`, winnerID); err != nil {
+				return fmt.Errorf("maybeFinalizeGame: update games_won (winner_id=%d game_id=%d): %w", winnerID, gameID, err)
+			}
+		}
+		if best, ok := bestHandForSeat(gameID, r.userID, int(r.pos), history); ok {
+			if err := models.InsertBestHandTx(ctx, tx, best); err != nil {
+				return fmt.Errorf("maybeFinalizeGame: %w", err)
+			}
+		}
 	}
-	if _, err := tx.ExecContext(ctx, `UPDATE users SET games_won = games_won + 1 WHERE id = ?`, winnerID); err != nil {
-		return fmt.Errorf("maybeFinalizeGame: update games_won (winner_id=%d game_id=%d): %w", winnerID, gameID, err)
+	if currentConfig().GameStatsEnabled {
+		if err := models.RecordGameStatsTx(ctx, tx, gameID, len(players)); err != nil {
+			return fmt.Errorf("maybeFinalizeGame: %w", err)
+		}
 	}
 	if err := models.SetGameStatusTx(tx, gameID, "finished"); err != nil {
 		return fmt.Errorf("maybeFinalizeGame: SetGameStatusTx finished failed (game_id=%d): %w", gameID, err)
 	}
-	if err := models.SetLobbyStatusTx(tx, lobbyID, "finished"); err != nil {
-		return fmt.Errorf("maybeFinalizeGame: SetLobbyStatusTx finished failed (lobby_id=%d game_id=%d): %w", lobbyID, gameID, err)
+	mm := NewMatchManager(db)
+	next, err := mm.RecordGameTx(tx, gameID, players, winnerID, gameSkunk, rules)
+	if errors.Is(err, models.ErrMatchGameRecorded) {
+		// Another finalization scored this match game and decided its lobby's fate; leave the
+		// lobby alone rather than finishing one whose match goes on.
+		log.Printf("maybeFinalizeGame: match game already recorded, leaving the lobby as is: game_id=%d lobby_id=%d", gameID, lobbyID)
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("maybeFinalizeGame: commit transaction: %w", err)
+		}
+		committed = true
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("maybeFinalizeGame: record match game (game_id=%d): %w", gameID, err)
+	}
+	seriesUpdated := false
+	if currentConfig().LobbySeries {
+		_, recorded, err := models.RecordSeriesWinTx(tx, lobbyID, gameID, winnerID)
+		if err != nil {
+			return fmt.Errorf("maybeFinalizeGame: record series win (lobby_id=%d game_id=%d): %w", lobbyID, gameID, err)
+		}
+		seriesUpdated = recorded
+	}
+	// A match that goes on keeps its lobby in play for the next game.
+	if next == nil {
+		if err := models.SetLobbyStatusTx(tx, lobbyID, "finished"); err != nil {
+			return fmt.Errorf("maybeFinalizeGame: SetLobbyStatusTx finished failed (lobby_id=%d game_id=%d): %w", lobbyID, gameID, err)
+		}
 	}
 
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("maybeFinalizeGame: commit transaction: %w", err)
 	}
 	committed = true
+	if next == nil {
+		activeLobbies.invalidate()
+	}
+	if seriesUpdated {
+		broadcastSeriesUpdate(ctx, db, lobbyID, gameID)
+	}
+	if next != nil {
+		mm.Start(next)
+	}
 	return nil
 }
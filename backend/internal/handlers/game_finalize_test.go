@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"fifteen-thirty-one-go/backend/internal/game/cribbage"
+	"fifteen-thirty-one-go/backend/internal/models"
+)
+
+// finishGame ends gameID on the board with seat 0 on 121 and seat 1 on 90.
+func finishGame(t *testing.T, gameID int64) {
+	t.Helper()
+	st, unlock, ok := defaultGameManager.GetLocked(gameID)
+	if !ok {
+		t.Fatalf("game %d has no engine state", gameID)
+	}
+	st.Scores = []int{121, 90}
+	st.Stage = "finished"
+	unlock()
+}
+
+func TestConcurrentFinalizeRecordsOnce(t *testing.T) {
+	db := newTestDB(t)
+	gameID, users := newTestGame(t, db, "alice", "bob")
+	finishGame(t, gameID)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = maybeFinalizeGame(context.Background(), db, gameID)
+		}()
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("finalizer %d: %v", i, err)
+		}
+	}
+
+	for _, u := range users {
+		if n := queryInt(t, db, `SELECT COUNT(*) FROM scoreboard WHERE game_id = ? AND user_id = ?`, gameID, u); n != 1 {
+			t.Errorf("user %d has %d scoreboard rows, want 1", u, n)
+		}
+		if n := queryInt(t, db, `SELECT games_played FROM users WHERE id = ?`, u); n != 1 {
+			t.Errorf("user %d games_played = %d, want 1", u, n)
+		}
+	}
+	if n := queryInt(t, db, `SELECT games_won FROM users WHERE id = ?`, users[0]); n != 1 {
+		t.Errorf("winner games_won = %d, want 1", n)
+	}
+	if n := queryInt(t, db, `SELECT games_won FROM users WHERE id = ?`, users[1]); n != 0 {
+		t.Errorf("loser games_won = %d, want 0", n)
+	}
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM games WHERE id = ? AND status = 'finished'`, gameID); n != 1 {
+		t.Errorf("game %d not marked finished", gameID)
+	}
+}
+
+func TestRecordMatchGameTwiceReportsAlreadyRecorded(t *testing.T) {
+	db := newTestDB(t)
+	gameID, users := newTestMatchGame(t, db, 3, "alice", "bob")
+	players, err := models.ListGamePlayersByGame(db, gameID)
+	if err != nil {
+		t.Fatalf("list players: %v", err)
+	}
+	mm := NewMatchManager(db)
+	record := func() (*nextMatchGame, error) {
+		tx, err := db.Begin()
+		if err != nil {
+			t.Fatalf("begin: %v", err)
+		}
+		defer tx.Rollback()
+		if err := models.SetGameStatusTx(tx, gameID, "finished"); err != nil {
+			t.Fatalf("finish game: %v", err)
+		}
+		next, err := mm.RecordGameTx(tx, gameID, players, users[0], cribbage.NoSkunk, cribbage.DefaultRules(2))
+		if err == nil {
+			if err := tx.Commit(); err != nil {
+				t.Fatalf("commit: %v", err)
+			}
+		}
+		return next, err
+	}
+	if next, err := record(); err != nil || next == nil {
+		t.Fatalf("first record: next %v err %v, want the next match game", next, err)
+	}
+	if next, err := record(); !errors.Is(err, models.ErrMatchGameRecorded) || next != nil {
+		t.Errorf("second record: next %v err %v, want ErrMatchGameRecorded", next, err)
+	}
+
+	// A finalization that gets past the scoreboard check after the match game was scored must
+	// leave the lobby to the match.
+	finishGame(t, gameID)
+	if err := maybeFinalizeGame(context.Background(), db, gameID); err != nil {
+		t.Fatalf("finalize: %v", err)
+	}
+	lobbyID := queryInt(t, db, `SELECT lobby_id FROM games WHERE id = ?`, gameID)
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM lobbies WHERE id = ? AND status = 'finished'`, lobbyID); n != 0 {
+		t.Error("late finalization finished the lobby of a match that goes on")
+	}
+}
+
+func TestConcurrentFinalizeKeepsMatchLobbyInPlay(t *testing.T) {
+	db := newTestDB(t)
+	gameID, _ := newTestMatchGame(t, db, 3, "alice", "bob")
+	finishGame(t, gameID)
+	lobbyID := queryInt(t, db, `SELECT lobby_id FROM games WHERE id = ?`, gameID)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := maybeFinalizeGame(context.Background(), db, gameID); err != nil {
+				t.Errorf("finalize: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM games WHERE lobby_id = ?`, lobbyID); n != 2 {
+		t.Errorf("lobby has %d games, want the finished one and one follow-up", n)
+	}
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM match_games WHERE winner_id IS NOT NULL`); n != 1 {
+		t.Errorf("%d match games scored, want 1", n)
+	}
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM lobbies WHERE id = ? AND status = 'finished'`, lobbyID); n != 0 {
+		t.Error("match lobby was finished while its match goes on")
+	}
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"fifteen-thirty-one-go/backend/internal/game/common"
+	"fifteen-thirty-one-go/backend/internal/models"
+	"fifteen-thirty-one-go/backend/internal/tracing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PlayerHand is the caller's own slice of a game, served by PlayerHandHandler.
+type PlayerHand struct {
+	GameID   int64         `json:"game_id"`
+	Position int64         `json:"position"`
+	Score    int           `json:"score"`
+	Hand     []common.Card `json:"hand"`
+	Stage    string        `json:"stage"`
+	YourTurn bool          `json:"your_turn"` // the seat has something to do now (see pendingActionFor)
+}
+
+// PlayerHandHandler handles GET /api/games/:id/hand: the caller's cards from their game_players
+// row plus their seat, score and whether they are up, without building a full snapshot. It never
+// touches other seats' cards. Seats a player resigned are no longer theirs and get 403.
+func PlayerHandHandler(db *sql.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, span := tracing.StartSpan(c.Request.Context(), "handlers.PlayerHandHandler")
+		defer span.End()
+
+		gameID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || gameID <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid game id"})
+			return
+		}
+		userID, ok := userIDFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		if _, err := models.GetGameByID(db, gameID); err != nil {
+			writeAPIError(c, err)
+			return
+		}
+		players, err := models.ListGamePlayersByGame(db, gameID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		var seat *models.GamePlayer
+		for i := range players {
+			if players[i].UserID == userID && !players[i].Resigned {
+				seat = &players[i]
+			}
+		}
+		if seat == nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": "not a player"})
+			return
+		}
+		var hand []common.Card
+		if err := json.Unmarshal([]byte(seat.Hand), &hand); err != nil {
+			log.Printf("PlayerHandHandler: decode hand failed: game_id=%d user_id=%d err=%v", gameID, userID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		st, unlock, err := ensureGameStateLocked(db, gameID, players)
+		if err != nil {
+			if errors.Is(err, models.ErrGameStateMissing) {
+				c.JSON(http.StatusConflict, gin.H{"error": "game state unavailable; recreate lobby"})
+				return
+			}
+			log.Printf("PlayerHandHandler: ensureGameStateLocked failed: game_id=%d err=%v", gameID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		pos := int(seat.Position)
+		// Same repair source as BuildGameSnapshotForUser when the row has not been written yet.
+		if len(hand) == 0 && pos < len(st.Hands) {
+			hand = append([]common.Card(nil), st.Hands[pos]...)
+		}
+		resp := PlayerHand{GameID: gameID, Position: seat.Position, Score: int(seat.Score), Hand: hand, Stage: st.Stage}
+		if pos < len(st.Scores) {
+			resp.Score = st.Scores[pos]
+		}
+		if pending := pendingActionFor(st, pos); pending != nil {
+			resp.YourTurn = pending.Action != PendingWait
+		}
+		unlock()
+		if resp.Hand == nil {
+			resp.Hand = []common.Card{}
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"crypto/rand"
 	"database/sql"
 	"encoding/json"
@@ -8,6 +9,7 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"fifteen-thirty-one-go/backend/internal/game/common"
 	"fifteen-thirty-one-go/backend/internal/game/cribbage"
@@ -18,6 +20,63 @@ type GameSnapshot struct {
 	Game    *models.Game        `json:"game"`
 	Players []models.GamePlayer `json:"players"`
 	State   cribbage.State      `json:"state"`
+	// Outlook is derived endgame data per seat ("needs X to win").
+	Outlook []cribbage.Outlook `json:"outlook"`
+	// PendingAction is what the game is waiting on from the requesting player, if anything.
+	PendingAction *PendingAction `json:"pending_action,omitempty"`
+	// CountingOrder (user ids) and NextToCount guide the counting stage; see countingOrder.
+	CountingOrder []int64       `json:"counting_order,omitempty"`
+	NextToCount   *CountingTurn `json:"next_to_count,omitempty"`
+	// CountProgress is the requesting player's own counting status during the counting stage.
+	CountProgress *CountProgress `json:"count_progress,omitempty"`
+	// MatchID is set for games played as part of a match (see GetMatchHandler).
+	MatchID *int64 `json:"match_id,omitempty"`
+	// Series is the lobby's running win tally across rematches, once a game has counted.
+	Series *models.LobbySeries `json:"series,omitempty"`
+	// Focus is set on spectator snapshots requested with ?follow=<user id>.
+	Focus *SpectatorFocus `json:"focus,omitempty"`
+}
+
+// addMatchID links the snapshot to the game's match, if any; lookup failures are logged.
+func (s *GameSnapshot) addMatchID(db *sql.DB, gameID int64) {
+	id, err := models.MatchIDForGame(db, gameID)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			log.Printf("addMatchID: MatchIDForGame failed: game_id=%d err=%v", gameID, err)
+		}
+		return
+	}
+	s.MatchID = &id
+}
+
+// addCountingOrder fills the counting-order hints when the game is in the counting stage.
+func (s *GameSnapshot) addCountingOrder(db *sql.DB, gameID int64, stage string, dealerIndex int) {
+	if stage != "counting" || !currentConfig().CountingOrderHints {
+		return
+	}
+	s.CountingOrder, s.NextToCount = countingOrder(db, gameID, s.Players, dealerIndex)
+}
+
+// userCountProgress is countProgress for the requesting seat; failures are logged and yield nil
+// so a snapshot never fails over counting hints.
+func userCountProgress(db *sql.DB, gameID, userID int64, players []models.GamePlayer, pos, dealerIndex int) *CountProgress {
+	manual := false
+	for _, p := range players {
+		if p.UserID == userID && !p.IsBot {
+			prefs, err := models.GetUserPreferences(db, userID)
+			if err != nil {
+				log.Printf("userCountProgress: GetUserPreferences failed: user_id=%d err=%v", userID, err)
+				return nil
+			}
+			manual = prefs.AutoCountMode == "off"
+		}
+	}
+	progress, err := countProgress(db, gameID, userID, pos, dealerIndex, manual)
+	if err != nil {
+		log.Printf("userCountProgress: countProgress failed: game_id=%d user_id=%d err=%v", gameID, userID, err)
+		return nil
+	}
+	return progress
 }
 
 func BuildGameSnapshotForUser(db *sql.DB, gameID int64, userID int64) (*GameSnapshot, error) {
@@ -32,12 +91,25 @@ func BuildGameSnapshotForUser(db *sql.DB, gameID int64, userID int64) (*GameSnap
 	if len(players) == 0 {
 		return nil, errors.New("no players")
 	}
+	for _, gp := range players {
+		if gp.UserID == userID && gp.Resigned {
+			// A bot may be playing the seat they left; if they stayed on to watch, they see
+			// what spectators see rather than the bot's cards.
+			if spectating, err := models.IsUserSpectatingGame(db, userID, gameID); err == nil && spectating {
+				return BuildGameSnapshotPublic(db, gameID)
+			}
+		}
+	}
 
+	reveal := pegRevealMode(db, userID)
 	st, unlock, err := ensureGameStateLocked(db, gameID, players)
 	if err != nil {
 		return nil, err
 	}
 	view := CloneStateForView(st)
+	if reveal != models.PeggingRevealSequenceEnd && len(st.Events) > 0 {
+		view.Events = st.HandEvents()
+	}
 
 	// Best-effort fallback: if the DB hand JSON is missing/empty for the requesting player,
 	// we can recover from the server-authoritative engine state and re-persist it.
@@ -52,7 +124,20 @@ func BuildGameSnapshotForUser(db *sql.DB, gameID int64, userID int64) (*GameSnap
 	if userPos >= 0 && int(userPos) < len(st.Hands) {
 		fallbackHand = append([]common.Card(nil), st.Hands[userPos]...)
 	}
+	outlook := st.Outlook()
+	var pending *PendingAction
+	if currentConfig().PendingActionPrompts {
+		pending = pendingActionFor(st, int(userPos))
+	}
+	dealerIndex := st.DealerIndex
 	unlock()
+	if pending != nil && pending.Action == PendingReady {
+		refineCountingAction(db, gameID, userID, int(userPos), dealerIndex, pending)
+	}
+	var progress *CountProgress
+	if view.Stage == "counting" && userPos >= 0 {
+		progress = userCountProgress(db, gameID, userID, players, int(userPos), dealerIndex)
+	}
 
 	for _, gp := range players {
 		if gp.UserID == userID {
@@ -101,13 +186,23 @@ func BuildGameSnapshotForUser(db *sql.DB, gameID int64, userID int64) (*GameSnap
 		}
 	}
 
-	return &GameSnapshot{
-		Game:    g,
-		Players: players,
-		State:   view,
-	}, nil
+	snap := &GameSnapshot{
+		Game:          g,
+		Players:       players,
+		State:         view,
+		Outlook:       outlook,
+		PendingAction: pending,
+		CountProgress: progress,
+	}
+	snap.addCountingOrder(db, gameID, view.Stage, dealerIndex)
+	snap.addMatchID(db, gameID)
+	snap.addSeries(context.Background(), db, g.LobbyID)
+	return snap, nil
 }
 
+// BuildGameSnapshotPublic builds the snapshot broadcast to game rooms and served to spectators.
+// It carries no cards anyone is still holding: views already leave hands empty and omit the
+// deck, and stripSeatCards reduces each seat to its hand count.
 func BuildGameSnapshotPublic(db *sql.DB, gameID int64) (*GameSnapshot, error) {
 	g, err := models.GetGameByID(db, gameID)
 	if err != nil {
@@ -125,44 +220,109 @@ func BuildGameSnapshotPublic(db *sql.DB, gameID int64) (*GameSnapshot, error) {
 		return nil, err
 	}
 	view := CloneStateForView(st)
+	stripSeatCards(players, st.Hands)
+	outlook := st.Outlook()
+	dealerIndex := st.DealerIndex
 	unlock()
-	return &GameSnapshot{Game: g, Players: players, State: view}, nil
+	snap := &GameSnapshot{Game: g, Players: players, State: view, Outlook: outlook}
+	snap.addCountingOrder(db, gameID, view.Stage, dealerIndex)
+	snap.addMatchID(db, gameID)
+	snap.addSeries(context.Background(), db, g.LobbyID)
+	return snap, nil
+}
+
+// stripSeatCards blanks every seat's persisted cards, leaving only hand counts taken from the
+// runtime hands.
+func stripSeatCards(players []models.GamePlayer, hands [][]common.Card) {
+	for i := range players {
+		players[i].Hand = "[]"
+		players[i].CribCards = nil
+		players[i].HandCount = nil
+		if pos := int(players[i].Position); pos >= 0 && pos < len(hands) {
+			n := int64(len(hands[pos]))
+			players[i].HandCount = &n
+		}
+	}
 }
 
+// ApplyMove applies a move submitted by a human client. Players who resigned (and whose
+// seat may now be bot-controlled) can no longer move.
 func ApplyMove(db *sql.DB, gameID int64, userID int64, req moveRequest) (any, error) {
+	return applyMove(db, gameID, userID, req, false)
+}
+
+func applyMove(db *sql.DB, gameID int64, userID int64, req moveRequest, asBot bool) (any, error) {
+	resp, peg, events, err := commitMove(db, gameID, userID, req, asBot)
+	if err != nil {
+		return resp, err
+	}
+	broadcastGameEvents(db, gameID, events)
+	if peg == nil {
+		return resp, nil
+	}
+	notifyPegging(db, gameID, peg)
+	if peg.goResolution != nil {
+		broadcastGoResolved(gameID, peg.goResolution)
+	}
+	if asBot {
+		return resp, nil
+	}
+	return shapePeggingResponse(db, userID, req.Type, resp, peg), nil
+}
+
+func commitMove(db *sql.DB, gameID int64, userID int64, req moveRequest, asBot bool) (any, *pegOutcome, *gameEventBatch, error) {
 	const maxAttempts = 3
 
 	for attempt := 0; attempt < maxAttempts; attempt++ {
 		players, err := models.ListGamePlayersByGame(db, gameID)
 		if err != nil {
-			return nil, err
+			return nil, nil, nil, err
 		}
 		pos := int64(-1)
 		var hand []common.Card
 		for _, p := range players {
 			if p.UserID == userID {
+				if p.Resigned && !asBot {
+					return nil, nil, nil, models.ErrPlayerResigned
+				}
 				pos = p.Position
 				if err := json.Unmarshal([]byte(p.Hand), &hand); err != nil {
-					return nil, err
+					return nil, nil, nil, err
 				}
 				break
 			}
 		}
 		if pos < 0 {
-			return nil, models.ErrNotAPlayer
+			return nil, nil, nil, models.ErrNotAPlayer
 		}
 
 		// 1) Lock just long enough to validate + compute the move against a consistent runtime snapshot.
 		st, unlock, err := ensureGameStateLocked(db, gameID, players)
 		if err != nil {
-			return nil, err
+			return nil, nil, nil, err
 		}
 		prevStage := st.Stage
 		baseVersion := st.Version
+		eventsBefore := st.LastEventSeq()
+		historyBefore := len(st.History)
 
 		working := cloneStateDeep(st)
 		working.Version = baseVersion
+		if working.Stage == "dealing" {
+			unlock()
+			return nil, nil, nil, models.ErrGameNotStarted
+		}
 		if int(pos) < len(working.Hands) {
+			// The persisted hand must match the engine's hand for this seat. A divergence means
+			// the DB row drifted (or was tampered with); never let it silently override engine state.
+			if !common.SameCards(working.Hands[pos], hand) {
+				log.Printf("ApplyMove: hand mismatch (possible tamper/desync): game_id=%d user_id=%d pos=%d db_hand=%v engine_hand=%v",
+					gameID, userID, pos, hand, working.Hands[pos])
+				if currentConfig().StrictHandValidation {
+					unlock()
+					return nil, nil, nil, models.ErrHandStateMismatch
+				}
+			}
 			working.Hands[pos] = hand
 		}
 
@@ -170,11 +330,16 @@ func ApplyMove(db *sql.DB, gameID int64, userID int64, req moveRequest) (any, er
 		if req.Type == "play_card" || req.Type == "go" {
 			if working.Stage != "pegging" {
 				unlock()
-				return nil, models.ErrNotInPeggingStage
+				return nil, nil, nil, models.ErrNotInPeggingStage
 			}
-			if working.CurrentIndex != int(pos) {
+			if req.Type == "go" {
+				if err := working.GoTurnError(int(pos)); err != nil {
+					unlock()
+					return nil, nil, nil, err
+				}
+			} else if working.CurrentIndex != int(pos) {
 				unlock()
-				return nil, models.ErrNotYourTurn
+				return nil, nil, nil, models.ErrNotYourTurn
 			}
 		}
 
@@ -183,7 +348,11 @@ func ApplyMove(db *sql.DB, gameID int64, userID int64, req moveRequest) (any, er
 			resp    any
 			move    models.GameMove
 			handOut *string
+			peg     *pegOutcome
+			// extraMoves are logged after move, e.g. the dealer's heels when a discard cuts a jack.
+			extraMoves []models.GameMove
 		)
+		seqBefore := len(working.PeggingSeq)
 
 		switch req.Type {
 		case "discard":
@@ -192,60 +361,102 @@ func ApplyMove(db *sql.DB, gameID int64, userID int64, req moveRequest) (any, er
 				card, err := common.ParseCard(s)
 				if err != nil {
 					unlock()
-					return nil, models.ErrInvalidCard
+					return nil, nil, nil, models.ErrInvalidCard
 				}
 				discards = append(discards, card)
 			}
 			if err := (&working).Discard(int(pos), discards); err != nil {
 				unlock()
-				return nil, err
+				return nil, nil, nil, err
 			}
 			b, err := json.Marshal(working.Hands[pos])
 			if err != nil {
 				unlock()
-				return nil, err
+				return nil, nil, nil, err
 			}
 			s := string(b)
 			handOut = &s
 			move = models.GameMove{GameID: gameID, PlayerID: userID, MoveType: "discard"}
 			resp = map[string]any{"ok": true}
+			if heels := heelsMove(gameID, players, &working); heels != nil {
+				extraMoves = append(extraMoves, *heels)
+			}
 
 		case "play_card":
 			card, err := common.ParseCard(req.Card)
 			if err != nil {
 				unlock()
-				return nil, models.ErrInvalidCard
+				return nil, nil, nil, models.ErrInvalidCard
 			}
 			points, reasons, err := (&working).PlayPeggingCard(int(pos), card)
 			if err != nil {
 				unlock()
-				return nil, err
+				return nil, nil, nil, err
 			}
 			b, err := json.Marshal(working.Hands[pos])
 			if err != nil {
 				unlock()
-				return nil, err
+				return nil, nil, nil, err
 			}
 			s := string(b)
 			handOut = &s
 			cardStr := card.String()
 			verified := int64(points)
 			move = models.GameMove{GameID: gameID, PlayerID: userID, MoveType: "play_card", CardPlayed: &cardStr, ScoreVerified: &verified}
-			resp = map[string]any{"points": points, "reasons": reasons, "total": working.PeggingTotal}
+			goes, goRes, err := autoGo(&working)
+			if err != nil {
+				unlock()
+				return nil, nil, nil, err
+			}
+			for _, g := range goes {
+				for _, p := range players {
+					if int(p.Position) == g.Player {
+						awarded := int64(g.Awarded)
+						extraMoves = append(extraMoves, models.GameMove{GameID: gameID, PlayerID: p.UserID, MoveType: "go", ScoreVerified: &awarded})
+					}
+				}
+			}
+			r := map[string]any{"points": points, "reasons": reasons, "total": working.PeggingTotal}
+			if len(goes) > 0 {
+				// The play can't be taken back once others have said go after it.
+				working.PeggingUndo = nil
+				r["auto_go"] = goes
+			}
+			resp = r
+			peg = newPegOutcome(&working, int(pos), points, reasons, true)
+			peg.goResolution = goRes
+			peg.autoGo = goes
 
 		case "go":
-			awarded, err := (&working).Go(int(pos))
+			awarded, goRes, err := (&working).GoWithResolution(int(pos))
 			if err != nil {
 				unlock()
-				return nil, err
+				return nil, nil, nil, err
 			}
 			verified := int64(awarded)
 			move = models.GameMove{GameID: gameID, PlayerID: userID, MoveType: "go", ScoreVerified: &verified}
 			resp = map[string]any{"awarded": awarded}
+			// The go itself scores nothing; a last-card award goes to whoever played last.
+			scorer, lastCard := int(pos), []string(nil)
+			if awarded > 0 && len(working.LastSequencePegs) > 0 {
+				scorer = working.LastSequencePegs[len(working.LastSequencePegs)-1].Player
+				lastCard = []string{cribbage.LastCardReason}
+			}
+			peg = newPegOutcome(&working, scorer, awarded, lastCard, seqBefore > 0)
+			peg.goResolution = goRes
 
 		default:
 			unlock()
-			return nil, models.ErrUnknownMoveType
+			return nil, nil, nil, models.ErrUnknownMoveType
+		}
+
+		// Track runs of timed-out turns; a move the player makes themselves ends theirs.
+		if req.timedOut {
+			// A card played for the player when their time ran out is not theirs to take back.
+			working.PeggingUndo = nil
+			working.RecordTurnTimeout(int(pos))
+		} else if !asBot {
+			working.ClearTurnTimeouts(int(pos))
 		}
 
 		// If the engine dealt a new round (pegging -> discard), we must persist the new dealt
@@ -254,11 +465,12 @@ func ApplyMove(db *sql.DB, gameID int64, userID int64, req moveRequest) (any, er
 
 		// Copy the computed state and release the per-game lock before DB I/O.
 		unlock()
+		newEvents := newGameEventBatch(&working, eventsBefore)
 
 		// 2) Persist the computed changes in a transaction, using optimistic (version) checks.
 		tx, err := db.Begin()
 		if err != nil {
-			return nil, err
+			return nil, nil, nil, err
 		}
 		committed := false
 		defer func() {
@@ -269,30 +481,56 @@ func ApplyMove(db *sql.DB, gameID int64, userID int64, req moveRequest) (any, er
 
 		if handOut != nil {
 			if err := models.UpdatePlayerHandTx(tx, gameID, userID, *handOut); err != nil {
-				return nil, err
+				return nil, nil, nil, err
 			}
 		}
 		if dealtNewRound {
 			for _, p := range players {
 				posIdx := int(p.Position)
 				if posIdx < 0 || posIdx >= len(working.Hands) {
-					return nil, models.ErrInvalidPlayerPosition
+					return nil, nil, nil, models.ErrInvalidPlayerPosition
 				}
 				b, err := json.Marshal(working.Hands[posIdx])
 				if err != nil {
-					return nil, err
+					return nil, nil, nil, err
 				}
 				if err := models.UpdatePlayerHandTx(tx, gameID, p.UserID, string(b)); err != nil {
-					return nil, err
+					return nil, nil, nil, err
 				}
 			}
 		}
 		if err := models.InsertMoveTx(tx, move); err != nil {
-			return nil, err
+			return nil, nil, nil, err
+		}
+		for _, m := range extraMoves {
+			if err := models.InsertMoveTx(tx, m); err != nil {
+				return nil, nil, nil, err
+			}
+		}
+		if len(working.History) > historyBefore {
+			if err := insertGameRoundsTx(tx, gameID, players, working.History[historyBefore:]); err != nil {
+				return nil, nil, nil, err
+			}
+		}
+		if stateWriteBehind() {
+			applied, err := commitMoveWriteBehind(db, tx, gameID, baseVersion, &working)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			if !applied {
+				// Another move won; discard ours and retry from the latest state.
+				_ = tx.Rollback()
+				if attempt < maxAttempts-1 {
+					continue
+				}
+				return nil, nil, nil, models.ErrGameStateConflict
+			}
+			committed = true
+			return resp, peg, newEvents, nil
 		}
 		sb, err := json.Marshal(working)
 		if err != nil {
-			return nil, err
+			return nil, nil, nil, err
 		}
 		if err := models.UpdateGameStateTxCAS(tx, gameID, baseVersion, string(sb)); err != nil {
 			// Another move committed first; retry from latest state.
@@ -300,10 +538,10 @@ func ApplyMove(db *sql.DB, gameID int64, userID int64, req moveRequest) (any, er
 				_ = tx.Rollback()
 				continue
 			}
-			return nil, err
+			return nil, nil, nil, err
 		}
 		if err := tx.Commit(); err != nil {
-			return nil, err
+			return nil, nil, nil, err
 		}
 		committed = true
 
@@ -327,7 +565,7 @@ func ApplyMove(db *sql.DB, gameID int64, userID int64, req moveRequest) (any, er
 					defaultGameManager.Set(gameID, &restored)
 				}
 			}
-			return resp, nil
+			return resp, peg, newEvents, nil
 		}
 
 		if st2.Version == baseVersion {
@@ -344,125 +582,318 @@ func ApplyMove(db *sql.DB, gameID int64, userID int64, req moveRequest) (any, er
 		}
 		unlock2()
 
-		return resp, nil
+		return resp, peg, newEvents, nil
 	}
 
-	return nil, models.ErrGameStateConflict
+	return nil, nil, nil, models.ErrGameStateConflict
 }
 
-func maybeRunBotTurns(db *sql.DB, gameID int64) error {
-	// Safety: prevent infinite bot loops.
-	const maxSteps = 64
+// commitMoveWriteBehind is the batched-durability tail of applyMove. The move and hand rows are
+// already written in tx; instead of a CAS on state_json, the version check happens against the
+// runtime state. Lock order stays DB -> memory: tx was opened before the game lock is taken.
+// It reports false (without committing) when another move got there first.
+func commitMoveWriteBehind(db *sql.DB, tx *sql.Tx, gameID, baseVersion int64, working *cribbage.State) (bool, error) {
+	st, unlock, ok := defaultGameManager.GetLocked(gameID)
+	if !ok {
+		return false, nil
+	}
+	if st.Version != baseVersion {
+		unlock()
+		return false, nil
+	}
+	if err := tx.Commit(); err != nil {
+		unlock()
+		return false, err
+	}
+	working.Version = baseVersion + 1
+	*st = *working
+	finished := st.Stage == "finished"
+	unlock()
 
-	for step := 0; step < maxSteps; step++ {
-		players, err := models.ListGamePlayersByGame(db, gameID)
-		if err != nil {
-			return err
-		}
-		if len(players) == 0 {
-			return nil
+	markStateDirty(db, gameID)
+	if finished {
+		// Final results must not sit in memory.
+		if err := flushGameState(db, gameID); err != nil {
+			log.Printf("ApplyMove: write-behind flush of finished game failed: game_id=%d err=%v", gameID, err)
 		}
+	}
+	return true, nil
+}
 
-		st, unlock, err := ensureGameStateLocked(db, gameID, players)
-		if err != nil {
-			// If the game isn't ready (e.g., lobby not full), just stop.
-			return nil
+// commitStateTx commits tx with working, computed from baseVersion, as the game's new state and
+// installs it in runtime memory. The version check and the apply happen under the game lock, so a
+// move that committed in between is never overwritten. It reports false (without committing) when
+// the state moved on since baseVersion; callers roll back and either retry or give up.
+func commitStateTx(db *sql.DB, tx *sql.Tx, gameID, baseVersion int64, working *cribbage.State) (bool, error) {
+	if stateWriteBehind() {
+		return commitMoveWriteBehind(db, tx, gameID, baseVersion, working)
+	}
+	sb, err := json.Marshal(working)
+	if err != nil {
+		return false, err
+	}
+	st, unlock, ok := defaultGameManager.GetLocked(gameID)
+	if ok {
+		defer unlock()
+		if st.Version != baseVersion {
+			return false, nil
+		}
+	}
+	if err := models.UpdateGameStateTxCAS(tx, gameID, baseVersion, string(sb)); err != nil {
+		if errors.Is(err, models.ErrGameStateConflict) {
+			return false, nil
 		}
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	working.Version = baseVersion + 1
+	if ok {
+		*st = *working
+	}
+	return true, nil
+}
 
-		stage := st.Stage
-		currentIdx := st.CurrentIndex
-		peggingTotal := st.PeggingTotal
-		peggingSeq := append([]common.Card(nil), st.PeggingSeq...)
-		discardCompleted := append([]bool(nil), st.DiscardCompleted...)
-		maxPlayers := st.Rules.MaxPlayers
-		unlock()
+// botTurn is the next thing the bots of a game have to do: one bot's discard or pegging move,
+// or (count set) the final counts autoCountBots records for every bot at once.
+type botTurn struct {
+	player     models.GamePlayer
+	count      bool
+	players    []models.GamePlayer
+	stage      string
+	hand       []common.Card
+	discardN   int
+	ownCrib    bool
+	peggingSum int
+	peggingSeq []common.Card
+}
 
-		// Map position -> player row.
-		byPos := make(map[int64]models.GamePlayer, len(players))
-		for _, p := range players {
-			byPos[p.Position] = p
-		}
+// nextBotTurn returns the bot turn the game is waiting on, or nil when it is waiting on a human
+// or isn't running. It only looks; decideBotMove picks the move.
+func nextBotTurn(db *sql.DB, gameID int64) (*botTurn, error) {
+	players, err := models.ListGamePlayersByGame(db, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if len(players) == 0 {
+		return nil, nil
+	}
 
-		switch stage {
-		case "discard":
-			// Let any bots who haven't discarded yet discard their required cards.
-			discardCount := 1
-			if maxPlayers == 2 {
-				discardCount = 2
-			}
+	st, unlock, err := ensureGameStateLocked(db, gameID, players)
+	if err != nil {
+		// If the game isn't ready (e.g., lobby not full), just stop.
+		return nil, nil
+	}
+	stage := st.Stage
+	currentIdx := st.CurrentIndex
+	peggingTotal := st.PeggingTotal
+	peggingSeq := append([]common.Card(nil), st.PeggingSeq...)
+	discardCompleted := append([]bool(nil), st.DiscardCompleted...)
+	discardN := st.Rules.DiscardCount()
+	dealerIdx := st.DealerIndex
+	unlock()
 
-			didOne := false
-			for _, p := range players {
-				if !p.IsBot {
-					continue
-				}
-				pos := int(p.Position)
-				if pos < 0 || pos >= len(discardCompleted) {
-					continue
-				}
-				if discardCompleted[pos] {
-					continue
-				}
-				var hand []common.Card
-				if err := json.Unmarshal([]byte(p.Hand), &hand); err != nil {
-					return err
-				}
-				diff := cribbage.BotEasy
-				if p.BotDifficulty != nil {
-					diff = cribbage.BotDifficulty(*p.BotDifficulty)
-				}
-				discards, err := cribbage.ChooseDiscardN(hand, discardCount, diff)
-				if err != nil {
-					return err
-				}
-				var out []string
-				for _, c := range discards {
-					out = append(out, c.String())
-				}
-				_, err = ApplyMove(db, gameID, p.UserID, moveRequest{Type: "discard", Cards: out})
-				if err != nil {
-					return err
-				}
-				didOne = true
-				break
-			}
-			if didOne {
+	switch stage {
+	case "discard":
+		// Bots who haven't discarded yet go in seat-list order.
+		for _, p := range players {
+			pos := int(p.Position)
+			if !p.IsBot || pos < 0 || pos >= len(discardCompleted) || discardCompleted[pos] {
 				continue
 			}
-			return nil
-
-		case "pegging":
-			gp, ok := byPos[int64(currentIdx)]
-			if !ok || !gp.IsBot {
-				return nil
-			}
 			var hand []common.Card
-			if err := json.Unmarshal([]byte(gp.Hand), &hand); err != nil {
-				return err
+			if err := json.Unmarshal([]byte(p.Hand), &hand); err != nil {
+				return nil, err
 			}
-			diff := cribbage.BotEasy
-			if gp.BotDifficulty != nil {
-				diff = cribbage.BotDifficulty(*gp.BotDifficulty)
+			return &botTurn{player: p, stage: stage, hand: hand, discardN: discardN, ownCrib: pos == dealerIdx}, nil
+		}
+		return nil, nil
+
+	case "pegging":
+		for _, p := range players {
+			if int(p.Position) != currentIdx {
+				continue
 			}
-			card, goPlay := cribbage.ChoosePeggingPlay(hand, peggingTotal, peggingSeq, diff)
-			var mr moveRequest
-			if goPlay {
-				mr = moveRequest{Type: "go"}
-			} else {
-				mr = moveRequest{Type: "play_card", Card: card.String()}
+			if !p.IsBot {
+				return nil, nil
 			}
-			if _, err := ApplyMove(db, gameID, gp.UserID, mr); err != nil {
-				return err
+			var hand []common.Card
+			if err := json.Unmarshal([]byte(p.Hand), &hand); err != nil {
+				return nil, err
 			}
-			continue
+			return &botTurn{player: p, stage: stage, hand: hand, peggingSum: peggingTotal, peggingSeq: peggingSeq}, nil
+		}
+		return nil, nil
 
-		default:
-			return nil
+	case "counting":
+		return &botTurn{count: true, players: players, stage: stage}, nil
+
+	default:
+		return nil, nil
+	}
+}
+
+// decideBotMove picks the bot's move for a discard or pegging turn.
+func decideBotMove(gameID int64, t *botTurn) (moveRequest, error) {
+	if t.stage == "discard" {
+		difficulty, release := acquireBotDifficulty(gameID, seatBotDifficulty(t.player))
+		discards, err := cribbage.ChooseDiscardN(t.hand, t.discardN, difficulty, t.ownCrib)
+		release()
+		if err != nil {
+			return moveRequest{}, err
+		}
+		var out []string
+		for _, c := range discards {
+			out = append(out, c.String())
+		}
+		return moveRequest{Type: "discard", Cards: out}, nil
+	}
+	card, goPlay := cribbage.ChoosePeggingPlay(t.hand, t.peggingSum, t.peggingSeq, seatBotDifficulty(t.player))
+	if goPlay {
+		return moveRequest{Type: "go"}, nil
+	}
+	return moveRequest{Type: "play_card", Card: card.String()}, nil
+}
+
+// playBotTurn carries out t. done reports that the bots have nothing further to do, which is
+// always the case after counting.
+func playBotTurn(db *sql.DB, gameID int64, t *botTurn) (done bool, err error) {
+	if t.count {
+		return true, autoCountBots(db, gameID, t.players)
+	}
+	mr, err := decideBotMove(gameID, t)
+	if err != nil {
+		return false, err
+	}
+	if _, err := applyMove(db, gameID, t.player.UserID, mr, true); err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+// botThinkTime is how long a bot pauses before t: BotThinkTime scaled by its difficulty, and
+// nothing for counting, which is bookkeeping rather than a turn anyone watches.
+func botThinkTime(t *botTurn) time.Duration {
+	base := currentConfig().BotThinkTime
+	if t.count {
+		return 0
+	}
+	switch seatBotDifficulty(t.player) {
+	case cribbage.BotEasy:
+		return base / 2
+	case cribbage.BotHard:
+		return base * 3 / 2
+	default:
+		return base
+	}
+}
+
+// maybeRunBotTurns plays every bot turn the game is waiting on. With no BotThinkTime it plays
+// them before returning; otherwise it hands the game to the GameManager bot scheduler, which
+// plays them one at a time on its own goroutine, pausing before each, and returns at once.
+func maybeRunBotTurns(db *sql.DB, gameID int64) error {
+	if currentConfig().BotThinkTime > 0 {
+		defaultGameManager.ScheduleBots(gameID, func() { runPacedBotTurns(db, gameID) })
+		return nil
+	}
+	for step := 0; step < maxBotSteps; step++ {
+		t, err := nextBotTurn(db, gameID)
+		if err != nil || t == nil {
+			return err
+		}
+		if done, err := playBotTurn(db, gameID, t); err != nil || done {
+			return err
 		}
 	}
 	return fmt.Errorf("bot loop exceeded max steps (game_id=%d)", gameID)
 }
 
+// maxBotSteps bounds the bot turns played in one go; a safety net against a bot loop.
+const maxBotSteps = 64
+
+// runPacedBotTurns is the scheduler body for paced bots. It sleeps holding no locks, then looks
+// at the game again, since a human may have resigned or undone in the meantime, and plays
+// whatever bot turn is due now. Each turn is broadcast on its own so clients see the bots act
+// one by one; the request that handed them the turn has long returned.
+func runPacedBotTurns(db *sql.DB, gameID int64) {
+	for step := 0; step < maxBotSteps; step++ {
+		t, err := nextBotTurn(db, gameID)
+		if err != nil || t == nil {
+			if err != nil {
+				log.Printf("bot scheduler: game_id=%d err=%v", gameID, err)
+			}
+			return
+		}
+		if d := botThinkTime(t); d > 0 {
+			time.Sleep(d)
+			if t, err = nextBotTurn(db, gameID); err != nil || t == nil {
+				if err != nil {
+					log.Printf("bot scheduler: game_id=%d err=%v", gameID, err)
+				}
+				return
+			}
+		}
+		done, err := playBotTurn(db, gameID, t)
+		if err != nil {
+			log.Printf("bot scheduler: game_id=%d err=%v", gameID, err)
+			return
+		}
+		if err := maybeFinalizeGame(context.Background(), db, gameID); err != nil {
+			log.Printf("bot scheduler: maybeFinalizeGame failed: game_id=%d err=%v", gameID, err)
+		}
+		broadcastGameUpdate(db, gameID)
+		if done {
+			return
+		}
+	}
+	log.Printf("bot scheduler: bot loop exceeded max steps (game_id=%d)", gameID)
+}
+
+// autoGoStep is a go the server said for a seat with no legal card.
+type autoGoStep struct {
+	Player  int `json:"player"`
+	Awarded int `json:"awarded"` // last-card points the go handed to whoever played last
+}
+
+// autoGo says go for each seat left on turn after a play that holds cards but none that fit
+// under 31, as the engine would accept from them anyway, so a player who forgets to press Go
+// can't stall the game. It stops at the first seat that can play or when pegging ends. res is
+// the resolution of the go that ended the sequence, if one did.
+func autoGo(st *cribbage.State) (goes []autoGoStep, res *cribbage.GoResolution, err error) {
+	for i := 0; i < 2*st.Rules.MaxPlayers && st.Stage == "pegging"; i++ {
+		cur := st.CurrentIndex
+		if cur < 0 || cur >= len(st.Hands) || len(st.Hands[cur]) == 0 || st.CanPlay(cur) {
+			break
+		}
+		awarded, r, err := st.GoWithResolution(cur)
+		if err != nil {
+			return goes, res, err
+		}
+		goes = append(goes, autoGoStep{Player: cur, Awarded: awarded})
+		if r != nil {
+			res = r
+		}
+	}
+	return goes, res, nil
+}
+
+// heelsMove returns the "heels" move crediting the dealer when the discard just made cut a jack,
+// or nil. The engine has already added the points; the move records them in the game log.
+func heelsMove(gameID int64, players []models.GamePlayer, st *cribbage.State) *models.GameMove {
+	if st.Stage == "discard" || st.Cut == nil || st.Cut.Rank != common.Jack {
+		return nil
+	}
+	for _, p := range players {
+		if int(p.Position) == st.DealerIndex {
+			card := st.Cut.String()
+			points := int64(cribbage.HeelsPoints)
+			return &models.GameMove{GameID: gameID, PlayerID: p.UserID, MoveType: "heels", CardPlayed: &card, ScoreVerified: &points}
+		}
+	}
+	return nil
+}
+
 func randSuffix(n int) (string, error) {
 	if n <= 0 {
 		return "", nil
@@ -478,6 +909,17 @@ func randSuffix(n int) (string, error) {
 	return string(b), nil
 }
 
+// seatBotDifficulty returns the bot seat's difficulty, treating missing or unrecognized stored
+// values as easy.
+func seatBotDifficulty(p models.GamePlayer) cribbage.BotDifficulty {
+	if p.BotDifficulty != nil {
+		if d, err := cribbage.ParseBotDifficulty(*p.BotDifficulty); err == nil {
+			return d
+		}
+	}
+	return cribbage.BotEasy
+}
+
 func ensureGameStateLocked(db *sql.DB, gameID int64, players []models.GamePlayer) (*cribbage.State, func(), error) {
 	playerCount := len(players)
 	return defaultGameManager.GetOrCreateLocked(gameID, func() (*cribbage.State, error) {
@@ -491,19 +933,43 @@ func ensureGameStateLocked(db *sql.DB, gameID int64, players []models.GamePlayer
 				return nil, err
 			}
 			restored.Version = ver
+			if restored.Stage == "dealing" {
+				// Undealt: the lobby is still filling, so fewer seats than MaxPlayers is expected.
+				if restored.Rules.Validate() != nil || playerCount > restored.Rules.MaxPlayers {
+					return nil, models.ErrInvalidJSON
+				}
+				return &restored, nil
+			}
 			// Sanity: if this doesn't match the current lobby size, we cannot safely resume.
-			if restored.Rules.MaxPlayers != playerCount ||
+			if restored.Rules.Validate() != nil ||
+				restored.Rules.MaxPlayers != playerCount ||
 				len(restored.Hands) != playerCount ||
 				len(restored.KeptHands) != playerCount ||
 				len(restored.Scores) != playerCount {
 				return nil, models.ErrInvalidJSON
 			}
+			if err := restored.RepairScores(); err != nil {
+				log.Printf("ensureGameStateLocked: repaired persisted scores: game_id=%d err=%v scores=%v stage=%s", gameID, err, restored.Scores, restored.Stage)
+				if restored.Stage == "finished" {
+					// The repair declared a winner; record the result once our caller releases the lock.
+					go func() {
+						if err := maybeFinalizeGame(context.Background(), db, gameID); err != nil {
+							log.Printf("maybeFinalizeGame failed after score repair: game_id=%d err=%v", gameID, err)
+						}
+					}()
+				}
+			}
+			if restored.TurnDeadline != nil {
+				// Time spent down doesn't count against whoever was on turn.
+				restored.RestartTurnClock()
+			}
 			return &restored, nil
 		}
 
 		tmp := cribbage.NewState(playerCount)
-		// If hands already exist in DB (e.g., after restart) but full state is missing,
-		// do NOT attempt to resume: hands alone are insufficient to reconstruct the game.
+		// If hands already exist in DB (e.g., after restart) but full state is missing, the
+		// process died mid-deal. Hands alone are insufficient to reconstruct the game, so the
+		// only safe repair is a fresh deal, and only while no move has been recorded.
 		hasHands := false
 		for _, p := range players {
 			if strings.TrimSpace(p.Hand) != "" && strings.TrimSpace(p.Hand) != "[]" {
@@ -511,18 +977,13 @@ func ensureGameStateLocked(db *sql.DB, gameID int64, players []models.GamePlayer
 				break
 			}
 		}
-		if hasHands {
-			// Without a full persisted engine state, we cannot safely resume an in-progress game.
-			// (Hands alone are insufficient to reconstruct deck/cut/crib/scores/pegging history/etc.)
-			return nil, models.ErrGameStateMissing
-		}
 
 		if err := tmp.Deal(); err != nil {
 			return nil, err
 		}
 
 		// Persist initial dealt hands immediately so a restart doesn't lose the deal.
-		// This is idempotent: it only updates rows whose hand is still the default '[]'.
+		// For a fresh game this is idempotent: it only updates rows whose hand is still '[]'.
 		tx, err := db.Begin()
 		if err != nil {
 			return nil, err
@@ -531,6 +992,23 @@ func ensureGameStateLocked(db *sql.DB, gameID int64, players []models.GamePlayer
 			// rollback is safe even after commit
 			_ = tx.Rollback()
 		}()
+		if hasHands {
+			// Re-check inside the transaction so a game that has seen any play is never re-dealt.
+			played, err := models.GameHasMovesTx(tx, gameID)
+			if err != nil {
+				return nil, err
+			}
+			hasState, err := models.HasGameStateTx(tx, gameID)
+			if err != nil {
+				return nil, err
+			}
+			if played || hasState {
+				// Without a full persisted engine state, we cannot safely resume an in-progress game.
+				// (Hands alone are insufficient to reconstruct deck/cut/crib/scores/pegging history/etc.)
+				return nil, models.ErrGameStateMissing
+			}
+			log.Printf("ensureGameStateLocked: re-dealing game with hands but no state and no moves: game_id=%d", gameID)
+		}
 		for _, p := range players {
 			pos := int(p.Position)
 			if pos < 0 || pos >= len(tmp.Hands) {
@@ -540,6 +1018,13 @@ func ensureGameStateLocked(db *sql.DB, gameID int64, players []models.GamePlayer
 			if err != nil {
 				return nil, err
 			}
+			if hasHands {
+				// Repairing an interrupted deal: the stale hands were never played, replace them.
+				if err := models.UpdatePlayerHandTx(tx, gameID, p.UserID, string(b)); err != nil {
+					return nil, err
+				}
+				continue
+			}
 			if _, err := models.UpdatePlayerHandIfEmptyTx(tx, gameID, p.UserID, string(b)); err != nil {
 				return nil, err
 			}
@@ -601,6 +1086,15 @@ func cloneStateDeep(st *cribbage.State) cribbage.State {
 	if st.Scores != nil {
 		out.Scores = append([]int(nil), st.Scores...)
 	}
+	if st.SequencePegs != nil {
+		out.SequencePegs = append([]cribbage.PegEvent(nil), st.SequencePegs...)
+	}
+	if st.LastSequencePegs != nil {
+		out.LastSequencePegs = append([]cribbage.PegEvent(nil), st.LastSequencePegs...)
+	}
+	if st.HandPegging != nil {
+		out.HandPegging = append([]int(nil), st.HandPegging...)
+	}
 	out.Hands = make([][]common.Card, len(st.Hands))
 	for i := range st.Hands {
 		out.Hands[i] = append([]common.Card(nil), st.Hands[i]...)
@@ -609,5 +1103,36 @@ func cloneStateDeep(st *cribbage.State) cribbage.State {
 	for i := range st.KeptHands {
 		out.KeptHands[i] = append([]common.Card(nil), st.KeptHands[i]...)
 	}
+	if st.Discards != nil {
+		out.Discards = make([][]common.Card, len(st.Discards))
+		for i := range st.Discards {
+			out.Discards[i] = append([]common.Card(nil), st.Discards[i]...)
+		}
+	}
+	// Round summaries and events are append-only and never mutated, so sharing them is safe;
+	// the slices are still copied so appends to the clone can't write into the original.
+	if st.History != nil {
+		out.History = append([]cribbage.RoundSummary(nil), st.History...)
+	}
+	if st.Events != nil {
+		out.Events = append([]cribbage.Event(nil), st.Events...)
+	}
+	if st.ReadyNextHand != nil {
+		out.ReadyNextHand = append([]bool(nil), st.ReadyNextHand...)
+	}
+	// Count summaries are replaced, never mutated, so the pointer can be shared.
+	out.CountSummary = st.CountSummary
+	if st.TurnDeadline != nil {
+		d := *st.TurnDeadline
+		out.TurnDeadline = &d
+	}
+	if st.TurnTimeouts != nil {
+		out.TurnTimeouts = append([]int(nil), st.TurnTimeouts...)
+	}
+	if st.PeggingUndo != nil {
+		u := *st.PeggingUndo
+		u.Scores = append([]int(nil), u.Scores...)
+		out.PeggingUndo = &u
+	}
 	return out
 }
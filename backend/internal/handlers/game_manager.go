@@ -1,7 +1,11 @@
 package handlers
 
 import (
+	"context"
+	"log"
+	"sort"
 	"sync"
+	"time"
 
 	"fifteen-thirty-one-go/backend/internal/game/cribbage"
 )
@@ -9,15 +13,28 @@ import (
 type gameEntry struct {
 	mu    sync.Mutex
 	state *cribbage.State
+	// dead marks an entry whose create failed. It is set under mu just before the entry is
+	// removed from the map, so anyone who locks it afterwards starts over with a fresh entry.
+	dead bool
 }
 
 type GameManager struct {
 	mu    sync.RWMutex
 	games map[int64]*gameEntry
+
+	// botMu guards botRuns. It is a leaf lock: nothing else is taken while it is held.
+	botMu   sync.Mutex
+	botRuns map[int64]*botRun
+}
+
+// botRun is the bot scheduler a game has running. again asks it for one more pass before it
+// exits, for bot turns handed out while it was finishing the last one.
+type botRun struct {
+	again bool
 }
 
 func NewGameManager() *GameManager {
-	return &GameManager{games: map[int64]*gameEntry{}}
+	return &GameManager{games: map[int64]*gameEntry{}, botRuns: map[int64]*botRun{}}
 }
 
 func (m *GameManager) GetLocked(gameID int64) (*cribbage.State, func(), bool) {
@@ -54,6 +71,12 @@ func (m *GameManager) Set(gameID int64, st *cribbage.State) {
 		m.games[gameID] = e
 	}
 	e.mu.Lock()
+	if e.dead {
+		e.mu.Unlock()
+		e = &gameEntry{}
+		m.games[gameID] = e
+		e.mu.Lock()
+	}
 	m.mu.Unlock()
 	e.state = st
 	e.mu.Unlock()
@@ -75,43 +98,135 @@ func (m *GameManager) Delete(gameID int64) {
 }
 
 func (m *GameManager) GetOrCreateLocked(gameID int64, createFn func() (*cribbage.State, error)) (*cribbage.State, func(), error) {
-	m.mu.Lock()
-	e, ok := m.games[gameID]
-	if !ok || e == nil {
-		e = &gameEntry{}
-		m.games[gameID] = e
+	for {
+		m.mu.Lock()
+		e, ok := m.games[gameID]
+		if !ok || e == nil {
+			e = &gameEntry{}
+			m.games[gameID] = e
+		}
+		e.mu.Lock()
+		m.mu.Unlock()
+		if e.dead {
+			// A concurrent create failed; its entry is on the way out of the map.
+			e.mu.Unlock()
+			continue
+		}
+		if e.state != nil {
+			return e.state, func() { e.mu.Unlock() }, nil
+		}
+		st, err := m.createLocked(gameID, e, createFn)
+		if err != nil {
+			return nil, nil, err
+		}
+		return st, func() { e.mu.Unlock() }, nil
 	}
-	e.mu.Lock()
-	m.mu.Unlock()
+}
 
+// createLocked runs createFn for e, which the caller has locked. On error or panic it retires
+// e and unlocks it; the map lock is only taken after e.mu is released, keeping the lock order
+// (map, then entry) that GetLocked, Set and Delete rely on.
+func (m *GameManager) createLocked(gameID int64, e *gameEntry, createFn func() (*cribbage.State, error)) (st *cribbage.State, err error) {
+	retire := func() {
+		e.dead = true
+		e.mu.Unlock()
+		m.mu.Lock()
+		if m.games[gameID] == e {
+			delete(m.games, gameID)
+		}
+		m.mu.Unlock()
+	}
 	defer func() {
 		if r := recover(); r != nil {
 			// Clean up on panic so future calls don't deadlock.
-			m.mu.Lock()
-			if m.games[gameID] == e {
-				delete(m.games, gameID)
-			}
-			m.mu.Unlock()
-			e.mu.Unlock()
+			retire()
 			panic(r)
 		}
 	}()
+	st, err = createFn()
+	if err != nil {
+		// Remove the placeholder entry on failure so future attempts can retry.
+		retire()
+		return nil, err
+	}
+	e.state = st
+	return st, nil
+}
 
-	if e.state == nil {
-		st, err := createFn()
-		if err != nil {
-			// Remove placeholder entry on failure so future attempts can retry.
-			m.mu.Lock()
-			if m.games[gameID] == e {
-				delete(m.games, gameID)
+// ExpiredTurns returns, in id order, the loaded games whose turn clock ran out by now.
+func (m *GameManager) ExpiredTurns(now time.Time) []int64 {
+	m.mu.RLock()
+	entries := make(map[int64]*gameEntry, len(m.games))
+	for id, e := range m.games {
+		entries[id] = e
+	}
+	m.mu.RUnlock()
+
+	var out []int64
+	for id, e := range entries {
+		// Only the entry lock is taken (never the map lock after it), so lock order holds.
+		e.mu.Lock()
+		if !e.dead && e.state != nil && e.state.TurnExpired(now) {
+			out = append(out, id)
+		}
+		e.mu.Unlock()
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+// RunTurnTimer calls onExpired for every loaded game whose turn ran out, checking each interval
+// until ctx is cancelled. onExpired runs on the timer goroutine with no locks held.
+func (m *GameManager) RunTurnTimer(ctx context.Context, interval time.Duration, onExpired func(gameID int64)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for _, id := range m.ExpiredTurns(now) {
+				onExpired(id)
 			}
-			m.mu.Unlock()
-			e.mu.Unlock()
-			return nil, nil, err
 		}
-		e.state = st
 	}
-	return e.state, func() { e.mu.Unlock() }, nil
+}
+
+// ScheduleBots runs run on a goroutine for gameID, at most one at a time per game: if one is
+// already running it is told to call run again when it finishes instead, so two schedulers
+// never play the same bot turn. run is called with no locks held.
+func (m *GameManager) ScheduleBots(gameID int64, run func()) {
+	m.botMu.Lock()
+	if r, ok := m.botRuns[gameID]; ok {
+		r.again = true
+		m.botMu.Unlock()
+		return
+	}
+	r := &botRun{}
+	m.botRuns[gameID] = r
+	m.botMu.Unlock()
+
+	go func() {
+		defer func() {
+			if rec := recover(); rec != nil {
+				log.Printf("bot scheduler panic: game_id=%d panic=%v", gameID, rec)
+				m.botMu.Lock()
+				delete(m.botRuns, gameID)
+				m.botMu.Unlock()
+			}
+		}()
+		for {
+			run()
+			m.botMu.Lock()
+			if !r.again {
+				delete(m.botRuns, gameID)
+				m.botMu.Unlock()
+				return
+			}
+			r.again = false
+			m.botMu.Unlock()
+		}
+	}()
 }
 
 var defaultGameManager = NewGameManager()
@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"errors"
+	"flag"
+	"math/rand"
+	"runtime/pprof"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"fifteen-thirty-one-go/backend/internal/game/cribbage"
+)
+
+// The stress test hammers GameManager from many goroutines over overlapping game ids to surface
+// locking bugs in the game-state hot path. CI runs it longer under the race detector:
+//
+//	go test -race -run TestGameManagerStress ./internal/handlers -gmstress.duration 10s
+var (
+	gmStressDuration = flag.Duration("gmstress.duration", time.Second, "length of TestGameManagerStress's mixed-operation phase")
+	gmStressWorkers  = flag.Int("gmstress.workers", 64, "concurrent goroutines in TestGameManagerStress")
+	gmStressGames    = flag.Int("gmstress.games", 8, "distinct game ids shared by TestGameManagerStress's workers")
+)
+
+var errInjectedCreate = errors.New("injected create failure")
+
+// gmStress tracks progress and invariant failures across a stress run's goroutines.
+type gmStress struct {
+	t        *testing.T
+	games    int
+	progress atomic.Int64
+}
+
+// watchdog fails the test with every goroutine's stack when no operation completes for stall.
+func (s *gmStress) watchdog(stall time.Duration, done <-chan struct{}) {
+	tick := time.NewTicker(stall / 4)
+	defer tick.Stop()
+	last, lastChange := s.progress.Load(), time.Now()
+	for {
+		select {
+		case <-done:
+			return
+		case <-tick.C:
+		}
+		if cur := s.progress.Load(); cur != last {
+			last, lastChange = cur, time.Now()
+			continue
+		}
+		if time.Since(lastChange) >= stall {
+			var b strings.Builder
+			_ = pprof.Lookup("goroutine").WriteTo(&b, 2)
+			panic("GameManager made no progress for " + stall.String() + " (deadlock?); goroutines:\n" + b.String())
+		}
+	}
+}
+
+// createOnce races every worker through GetOrCreateLocked for every game on a fresh manager
+// and checks that createFn ran exactly once per game and every worker got the same state.
+func (s *gmStress) createOnce(workers int) {
+	m := NewGameManager()
+	creates := make([]atomic.Int64, s.games+1)
+	got := make([]atomic.Pointer[cribbage.State], s.games+1)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < s.games; i++ {
+				id := int64((w+i)%s.games + 1)
+				st, unlock, err := m.GetOrCreateLocked(id, func() (*cribbage.State, error) {
+					creates[id].Add(1)
+					time.Sleep(50 * time.Microsecond) // widen the window for a second creator
+					return &cribbage.State{}, nil
+				})
+				if err != nil {
+					s.t.Errorf("create-once: game %d: unexpected error %v", id, err)
+					continue
+				}
+				st.Version++
+				unlock()
+				if prev := got[id].Swap(st); prev != nil && prev != st {
+					s.t.Errorf("create-once: game %d: workers saw different states", id)
+				}
+				s.progress.Add(1)
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	for id := 1; id <= s.games; id++ {
+		if n := creates[id].Load(); n != 1 {
+			s.t.Errorf("create-once: game %d: createFn ran %d times", id, n)
+		}
+		st, unlock, ok := m.GetLocked(int64(id))
+		if !ok {
+			s.t.Errorf("create-once: game %d: missing after create", id)
+			continue
+		}
+		if st.Version != int64(workers) {
+			s.t.Errorf("create-once: game %d: version %d after %d locked increments", id, st.Version, workers)
+		}
+		unlock()
+	}
+}
+
+// mixed runs random GetLocked, Set, Delete and GetOrCreateLocked calls (with creates that fail
+// or panic) until d elapses, checking that creates for one game never overlap and that state
+// is only touched under its lock. It returns how many operations completed.
+func (s *gmStress) mixed(workers int, d time.Duration, seed int64) int64 {
+	m := NewGameManager()
+	inflight := make([]atomic.Int32, s.games+1)
+	deadline := time.Now().Add(d)
+	var ops atomic.Int64
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(rng *rand.Rand) {
+			defer wg.Done()
+			for time.Now().Before(deadline) {
+				id := int64(rng.Intn(s.games) + 1)
+				s.mixedOp(m, rng, id, &inflight[id])
+				ops.Add(1)
+				s.progress.Add(1)
+			}
+		}(rand.New(rand.NewSource(seed + int64(w))))
+	}
+	wg.Wait()
+	return ops.Load()
+}
+
+func (s *gmStress) mixedOp(m *GameManager, rng *rand.Rand, id int64, inflight *atomic.Int32) {
+	switch p := rng.Intn(100); {
+	case p < 35:
+		mode := rng.Intn(20) // 0: error, 1: panic, otherwise succeed
+		defer func() {
+			if r := recover(); r != nil && mode != 1 {
+				s.t.Errorf("mixed: game %d: unexpected panic %v", id, r)
+			}
+		}()
+		st, unlock, err := m.GetOrCreateLocked(id, func() (*cribbage.State, error) {
+			if n := inflight.Add(1); n != 1 {
+				s.t.Errorf("mixed: game %d: %d overlapping creates", id, n)
+			}
+			defer inflight.Add(-1)
+			switch mode {
+			case 0:
+				return nil, errInjectedCreate
+			case 1:
+				panic("injected create panic")
+			}
+			return &cribbage.State{}, nil
+		})
+		if err != nil {
+			if !errors.Is(err, errInjectedCreate) {
+				s.t.Errorf("mixed: game %d: unexpected error %v", id, err)
+			}
+			return
+		}
+		if st == nil {
+			s.t.Errorf("mixed: game %d: nil state from GetOrCreateLocked", id)
+			unlock()
+			return
+		}
+		st.Version++ // a racing unlocked writer shows up under -race
+		unlock()
+	case p < 75:
+		if st, unlock, ok := m.GetLocked(id); ok {
+			st.Version++
+			unlock()
+		}
+	case p < 90:
+		m.Set(id, &cribbage.State{})
+	default:
+		m.Delete(id)
+	}
+}
+
+func TestGameManagerStress(t *testing.T) {
+	if testing.Short() {
+		t.Skip("stress test skipped in -short mode")
+	}
+	if *gmStressWorkers <= 0 || *gmStressGames <= 0 {
+		t.Fatalf("-gmstress.workers and -gmstress.games must be positive")
+	}
+	s := &gmStress{t: t, games: *gmStressGames}
+	done := make(chan struct{})
+	defer close(done)
+	go s.watchdog(10*time.Second, done)
+
+	seed := time.Now().UnixNano()
+	t.Logf("seed=%d workers=%d games=%d duration=%s", seed, *gmStressWorkers, *gmStressGames, *gmStressDuration)
+	for r := 0; r < 50; r++ {
+		s.createOnce(*gmStressWorkers)
+	}
+	ops := s.mixed(*gmStressWorkers, *gmStressDuration, seed)
+	t.Logf("mixed phase: %d ops", ops)
+}
+
+// A failed create must release the entry lock before it takes the map lock to retire the entry:
+// GetLocked holds the map's read lock while it waits for the entry, so the old order deadlocked.
+func TestGameManagerFailedCreateLockOrder(t *testing.T) {
+	m := NewGameManager()
+	waiting := make(chan struct{})
+	getDone := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		_, _, err := m.GetOrCreateLocked(1, func() (*cribbage.State, error) {
+			go func() {
+				close(waiting)
+				if _, unlock, ok := m.GetLocked(1); ok {
+					unlock()
+				}
+				close(getDone)
+			}()
+			<-waiting
+			time.Sleep(20 * time.Millisecond) // let GetLocked block on the entry under the map lock
+			return nil, errInjectedCreate
+		})
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, errInjectedCreate) {
+			t.Fatalf("GetOrCreateLocked error = %v, want the create failure", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("GetOrCreateLocked deadlocked retiring a failed create")
+	}
+	select {
+	case <-getDone:
+	case <-time.After(5 * time.Second):
+		t.Fatal("GetLocked never returned after the failed create")
+	}
+	if _, _, ok := m.GetLocked(1); ok {
+		t.Error("failed create left a state behind")
+	}
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"fifteen-thirty-one-go/backend/internal/game/cribbage"
+	"fifteen-thirty-one-go/backend/internal/models"
+)
+
+// gameRoundRecords converts completed engine rounds into game_rounds rows. Seats map to users
+// through players; a seat with no player row is skipped.
+func gameRoundRecords(gameID int64, players []models.GamePlayer, rounds []cribbage.RoundSummary) ([]models.GameRound, error) {
+	userBySeat := make(map[int]int64, len(players))
+	for _, p := range players {
+		userBySeat[int(p.Position)] = p.UserID
+	}
+	out := make([]models.GameRound, 0, len(rounds))
+	for _, rs := range rounds {
+		summary, err := json.Marshal(rs)
+		if err != nil {
+			return nil, err
+		}
+		r := models.GameRound{GameID: gameID, Round: rs.Round, DealerIndex: rs.DealerIndex, Summary: summary}
+		if rs.Cut != nil {
+			r.Cut = rs.Cut.String()
+		}
+		if rs.Crib != nil {
+			v := int64(rs.Crib.Total)
+			r.CribPoints = &v
+		}
+		for seat, b := range rs.Hands {
+			userID, ok := userBySeat[seat]
+			if !ok {
+				continue
+			}
+			breakdown, err := json.Marshal(b)
+			if err != nil {
+				return nil, err
+			}
+			r.Hands = append(r.Hands, models.GameRoundHand{Seat: seat, UserID: userID, Points: int64(b.Total), Breakdown: breakdown})
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+// insertGameRoundsTx records rounds the engine completed while applying a move, in the move's
+// transaction.
+func insertGameRoundsTx(tx *sql.Tx, gameID int64, players []models.GamePlayer, rounds []cribbage.RoundSummary) error {
+	records, err := gameRoundRecords(gameID, players, rounds)
+	if err != nil {
+		return err
+	}
+	for _, r := range records {
+		if err := models.InsertGameRoundTx(tx, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// persistedRoundHistory rebuilds the round history from game_rounds, for when no engine state
+// is available.
+func persistedRoundHistory(ctx context.Context, db *sql.DB, gameID int64) ([]cribbage.RoundSummary, error) {
+	rounds, err := models.ListGameRounds(ctx, db, gameID)
+	if err != nil {
+		return nil, err
+	}
+	history := make([]cribbage.RoundSummary, 0, len(rounds))
+	for _, r := range rounds {
+		var rs cribbage.RoundSummary
+		if err := json.Unmarshal(r.Summary, &rs); err != nil {
+			return nil, err
+		}
+		history = append(history, rs)
+	}
+	return history, nil
+}
@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+
+	"fifteen-thirty-one-go/backend/internal/config"
+	"fifteen-thirty-one-go/backend/internal/game/common"
+	"fifteen-thirty-one-go/backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCountIsAlwaysForYourOwnHand(t *testing.T) {
+	db := newTestDB(t)
+	gameID, users := newTestGame(t, db, "alice", "bob")
+	alice, bob := users[0], users[1]
+
+	parse := func(s ...string) []common.Card {
+		out := make([]common.Card, len(s))
+		for i, c := range s {
+			card, err := common.ParseCard(c)
+			if err != nil {
+				t.Fatalf("parse %s: %v", c, err)
+			}
+			out[i] = card
+		}
+		return out
+	}
+	cut := parse("5C")[0]
+	st, unlock, _ := defaultGameManager.GetLocked(gameID)
+	st.Stage = "counting"
+	st.Cut = &cut
+	st.DealerIndex = 0
+	// Alice (the dealer) holds a 29; bob's hand is worth 4.
+	st.KeptHands = [][]common.Card{parse("5H", "5D", "5S", "JC"), parse("AH", "2D", "4S", "9C")}
+	unlock()
+
+	path := fmt.Sprintf("/games/%d/count", gameID)
+	for _, body := range []gin.H{
+		{"kind": "hand", "claim": 29, "user_id": alice},
+		{"kind": "hand", "claim": 29, "player_id": alice},
+		{"kind": "hand", "claim": 29, "seat": 0},
+		{"kind": "hand", "claim": 29, "position": 0},
+	} {
+		var out struct {
+			Code string `json:"code"`
+		}
+		code := doRequest(t, CountHandler(db), http.MethodPost, "/games/:id/count", path, bob, body, &out)
+		if code != http.StatusBadRequest || out.Code != "count_unknown_field" {
+			t.Errorf("bob counts with %v: status %d code %q, want 400 count_unknown_field", body, code, out.Code)
+		}
+	}
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM game_moves WHERE game_id = ? AND move_type LIKE 'count_%'`, gameID); n != 0 {
+		t.Fatalf("%d counts recorded after rejected requests, want none", n)
+	}
+
+	// Without a target the count is bob's own hand, verified against his cards.
+	var out struct {
+		Verified int64 `json:"verified"`
+	}
+	code := doRequest(t, CountHandler(db), http.MethodPost, "/games/:id/count", path, bob, gin.H{"kind": "hand", "claim": 29}, &out)
+	if code != http.StatusOK || out.Verified != 4 {
+		t.Errorf("bob counts his hand: status %d verified %d, want 200 and 4", code, out.Verified)
+	}
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM game_moves WHERE game_id = ? AND player_id = ? AND move_type LIKE 'count_%'`, gameID, alice); n != 0 {
+		t.Errorf("%d counts recorded for alice, want none", n)
+	}
+	if code := doRequest(t, CountHandler(db), http.MethodPost, "/games/:id/count", path, bob, gin.H{"kind": "crib", "claim": 0}, nil); code != http.StatusForbidden {
+		t.Errorf("bob counts alice's crib: status %d, want 403", code)
+	}
+}
+
+func TestMoveRejectedWhenPersistedHandDiverges(t *testing.T) {
+	db := newTestDB(t)
+	gameID, users := newTestGame(t, db, "alice", "bob")
+	alice, bob := users[0], users[1]
+
+	// Swap one of alice's persisted cards for one bob holds: the row no longer matches the
+	// engine's hand for her seat.
+	hand := seatHand(t, db, gameID, alice)
+	forged := append([]string{seatHand(t, db, gameID, bob)[0]}, hand[1:]...)
+	cards := make([]common.Card, len(forged))
+	for i, s := range forged {
+		c, err := common.ParseCard(s)
+		if err != nil {
+			t.Fatalf("parse %s: %v", s, err)
+		}
+		cards[i] = c
+	}
+	raw, err := json.Marshal(cards)
+	if err != nil {
+		t.Fatalf("encode hand: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE game_players SET hand = ? WHERE game_id = ? AND user_id = ?`, string(raw), gameID, alice); err != nil {
+		t.Fatalf("forge hand: %v", err)
+	}
+
+	path := fmt.Sprintf("/games/%d/move", gameID)
+	body := gin.H{"type": "discard", "cards": forged[:2]}
+	if code := doRequest(t, MoveHandler(db), http.MethodPost, "/games/:id/move", path, alice, body, nil); code != http.StatusConflict {
+		t.Errorf("discard from a diverged hand: status %d, want 409", code)
+	}
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM game_moves WHERE game_id = ?`, gameID); n != 0 {
+		t.Errorf("%d moves recorded from a diverged hand, want none", n)
+	}
+	st, unlock, _ := defaultGameManager.GetLocked(gameID)
+	engineHand := make([]string, len(st.Hands[0]))
+	for i, c := range st.Hands[0] {
+		engineHand[i] = c.String()
+	}
+	discarded := st.DiscardCompleted[0]
+	unlock()
+	if discarded || !slices.Equal(engineHand, hand) {
+		t.Errorf("engine hand %v (discarded %t) after the rejected move, want %v untouched", engineHand, discarded, hand)
+	}
+}
+
+func TestResignHandsSeatToConfiguredBot(t *testing.T) {
+	db := newTestDB(t)
+	setTestConfig(t, func(cfg *config.Config) {
+		cfg.ResignPolicy = "bot_takeover"
+		cfg.ResignBotDifficulty = "hard"
+	})
+	gameID, users := newTestGame(t, db, "alice", "bob", "carol")
+	alice := users[0]
+	versionBefore := queryInt(t, db, `SELECT state_version FROM games WHERE id = ?`, gameID)
+
+	var out struct {
+		GameContinues bool `json:"game_continues"`
+	}
+	path := fmt.Sprintf("/games/%d/resign", gameID)
+	if code := doRequest(t, ResignGameHandler(db), http.MethodPost, "/games/:id/resign", path, alice, nil, &out); code != http.StatusOK || !out.GameContinues {
+		t.Fatalf("resign: status %d game_continues %t, want 200 and true", code, out.GameContinues)
+	}
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM game_players WHERE game_id = ? AND user_id = ? AND resigned = 1 AND is_bot = 1 AND bot_difficulty = 'hard'`, gameID, alice); n != 1 {
+		t.Errorf("alice's seat is not a resigned hard bot")
+	}
+	// The flip bumps the state version, so a move computed before it can't land afterwards.
+	if v := queryInt(t, db, `SELECT state_version FROM games WHERE id = ?`, gameID); v <= versionBefore {
+		t.Errorf("state_version %d after the resign, want past %d", v, versionBefore)
+	}
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM games WHERE id = ? AND status = 'finished'`, gameID); n != 0 {
+		t.Errorf("game finished on a bot takeover")
+	}
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM scoreboard WHERE game_id = ?`, gameID); n != 0 {
+		t.Errorf("%d scoreboard rows while the game continues, want none", n)
+	}
+	movePath := fmt.Sprintf("/games/%d/move", gameID)
+	body := gin.H{"type": "discard", "cards": seatHand(t, db, gameID, alice)[:1]}
+	if code := doRequest(t, MoveHandler(db), http.MethodPost, "/games/:id/move", movePath, alice, body, nil); code != http.StatusForbidden {
+		t.Errorf("move after resigning: status %d, want 403", code)
+	}
+}
+
+func TestResignEndGameRecordsForfeit(t *testing.T) {
+	db := newTestDB(t)
+	setTestConfig(t, func(cfg *config.Config) { cfg.ResignPolicy = "end_game" })
+	gameID, users := newTestGame(t, db, "alice", "bob", "carol")
+	alice, bob := users[0], users[1]
+
+	var out struct {
+		GameContinues bool `json:"game_continues"`
+	}
+	path := fmt.Sprintf("/games/%d/resign", gameID)
+	if code := doRequest(t, ResignGameHandler(db), http.MethodPost, "/games/:id/resign", path, bob, nil, &out); code != http.StatusOK || out.GameContinues {
+		t.Fatalf("resign: status %d game_continues %t, want 200 and false", code, out.GameContinues)
+	}
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM games WHERE id = ? AND status = 'finished'`, gameID); n != 1 {
+		t.Errorf("game not finished after an end_game resign")
+	}
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM scoreboard WHERE game_id = ? AND outcome = ?`, gameID, models.OutcomeForfeit); n != int64(len(users)) {
+		t.Errorf("%d forfeit scoreboard rows, want one per player (%d)", n, len(users))
+	}
+	if pos := queryInt(t, db, `SELECT position FROM scoreboard WHERE game_id = ? AND user_id = ?`, gameID, bob); pos != int64(len(users)) {
+		t.Errorf("resigner ranked %d, want last (%d)", pos, len(users))
+	}
+	if n := queryInt(t, db, `SELECT games_played FROM users WHERE id = ?`, alice); n != 1 {
+		t.Errorf("alice has %d games played, want 1", n)
+	}
+	if _, _, ok := defaultGameManager.GetLocked(gameID); ok {
+		t.Errorf("engine state kept for a finished game")
+	}
+
+	// Finalizing again must not add rows or count the game twice.
+	if err := maybeFinalizeGame(context.Background(), db, gameID); err != nil {
+		t.Fatalf("finalize: %v", err)
+	}
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM scoreboard WHERE game_id = ?`, gameID); n != int64(len(users)) {
+		t.Errorf("%d scoreboard rows after finalizing, want %d", n, len(users))
+	}
+}
+
+func TestCorrectionCommitsAgainstTheCountingState(t *testing.T) {
+	db := newTestDB(t)
+	gameID, users := newTestGame(t, db, "alice", "bob")
+	bob := users[1]
+
+	var hands [][]common.Card
+	for _, h := range [][]string{{"5H", "5D", "5S", "JC"}, {"AH", "2D", "4S", "9C", "5C"}} {
+		var hand []common.Card
+		for _, s := range h {
+			c, err := common.ParseCard(s)
+			if err != nil {
+				t.Fatalf("parse %s: %v", s, err)
+			}
+			hand = append(hand, c)
+		}
+		hands = append(hands, hand)
+	}
+	cut := hands[1][4]
+	setStage := func(stage string) {
+		st, unlock, _ := defaultGameManager.GetLocked(gameID)
+		st.Stage = stage
+		st.Cut = &cut
+		st.DealerIndex = 0
+		st.KeptHands = [][]common.Card{hands[0], hands[1][:4]}
+		unlock()
+	}
+	setStage("counting")
+
+	countPath := fmt.Sprintf("/games/%d/count", gameID)
+	if code := doRequest(t, CountHandler(db), http.MethodPost, "/games/:id/count", countPath, bob, gin.H{"kind": "hand", "claim": 2}, nil); code != http.StatusOK {
+		t.Fatalf("bob counts: status %d, want 200", code)
+	}
+	moveID := queryInt(t, db, `SELECT MAX(id) FROM game_moves WHERE game_id = ? AND player_id = ? AND move_type LIKE 'count_%'`, gameID, bob)
+	path := fmt.Sprintf("/games/%d/correct", gameID)
+	body := gin.H{"move_id": moveID, "new_claim": 4}
+
+	// Once the next hand is under way the count is no longer open to correction.
+	setStage("discard")
+	var out struct {
+		Code string `json:"code"`
+	}
+	if code := doRequest(t, CorrectHandler(db), http.MethodPost, "/games/:id/correct", path, bob, body, &out); code != http.StatusConflict || out.Code != "correction_wrong_stage" {
+		t.Errorf("correct after the deal: status %d code %q, want 409 correction_wrong_stage", code, out.Code)
+	}
+
+	setStage("counting")
+	versionBefore := queryInt(t, db, `SELECT state_version FROM games WHERE id = ?`, gameID)
+	if code := doRequest(t, CorrectHandler(db), http.MethodPost, "/games/:id/correct", path, bob, body, nil); code != http.StatusOK {
+		t.Fatalf("correct while counting: status %d, want 200", code)
+	}
+	if v := queryInt(t, db, `SELECT state_version FROM games WHERE id = ?`, gameID); v <= versionBefore {
+		t.Errorf("state_version %d after the correction, want past %d", v, versionBefore)
+	}
+	if code := doRequest(t, CorrectHandler(db), http.MethodPost, "/games/:id/correct", path, bob, body, nil); code != http.StatusConflict {
+		t.Errorf("second correction: status %d, want 409", code)
+	}
+}
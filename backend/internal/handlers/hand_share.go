@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"fifteen-thirty-one-go/backend/internal/game/common"
+	"fifteen-thirty-one-go/backend/internal/game/cribbage"
+	"fifteen-thirty-one-go/backend/internal/models"
+	"fifteen-thirty-one-go/backend/internal/tracing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ShareItem is one scoring line of a shared hand.
+type ShareItem struct {
+	Reason string `json:"reason"`
+	Points int    `json:"points"`
+}
+
+// HandShare is a render-ready description of one counted hand (or crib) for share images.
+type HandShare struct {
+	GameID    int64       `json:"game_id"`
+	HandIndex int         `json:"hand_index"` // 1-based round number
+	Seat      int         `json:"seat"`
+	Username  string      `json:"username"`
+	IsCrib    bool        `json:"is_crib"`
+	Cards     []string    `json:"cards"`
+	Cut       string      `json:"cut"`
+	Total     int         `json:"total"`
+	Items     []ShareItem `json:"items"`
+}
+
+// shareItems itemizes a breakdown in a fixed display order, skipping categories that scored nothing.
+func shareItems(b cribbage.ScoreBreakdown) []ShareItem {
+	items := []ShareItem{}
+	for _, it := range []ShareItem{
+		{"fifteens", b.Fifteens},
+		{"pairs", b.Pairs},
+		{"runs", b.Runs},
+		{"flush", b.Flush},
+		{"nobs", b.Nobs},
+	} {
+		if it.Points > 0 {
+			items = append(items, it)
+		}
+	}
+	return items
+}
+
+func cardCodes(cards []common.Card) []string {
+	out := make([]string, 0, len(cards))
+	for _, c := range cards {
+		out = append(out, c.String())
+	}
+	return out
+}
+
+// roundHistory returns the game's completed rounds from the in-memory engine when loaded,
+// otherwise from the persisted state, falling back to the game_rounds table.
+func roundHistory(ctx context.Context, db *sql.DB, gameID int64) ([]cribbage.RoundSummary, error) {
+	if st, unlock, ok := defaultGameManager.GetLocked(gameID); ok {
+		history := append([]cribbage.RoundSummary(nil), st.History...)
+		unlock()
+		return history, nil
+	}
+	raw, _, ok, err := models.GetGameStateJSON(db, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return persistedRoundHistory(ctx, db, gameID)
+	}
+	var persisted struct {
+		History []cribbage.RoundSummary `json:"history"`
+	}
+	if err := json.Unmarshal([]byte(raw), &persisted); err != nil {
+		return nil, err
+	}
+	return persisted.History, nil
+}
+
+// HandShareHandler returns a counted hand as a share payload. By default it is the caller's own
+// hand; ?seat=N selects another seat and ?crib=true the crib. A player may always share their own
+// hand (and their crib as dealer) once it has been counted; other seats are only available to
+// participants and spectators after the game has finished.
+func HandShareHandler(db *sql.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, span := tracing.StartSpan(c.Request.Context(), "handlers.HandShareHandler")
+		defer span.End()
+
+		gameID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || gameID <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid game id"})
+			return
+		}
+		handIndex, err := strconv.Atoi(c.Param("handIndex"))
+		if err != nil || handIndex <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hand index"})
+			return
+		}
+		userID, ok := userIDFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		allowed, err := canViewGame(db, userID, gameID)
+		if err != nil {
+			log.Printf("HandShareHandler: authorization check failed: game_id=%d user_id=%d err=%v", gameID, userID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		if !allowed {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+		g, err := models.GetGameByID(db, gameID)
+		if err != nil {
+			writeAPIError(c, err)
+			return
+		}
+		players, err := models.ListGamePlayersByGame(db, gameID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		mySeat := -1
+		for _, p := range players {
+			if p.UserID == userID {
+				mySeat = int(p.Position)
+			}
+		}
+
+		history, err := roundHistory(c.Request.Context(), db, gameID)
+		if err != nil {
+			log.Printf("HandShareHandler: load history failed: game_id=%d err=%v", gameID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		if handIndex > len(history) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "hand not found or not yet counted"})
+			return
+		}
+		round := history[handIndex-1]
+		if round.Cut == nil || round.Kept == nil {
+			// Rounds recorded before hands were kept in history can't be reconstructed.
+			c.JSON(http.StatusNotFound, gin.H{"error": "hand not available for sharing"})
+			return
+		}
+
+		isCrib := c.Query("crib") == "true"
+		seat := mySeat
+		if isCrib {
+			seat = round.DealerIndex
+		}
+		if v := c.Query("seat"); v != "" && !isCrib {
+			seat, err = strconv.Atoi(v)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid seat"})
+				return
+			}
+		}
+		if seat < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "seat required for spectators"})
+			return
+		}
+		if seat != mySeat && g.Status != "finished" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "other players' hands can be shared after the game ends"})
+			return
+		}
+
+		cards, found := round.Kept[seat]
+		if isCrib {
+			cards, found = round.CribCards, len(round.CribCards) > 0
+		}
+		if !found {
+			c.JSON(http.StatusNotFound, gin.H{"error": "hand not found"})
+			return
+		}
+		share := HandShare{
+			GameID:    gameID,
+			HandIndex: handIndex,
+			Seat:      seat,
+			IsCrib:    isCrib,
+			Cards:     cardCodes(cards),
+			Cut:       round.Cut.String(),
+		}
+		for _, p := range players {
+			if int(p.Position) == seat {
+				share.Username = p.Username
+			}
+		}
+		// Prefer the breakdown recorded at counting time: it was scored under the game's rules.
+		b, scored := round.Hands[seat]
+		if isCrib {
+			scored = round.Crib != nil
+			if scored {
+				b = *round.Crib
+			}
+		}
+		if !scored {
+			b = cribbage.ScoreHand(cards, *round.Cut, isCrib)
+		}
+		share.Total = b.Total
+		share.Items = shareItems(b)
+		c.JSON(http.StatusOK, share)
+	}
+}
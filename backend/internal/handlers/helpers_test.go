@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"fifteen-thirty-one-go/backend/internal/config"
+	"fifteen-thirty-one-go/backend/internal/database"
+	"fifteen-thirty-one-go/backend/internal/game/common"
+	"fifteen-thirty-one-go/backend/internal/game/cribbage"
+	"fifteen-thirty-one-go/backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// newTestDB opens a migrated SQLite database in a temp dir and installs the default config.
+// Ids restart at 1 in every database, so the engine cache and lobby gauge are reset too.
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.db")
+	t.Setenv("JWT_SECRET", "test-secret-test-secret-test-secret-0123")
+	t.Setenv("DATABASE_PATH", path)
+	t.Setenv("BACKEND_ADDR", "127.0.0.1:0")
+	cfg, err := config.LoadFromEnv()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	SetRuntimeConfig(cfg)
+
+	db, err := database.OpenAndMigrate(path)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	prev := defaultGameManager
+	defaultGameManager = NewGameManager()
+	t.Cleanup(func() { defaultGameManager = prev })
+	activeLobbies.invalidate()
+	dirtyStates.mu.Lock()
+	clear(dirtyStates.games)
+	dirtyStates.mu.Unlock()
+	return db
+}
+
+// setTestConfig applies edit to the installed runtime config.
+func setTestConfig(t *testing.T, edit func(*config.Config)) {
+	t.Helper()
+	cfg := currentConfig()
+	edit(&cfg)
+	SetRuntimeConfig(cfg)
+}
+
+// newTestGame creates a lobby for len(usernames) humans, seats them all (the first hosts) and
+// returns the dealt game's id and the users' ids in seat order.
+func newTestGame(t *testing.T, db *sql.DB, usernames ...string) (int64, []int64) {
+	t.Helper()
+	return newTestMatchGame(t, db, 0, usernames...)
+}
+
+// newTestMatchGame is newTestGame for the first game of a match to matchPoints (0 for none).
+func newTestMatchGame(t *testing.T, db *sql.DB, matchPoints int64, usernames ...string) (int64, []int64) {
+	t.Helper()
+	users := make([]int64, len(usernames))
+	for i, name := range usernames {
+		users[i] = newTestUser(t, db, name)
+	}
+	l, g, err := createLobbyWithGame(db, "test", users[0], cribbage.DefaultRules(len(users)), matchPoints, nil, 0)
+	if err != nil {
+		t.Fatalf("create lobby: %v", err)
+	}
+	for _, u := range users[1:] {
+		path := fmt.Sprintf("/lobbies/%d/join", l.ID)
+		if code := doRequest(t, JoinLobbyHandler(db), http.MethodPost, "/lobbies/:id/join", path, u, nil, nil); code != http.StatusOK {
+			t.Fatalf("join user %d: status %d", u, code)
+		}
+	}
+	st, unlock, ok := defaultGameManager.GetLocked(g.ID)
+	if !ok {
+		t.Fatalf("game %d has no engine state", g.ID)
+	}
+	stage := st.Stage
+	unlock()
+	if stage != "discard" {
+		t.Fatalf("game %d in stage %q after seating everyone, want discard", g.ID, stage)
+	}
+	return g.ID, users
+}
+
+// seatHand returns the cards userID holds in gameID, as move requests spell them.
+func seatHand(t *testing.T, db *sql.DB, gameID, userID int64) []string {
+	t.Helper()
+	players, err := models.ListGamePlayersByGame(db, gameID)
+	if err != nil {
+		t.Fatalf("list players: %v", err)
+	}
+	for _, p := range players {
+		if p.UserID != userID {
+			continue
+		}
+		var hand []common.Card
+		if err := json.Unmarshal([]byte(p.Hand), &hand); err != nil {
+			t.Fatalf("decode hand: %v", err)
+		}
+		out := make([]string, len(hand))
+		for i, c := range hand {
+			out[i] = c.String()
+		}
+		return out
+	}
+	t.Fatalf("user %d not seated in game %d", userID, gameID)
+	return nil
+}
+
+// newTestUser creates a user and returns its id.
+func newTestUser(t *testing.T, db *sql.DB, username string) int64 {
+	t.Helper()
+	u, err := models.CreateUser(db, username, "x")
+	if err != nil {
+		t.Fatalf("create user %s: %v", username, err)
+	}
+	return u.ID
+}
+
+// doRequest serves one request through h, registered on pattern, as userID (0 for none), and
+// decodes a JSON response body into out when out is not nil.
+func doRequest(t *testing.T, h gin.HandlerFunc, method, pattern, path string, userID int64, body any, out any) int {
+	t.Helper()
+	r := gin.New()
+	r.Handle(method, pattern, func(c *gin.Context) {
+		if userID != 0 {
+			c.Set("userID", userID)
+		}
+		c.Next()
+	}, h)
+
+	var rd *bytes.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("marshal body: %v", err)
+		}
+		rd = bytes.NewReader(b)
+	} else {
+		rd = bytes.NewReader(nil)
+	}
+	req := httptest.NewRequest(method, path, rd)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if out != nil && w.Body.Len() > 0 {
+		if err := json.Unmarshal(w.Body.Bytes(), out); err != nil {
+			t.Fatalf("%s %s: decode %q: %v", method, path, w.Body.String(), err)
+		}
+	}
+	return w.Code
+}
+
+// queryInt runs a single-value count query.
+func queryInt(t *testing.T, db *sql.DB, query string, args ...any) int64 {
+	t.Helper()
+	var n int64
+	if err := db.QueryRow(query, args...).Scan(&n); err != nil {
+		t.Fatalf("%s: %v", query, err)
+	}
+	return n
+}
@@ -27,4 +27,5 @@ func broadcastGameUpdate(db *sql.DB, gameID int64) {
 		return
 	}
 	hub.Broadcast("game:"+strconv.FormatInt(gameID, 10), "game_update", snap)
+	pushTurnNotifications(db, snap)
 }
@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"unicode/utf8"
+
+	"fifteen-thirty-one-go/backend/internal/game/cribbage"
+	"fifteen-thirty-one-go/backend/internal/models"
+	"fifteen-thirty-one-go/backend/internal/tracing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ChallengeHandler starts a "play again" lobby for a finished game: same seat count, the same
+// bots (by difficulty), and invitations to every previous human opponent. The caller hosts.
+func ChallengeHandler(db *sql.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, span := tracing.StartSpan(c.Request.Context(), "handlers.ChallengeHandler")
+		defer span.End()
+
+		gameID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || gameID <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid game id"})
+			return
+		}
+		userID, ok := userIDFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		g, err := models.GetGameByID(db, gameID)
+		if err != nil {
+			writeAPIError(c, err)
+			return
+		}
+		if g.Status != "finished" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "game not finished"})
+			return
+		}
+		prevLobby, err := models.GetLobbyByID(db, g.LobbyID)
+		if err != nil {
+			writeAPIError(c, err)
+			return
+		}
+		players, err := models.ListGamePlayersByGame(db, gameID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+
+		isPlayer := false
+		var opponents []int64
+		var bots []string
+		for _, p := range players {
+			// Resigned and taken-over seats are still humans; only original bot seats are re-created.
+			realBot := p.IsBot && !p.Resigned && !p.BotTakeover
+			switch {
+			case p.UserID == userID:
+				isPlayer = !realBot
+			case realBot:
+				bots = append(bots, string(seatBotDifficulty(p)))
+			default:
+				opponents = append(opponents, p.UserID)
+			}
+		}
+		if !isPlayer {
+			c.JSON(http.StatusForbidden, gin.H{"error": "not a player"})
+			return
+		}
+
+		// The lobby, its bots and the invitations are created together: a failure part way must
+		// not leave a half-built lobby counting against MaxActiveLobbies.
+		invitations := make([]models.LobbyInvitation, 0, len(opponents))
+		l, newGame, err := createLobbyWithGameThen(db, rematchLobbyName(prevLobby), userID, previousGameRules(db, gameID, prevLobby.MaxPlayers), 0, previousAllowedBots(db, prevLobby.ID), 0,
+			func(tx *sql.Tx, lobbyID, _ int64) error {
+				if err := models.ContinueLobbySeriesTx(tx, lobbyID, prevLobby.ID); err != nil {
+					return err
+				}
+				for _, diff := range bots {
+					if _, _, _, _, err := addBotToLobbyTx(tx, lobbyID, prevLobby.MaxPlayers, diff); err != nil {
+						return fmt.Errorf("add bot (lobby_id=%d): %w", lobbyID, err)
+					}
+				}
+				for _, inviteeID := range opponents {
+					token, err := randSuffix(24)
+					if err != nil {
+						return err
+					}
+					inv, err := models.CreateLobbyInvitationTx(tx, lobbyID, userID, inviteeID, token)
+					if err != nil {
+						return err
+					}
+					invitations = append(invitations, *inv)
+				}
+				return nil
+			})
+		if errors.Is(err, errServerAtCapacity) {
+			writeAtCapacity(c)
+			return
+		}
+		if err != nil {
+			log.Printf("ChallengeHandler: create lobby failed: game_id=%d err=%v", gameID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "unable to create lobby"})
+			return
+		}
+		if hub, ok := getHubProvider(); ok && hub != nil {
+			for _, inv := range invitations {
+				hub.SendToUser(inv.InviteeID, "lobby:invitation", inv)
+			}
+		}
+		startLobbyIfFull(db, l.ID)
+
+		broadcastGameUpdate(db, newGame.ID)
+		c.JSON(http.StatusCreated, gin.H{"lobby_id": l.ID, "game_id": newGame.ID, "invitations": invitations})
+	}
+}
+
+// rematchLobbyName names a lobby that replays prev.
+func rematchLobbyName(prev *models.Lobby) string {
+	name := "Rematch: " + prev.Name
+	// Lobby names are capped at 100 bytes; cut whole runes so the name stays valid UTF-8.
+	for len(name) > 100 {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	return name
+}
+
+// previousGameRules returns the variant a finished game was played with, falling back to the
+// defaults for maxPlayers when its engine state is gone or doesn't match the seat count.
+func previousGameRules(db *sql.DB, gameID, maxPlayers int64) cribbage.Rules {
+	rules := cribbage.DefaultRules(int(maxPlayers))
+	if raw, _, ok, err := models.GetGameStateJSON(db, gameID); err == nil && ok {
+		var prev struct {
+			Rules cribbage.Rules `json:"rules"`
+		}
+		if json.Unmarshal([]byte(raw), &prev) == nil && prev.Rules.Validate() == nil && prev.Rules.MaxPlayers == rules.MaxPlayers {
+			rules = prev.Rules
+		}
+	}
+	return rules
+}
+
+// previousAllowedBots carries a lobby's bot difficulty restriction over to its rematch lobby.
+func previousAllowedBots(db *sql.DB, lobbyID int64) []string {
+	allowed, err := models.LobbyAllowedBotDifficulties(db, lobbyID)
+	if err != nil {
+		log.Printf("previousAllowedBots: lobby_id=%d err=%v", lobbyID, err)
+		return nil
+	}
+	return allowed
+}
+
+// ListInvitationsHandler returns the caller's pending invitations to lobbies that are still open.
+func ListInvitationsHandler(db *sql.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, span := tracing.StartSpan(c.Request.Context(), "handlers.ListInvitationsHandler")
+		defer span.End()
+
+		userID, ok := userIDFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		invs, err := models.ListPendingInvitationsForUser(db, userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"invitations": invs})
+	}
+}
+
+// RespondInvitationHandler accepts or declines an invitation by token. Accepting only marks the
+// invitation; the client then joins via POST /lobbies/:id/join with the returned lobby id.
+func RespondInvitationHandler(db *sql.DB, accept bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, span := tracing.StartSpan(c.Request.Context(), "handlers.RespondInvitationHandler")
+		defer span.End()
+
+		userID, ok := userIDFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		inv, err := models.GetLobbyInvitationByToken(db, c.Param("token"))
+		if err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "invitation not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		if inv.InviteeID != userID {
+			// Don't reveal that the token exists.
+			c.JSON(http.StatusNotFound, gin.H{"error": "invitation not found"})
+			return
+		}
+		status := "declined"
+		if accept {
+			l, err := models.GetLobbyByID(db, inv.LobbyID)
+			if err != nil {
+				writeAPIError(c, err)
+				return
+			}
+			if l.Status != "waiting" {
+				c.JSON(http.StatusConflict, gin.H{"error": "lobby not joinable"})
+				return
+			}
+			status = "accepted"
+		}
+		if err := models.SetLobbyInvitationStatus(db, inv.ID, status); err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				c.JSON(http.StatusConflict, gin.H{"error": "invitation already answered"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		if hub, ok := getHubProvider(); ok && hub != nil {
+			hub.SendToUser(inv.InviterID, "lobby:invitation_"+status, gin.H{"lobby_id": inv.LobbyID, "user_id": userID})
+		}
+		c.JSON(http.StatusOK, gin.H{"lobby_id": inv.LobbyID, "status": status})
+	}
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"fifteen-thirty-one-go/backend/internal/models"
+)
+
+func TestRematchLobbyNameTruncatesWholeRunes(t *testing.T) {
+	tests := []struct {
+		prev string
+		want string
+	}{
+		{"Friday night", "Rematch: Friday night"},
+		{strings.Repeat("a", 91), "Rematch: " + strings.Repeat("a", 91)},
+		{strings.Repeat("a", 95), "Rematch: " + strings.Repeat("a", 91)},
+		// 9 + 2*45 = 99 bytes fit; the 46th two-byte rune would straddle the cap.
+		{strings.Repeat("é", 60), "Rematch: " + strings.Repeat("é", 45)},
+		{strings.Repeat("🂡", 30), "Rematch: " + strings.Repeat("🂡", 22)},
+	}
+	for _, tt := range tests {
+		got := rematchLobbyName(&models.Lobby{Name: tt.prev})
+		if got != tt.want {
+			t.Errorf("rematchLobbyName(%q) = %q, want %q", tt.prev, got, tt.want)
+		}
+		if len(got) > 100 || !utf8.ValidString(got) {
+			t.Errorf("rematchLobbyName(%q) = %q: %d bytes, valid UTF-8 %t", tt.prev, got, len(got), utf8.ValidString(got))
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -109,13 +110,41 @@ func syncRuntimeStateFromDB(gameID int64, nextPos int, stateVersion int64, state
 }
 
 type createLobbyRequest struct {
-	Name       string `json:"name"`
-	MaxPlayers int    `json:"max_players"`
+	Name        string `json:"name"`
+	MaxPlayers  int    `json:"max_players"`
+	TargetScore int    `json:"target_score,omitempty"` // 121 (default) or 61
+	// LastCardPoints overrides the pegging last-card value for house-rule variants (default 1).
+	LastCardPoints int `json:"last_card_points,omitempty"`
+	// CutTiePolicy settles ties when cutting for deal: "recut" (default) or "suit".
+	CutTiePolicy string `json:"cut_tie_policy,omitempty"`
+	// CribFourCardFlush lets the crib flush on four cards without the cut (variant; default off).
+	CribFourCardFlush bool `json:"crib_four_card_flush,omitempty"`
+	// Muggins lets opponents claim points missed in final counts (default off).
+	Muggins bool `json:"muggins,omitempty"`
+	// TurnTimeoutSeconds limits each discard and pegging turn (15-600; default 0, untimed).
+	TurnTimeoutSeconds int `json:"turn_timeout_seconds,omitempty"`
+	// RevealHands shows every seat's discards while each hand is counted (casual; default off).
+	RevealHands bool `json:"reveal_hands,omitempty"`
+	// PeggingFlush scores same-suit cards played in a row during pegging (house variant).
+	PeggingFlush bool `json:"pegging_flush,omitempty"`
+	// Variant is "standard" (default) or "five_card": the older 2-player game dealt five cards
+	// and played to 61.
+	Variant string `json:"variant,omitempty"`
+	// MatchPoints plays a match of consecutive games to this many game points (2..7) instead of
+	// a single game; see MatchManager.
+	MatchPoints int `json:"match_points,omitempty"`
+	// AllowedBotDifficulties restricts the bots the host may add (e.g. ["easy"]); empty allows all.
+	AllowedBotDifficulties []string `json:"allowed_bot_difficulties,omitempty"`
+	// IdleKickSeconds frees the seat of a human idle this long (60..3600) while the lobby waits;
+	// 0 never kicks. See RunIdleLobbyKicker.
+	IdleKickSeconds int `json:"idle_kick_seconds,omitempty"`
 }
 
 type createLobbyResponse struct {
-	Lobby *models.Lobby `json:"lobby"`
-	Game  *models.Game  `json:"game"`
+	Lobby                  *models.Lobby `json:"lobby"`
+	Game                   *models.Game  `json:"game"`
+	AllowedBotDifficulties []string      `json:"allowed_bot_difficulties,omitempty"`
+	IdleKickSeconds        int           `json:"idle_kick_seconds,omitempty"`
 }
 
 func ListLobbiesHandler(db *sql.DB) gin.HandlerFunc {
@@ -123,33 +152,9 @@ func ListLobbiesHandler(db *sql.DB) gin.HandlerFunc {
 		_, span := tracing.StartSpan(c.Request.Context(), "handlers.ListLobbiesHandler")
 		defer span.End()
 
-		// Keep handler defaults consistent with models.ListLobbies, and avoid the
-		// common "LIMIT 0 returns empty set" pitfall.
-		limit := int64(50)
-		offset := int64(0)
-		if v := strings.TrimSpace(c.Query("limit")); v != "" {
-			n, err := strconv.ParseInt(v, 10, 64)
-			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
-				return
-			}
-			if n < 0 {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
-				return
-			}
-			limit = n
-		}
-		if v := strings.TrimSpace(c.Query("offset")); v != "" {
-			n, err := strconv.ParseInt(v, 10, 64)
-			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid offset"})
-				return
-			}
-			if n < 0 {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid offset"})
-				return
-			}
-			offset = n
+		limit, offset, ok := parseLimitOffset(c)
+		if !ok {
+			return
 		}
 		lobbies, err := models.ListLobbies(db, limit, offset)
 		if err != nil {
@@ -160,6 +165,30 @@ func ListLobbiesHandler(db *sql.DB) gin.HandlerFunc {
 	}
 }
 
+// parseLimitOffset reads ?limit= and ?offset=, writing a 400 and returning ok=false on bad
+// input. Defaults match the models' list helpers (and avoid the "LIMIT 0 returns empty set"
+// pitfall); the models clamp the upper bound.
+func parseLimitOffset(c *gin.Context) (limit, offset int64, ok bool) {
+	limit = 50
+	if v := strings.TrimSpace(c.Query("limit")); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return 0, 0, false
+		}
+		limit = n
+	}
+	if v := strings.TrimSpace(c.Query("offset")); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid offset"})
+			return 0, 0, false
+		}
+		offset = n
+	}
+	return limit, offset, true
+}
+
 func CreateLobbyHandler(db *sql.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		_, span := tracing.StartSpan(c.Request.Context(), "handlers.CreateLobbyHandler")
@@ -170,8 +199,27 @@ func CreateLobbyHandler(db *sql.DB) gin.HandlerFunc {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid json"})
 			return
 		}
-		if req.MaxPlayers < 2 || req.MaxPlayers > 4 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "max_players must be 2-4"})
+		rules := cribbage.Rules{MaxPlayers: req.MaxPlayers, TargetScore: req.TargetScore, LastCardPoints: req.LastCardPoints, CutTiePolicy: req.CutTiePolicy, CribFourCardFlush: req.CribFourCardFlush, Muggins: req.Muggins, TurnTimeoutSeconds: req.TurnTimeoutSeconds, RevealHands: req.RevealHands, PeggingFlush: req.PeggingFlush, Variant: req.Variant}
+		if err := rules.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if req.MatchPoints != 0 && (req.MatchPoints < minMatchPoints || req.MatchPoints > maxMatchPoints) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("match_points must be between %d and %d", minMatchPoints, maxMatchPoints)})
+			return
+		}
+		// Matches are the competitive format, so the casual reveal stays out of them.
+		if req.RevealHands && req.MatchPoints != 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "reveal_hands is a casual option and cannot be used with match_points", "code": "reveal_hands_in_match"})
+			return
+		}
+		allowedBots, err := parseAllowedBotDifficulties(req.AllowedBotDifficulties)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "allowed_bot_difficulties may only list easy, medium and hard", "code": "invalid_bot_difficulty"})
+			return
+		}
+		if req.IdleKickSeconds != 0 && (req.IdleKickSeconds < minIdleKickSeconds || req.IdleKickSeconds > maxIdleKickSeconds) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("idle_kick_seconds must be 0 or between %d and %d", minIdleKickSeconds, maxIdleKickSeconds)})
 			return
 		}
 		req.Name = strings.TrimSpace(req.Name)
@@ -189,95 +237,144 @@ func CreateLobbyHandler(db *sql.DB) gin.HandlerFunc {
 			return
 		}
 
-		// Transaction: avoid orphaned lobby/game records on partial failure.
-		tx, err := db.Begin()
+		l, g, err := createLobbyWithGame(db, req.Name, hostID, rules, int64(req.MatchPoints), allowedBots, int64(req.IdleKickSeconds))
 		if err != nil {
+			if errors.Is(err, errServerAtCapacity) {
+				writeAtCapacity(c)
+				return
+			}
+			log.Printf("createLobbyWithGame failed: host_id=%d err=%v", hostID, err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
 			return
 		}
-		defer tx.Rollback()
 
-		res, err := tx.Exec(
-			`INSERT INTO lobbies(name, host_id, max_players, current_players, status) VALUES (?, ?, ?, 1, 'waiting')`,
-			req.Name, hostID, int64(req.MaxPlayers),
-		)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
-			return
-		}
-		lobbyID, err := res.LastInsertId()
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
-			return
-		}
-		res, err = tx.Exec(`INSERT INTO games(lobby_id, status) VALUES (?, 'waiting')`, lobbyID)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
-			return
-		}
-		gameID, err := res.LastInsertId()
+		c.JSON(http.StatusCreated, createLobbyResponse{Lobby: l, Game: g, AllowedBotDifficulties: allowedBots, IdleKickSeconds: req.IdleKickSeconds})
+	}
+}
+
+// errGameInit reports that the engine could not deal the opening hand of a game.
+var errGameInit = errors.New("game init error")
+
+// Bounds for createLobbyRequest.MatchPoints.
+const (
+	minMatchPoints = 2
+	maxMatchPoints = 7
+)
+
+// parseAllowedBotDifficulties normalizes a lobby's bot difficulty allow-list, dropping
+// duplicates. An empty list allows every difficulty.
+func parseAllowedBotDifficulties(raw []string) ([]string, error) {
+	var out []string
+	for _, s := range raw {
+		d, err := cribbage.ParseBotDifficulty(s)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
-			return
+			return nil, err
 		}
-		if _, err := tx.Exec(
-			`INSERT INTO game_players(game_id, user_id, position, is_bot, bot_difficulty) VALUES (?, ?, 0, 0, NULL)`,
-			gameID, hostID,
-		); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
-			return
+		if !slices.Contains(out, string(d)) {
+			out = append(out, string(d))
 		}
+	}
+	return out, nil
+}
 
-		// Initialize in-memory engine state BEFORE commit so we don't create DB rows
-		// without a corresponding in-memory state if dealing fails.
-		st := cribbage.NewState(req.MaxPlayers)
-		if err := st.Deal(); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "game init error"})
-			return
-		}
-		// Persist the host's initial hand for UI convenience (idempotent; should be empty here).
-		if b, err := json.Marshal(st.Hands[0]); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
-			return
-		} else {
-			if _, err := models.UpdatePlayerHandIfEmptyTx(tx, gameID, hostID, string(b)); err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
-				return
-			}
-		}
-		// Persist full engine state for restart recovery.
-		if sb, err := json.Marshal(st); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
-			return
-		} else {
-			if err := models.UpdateGameStateTx(tx, gameID, string(sb)); err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
-				return
-			}
-		}
+// createLobbyWithGame creates a waiting lobby, its game, and the host's seat, and registers the
+// still undealt engine state in memory; startLobbyGame deals once the lobby fills or the host
+// starts it. rules must already be validated.
+// A positive matchPoints makes the game the first of a match played to that many points.
+// allowedBots restricts the bot difficulties the host may add; empty allows all. A positive
+// idleKickSeconds turns on idle kicking for the lobby. It fails with errServerAtCapacity once
+// MaxActiveLobbies lobbies are open.
+func createLobbyWithGame(db *sql.DB, name string, hostID int64, rules cribbage.Rules, matchPoints int64, allowedBots []string, idleKickSeconds int64) (*models.Lobby, *models.Game, error) {
+	return createLobbyWithGameThen(db, name, hostID, rules, matchPoints, allowedBots, idleKickSeconds, nil)
+}
 
-		if err := tx.Commit(); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
-			return
-		}
+// createLobbyWithGameThen is createLobbyWithGame with fill run in the same transaction once the
+// lobby, game and host seat exist, so callers that also seat bots or send invitations leave
+// nothing behind when a later step fails. fill must not change the game's engine state; the
+// lobby is still waiting, so callers run startLobbyIfFull themselves after it returns.
+func createLobbyWithGameThen(db *sql.DB, name string, hostID int64, rules cribbage.Rules, matchPoints int64, allowedBots []string, idleKickSeconds int64, fill func(tx *sql.Tx, lobbyID, gameID int64) error) (*models.Lobby, *models.Game, error) {
+	if err := activeLobbies.reserve(db, currentConfig().MaxActiveLobbies); err != nil {
+		return nil, nil, err
+	}
+	created := false
+	defer func() { activeLobbies.finish(created) }()
 
-		l, err := models.GetLobbyByID(db, lobbyID)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
-			return
+	// Transaction: avoid orphaned lobby/game records on partial failure.
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	var allowed sql.NullString
+	if len(allowedBots) > 0 {
+		allowed = sql.NullString{String: strings.Join(allowedBots, ","), Valid: true}
+	}
+	var idleKick sql.NullInt64
+	if idleKickSeconds > 0 {
+		idleKick = sql.NullInt64{Int64: idleKickSeconds, Valid: true}
+	}
+	res, err := tx.Exec(
+		`INSERT INTO lobbies(name, host_id, max_players, current_players, status, allowed_bot_difficulties, idle_kick_seconds) VALUES (?, ?, ?, 1, 'waiting', ?, ?)`,
+		name, hostID, int64(rules.MaxPlayers), allowed, idleKick,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	lobbyID, err := res.LastInsertId()
+	if err != nil {
+		return nil, nil, err
+	}
+	gameID, err := models.CreateGameTx(tx, lobbyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := tx.Exec(
+		`INSERT INTO game_players(game_id, user_id, position, is_bot, bot_difficulty) VALUES (?, ?, 0, 0, NULL)`,
+		gameID, hostID,
+	); err != nil {
+		return nil, nil, err
+	}
+	if matchPoints > 0 {
+		if _, err := models.CreateMatchTx(tx, lobbyID, gameID, matchPoints); err != nil {
+			return nil, nil, err
 		}
-		g, err := models.GetGameByID(db, gameID)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
-			return
+	}
+
+	// The game waits undealt (stage "dealing") until the lobby fills or the host starts it;
+	// see startLobbyGame. Persist the state anyway so joins and restarts find it.
+	st := cribbage.NewStateWithRules(rules)
+	sb, err := json.Marshal(st)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := models.UpdateGameStateTx(tx, gameID, string(sb)); err != nil {
+		return nil, nil, err
+	}
+	if fill != nil {
+		if err := fill(tx, lobbyID, gameID); err != nil {
+			return nil, nil, err
 		}
+	}
 
-		// Fresh game: UpdateGameStateTx has incremented from 0 -> 1.
-		st.Version = 1
-		defaultGameManager.Set(g.ID, st)
+	if err := tx.Commit(); err != nil {
+		return nil, nil, err
+	}
+	created = true
 
-		c.JSON(http.StatusCreated, createLobbyResponse{Lobby: l, Game: g})
+	l, err := models.GetLobbyByID(db, lobbyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	g, err := models.GetGameByID(db, gameID)
+	if err != nil {
+		return nil, nil, err
 	}
+
+	// Fresh game: UpdateGameStateTx has incremented from 0 -> 1.
+	st.Version = 1
+	defaultGameManager.Set(g.ID, st)
+	return l, g, nil
 }
 
 func JoinLobbyHandler(db *sql.DB) gin.HandlerFunc {
@@ -304,10 +401,9 @@ func JoinLobbyHandler(db *sql.DB) gin.HandlerFunc {
 		}
 		defer tx.Rollback()
 
-		// Find game for lobby (assumes one game per lobby for now).
-		// This is a small shortcut until we add explicit lobby membership and game start.
-		var gameID int64
-		if err := tx.QueryRow(`SELECT id FROM games WHERE lobby_id = ? ORDER BY id DESC LIMIT 1`, lobbyID).Scan(&gameID); err != nil {
+		// A lobby has at most one active game (enforced by idx_games_one_active_per_lobby).
+		gameID, _, err := models.LobbyGameTx(tx, lobbyID)
+		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
 			return
 		}
@@ -358,7 +454,10 @@ func JoinLobbyHandler(db *sql.DB) gin.HandlerFunc {
 				}
 				restored.Version = stateVersion
 				pos := int(existingPos.Int64)
-				if pos >= 0 && pos < len(restored.Hands) {
+				switch {
+				case restored.Stage == "dealing":
+					// Not dealt yet: there is no hand to persist.
+				case pos >= 0 && pos < len(restored.Hands):
 					if b, err := json.Marshal(restored.Hands[pos]); err == nil {
 						handJSON = string(b)
 						if _, err := models.UpdatePlayerHandIfEmptyTx(tx, gameID, userID, handJSON); err != nil {
@@ -370,7 +469,7 @@ func JoinLobbyHandler(db *sql.DB) gin.HandlerFunc {
 						c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
 						return
 					}
-				} else {
+				default:
 					log.Printf(
 						"JoinLobbyHandler: position out of bounds while persisting player hand (already joined): game_id=%d user_id=%d pos=%d hands_len=%d",
 						gameID, userID, pos, len(restored.Hands),
@@ -384,6 +483,9 @@ func JoinLobbyHandler(db *sql.DB) gin.HandlerFunc {
 			}
 
 			resp := gin.H{"lobby": &l, "game_id": gameID, "already_joined": true, "realtime_sync": "ok"}
+			if existingPos.Valid {
+				resp["position"] = existingPos.Int64
+			}
 			// existingPos may be invalid if the row somehow had NULL position; in that case skip runtime sync.
 			if existingPos.Valid {
 				if err := syncRuntimeStateFromDB(gameID, int(existingPos.Int64), stateVersion, stateJSON, handJSON); err != nil {
@@ -402,76 +504,22 @@ func JoinLobbyHandler(db *sql.DB) gin.HandlerFunc {
 			return
 		}
 
-		l, err := models.JoinLobbyTx(tx, lobbyID)
+		j, err := seatJoiningPlayerTx(tx, lobbyID, gameID, userID)
 		if err != nil {
-			// Don't leak internal details; map known messages to safe ones.
-			msg := "unable to join lobby"
-			if errors.Is(err, models.ErrNotFound) {
-				c.JSON(http.StatusNotFound, gin.H{"error": "lobby not found"})
-				return
-			}
-			if errors.Is(err, models.ErrLobbyFull) {
-				msg = "lobby full"
-			} else if errors.Is(err, models.ErrLobbyNotJoinable) {
-				msg = "lobby not joinable"
-			}
-			log.Printf("JoinLobbyTx failed: lobby_id=%d user_id=%d err=%v", lobbyID, userID, err)
-			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
-			return
-		}
-
-		nextPos, err := models.AddGamePlayerAutoPositionTx(tx, gameID, userID, l.MaxPlayers, false, nil)
-		if err != nil {
-			log.Printf("AddGamePlayerAutoPositionTx failed: game_id=%d user_id=%d err=%v", gameID, userID, err)
-			c.JSON(http.StatusBadRequest, gin.H{"error": "unable to join game"})
-			return
-		}
-
-		// Persist joining player's initial hand WITHOUT taking the in-memory state lock.
-		// Use the persisted engine state in DB (if present) to keep lock ordering DB -> memory.
-		var handJSON string
-		var stateJSON string
-		var stateVersion int64
-		var s sql.NullString
-		var v sql.NullInt64
-		if err := tx.QueryRow(`SELECT state_json, state_version FROM games WHERE id = ?`, gameID).Scan(&s, &v); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
-			return
-		}
-		if v.Valid {
-			stateVersion = v.Int64
-		}
-		if s.Valid && strings.TrimSpace(s.String) != "" {
-			stateJSON = s.String
-
-			var restored cribbage.State
-			if err := json.Unmarshal([]byte(stateJSON), &restored); err != nil {
-				log.Printf("JoinLobbyHandler restore state_json unmarshal failed: game_id=%d err=%v state_json_len=%d", gameID, err, len(stateJSON))
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
-				return
-			}
-			restored.Version = stateVersion
-			if int(nextPos) >= 0 && int(nextPos) < len(restored.Hands) {
-				if b, err := json.Marshal(restored.Hands[nextPos]); err == nil {
-					handJSON = string(b)
-					if _, err := models.UpdatePlayerHandIfEmptyTx(tx, gameID, userID, handJSON); err != nil {
-						log.Printf("UpdatePlayerHandIfEmptyTx failed: game_id=%d user_id=%d err=%v", gameID, userID, err)
-						c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			if models.IsUniqueConstraint(err) {
+				// A concurrent join by the same user won the race after our membership check.
+				// The (game_id, user_id) primary key kept the count intact; answer like a repeat join.
+				_ = tx.Rollback()
+				var pos int64
+				if err := db.QueryRow(`SELECT position FROM game_players WHERE game_id = ? AND user_id = ?`, gameID, userID).Scan(&pos); err == nil {
+					if cur, err := models.GetLobbyByID(db, lobbyID); err == nil {
+						c.JSON(http.StatusOK, gin.H{"lobby": cur, "game_id": gameID, "position": pos, "already_joined": true, "realtime_sync": "ok"})
 						return
 					}
-				} else {
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
-					return
 				}
-			} else {
-				// This indicates a mismatch between the persisted engine state and the assigned position.
-				log.Printf(
-					"JoinLobbyHandler: position out of bounds while persisting player hand: game_id=%d user_id=%d next_pos=%d hands_len=%d",
-					gameID, userID, nextPos, len(restored.Hands),
-				)
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "position out of bounds"})
-				return
 			}
+			writeJoinError(c, "JoinLobbyHandler", lobbyID, userID, err)
+			return
 		}
 
 		if err := tx.Commit(); err != nil {
@@ -479,19 +527,108 @@ func JoinLobbyHandler(db *sql.DB) gin.HandlerFunc {
 			return
 		}
 
-		resp := gin.H{"lobby": l, "game_id": gameID, "joined_persisted": true, "realtime_sync": "ok"}
-		if err := syncRuntimeStateFromDB(gameID, int(nextPos), stateVersion, stateJSON, handJSON); err != nil {
+		resp := gin.H{"lobby": j.lobby, "game_id": gameID, "position": j.position, "joined_persisted": true, "realtime_sync": "ok"}
+		if err := syncRuntimeStateFromDB(gameID, int(j.position), j.stateVersion, j.stateJSON, j.handJSON); err != nil {
 			log.Printf(
 				"JoinLobbyHandler: runtime state sync encountered errors after commit (best-effort; continuing): game_id=%d user_id=%d next_pos=%d err=%v",
-				gameID, userID, nextPos, err,
+				gameID, userID, j.position, err,
 			)
 			resp["realtime_sync"] = "failed"
 		}
+		startLobbyIfFull(db, lobbyID)
 
 		c.JSON(http.StatusOK, resp)
 	}
 }
 
+// errJoinSeat and errJoinPosition report seat allocation failures inside seatJoiningPlayerTx.
+var (
+	errJoinSeat     = errors.New("unable to join game")
+	errJoinPosition = errors.New("position out of bounds")
+)
+
+// joinResult carries what a committed join needs for the post-commit runtime sync.
+type joinResult struct {
+	lobby        *models.Lobby
+	position     int64
+	stateVersion int64
+	stateJSON    string
+	handJSON     string
+}
+
+// seatJoiningPlayerTx is the join transaction body shared by lobby joins and spectator seat
+// claims: it checks the lobby is joinable, allocates the next seat, and persists the new
+// player's dealt hand from the persisted engine state (lock order DB -> memory).
+// Raw insert errors stay wrapped so callers can detect unique-constraint races.
+func seatJoiningPlayerTx(tx *sql.Tx, lobbyID, gameID, userID int64) (*joinResult, error) {
+	l, err := models.JoinLobbyTx(tx, lobbyID)
+	if err != nil {
+		return nil, err
+	}
+
+	nextPos, err := models.AddGamePlayerAutoPositionTx(tx, gameID, userID, l.MaxPlayers, false, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errJoinSeat, err)
+	}
+
+	res := &joinResult{lobby: l, position: nextPos}
+	var s sql.NullString
+	var v sql.NullInt64
+	if err := tx.QueryRow(`SELECT state_json, state_version FROM games WHERE id = ?`, gameID).Scan(&s, &v); err != nil {
+		return nil, err
+	}
+	if v.Valid {
+		res.stateVersion = v.Int64
+	}
+	if !s.Valid || strings.TrimSpace(s.String) == "" {
+		return res, nil
+	}
+	res.stateJSON = s.String
+
+	var restored cribbage.State
+	if err := json.Unmarshal([]byte(res.stateJSON), &restored); err != nil {
+		return nil, fmt.Errorf("restore state_json (game_id=%d len=%d): %w", gameID, len(res.stateJSON), err)
+	}
+	if restored.Stage == "dealing" {
+		// Nothing dealt yet; startLobbyGame hands out every seat's cards.
+		return res, nil
+	}
+	if int(nextPos) < 0 || int(nextPos) >= len(restored.Hands) {
+		// This indicates a mismatch between the persisted engine state and the assigned position.
+		return nil, fmt.Errorf("%w: game_id=%d next_pos=%d hands_len=%d", errJoinPosition, gameID, nextPos, len(restored.Hands))
+	}
+	b, err := json.Marshal(restored.Hands[nextPos])
+	if err != nil {
+		return nil, err
+	}
+	res.handJSON = string(b)
+	if _, err := models.UpdatePlayerHandIfEmptyTx(tx, gameID, userID, res.handJSON); err != nil {
+		return nil, fmt.Errorf("UpdatePlayerHandIfEmptyTx (game_id=%d user_id=%d): %w", gameID, userID, err)
+	}
+	return res, nil
+}
+
+// writeJoinError maps seatJoiningPlayerTx failures to safe client errors.
+func writeJoinError(c *gin.Context, caller string, lobbyID, userID int64, err error) {
+	switch {
+	case errors.Is(err, models.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "lobby not found"})
+	case errors.Is(err, models.ErrLobbyFull):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "lobby full"})
+	case errors.Is(err, models.ErrLobbyNotJoinable):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "lobby not joinable"})
+	case errors.Is(err, errJoinSeat):
+		log.Printf("%s: seat allocation failed: lobby_id=%d user_id=%d err=%v", caller, lobbyID, userID, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unable to join game"})
+	case errors.Is(err, errJoinPosition):
+		log.Printf("%s: %v (user_id=%d)", caller, err, userID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "position out of bounds"})
+	default:
+		log.Printf("%s: join failed: lobby_id=%d user_id=%d err=%v", caller, lobbyID, userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+	}
+}
+
 type addBotRequest struct {
 	Difficulty string `json:"difficulty"` // easy|medium|hard (optional; defaults to easy)
 }
@@ -514,13 +651,14 @@ func AddBotToLobbyHandler(db *sql.DB) gin.HandlerFunc {
 
 		var req addBotRequest
 		_ = c.ShouldBindJSON(&req) // optional body
-		diff := strings.TrimSpace(strings.ToLower(req.Difficulty))
-		if diff == "" {
-			diff = string(cribbage.BotEasy)
-		}
-		if diff != string(cribbage.BotEasy) && diff != string(cribbage.BotMedium) && diff != string(cribbage.BotHard) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid difficulty"})
-			return
+		diff := ""
+		if strings.TrimSpace(req.Difficulty) != "" {
+			d, err := cribbage.ParseBotDifficulty(req.Difficulty)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid difficulty (expected easy, medium or hard)"})
+				return
+			}
+			diff = string(d)
 		}
 
 		// Ensure lobby exists and the caller is the host.
@@ -541,122 +679,207 @@ func AddBotToLobbyHandler(db *sql.DB) gin.HandlerFunc {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "lobby not joinable"})
 			return
 		}
-
-		tx, err := db.Begin()
+		allowed, err := models.LobbyAllowedBotDifficulties(db, lobbyID)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
 			return
 		}
-		defer tx.Rollback()
-
-		// Find current game for lobby.
-		var gameID int64
-		if err := tx.QueryRow(`SELECT id FROM games WHERE lobby_id = ? ORDER BY id DESC LIMIT 1`, lobbyID).Scan(&gameID); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+		switch {
+		case diff == "" && len(allowed) > 0:
+			// Without a choice, take the lobby's first allowed difficulty.
+			diff = allowed[0]
+		case diff == "":
+			diff = string(cribbage.BotEasy)
+		case len(allowed) > 0 && !slices.Contains(allowed, diff):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "bot difficulty not allowed in this lobby", "code": "bot_difficulty_not_allowed", "allowed": allowed})
 			return
 		}
-
-		// Create a bot user row (required by FK on game_players.user_id).
-		var botID int64
-		var botName string
-		for attempt := 0; attempt < 5; attempt++ {
-			suf, rerr := randSuffix(8)
-			if rerr != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
-				return
-			}
-			botName = fmt.Sprintf("bot_%s_%d_%s", diff, lobbyID, suf)
-			pw, rerr := randSuffix(16)
-			if rerr != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
-				return
-			}
-			hash, herr := auth.HashPassword("bot-" + pw) // meets min length
-			if herr != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
-				return
-			}
-			res, err := tx.Exec(`INSERT INTO users(username, password_hash) VALUES (?, ?)`, botName, hash)
-			if err != nil {
-				if models.IsUniqueConstraint(err) && attempt < 4 {
-					continue
-				}
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
-				return
-			}
-			id, err := res.LastInsertId()
-			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
-				return
-			}
-			botID = id
-			break
-		}
-		if botID == 0 {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "unable to create bot"})
+		// Cheap pre-check; addBotToLobby still settles races on seat allocation.
+		if l.CurrentPlayers >= l.MaxPlayers {
+			c.JSON(http.StatusConflict, gin.H{"error": "lobby full"})
 			return
 		}
 
-		// Add bot as a game player in the next available position.
-		botDiff := diff
-		nextPos, err := models.AddGamePlayerAutoPositionTx(tx, gameID, botID, int64(l.MaxPlayers), true, &botDiff)
+		gameID, botID, botName, err := addBotToLobby(db, lobbyID, l.MaxPlayers, diff)
 		if err != nil {
-			// If we can't allocate a position, treat it as "lobby full" for UX consistency.
-			if strings.Contains(err.Error(), "could not allocate position") {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "lobby full"})
-				return
+			var limitErr *botLimitError
+			switch {
+			case errors.Is(err, models.ErrLobbyFull):
+				c.JSON(http.StatusConflict, gin.H{"error": "lobby full"})
+			case errors.As(err, &limitErr):
+				c.JSON(http.StatusConflict, gin.H{
+					"error":    "bot limit reached",
+					"code":     "bot_limit",
+					"humans":   limitErr.Humans,
+					"bots":     limitErr.Bots,
+					"max_bots": limitErr.MaxBots,
+				})
+			case errors.Is(err, errBotSeat):
+				c.JSON(http.StatusBadRequest, gin.H{"error": "unable to add bot"})
+			default:
+				log.Printf("addBotToLobby failed: lobby_id=%d err=%v", lobbyID, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
 			}
-			c.JSON(http.StatusBadRequest, gin.H{"error": "unable to add bot"})
 			return
 		}
-		// NOTE: lobbies.current_players is maintained by SQLite triggers on game_players insert/delete.
 
-		// Persist the bot's initial hand from the persisted engine snapshot (lock order DB -> memory).
-		var handJSON string
-		var stateJSON string
-		var stateVersion int64
-		var s sql.NullString
-		var v sql.NullInt64
-		if err := tx.QueryRow(`SELECT state_json, state_version FROM games WHERE id = ?`, gameID).Scan(&s, &v); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
-			return
+		broadcastGameUpdate(db, gameID)
+		c.JSON(http.StatusOK, gin.H{"game_id": gameID, "bot_user_id": botID, "bot_username": botName})
+	}
+}
+
+// errBotSeat reports that a bot user was created but could not be seated in the game.
+var errBotSeat = errors.New("unable to add bot")
+
+// botLimitError rejects a bot that would exceed the per-game cap or leave no human seat.
+type botLimitError struct {
+	Humans  int64
+	Bots    int64
+	MaxBots int64
+}
+
+func (e *botLimitError) Error() string {
+	return fmt.Sprintf("bot limit reached: humans=%d bots=%d max_bots=%d", e.Humans, e.Bots, e.MaxBots)
+}
+
+// checkBotLimitTx enforces MaxBotsPerGame and, unless AllowAllBotGames is set, keeps at least
+// one seat for a human. Resigned and taken-over seats belong to humans and count as such.
+func checkBotLimitTx(tx *sql.Tx, gameID, maxPlayers int64) error {
+	var humans, bots int64
+	if err := tx.QueryRow(
+		`SELECT COALESCE(SUM(CASE WHEN is_bot = 0 OR resigned = 1 OR bot_takeover_at IS NOT NULL THEN 1 ELSE 0 END), 0),
+		        COALESCE(SUM(CASE WHEN is_bot = 1 AND resigned = 0 AND bot_takeover_at IS NULL THEN 1 ELSE 0 END), 0)
+		 FROM game_players WHERE game_id = ?`,
+		gameID,
+	).Scan(&humans, &bots); err != nil {
+		return err
+	}
+	cfg := currentConfig()
+	maxBots := min(cfg.MaxBotsPerGame, maxPlayers-1)
+	if cfg.AllowAllBotGames {
+		maxBots = maxPlayers
+	} else if humans > 0 {
+		maxBots = min(maxBots, maxPlayers-humans)
+	}
+	if bots >= maxBots || (humans == 0 && !cfg.AllowAllBotGames) {
+		return &botLimitError{Humans: humans, Bots: bots, MaxBots: maxBots}
+	}
+	return nil
+}
+
+// addBotToLobby creates a bot user of the given difficulty and seats it in the lobby's current
+// game. Seat allocation failures map to models.ErrLobbyFull (no free position) or errBotSeat.
+func addBotToLobby(db *sql.DB, lobbyID, maxPlayers int64, diff string) (gameID, botID int64, botName string, err error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, 0, "", err
+	}
+	defer tx.Rollback()
+
+	gameID, botID, botName, seat, err := addBotToLobbyTx(tx, lobbyID, maxPlayers, diff)
+	if err != nil {
+		return 0, 0, "", err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, 0, "", err
+	}
+
+	// Best-effort: align runtime state after commit.
+	if err := syncRuntimeStateFromDB(gameID, int(seat.position), seat.stateVersion, seat.stateJSON, seat.handJSON); err != nil {
+		log.Printf("addBotToLobby runtime sync failed (best-effort): lobby_id=%d game_id=%d bot_id=%d err=%v", lobbyID, gameID, botID, err)
+	}
+	startLobbyIfFull(db, lobbyID)
+	return gameID, botID, botName, nil
+}
+
+// addBotToLobbyTx is the transaction body of addBotToLobby. seat carries what the caller needs
+// to sync the runtime state after commit (its lobby is unset).
+func addBotToLobbyTx(tx *sql.Tx, lobbyID, maxPlayers int64, diff string) (gameID, botID int64, botName string, seat *joinResult, err error) {
+	// Find current game for lobby.
+	gameID, _, err = models.LobbyGameTx(tx, lobbyID)
+	if err != nil {
+		return 0, 0, "", nil, err
+	}
+
+	// Create a bot user row (required by FK on game_players.user_id).
+	for attempt := 0; attempt < 5; attempt++ {
+		suf, rerr := randSuffix(8)
+		if rerr != nil {
+			return 0, 0, "", nil, rerr
 		}
-		if v.Valid {
-			stateVersion = v.Int64
+		botName = fmt.Sprintf("bot_%s_%d_%s", diff, lobbyID, suf)
+		pw, rerr := randSuffix(16)
+		if rerr != nil {
+			return 0, 0, "", nil, rerr
 		}
-		if s.Valid && strings.TrimSpace(s.String) != "" {
-			stateJSON = s.String
-			var restored cribbage.State
-			if err := json.Unmarshal([]byte(stateJSON), &restored); err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
-				return
-			}
-			restored.Version = stateVersion
-			if int(nextPos) >= 0 && int(nextPos) < len(restored.Hands) {
-				if b, err := json.Marshal(restored.Hands[nextPos]); err == nil {
-					handJSON = string(b)
-					if _, err := models.UpdatePlayerHandIfEmptyTx(tx, gameID, botID, handJSON); err != nil {
-						c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
-						return
-					}
-				} else {
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
-					return
-				}
+		hash, herr := auth.HashPassword("bot-" + pw) // meets min length
+		if herr != nil {
+			return 0, 0, "", nil, herr
+		}
+		res, err := tx.Exec(`INSERT INTO users(username, password_hash) VALUES (?, ?)`, botName, hash)
+		if err != nil {
+			if models.IsUniqueConstraint(err) && attempt < 4 {
+				continue
 			}
+			return 0, 0, "", nil, err
 		}
-
-		if err := tx.Commit(); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
-			return
+		id, err := res.LastInsertId()
+		if err != nil {
+			return 0, 0, "", nil, err
 		}
+		botID = id
+		break
+	}
+	if botID == 0 {
+		return 0, 0, "", nil, errors.New("unable to create bot")
+	}
 
-		// Best-effort: align runtime state after commit.
-		if err := syncRuntimeStateFromDB(gameID, int(nextPos), stateVersion, stateJSON, handJSON); err != nil {
-			log.Printf("AddBotToLobbyHandler runtime sync failed (best-effort): lobby_id=%d game_id=%d bot_id=%d err=%v", lobbyID, gameID, botID, err)
-		}
+	if err := checkBotLimitTx(tx, gameID, maxPlayers); err != nil {
+		return 0, 0, "", nil, err
+	}
 
-		broadcastGameUpdate(db, gameID)
-		c.JSON(http.StatusOK, gin.H{"game_id": gameID, "bot_user_id": botID, "bot_username": botName})
+	// Add bot as a game player in the next available position.
+	botDiff := diff
+	nextPos, err := models.AddGamePlayerAutoPositionTx(tx, gameID, botID, maxPlayers, true, &botDiff)
+	if err != nil {
+		// If we can't allocate a position, treat it as "lobby full" for UX consistency.
+		if strings.Contains(err.Error(), "could not allocate position") {
+			return 0, 0, "", nil, models.ErrLobbyFull
+		}
+		return 0, 0, "", nil, fmt.Errorf("%w: %v", errBotSeat, err)
+	}
+	// NOTE: lobbies.current_players is maintained by SQLite triggers on game_players insert/delete.
+
+	// Persist the bot's initial hand from the persisted engine snapshot (lock order DB -> memory).
+	var handJSON string
+	var stateJSON string
+	var stateVersion int64
+	var s sql.NullString
+	var v sql.NullInt64
+	if err := tx.QueryRow(`SELECT state_json, state_version FROM games WHERE id = ?`, gameID).Scan(&s, &v); err != nil {
+		return 0, 0, "", nil, err
 	}
+	if v.Valid {
+		stateVersion = v.Int64
+	}
+	if s.Valid && strings.TrimSpace(s.String) != "" {
+		stateJSON = s.String
+		var restored cribbage.State
+		if err := json.Unmarshal([]byte(stateJSON), &restored); err != nil {
+			return 0, 0, "", nil, err
+		}
+		restored.Version = stateVersion
+		if restored.Stage != "dealing" && int(nextPos) >= 0 && int(nextPos) < len(restored.Hands) {
+			b, err := json.Marshal(restored.Hands[nextPos])
+			if err != nil {
+				return 0, 0, "", nil, err
+			}
+			handJSON = string(b)
+			if _, err := models.UpdatePlayerHandIfEmptyTx(tx, gameID, botID, handJSON); err != nil {
+				return 0, 0, "", nil, err
+			}
+		}
+	}
+
+	return gameID, botID, botName, &joinResult{position: nextPos, stateVersion: stateVersion, stateJSON: stateJSON, handJSON: handJSON}, nil
 }
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"sync"
+
+	"fifteen-thirty-one-go/backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errServerAtCapacity reports that MaxActiveLobbies lobbies are already open.
+var errServerAtCapacity = errors.New("server at capacity")
+
+// lobbyGauge caches the number of active (not finished) lobbies so MaxActiveLobbies can be
+// checked without a count query per create. Creates bump it; whatever finishes a lobby calls
+// invalidate, and the next check recounts from the database.
+type lobbyGauge struct {
+	mu      sync.Mutex
+	loaded  bool
+	count   int64
+	pending int64 // creates reserved but not yet committed or abandoned
+}
+
+var activeLobbies lobbyGauge
+
+// reserve claims room for one more lobby, returning errServerAtCapacity when there is none.
+// Every successful reserve must be followed by finish.
+func (g *lobbyGauge) reserve(db *sql.DB, max int64) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.loaded {
+		n, err := models.CountActiveLobbies(db)
+		if err != nil {
+			return err
+		}
+		g.count, g.loaded = n, true
+	}
+	if max > 0 && g.count+g.pending >= max {
+		return errServerAtCapacity
+	}
+	g.pending++
+	return nil
+}
+
+// finish settles a reservation; created reports whether the lobby was committed.
+func (g *lobbyGauge) finish(created bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.pending--
+	if created && g.loaded {
+		g.count++
+	}
+}
+
+// invalidate drops the cached count after a lobby was finished.
+func (g *lobbyGauge) invalidate() {
+	g.mu.Lock()
+	g.loaded = false
+	g.mu.Unlock()
+}
+
+// writeAtCapacity answers a create refused by MaxActiveLobbies. The 503 tells clients to come
+// back later and operators that the instance needs scaling out.
+func writeAtCapacity(c *gin.Context) {
+	c.Header("Retry-After", "30")
+	c.JSON(http.StatusServiceUnavailable, gin.H{"error": "server at capacity, try later", "code": "server_at_capacity"})
+}
@@ -26,6 +26,7 @@ type LobbyChatMessage struct {
 	Username    string    `json:"username"`
 	Message     string    `json:"message"`
 	MessageType string    `json:"message_type"` // chat, system, join, leave
+	Pinned      bool      `json:"pinned,omitempty"`
 	CreatedAt   time.Time `json:"created_at"`
 }
 
@@ -162,31 +163,17 @@ func GetLobbyChatHistory(db *sql.DB) gin.HandlerFunc {
 		ctx := c.Request.Context()
 
 		// Verify user is in the lobby or is a spectator
-		var authorized int
-		err = db.QueryRowContext(ctx, `
-			SELECT 1
-			FROM (
-				SELECT gp.user_id
-				FROM game_players gp
-				JOIN games g ON g.id = gp.game_id
-				WHERE g.lobby_id = ? AND gp.user_id = ? AND g.status IN ('waiting', 'in_progress')
-				UNION
-				SELECT ls.user_id
-				FROM lobby_spectators ls
-				WHERE ls.lobby_id = ? AND ls.user_id = ?
-			) AS authorized_users
-			LIMIT 1
-		`, lobbyID, userID, lobbyID, userID).Scan(&authorized)
-		if err == sql.ErrNoRows {
-			c.JSON(http.StatusForbidden, gin.H{"error": "you are not in this lobby"})
-			return
-		}
+		authorized, err := canReadLobbyChat(ctx, db, lobbyID, userID)
 		if err != nil {
 			wrappedErr := fmt.Errorf("GetLobbyChatHistory: check authorization (lobby_id=%d user_id=%d): %w", lobbyID, userID, err)
 			log.Printf("%v", wrappedErr)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
 			return
 		}
+		if !authorized {
+			c.JSON(http.StatusForbidden, gin.H{"error": "you are not in this lobby"})
+			return
+		}
 
 		// Get chat history (last 100 messages)
 		limit := 100
@@ -197,7 +184,7 @@ func GetLobbyChatHistory(db *sql.DB) gin.HandlerFunc {
 		}
 
 		rows, err := db.QueryContext(ctx, `
-			SELECT id, lobby_id, user_id, username, message, message_type, created_at
+			SELECT id, lobby_id, user_id, username, message, message_type, pinned, created_at
 			FROM lobby_messages
 			WHERE lobby_id = ?
 			ORDER BY created_at DESC
@@ -216,7 +203,7 @@ func GetLobbyChatHistory(db *sql.DB) gin.HandlerFunc {
 		for rows.Next() {
 			var msg LobbyChatMessage
 			var nullUserID sql.NullInt64
-			err := rows.Scan(&msg.ID, &msg.LobbyID, &nullUserID, &msg.Username, &msg.Message, &msg.MessageType, &msg.CreatedAt)
+			err := rows.Scan(&msg.ID, &msg.LobbyID, &nullUserID, &msg.Username, &msg.Message, &msg.MessageType, &msg.Pinned, &msg.CreatedAt)
 			if err != nil {
 				scanErrors++
 				log.Printf("Error scanning chat message for lobby %d (row skipped): %v", lobbyID, err)
@@ -246,6 +233,32 @@ func GetLobbyChatHistory(db *sql.DB) gin.HandlerFunc {
 	}
 }
 
+// canReadLobbyChat reports whether userID plays in the lobby's active game or spectates it.
+func canReadLobbyChat(ctx context.Context, db *sql.DB, lobbyID, userID int64) (bool, error) {
+	var authorized int
+	err := db.QueryRowContext(ctx, `
+		SELECT 1
+		FROM (
+			SELECT gp.user_id
+			FROM game_players gp
+			JOIN games g ON g.id = gp.game_id
+			WHERE g.lobby_id = ? AND gp.user_id = ? AND g.status IN ('waiting', 'in_progress')
+			UNION
+			SELECT ls.user_id
+			FROM lobby_spectators ls
+			WHERE ls.lobby_id = ? AND ls.user_id = ?
+		) AS authorized_users
+		LIMIT 1
+	`, lobbyID, userID, lobbyID, userID).Scan(&authorized)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // handleLobbyChatWS handles WebSocket "lobby:send_message" events
 func handleLobbyChatWS(hub *ws.Hub, client *ws.Client, db *sql.DB, payload json.RawMessage) {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"fifteen-thirty-one-go/backend/internal/tracing"
+	ws "fifteen-thirty-one-go/backend/pkg/websocket"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxLobbyPins caps how many chat messages a lobby may have pinned at once.
+const maxLobbyPins = 3
+
+// PinLobbyChatMessage returns a Gin handler for POST (pinned=true) and DELETE (pinned=false)
+// /api/lobbies/:id/chat/:messageId/pin. Only the lobby host may pin, only chat messages can be
+// pinned, and a lobby holds at most maxLobbyPins. Pinning an already pinned message (or
+// unpinning an unpinned one) succeeds without a broadcast; changes go to the lobby room as
+// lobby:chat_pinned or lobby:chat_unpinned.
+func PinLobbyChatMessage(db *sql.DB, hubProvider func() (*ws.Hub, bool), pinned bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, span := tracing.StartSpan(c.Request.Context(), "handlers.PinLobbyChatMessage")
+		defer span.End()
+
+		userID, ok := userIDFromContext(c)
+		if !ok || userID <= 0 {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		lobbyID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || lobbyID <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid lobby id"})
+			return
+		}
+		messageID, err := strconv.ParseInt(c.Param("messageId"), 10, 64)
+		if err != nil || messageID <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid message id"})
+			return
+		}
+
+		ctx := c.Request.Context()
+
+		var hostID int64
+		err = db.QueryRowContext(ctx, `SELECT host_id FROM lobbies WHERE id = ?`, lobbyID).Scan(&hostID)
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "lobby not found"})
+			return
+		}
+		if err != nil {
+			log.Printf("PinLobbyChatMessage: get lobby (lobby_id=%d): %v", lobbyID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			return
+		}
+		if hostID != userID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "only the host can pin messages"})
+			return
+		}
+
+		// One statement, so two pins racing for the last slot can't both land.
+		var res sql.Result
+		if pinned {
+			res, err = db.ExecContext(ctx, `
+				UPDATE lobby_messages SET pinned = 1
+				WHERE id = ? AND lobby_id = ? AND message_type = 'chat' AND pinned = 0
+				  AND (SELECT COUNT(*) FROM lobby_messages WHERE lobby_id = ? AND pinned = 1) < ?
+			`, messageID, lobbyID, lobbyID, maxLobbyPins)
+		} else {
+			res, err = db.ExecContext(ctx, `
+				UPDATE lobby_messages SET pinned = 0 WHERE id = ? AND lobby_id = ? AND pinned = 1
+			`, messageID, lobbyID)
+		}
+		if err != nil {
+			log.Printf("PinLobbyChatMessage: update (lobby_id=%d message_id=%d pinned=%t): %v", lobbyID, messageID, pinned, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			return
+		}
+		changed, err := res.RowsAffected()
+		if err != nil {
+			log.Printf("PinLobbyChatMessage: rows affected (lobby_id=%d message_id=%d): %v", lobbyID, messageID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			return
+		}
+
+		msg, err := getLobbyChatMessage(db, lobbyID, messageID)
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "message not found"})
+			return
+		}
+		if err != nil {
+			log.Printf("PinLobbyChatMessage: get message (lobby_id=%d message_id=%d): %v", lobbyID, messageID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			return
+		}
+		if changed == 0 && msg.Pinned != pinned {
+			if msg.MessageType != "chat" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "only chat messages can be pinned"})
+				return
+			}
+			c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("a lobby can pin at most %d messages", maxLobbyPins), "code": "too_many_pins"})
+			return
+		}
+
+		if changed > 0 {
+			event := "lobby:chat_unpinned"
+			if pinned {
+				event = "lobby:chat_pinned"
+			}
+			if hub, ok := hubProvider(); ok && hub != nil {
+				hub.Broadcast(fmt.Sprintf("lobby:%d", lobbyID), event, msg)
+			}
+		}
+		c.JSON(http.StatusOK, msg)
+	}
+}
+
+// GetPinnedLobbyChatMessages returns a Gin handler for GET /api/lobbies/:id/chat/pinned: the
+// lobby's pinned messages, oldest first, for anyone who may read its chat.
+func GetPinnedLobbyChatMessages(db *sql.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, span := tracing.StartSpan(c.Request.Context(), "handlers.GetPinnedLobbyChatMessages")
+		defer span.End()
+
+		userID, ok := userIDFromContext(c)
+		if !ok || userID <= 0 {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		lobbyID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || lobbyID <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid lobby id"})
+			return
+		}
+
+		ctx := c.Request.Context()
+		authorized, err := canReadLobbyChat(ctx, db, lobbyID, userID)
+		if err != nil {
+			log.Printf("GetPinnedLobbyChatMessages: check authorization (lobby_id=%d user_id=%d): %v", lobbyID, userID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			return
+		}
+		if !authorized {
+			c.JSON(http.StatusForbidden, gin.H{"error": "you are not in this lobby"})
+			return
+		}
+
+		rows, err := db.QueryContext(ctx, `
+			SELECT id, lobby_id, user_id, username, message, message_type, pinned, created_at
+			FROM lobby_messages
+			WHERE lobby_id = ? AND pinned = 1
+			ORDER BY created_at, id
+		`, lobbyID)
+		if err != nil {
+			log.Printf("GetPinnedLobbyChatMessages: query (lobby_id=%d): %v", lobbyID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			return
+		}
+		defer rows.Close()
+
+		messages := []LobbyChatMessage{}
+		for rows.Next() {
+			msg, err := scanLobbyChatMessage(rows)
+			if err != nil {
+				log.Printf("GetPinnedLobbyChatMessages: scan (lobby_id=%d): %v", lobbyID, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+				return
+			}
+			messages = append(messages, msg)
+		}
+		if err := rows.Err(); err != nil {
+			log.Printf("GetPinnedLobbyChatMessages: iterate (lobby_id=%d): %v", lobbyID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"messages": messages})
+	}
+}
+
+// getLobbyChatMessage loads one message of lobbyID; sql.ErrNoRows when it is not in that lobby.
+func getLobbyChatMessage(db *sql.DB, lobbyID, messageID int64) (LobbyChatMessage, error) {
+	return scanLobbyChatMessage(db.QueryRow(`
+		SELECT id, lobby_id, user_id, username, message, message_type, pinned, created_at
+		FROM lobby_messages
+		WHERE id = ? AND lobby_id = ?
+	`, messageID, lobbyID))
+}
+
+// scanLobbyChatMessage scans the columns selected by getLobbyChatMessage.
+func scanLobbyChatMessage(row interface{ Scan(...any) error }) (LobbyChatMessage, error) {
+	var msg LobbyChatMessage
+	var nullUserID sql.NullInt64
+	if err := row.Scan(&msg.ID, &msg.LobbyID, &nullUserID, &msg.Username, &msg.Message, &msg.MessageType, &msg.Pinned, &msg.CreatedAt); err != nil {
+		return LobbyChatMessage{}, err
+	}
+	if nullUserID.Valid {
+		msg.UserID = &nullUserID.Int64
+	}
+	return msg, nil
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"fifteen-thirty-one-go/backend/internal/game/common"
+	"fifteen-thirty-one-go/backend/internal/game/cribbage"
+	"fifteen-thirty-one-go/backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Bounds for createLobbyRequest.IdleKickSeconds.
+const (
+	minIdleKickSeconds = 60
+	maxIdleKickSeconds = 3600
+)
+
+// idleKickInterval is how often RunIdleLobbyKicker sweeps waiting lobbies.
+const idleKickInterval = 15 * time.Second
+
+// errNoHostHeir reports that an idle host could not be kicked because no other human is
+// seated to take the lobby over.
+var errNoHostHeir = errors.New("no human to take over as host")
+
+// RunIdleLobbyKicker frees the seats of humans idle past their lobby's idle_kick_seconds while
+// the lobby is still filling, sweeping every idleKickInterval until ctx is cancelled.
+func RunIdleLobbyKicker(ctx context.Context, db *sql.DB) {
+	if !currentConfig().LobbyIdleKick {
+		return
+	}
+	ticker := time.NewTicker(idleKickInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		kickIdlePlayers(ctx, db)
+	}
+}
+
+func kickIdlePlayers(ctx context.Context, db *sql.DB) {
+	seats, err := models.ListIdleLobbySeats(db)
+	if err != nil {
+		log.Printf("RunIdleLobbyKicker: err=%v", err)
+		return
+	}
+	kicked := map[int64]bool{} // lobby id; seats shift after a kick, so one per lobby per sweep
+	for _, s := range seats {
+		if kicked[s.LobbyID] || seatConnected(s.GameID, s.UserID) {
+			continue
+		}
+		hostID, err := kickIdleSeat(db, s)
+		if err != nil {
+			if !errors.Is(err, errNoHostHeir) && !errors.Is(err, models.ErrLobbyNotJoinable) && !errors.Is(err, models.ErrNotFound) {
+				log.Printf("RunIdleLobbyKicker: lobby_id=%d user_id=%d err=%v", s.LobbyID, s.UserID, err)
+			}
+			continue
+		}
+		kicked[s.LobbyID] = true
+		log.Printf("RunIdleLobbyKicker: kicked idle player lobby_id=%d user_id=%d host_id=%d", s.LobbyID, s.UserID, hostID)
+		announceIdleKick(ctx, db, s, hostID)
+	}
+}
+
+// kickIdleSeat removes s's player from their lobby before play begins, handing the lobby to the
+// lowest-seated other human first when they host. Their dealt hand moves to the last seat so
+// every remaining player keeps the hand they were shown. It returns the lobby's host after the
+// kick, and fails with errNoHostHeir (leaving the seat alone) for a host with no human to
+// succeed them.
+func kickIdleSeat(db *sql.DB, s models.IdleLobbySeat) (int64, error) {
+	hostID := s.HostID
+	if s.UserID == hostID {
+		players, err := models.ListGamePlayersByGame(db, s.GameID)
+		if err != nil {
+			return 0, err
+		}
+		heir, heirPos := int64(0), int64(0)
+		for _, p := range players {
+			if !p.IsBot && p.UserID != s.UserID && (heir == 0 || p.Position < heirPos) {
+				heir, heirPos = p.UserID, p.Position
+			}
+		}
+		if heir == 0 {
+			return 0, errNoHostHeir
+		}
+		hostID = heir
+	}
+
+	if err := flushGameState(db, s.GameID); err != nil {
+		log.Printf("RunIdleLobbyKicker: flushGameState failed: game_id=%d err=%v", s.GameID, err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if hostID != s.HostID {
+		if err := models.SetLobbyHostTx(tx, s.LobbyID, hostID); err != nil {
+			return 0, err
+		}
+	}
+	if err := models.RemoveWaitingSeatTx(tx, s.LobbyID, s.GameID, s.UserID, s.Position); err != nil {
+		return 0, err
+	}
+	var raw sql.NullString
+	var version int64
+	if err := tx.QueryRow(`SELECT state_json, state_version FROM games WHERE id = ?`, s.GameID).Scan(&raw, &version); err != nil {
+		return 0, fmt.Errorf("query state_json (game_id=%d): %w", s.GameID, err)
+	}
+	if !raw.Valid {
+		return 0, fmt.Errorf("state_json missing (game_id=%d)", s.GameID)
+	}
+	var st cribbage.State
+	if err := json.Unmarshal([]byte(raw.String), &st); err != nil {
+		return 0, fmt.Errorf("restore state_json (game_id=%d): %w", s.GameID, err)
+	}
+	pos := int(s.Position)
+	if pos < 0 || pos >= len(st.Hands) {
+		return 0, fmt.Errorf("%w: game_id=%d pos=%d hands_len=%d", errJoinPosition, s.GameID, pos, len(st.Hands))
+	}
+	hands := make([][]common.Card, 0, len(st.Hands))
+	hands = append(hands, st.Hands[:pos]...)
+	hands = append(hands, st.Hands[pos+1:]...)
+	st.Hands = append(hands, st.Hands[pos])
+	sb, err := json.Marshal(st)
+	if err != nil {
+		return 0, err
+	}
+	if err := models.UpdateGameStateTxCAS(tx, s.GameID, version, string(sb)); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	st.Version = version + 1
+	defaultGameManager.Set(s.GameID, &st)
+	return hostID, nil
+}
+
+// announceIdleKick tells the lobby, the game room and the kicked player about the kick.
+func announceIdleKick(ctx context.Context, db *sql.DB, s models.IdleLobbySeat, hostID int64) {
+	if hub, ok := getHubProvider(); ok && hub != nil {
+		payload := gin.H{"lobby_id": s.LobbyID, "game_id": s.GameID, "user_id": s.UserID, "host_id": hostID, "reason": "idle"}
+		hub.Broadcast(fmt.Sprintf("lobby:%d", s.LobbyID), "lobby:player_kicked", payload)
+		hub.Broadcast(fmt.Sprintf("game:%d", s.GameID), "lobby:player_kicked", payload)
+		hub.SendToUser(s.UserID, "lobby:kicked", payload)
+		if u, err := models.GetUserByID(db, s.UserID); err == nil {
+			_ = SendSystemMessage(ctx, db, hub, s.LobbyID, fmt.Sprintf("%s was removed for inactivity", u.Username), "leave")
+		}
+	}
+	broadcastGameUpdate(db, s.GameID)
+}
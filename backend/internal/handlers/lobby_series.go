@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	"fifteen-thirty-one-go/backend/internal/models"
+)
+
+// addSeries attaches the lobby's running win tally, if it has one; lookup failures are logged.
+func (s *GameSnapshot) addSeries(ctx context.Context, db *sql.DB, lobbyID int64) {
+	if !currentConfig().LobbySeries {
+		return
+	}
+	series, err := models.GetLobbySeries(ctx, db, lobbyID)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			log.Printf("addSeries: GetLobbySeries failed: lobby_id=%d err=%v", lobbyID, err)
+		}
+		return
+	}
+	s.Series = series
+}
+
+// broadcastSeriesUpdate sends the lobby's series standings to the lobby room and to the room of
+// the game that just counted towards it.
+func broadcastSeriesUpdate(ctx context.Context, db *sql.DB, lobbyID, gameID int64) {
+	hub, ok := getHubProvider()
+	if !ok || hub == nil {
+		return
+	}
+	series, err := models.GetLobbySeries(ctx, db, lobbyID)
+	if err != nil {
+		log.Printf("broadcastSeriesUpdate: GetLobbySeries failed: lobby_id=%d err=%v", lobbyID, err)
+		return
+	}
+	payload := map[string]any{"lobby_id": lobbyID, "game_id": gameID, "series": series}
+	hub.Broadcast(fmt.Sprintf("lobby:%d", lobbyID), "lobby:series_update", payload)
+	hub.Broadcast("game:"+strconv.FormatInt(gameID, 10), "lobby:series_update", payload)
+}
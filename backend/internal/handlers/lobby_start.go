@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"fifteen-thirty-one-go/backend/internal/game/cribbage"
+	"fifteen-thirty-one-go/backend/internal/models"
+	"fifteen-thirty-one-go/backend/internal/tracing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errGameStarted reports that a lobby's game has already been dealt, so it cannot be started
+// again.
+var errGameStarted = errors.New("game already started")
+
+// StartLobbyHandler handles POST /api/lobbies/:id/start: the host deals the lobby's game before
+// every seat is filled, once at least LobbyMinPlayersToStart players sit. The table shrinks to
+// the seated players (a 4-seat lobby started with three plays a 3-player game). Full lobbies
+// start on their own (see startLobbyIfFull), so this is only needed to play short-handed.
+func StartLobbyHandler(db *sql.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, span := tracing.StartSpan(c.Request.Context(), "handlers.StartLobbyHandler")
+		defer span.End()
+
+		lobbyID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || lobbyID <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid lobby id"})
+			return
+		}
+		userID, ok := userIDFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		l, err := models.GetLobbyByID(db, lobbyID)
+		if err != nil {
+			writeAPIError(c, err)
+			return
+		}
+		if l.HostID != userID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "only the host can start the game"})
+			return
+		}
+		if l.Status != "waiting" {
+			c.JSON(http.StatusConflict, gin.H{"error": "game already started", "code": "game_already_started"})
+			return
+		}
+		if minPlayers := currentConfig().LobbyMinPlayersToStart; l.CurrentPlayers < minPlayers {
+			c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("at least %d players are needed to start", minPlayers), "code": "not_enough_players", "min_players": minPlayers})
+			return
+		}
+
+		gameID, err := startLobbyGame(db, lobbyID)
+		if err != nil {
+			switch {
+			case errors.Is(err, errGameStarted), errors.Is(err, models.ErrGameStateConflict):
+				c.JSON(http.StatusConflict, gin.H{"error": "game already started", "code": "game_already_started"})
+			default:
+				log.Printf("StartLobbyHandler: lobby_id=%d err=%v", lobbyID, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "unable to start game"})
+			}
+			return
+		}
+		announceGameStart(c.Request.Context(), db, lobbyID, gameID)
+
+		l, err = models.GetLobbyByID(db, lobbyID)
+		if err != nil {
+			writeAPIError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"lobby": l, "game_id": gameID})
+	}
+}
+
+// startLobbyIfFull starts the lobby's game once its last seat is taken. Joins call it after
+// committing; it is a no-op for lobbies still filling or already started.
+func startLobbyIfFull(db *sql.DB, lobbyID int64) {
+	l, err := models.GetLobbyByID(db, lobbyID)
+	if err != nil {
+		log.Printf("startLobbyIfFull: lobby_id=%d err=%v", lobbyID, err)
+		return
+	}
+	if l.Status != "waiting" || l.CurrentPlayers < l.MaxPlayers {
+		return
+	}
+	gameID, err := startLobbyGame(db, lobbyID)
+	if err != nil {
+		if !errors.Is(err, errGameStarted) && !errors.Is(err, models.ErrGameStateConflict) {
+			log.Printf("startLobbyIfFull: lobby_id=%d err=%v", lobbyID, err)
+		}
+		return
+	}
+	announceGameStart(context.Background(), db, lobbyID, gameID)
+}
+
+// startLobbyGame deals the lobby's undealt game to its seated players, moves the lobby and game
+// to in_progress, and returns the game's id. With seats still empty, the rules and the lobby's
+// max_players shrink to the seated count first. It fails with errGameStarted when the game was
+// already dealt, including games created before deals waited for the start.
+func startLobbyGame(db *sql.DB, lobbyID int64) (int64, error) {
+	var gameID int64
+	if err := db.QueryRow(
+		`SELECT id FROM games WHERE lobby_id = ? AND status IN ('waiting', 'in_progress') ORDER BY id DESC LIMIT 1`,
+		lobbyID,
+	).Scan(&gameID); err != nil {
+		return 0, fmt.Errorf("query game (lobby_id=%d): %w", lobbyID, err)
+	}
+	if err := flushGameState(db, gameID); err != nil {
+		log.Printf("startLobbyGame: flushGameState failed: game_id=%d err=%v", gameID, err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var status string
+	if err := tx.QueryRow(`SELECT status FROM lobbies WHERE id = ?`, lobbyID).Scan(&status); err != nil {
+		return 0, fmt.Errorf("query lobby (lobby_id=%d): %w", lobbyID, err)
+	}
+	if status != "waiting" {
+		return 0, errGameStarted
+	}
+	var raw sql.NullString
+	var version int64
+	if err := tx.QueryRow(`SELECT state_json, state_version FROM games WHERE id = ?`, gameID).Scan(&raw, &version); err != nil {
+		return 0, fmt.Errorf("query state_json (game_id=%d): %w", gameID, err)
+	}
+	if !raw.Valid {
+		return 0, fmt.Errorf("%w: game_id=%d", models.ErrGameStateMissing, gameID)
+	}
+	var st cribbage.State
+	if err := json.Unmarshal([]byte(raw.String), &st); err != nil {
+		return 0, fmt.Errorf("restore state_json (game_id=%d): %w", gameID, err)
+	}
+	if st.Stage != "dealing" {
+		return 0, errGameStarted
+	}
+
+	rows, err := tx.Query(`SELECT user_id, position FROM game_players WHERE game_id = ? ORDER BY position`, gameID)
+	if err != nil {
+		return 0, err
+	}
+	var seats []int64 // user id by position
+	for rows.Next() {
+		var userID, pos int64
+		if err := rows.Scan(&userID, &pos); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if pos != int64(len(seats)) {
+			rows.Close()
+			return 0, fmt.Errorf("%w: game_id=%d pos=%d want=%d", errJoinPosition, gameID, pos, len(seats))
+		}
+		seats = append(seats, userID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	if len(seats) != st.Rules.MaxPlayers {
+		rules := st.Rules
+		rules.MaxPlayers = len(seats)
+		if err := rules.Validate(); err != nil {
+			return 0, err
+		}
+		st = *cribbage.NewStateWithRules(rules)
+		if _, err := tx.Exec(`UPDATE lobbies SET max_players = ? WHERE id = ?`, len(seats), lobbyID); err != nil {
+			return 0, fmt.Errorf("shrink lobby (lobby_id=%d): %w", lobbyID, err)
+		}
+	}
+	// The first deal goes to whoever cuts lowest; later hands rotate from there.
+	if err := st.CutForFirstDeal(); err != nil {
+		return 0, fmt.Errorf("%w: %v", errGameInit, err)
+	}
+	if err := st.Deal(); err != nil {
+		return 0, fmt.Errorf("%w: %v", errGameInit, err)
+	}
+	for pos, userID := range seats {
+		b, err := json.Marshal(st.Hands[pos])
+		if err != nil {
+			return 0, err
+		}
+		if err := models.UpdatePlayerHandTx(tx, gameID, userID, string(b)); err != nil {
+			return 0, err
+		}
+	}
+	sb, err := json.Marshal(st)
+	if err != nil {
+		return 0, err
+	}
+	if err := models.UpdateGameStateTxCAS(tx, gameID, version, string(sb)); err != nil {
+		return 0, err
+	}
+	if err := models.SetLobbyStatusTx(tx, lobbyID, "in_progress"); err != nil {
+		return 0, err
+	}
+	if err := models.SetGameStatusTx(tx, gameID, "in_progress"); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	st.Version = version + 1
+	defaultGameManager.Set(gameID, &st)
+	return gameID, nil
+}
+
+// announceGameStart tells the lobby and game rooms that play has begun, lets bots make their
+// opening discards, and pushes the dealt game.
+func announceGameStart(ctx context.Context, db *sql.DB, lobbyID, gameID int64) {
+	if hub, ok := getHubProvider(); ok && hub != nil {
+		payload := gin.H{"lobby_id": lobbyID, "game_id": gameID}
+		hub.Broadcast(fmt.Sprintf("lobby:%d", lobbyID), "lobby:game_started", payload)
+		hub.Broadcast(fmt.Sprintf("game:%d", gameID), "lobby:game_started", payload)
+		_ = SendSystemMessage(ctx, db, hub, lobbyID, "The game has started", "system")
+	}
+	if err := maybeRunBotTurns(db, gameID); err != nil {
+		log.Printf("announceGameStart: maybeRunBotTurns failed: game_id=%d err=%v", gameID, err)
+	}
+	broadcastGameUpdate(db, gameID)
+}
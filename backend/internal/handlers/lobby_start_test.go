@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"fifteen-thirty-one-go/backend/internal/game/cribbage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newWaitingLobby creates a lobby with seats for maxPlayers, seats the named users (the first
+// hosts) and returns the lobby and game ids with the users' ids in seat order.
+func newWaitingLobby(t *testing.T, db *sql.DB, maxPlayers int, usernames ...string) (int64, int64, []int64) {
+	t.Helper()
+	users := make([]int64, len(usernames))
+	for i, name := range usernames {
+		users[i] = newTestUser(t, db, name)
+	}
+	l, g, err := createLobbyWithGame(db, "waiting", users[0], cribbage.DefaultRules(maxPlayers), 0, nil, 0)
+	if err != nil {
+		t.Fatalf("create lobby: %v", err)
+	}
+	for _, u := range users[1:] {
+		joinLobby(t, db, l.ID, u)
+	}
+	return l.ID, g.ID, users
+}
+
+func joinLobby(t *testing.T, db *sql.DB, lobbyID, userID int64) {
+	t.Helper()
+	path := fmt.Sprintf("/lobbies/%d/join", lobbyID)
+	if code := doRequest(t, JoinLobbyHandler(db), http.MethodPost, "/lobbies/:id/join", path, userID, nil, nil); code != http.StatusOK {
+		t.Fatalf("join user %d: status %d", userID, code)
+	}
+}
+
+func startLobby(t *testing.T, db *sql.DB, lobbyID, userID int64) (int, string) {
+	t.Helper()
+	var out struct {
+		Code string `json:"code"`
+	}
+	path := fmt.Sprintf("/lobbies/%d/start", lobbyID)
+	code := doRequest(t, StartLobbyHandler(db), http.MethodPost, "/lobbies/:id/start", path, userID, nil, &out)
+	return code, out.Code
+}
+
+func gameStage(t *testing.T, gameID int64) string {
+	t.Helper()
+	st, unlock, ok := defaultGameManager.GetLocked(gameID)
+	if !ok {
+		t.Fatalf("game %d has no engine state", gameID)
+	}
+	defer unlock()
+	return st.Stage
+}
+
+func TestMoveBeforeStartIsRefused(t *testing.T) {
+	db := newTestDB(t)
+	_, gameID, users := newWaitingLobby(t, db, 3, "alice", "bob")
+
+	var out struct {
+		Code string `json:"code"`
+	}
+	path := fmt.Sprintf("/games/%d/move", gameID)
+	body := gin.H{"type": "discard", "cards": []string{"5H"}}
+	if code := doRequest(t, MoveHandler(db), http.MethodPost, "/games/:id/move", path, users[0], body, &out); code != http.StatusConflict || out.Code != "game_not_started" {
+		t.Errorf("move before start: status %d code %q, want 409 game_not_started", code, out.Code)
+	}
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM game_moves WHERE game_id = ?`, gameID); n != 0 {
+		t.Errorf("%d moves recorded before the start, want none", n)
+	}
+}
+
+func TestOnlyTheHostStartsAndOnlyOnce(t *testing.T) {
+	db := newTestDB(t)
+	lobbyID, gameID, users := newWaitingLobby(t, db, 3, "alice", "bob")
+	alice, bob := users[0], users[1]
+
+	if code, _ := startLobby(t, db, lobbyID, bob); code != http.StatusForbidden {
+		t.Errorf("start by a guest: status %d, want 403", code)
+	}
+	if stage := gameStage(t, gameID); stage != "dealing" {
+		t.Fatalf("stage %q after a refused start, want dealing", stage)
+	}
+
+	if code, _ := startLobby(t, db, lobbyID, alice); code != http.StatusOK {
+		t.Fatalf("start by the host: status %d, want 200", code)
+	}
+	if code, errCode := startLobby(t, db, lobbyID, alice); code != http.StatusConflict || errCode != "game_already_started" {
+		t.Errorf("second start: status %d code %q, want 409 game_already_started", code, errCode)
+	}
+}
+
+func TestStartingShortHandedShrinksTheTable(t *testing.T) {
+	db := newTestDB(t)
+	lobbyID, gameID, users := newWaitingLobby(t, db, 4, "alice", "bob", "carol")
+
+	if code, _ := startLobby(t, db, lobbyID, users[0]); code != http.StatusOK {
+		t.Fatalf("start: status %d, want 200", code)
+	}
+	if n := queryInt(t, db, `SELECT max_players FROM lobbies WHERE id = ?`, lobbyID); n != 3 {
+		t.Errorf("lobby max_players %d after a 3-player start, want 3", n)
+	}
+	st, unlock, _ := defaultGameManager.GetLocked(gameID)
+	maxPlayers, stage, hands := st.Rules.MaxPlayers, st.Stage, len(st.Hands)
+	unlock()
+	if maxPlayers != 3 || hands != 3 || stage != "discard" {
+		t.Errorf("engine has %d players, %d hands, stage %q; want 3, 3 and discard", maxPlayers, hands, stage)
+	}
+	for _, u := range users {
+		if hand := seatHand(t, db, gameID, u); len(hand) != 5 {
+			t.Errorf("user %d dealt %d cards, want 5", u, len(hand))
+		}
+	}
+}
+
+func TestLobbyStartsWhenTheLastSeatFills(t *testing.T) {
+	db := newTestDB(t)
+	lobbyID, gameID, _ := newWaitingLobby(t, db, 3, "alice", "bob")
+	if stage := gameStage(t, gameID); stage != "dealing" {
+		t.Fatalf("stage %q with a seat open, want dealing", stage)
+	}
+
+	joinLobby(t, db, lobbyID, newTestUser(t, db, "carol"))
+
+	if stage := gameStage(t, gameID); stage != "discard" {
+		t.Errorf("stage %q after the last seat filled, want discard", stage)
+	}
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM lobbies WHERE id = ? AND status = 'in_progress'`, lobbyID); n != 1 {
+		t.Error("lobby not in progress after the last seat filled")
+	}
+}
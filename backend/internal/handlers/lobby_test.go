@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"fifteen-thirty-one-go/backend/internal/game/cribbage"
+)
+
+func TestJoinLobbyTwiceKeepsOneSeat(t *testing.T) {
+	db := newTestDB(t)
+	host := newTestUser(t, db, "host")
+	guest := newTestUser(t, db, "guest")
+	l, g, err := createLobbyWithGame(db, "double join", host, cribbage.DefaultRules(3), 0, nil, 0)
+	if err != nil {
+		t.Fatalf("create lobby: %v", err)
+	}
+	path := fmt.Sprintf("/lobbies/%d/join", l.ID)
+
+	type joinResp struct {
+		GameID        int64 `json:"game_id"`
+		Position      int64 `json:"position"`
+		AlreadyJoined bool  `json:"already_joined"`
+	}
+	var first, second joinResp
+	if code := doRequest(t, JoinLobbyHandler(db), http.MethodPost, "/lobbies/:id/join", path, guest, nil, &first); code != http.StatusOK {
+		t.Fatalf("first join: status %d", code)
+	}
+	if code := doRequest(t, JoinLobbyHandler(db), http.MethodPost, "/lobbies/:id/join", path, guest, nil, &second); code != http.StatusOK {
+		t.Fatalf("second join: status %d", code)
+	}
+	if first.AlreadyJoined || !second.AlreadyJoined {
+		t.Errorf("already_joined = %t then %t, want false then true", first.AlreadyJoined, second.AlreadyJoined)
+	}
+	if first.Position != 1 || second.Position != first.Position {
+		t.Errorf("positions %d then %d, want 1 both times", first.Position, second.Position)
+	}
+	if second.GameID != g.ID {
+		t.Errorf("second join game_id = %d, want %d", second.GameID, g.ID)
+	}
+
+	// The host "joining" their own lobby is the same no-op.
+	var hostJoin joinResp
+	if code := doRequest(t, JoinLobbyHandler(db), http.MethodPost, "/lobbies/:id/join", path, host, nil, &hostJoin); code != http.StatusOK || !hostJoin.AlreadyJoined || hostJoin.Position != 0 {
+		t.Errorf("host join: status %d already_joined %t position %d, want 200 true 0", code, hostJoin.AlreadyJoined, hostJoin.Position)
+	}
+
+	if n := queryInt(t, db, `SELECT current_players FROM lobbies WHERE id = ?`, l.ID); n != 2 {
+		t.Errorf("current_players = %d, want 2", n)
+	}
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM game_players WHERE game_id = ? AND user_id = ?`, g.ID, guest); n != 1 {
+		t.Errorf("guest holds %d seats, want 1", n)
+	}
+}
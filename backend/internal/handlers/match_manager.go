@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	"fifteen-thirty-one-go/backend/internal/game/cribbage"
+	"fifteen-thirty-one-go/backend/internal/models"
+)
+
+// MatchManager runs best-of-N matches: lobbies created with match points play consecutive games,
+// each worth 1 match point to its winner plus 1 per skunk level, until a player reaches the
+// match's target. Game results are recorded in the same transaction that finalizes the game, and
+// the next game is created there too, so a retried finalization can neither score a game twice
+// nor start two follow-ups.
+type MatchManager struct {
+	db *sql.DB
+}
+
+func NewMatchManager(db *sql.DB) *MatchManager {
+	return &MatchManager{db: db}
+}
+
+// nextMatchGame is a match game created inside a finalization transaction. Its engine state is
+// registered and announced by Start once the transaction commits.
+type nextMatchGame struct {
+	matchID    int64
+	prevGameID int64
+	gameID     int64
+	lobbyID    int64
+	state      *cribbage.State
+	players    []models.GamePlayer
+}
+
+// RecordGameTx scores the finished game for its match, if it belongs to one, and either ends the
+// match or creates its next game. players are the finished game's seats; rules are its rules,
+// reused for the next game. It returns the next game to Start after commit, or nil. If another
+// finalization already recorded this game's result it returns models.ErrMatchGameRecorded and
+// changes nothing.
+func (mm *MatchManager) RecordGameTx(tx *sql.Tx, gameID int64, players []models.GamePlayer, winnerID int64, skunk int, rules cribbage.Rules) (*nextMatchGame, error) {
+	m, err := models.ActiveMatchForGameTx(tx, gameID)
+	if errors.Is(err, models.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	points := int64(1 + skunk)
+	recorded, err := models.RecordMatchGameResultTx(tx, gameID, winnerID, skunk, points)
+	if err != nil {
+		return nil, err
+	}
+	if !recorded {
+		return nil, models.ErrMatchGameRecorded
+	}
+	// m was loaded before this game's result landed.
+	standings := m.Points()
+	standings[winnerID] += points
+	if standings[winnerID] >= m.TargetPoints {
+		return nil, models.EndMatchTx(tx, m.ID, models.MatchFinished, &winnerID, nil)
+	}
+	for _, p := range players {
+		if p.Resigned {
+			// A player who resigned mid-game has left the match, not just the game.
+			leader := matchLeader(standings, players, p.UserID)
+			return nil, models.EndMatchTx(tx, m.ID, models.MatchForfeited, leader, &p.UserID)
+		}
+	}
+	return mm.createNextGameTx(tx, m, gameID, players, rules)
+}
+
+// createNextGameTx seats the finished game's players in a new game in the same lobby and deals
+// it. Seats a bot took over after a disconnect go back to their humans. The opening dealer
+// rotates with each game of the match.
+func (mm *MatchManager) createNextGameTx(tx *sql.Tx, m *models.Match, prevGameID int64, players []models.GamePlayer, rules cribbage.Rules) (*nextMatchGame, error) {
+	gameID, err := models.CreateGameTx(tx, m.LobbyID)
+	if err != nil {
+		return nil, fmt.Errorf("create next match game (match_id=%d): %w", m.ID, err)
+	}
+	for _, p := range players {
+		isBot := p.IsBot && !p.BotTakeover
+		var diff *string
+		if isBot {
+			diff = p.BotDifficulty
+		}
+		if err := models.AddGamePlayerTx(tx, gameID, p.UserID, p.Position, isBot, diff); err != nil {
+			return nil, fmt.Errorf("seat next match game (match_id=%d user_id=%d): %w", m.ID, p.UserID, err)
+		}
+	}
+
+	st := cribbage.NewStateWithRules(rules)
+	st.DealerIndex = len(m.Games) % rules.MaxPlayers
+	if err := st.Deal(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errGameInit, err)
+	}
+	for _, p := range players {
+		pos := int(p.Position)
+		if pos < 0 || pos >= len(st.Hands) {
+			continue
+		}
+		b, err := json.Marshal(st.Hands[pos])
+		if err != nil {
+			return nil, err
+		}
+		if _, err := models.UpdatePlayerHandIfEmptyTx(tx, gameID, p.UserID, string(b)); err != nil {
+			return nil, err
+		}
+	}
+	sb, err := json.Marshal(st)
+	if err != nil {
+		return nil, err
+	}
+	if err := models.UpdateGameStateTx(tx, gameID, string(sb)); err != nil {
+		return nil, err
+	}
+	if err := models.AddMatchGameTx(tx, m.ID, int64(len(m.Games)+1), gameID); err != nil {
+		return nil, err
+	}
+	if err := models.SetGameStatusTx(tx, gameID, "in_progress"); err != nil {
+		return nil, err
+	}
+	return &nextMatchGame{matchID: m.ID, prevGameID: prevGameID, gameID: gameID, lobbyID: m.LobbyID, state: st, players: players}, nil
+}
+
+// Start registers a committed next game's engine state, lets bots make their opening discards,
+// and tells the finished game's room and every human player where play continues.
+func (mm *MatchManager) Start(next *nextMatchGame) {
+	// Fresh game: UpdateGameStateTx has incremented from 0 -> 1.
+	next.state.Version = 1
+	defaultGameManager.Set(next.gameID, next.state)
+	if err := maybeRunBotTurns(mm.db, next.gameID); err != nil {
+		log.Printf("MatchManager: maybeRunBotTurns failed: match_id=%d game_id=%d err=%v", next.matchID, next.gameID, err)
+	}
+
+	hub, ok := getHubProvider()
+	if !ok || hub == nil {
+		return
+	}
+	payload := map[string]any{"match_id": next.matchID, "game_id": next.gameID, "lobby_id": next.lobbyID}
+	hub.Broadcast("game:"+strconv.FormatInt(next.prevGameID, 10), "match:next_game", payload)
+	for _, p := range next.players {
+		if !p.IsBot || p.BotTakeover {
+			hub.SendToUser(p.UserID, "match:next_game", payload)
+		}
+	}
+}
+
+// ForfeitTx ends the in-progress match containing gameID with userID forfeiting it. The player
+// with the most match points among the others wins. Games outside a match are left alone.
+func (mm *MatchManager) ForfeitTx(tx *sql.Tx, gameID, userID int64, players []models.GamePlayer) error {
+	m, err := models.ActiveMatchForGameTx(tx, gameID)
+	if errors.Is(err, models.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	leader := matchLeader(m.Points(), players, userID)
+	return models.EndMatchTx(tx, m.ID, models.MatchForfeited, leader, &userID)
+}
+
+// Forfeit is ForfeitTx in its own transaction.
+func (mm *MatchManager) Forfeit(gameID, userID int64) error {
+	players, err := models.ListGamePlayersByGame(mm.db, gameID)
+	if err != nil {
+		return err
+	}
+	tx, err := mm.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := mm.ForfeitTx(tx, gameID, userID, players); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// AbandonTx ends the in-progress match containing gameID without a winner.
+func (mm *MatchManager) AbandonTx(tx *sql.Tx, gameID int64) error {
+	m, err := models.ActiveMatchForGameTx(tx, gameID)
+	if errors.Is(err, models.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return models.EndMatchTx(tx, m.ID, models.MatchAbandoned, nil, nil)
+}
+
+// matchLeader returns the player other than exclude with the most match points, the earliest
+// seat winning ties, or nil when nobody else is seated.
+func matchLeader(points map[int64]int64, players []models.GamePlayer, exclude int64) *int64 {
+	var leader *int64
+	for i := range players {
+		id := players[i].UserID
+		if id == exclude {
+			continue
+		}
+		if leader == nil || points[id] > points[*leader] {
+			leader = &players[i].UserID
+		}
+	}
+	return leader
+}
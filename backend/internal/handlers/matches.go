@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"fifteen-thirty-one-go/backend/internal/models"
+	"fifteen-thirty-one-go/backend/internal/tracing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// matchScore is one player's standing in a match.
+type matchScore struct {
+	UserID   int64  `json:"user_id"`
+	Username string `json:"username"`
+	Points   int64  `json:"points"`
+}
+
+type matchResponse struct {
+	*models.Match
+	Scores []matchScore `json:"scores"`
+}
+
+// GetMatchHandler returns a match's per-game results and every player's match points. Anyone who
+// may view the match's current game may view the match.
+func GetMatchHandler(db *sql.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, span := tracing.StartSpan(c.Request.Context(), "handlers.GetMatchHandler")
+		defer span.End()
+
+		matchID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || matchID <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid match id"})
+			return
+		}
+		userID, ok := userIDFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		m, err := models.GetMatch(c.Request.Context(), db, matchID)
+		if err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "match not found"})
+				return
+			}
+			log.Printf("GetMatchHandler: GetMatch failed: match_id=%d err=%v", matchID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		if len(m.Games) == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "match not found"})
+			return
+		}
+		current := m.Games[len(m.Games)-1].GameID
+		allowed, err := canViewGame(db, userID, current)
+		if err != nil {
+			log.Printf("GetMatchHandler: authorization check failed: match_id=%d user_id=%d err=%v", matchID, userID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		if !allowed {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+
+		players, err := models.ListGamePlayersByGameContext(c.Request.Context(), db, current)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		points := m.Points()
+		scores := make([]matchScore, 0, len(players))
+		for _, p := range players {
+			scores = append(scores, matchScore{UserID: p.UserID, Username: p.Username, Points: points[p.UserID]})
+		}
+		c.JSON(http.StatusOK, matchResponse{Match: m, Scores: scores})
+	}
+}
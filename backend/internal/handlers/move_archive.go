@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"fifteen-thirty-one-go/backend/internal/models"
+)
+
+// moveArchiveBatch caps how many games one archival pass processes.
+const moveArchiveBatch = 100
+
+// RunMoveArchiver prunes the raw moves of games finished more than MoveArchiveAge ago, keeping a
+// per-player summary (see models.ArchiveGameMoves). It runs hourly until ctx is cancelled.
+func RunMoveArchiver(ctx context.Context, db *sql.DB) {
+	cfg := currentConfig()
+	if !cfg.MoveArchival {
+		return
+	}
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		archiveFinishedGames(ctx, db, cfg.MoveArchiveAge)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func archiveFinishedGames(ctx context.Context, db *sql.DB, age time.Duration) {
+	ids, err := models.ListArchivableGames(db, age, moveArchiveBatch)
+	if err != nil {
+		log.Printf("RunMoveArchiver: err=%v", err)
+		return
+	}
+	for _, gameID := range ids {
+		if ctx.Err() != nil {
+			return
+		}
+		pruned, err := models.ArchiveGameMoves(ctx, db, gameID)
+		if err != nil {
+			log.Printf("RunMoveArchiver: game_id=%d err=%v", gameID, err)
+			continue
+		}
+		log.Printf("RunMoveArchiver: archived game_id=%d pruned_moves=%d", gameID, pruned)
+	}
+}
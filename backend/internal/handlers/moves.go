@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"fifteen-thirty-one-go/backend/internal/game/cribbage"
 	"fifteen-thirty-one-go/backend/internal/models"
 	"fifteen-thirty-one-go/backend/internal/tracing"
 
@@ -44,6 +45,31 @@ func GameMovesHandler(db *sql.DB) gin.HandlerFunc {
 			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
 			return
 		}
+		archived, err := models.GameMovesArchived(db, gameID)
+		if err != nil {
+			writeAPIError(c, err)
+			return
+		}
+		if archived {
+			// Raw moves were pruned; fall back to the archived summary and round history.
+			summary, err := models.ListMoveSummaries(db, gameID)
+			if err != nil {
+				log.Printf("GameMovesHandler ListMoveSummaries failed: err=%v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+				return
+			}
+			history, err := roundHistory(c.Request.Context(), db, gameID)
+			if err != nil {
+				log.Printf("GameMovesHandler roundHistory failed: game_id=%d err=%v", gameID, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+				return
+			}
+			if history == nil {
+				history = []cribbage.RoundSummary{}
+			}
+			c.JSON(http.StatusOK, gin.H{"moves": []models.GameMove{}, "archived": true, "summary": summary, "history": history})
+			return
+		}
 		moves, err := models.ListMovesByGame(db, gameID, 200)
 		if err != nil {
 			log.Printf("GameMovesHandler ListMovesByGame failed: err=%v", err)
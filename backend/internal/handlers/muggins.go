@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"fifteen-thirty-one-go/backend/internal/models"
+	"fifteen-thirty-one-go/backend/internal/tracing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type mugginsRequest struct {
+	MoveID int64 `json:"move_id"`
+}
+
+// MugginsHandler lets a player call muggins on an opponent's final count that claimed less than
+// the verified score, within MugginsWindow of the count and before the next hand is dealt. The
+// missed points move from the claimant to the caller, capped at the claimant's score (see
+// cribbage.State.Muggins), and a "muggins" move referencing the count records the points that
+// moved; each count can be called once. Only games created with the muggins rule accept calls.
+func MugginsHandler(db *sql.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, span := tracing.StartSpan(c.Request.Context(), "handlers.MugginsHandler")
+		defer span.End()
+
+		gameID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || gameID <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid game id"})
+			return
+		}
+		userID, ok := userIDFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		var req mugginsRequest
+		if err := c.ShouldBindJSON(&req); err != nil || req.MoveID <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid json"})
+			return
+		}
+
+		players, err := models.ListGamePlayersByGame(db, gameID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		callerPos, claimantPos := -1, -1
+		for _, p := range players {
+			if p.UserID == userID && !p.Resigned {
+				callerPos = int(p.Position)
+			}
+		}
+		if callerPos < 0 {
+			c.JSON(http.StatusForbidden, gin.H{"error": "not a player"})
+			return
+		}
+
+		st, unlock, err := ensureGameStateLocked(db, gameID, players)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "game not ready"})
+			return
+		}
+		enabled := st.Rules.Muggins
+		unlock()
+		if !enabled {
+			writeAPIError(c, models.ErrMugginsDisabled)
+			return
+		}
+
+		prev, err := models.GetMoveByID(db, req.MoveID)
+		if err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid move"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		if prev.GameID != gameID || (prev.MoveType != "count_hand_final" && prev.MoveType != "count_crib_final") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "muggins can only be called on a final count", "code": "muggins_not_a_count"})
+			return
+		}
+		if prev.PlayerID == userID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "you cannot call muggins on your own count", "code": "muggins_own_count"})
+			return
+		}
+		if prev.IsCorrected || prev.ScoreClaimed == nil || prev.ScoreVerified == nil || *prev.ScoreClaimed >= *prev.ScoreVerified {
+			c.JSON(http.StatusConflict, gin.H{"error": "that count did not miss any points", "code": "muggins_no_missed_points"})
+			return
+		}
+		if time.Since(prev.CreatedAt) > currentConfig().MugginsWindow {
+			c.JSON(http.StatusConflict, gin.H{"error": "the muggins window has closed", "code": "muggins_window_closed"})
+			return
+		}
+		// A discard after the count means the next hand is already under way.
+		later, err := models.HasMoveTypeAfter(db, gameID, "discard", prev.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		if later {
+			c.JSON(http.StatusConflict, gin.H{"error": "the muggins window has closed", "code": "muggins_window_closed"})
+			return
+		}
+		for _, p := range players {
+			if p.UserID == prev.PlayerID {
+				claimantPos = int(p.Position)
+			}
+		}
+		if claimantPos < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid move"})
+			return
+		}
+		missed := *prev.ScoreVerified - *prev.ScoreClaimed
+
+		st, unlock, err = ensureGameStateLocked(db, gameID, players)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "game not ready"})
+			return
+		}
+		baseVersion := st.Version
+		eventsBefore := st.LastEventSeq()
+		working := cloneStateDeep(st)
+		working.Version = baseVersion
+		unlock()
+		moved, err := working.Muggins(callerPos, claimantPos, int(missed))
+		if err != nil {
+			writeAPIError(c, err)
+			return
+		}
+
+		tx, err := db.Begin()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		committed := false
+		defer func() {
+			if !committed {
+				_ = tx.Rollback()
+			}
+		}()
+		if err := models.InsertMugginsMoveTx(tx, gameID, userID, prev.ID, int64(moved)); err != nil {
+			if !errors.Is(err, models.ErrMugginsAlreadyCalled) {
+				log.Printf("MugginsHandler: insert move failed: game_id=%d move_id=%d err=%v", gameID, prev.ID, err)
+			}
+			writeAPIError(c, err)
+			return
+		}
+		applied, err := commitStateTx(db, tx, gameID, baseVersion, &working)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		if !applied {
+			writeAPIError(c, models.ErrGameStateConflict)
+			return
+		}
+		committed = true
+		log.Printf("MugginsHandler: muggins called: game_id=%d move_id=%d caller_id=%d claimant_id=%d missed=%d claimant_lost=%d", gameID, prev.ID, userID, prev.PlayerID, missed, moved)
+
+		if working.Stage == "finished" {
+			if err := maybeFinalizeGame(c.Request.Context(), db, gameID); err != nil {
+				log.Printf("maybeFinalizeGame failed: game_id=%d err=%v", gameID, err)
+			}
+		}
+
+		broadcastGameEvents(db, gameID, newGameEventBatch(&working, eventsBefore))
+		broadcastGameUpdate(db, gameID)
+		c.JSON(http.StatusOK, gin.H{"awarded": moved, "scores": working.Scores})
+	}
+}
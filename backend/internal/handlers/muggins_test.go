@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"fifteen-thirty-one-go/backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMugginsAwardsOnlyPointsMoved(t *testing.T) {
+	db := newTestDB(t)
+	gameID, users := newTestGame(t, db, "alice", "bob")
+	claimant, caller := users[0], users[1]
+
+	st, unlock, _ := defaultGameManager.GetLocked(gameID)
+	st.Rules.Muggins = true
+	st.Stage = "counting"
+	st.Scores = []int{3, 5}
+	unlock()
+
+	claimed, verified := int64(2), int64(8)
+	count, err := models.InsertMove(db, models.GameMove{GameID: gameID, PlayerID: claimant, MoveType: "count_hand_final", ScoreClaimed: &claimed, ScoreVerified: &verified})
+	if err != nil {
+		t.Fatalf("insert count: %v", err)
+	}
+
+	var out struct {
+		Awarded int   `json:"awarded"`
+		Scores  []int `json:"scores"`
+	}
+	path := fmt.Sprintf("/games/%d/muggins", gameID)
+	if code := doRequest(t, MugginsHandler(db), http.MethodPost, "/games/:id/muggins", path, caller, gin.H{"move_id": count.ID}, &out); code != http.StatusOK {
+		t.Fatalf("muggins: status %d", code)
+	}
+	// Six points were missed but the claimant only had three to lose.
+	if out.Awarded != 3 || out.Scores[0] != 0 || out.Scores[1] != 8 {
+		t.Errorf("muggins awarded %d with scores %v, want 3 with [0 8]", out.Awarded, out.Scores)
+	}
+	if n := queryInt(t, db, `SELECT score_verified FROM game_moves WHERE game_id = ? AND move_type = 'muggins'`, gameID); n != 3 {
+		t.Errorf("muggins move records %d points, want 3", n)
+	}
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"database/sql"
+	"log"
+	"strconv"
+
+	"fifteen-thirty-one-go/backend/internal/game/cribbage"
+	"fifteen-thirty-one-go/backend/internal/models"
+)
+
+// pegOutcome is the pegging result of one play_card or go, shaped per viewer according to
+// their pegging_reveal preference.
+type pegOutcome struct {
+	Player  int      `json:"player"`
+	Points  int      `json:"points"`
+	Reasons []string `json:"reasons"`
+	Total   int      `json:"total"`
+
+	sequenceEnded bool
+	sequence      []cribbage.PegEvent    // scoring events of the sequence that just ended
+	goResolution  *cribbage.GoResolution // set when a go ended the sequence
+	autoGo        []autoGoStep           // gos the server said after a play_card
+}
+
+// newPegOutcome captures the outcome from the post-move state. inSequence reports whether a
+// sequence was in progress when the move was made.
+func newPegOutcome(st *cribbage.State, player, points int, reasons []string, inSequence bool) *pegOutcome {
+	out := &pegOutcome{Player: player, Points: points, Reasons: reasons, Total: st.PeggingTotal}
+	if inSequence && (len(st.PeggingSeq) == 0 || st.Stage != "pegging") {
+		out.sequenceEnded = true
+		out.sequence = append([]cribbage.PegEvent{}, st.LastSequencePegs...)
+	}
+	return out
+}
+
+// pegRevealMode returns the user's pegging_reveal preference, falling back to immediate.
+func pegRevealMode(db *sql.DB, userID int64) string {
+	prefs, err := models.GetUserPreferences(db, userID)
+	if err != nil {
+		log.Printf("pegRevealMode: GetUserPreferences failed: user_id=%d err=%v", userID, err)
+		return models.PeggingRevealImmediate
+	}
+	return prefs.PeggingReveal
+}
+
+// shapePeggingResponse rewrites the mover's play_card/go response for the sequence_end mode:
+// only the running total until the sequence ends, then the whole sequence's breakdown.
+func shapePeggingResponse(db *sql.DB, userID int64, moveType string, resp any, peg *pegOutcome) any {
+	if pegRevealMode(db, userID) != models.PeggingRevealSequenceEnd {
+		return resp
+	}
+	out := map[string]any{"total": peg.Total, "sequence_complete": peg.sequenceEnded}
+	if moveType == "go" {
+		// A go only ever scores the last-card point, which ends the sequence anyway.
+		out["awarded"] = peg.Points
+	}
+	if peg.sequenceEnded {
+		out["sequence"] = peg.sequence
+	}
+	if len(peg.autoGo) > 0 {
+		out["auto_go"] = peg.autoGo
+	}
+	return out
+}
+
+// notifyPegging sends each human participant a "pegging:points" event shaped by their
+// preference: every scoring play for immediate, or one breakdown per finished sequence.
+func notifyPegging(db *sql.DB, gameID int64, peg *pegOutcome) {
+	if hubProvider == nil {
+		return
+	}
+	hub, ok := hubProvider()
+	if !ok || hub == nil {
+		return
+	}
+	players, err := models.ListGamePlayersByGame(db, gameID)
+	if err != nil {
+		log.Printf("notifyPegging: ListGamePlayersByGame failed: game_id=%d err=%v", gameID, err)
+		return
+	}
+	for _, p := range players {
+		if p.IsBot {
+			continue
+		}
+		if pegRevealMode(db, p.UserID) == models.PeggingRevealSequenceEnd {
+			if peg.sequenceEnded {
+				hub.SendToUser(p.UserID, "pegging:points", map[string]any{"game_id": gameID, "sequence": peg.sequence})
+			}
+			continue
+		}
+		if peg.Points > 0 {
+			hub.SendToUser(p.UserID, "pegging:points", map[string]any{"game_id": gameID, "player": peg.Player, "points": peg.Points, "reasons": peg.Reasons, "total": peg.Total})
+		}
+	}
+}
+
+// broadcastGoResolved tells the game room how an all-pass sequence ended (who passed, who took
+// the last-card point, who leads next) so clients can narrate it without diffing scores.
+func broadcastGoResolved(gameID int64, res *cribbage.GoResolution) {
+	if !currentConfig().GoResolvedEvents || hubProvider == nil {
+		return
+	}
+	hub, ok := hubProvider()
+	if !ok || hub == nil {
+		return
+	}
+	reasons := []string{"go"}
+	if res.LastCardPlayer >= 0 {
+		reasons = append(reasons, cribbage.LastCardReason)
+	}
+	hub.Broadcast("game:"+strconv.FormatInt(gameID, 10), "game:go_resolved", map[string]any{
+		"game_id":          gameID,
+		"passed":           res.Passed,
+		"last_card_player": res.LastCardPlayer,
+		"points":           res.Points,
+		"next_leader":      res.NextLeader,
+		"reasons":          reasons,
+	})
+}
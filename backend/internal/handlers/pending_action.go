@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"fifteen-thirty-one-go/backend/internal/game/cribbage"
+	"fifteen-thirty-one-go/backend/internal/models"
+)
+
+// Pending action kinds.
+const (
+	PendingDiscard = "discard"
+	PendingPlay    = "play"
+	PendingGo      = "go"
+	PendingCount   = "count"
+	PendingReady   = "ready"
+	PendingWait    = "wait"
+)
+
+// PendingAction tells a player what the game is waiting on from them.
+type PendingAction struct {
+	Action  string   `json:"action"`
+	Message string   `json:"message"`
+	Cards   int      `json:"cards,omitempty"`   // discard: how many cards to throw
+	Missing []string `json:"missing,omitempty"` // count: final counts still owed ("hand", "crib")
+}
+
+// pendingActionFor derives the seat's next action from engine state; call it under the state
+// lock. Counting duties live in the move log, so a counting-stage result is refined by
+// refineCountingAction once the lock is released. Non-players and finished games get nil.
+func pendingActionFor(st *cribbage.State, pos int) *PendingAction {
+	if pos < 0 || pos >= st.Rules.MaxPlayers {
+		return nil
+	}
+	switch st.Stage {
+	case "discard":
+		if pos < len(st.DiscardCompleted) && st.DiscardCompleted[pos] {
+			return &PendingAction{Action: PendingWait, Message: "Waiting for the other players to discard"}
+		}
+		n := st.Rules.DiscardCount()
+		whose := "your opponent's crib"
+		switch {
+		case pos == st.DealerIndex:
+			whose = "your crib"
+		case st.Rules.MaxPlayers > 2:
+			whose = "the dealer's crib"
+		}
+		noun := "cards"
+		if n == 1 {
+			noun = "card"
+		}
+		return &PendingAction{Action: PendingDiscard, Cards: n, Message: fmt.Sprintf("Discard %d %s to %s", n, noun, whose)}
+	case "pegging":
+		if st.CurrentIndex != pos {
+			return &PendingAction{Action: PendingWait, Message: "Waiting for another player to play"}
+		}
+		if st.CanPlay(pos) {
+			return &PendingAction{Action: PendingPlay, Message: "It's your turn to play"}
+		}
+		return &PendingAction{Action: PendingGo, Message: "You have no playable card; say go"}
+	case "counting":
+		if pos < len(st.ReadyNextHand) && st.ReadyNextHand[pos] {
+			return &PendingAction{Action: PendingWait, Message: "Waiting for the other players to be ready"}
+		}
+		return &PendingAction{Action: PendingReady, Message: "Review the count and ready up for the next hand"}
+	}
+	return nil
+}
+
+// refineCountingAction turns a "ready" prompt into "count" for players who count manually and
+// still owe final counts this hand (see NextHandHandler's counts_required gate).
+func refineCountingAction(db *sql.DB, gameID, userID int64, pos, dealerIndex int, pending *PendingAction) {
+	prefs, err := models.GetUserPreferences(db, userID)
+	if err != nil {
+		log.Printf("refineCountingAction: GetUserPreferences failed: user_id=%d err=%v", userID, err)
+		return
+	}
+	if prefs.AutoCountMode != "off" {
+		return
+	}
+	missing, err := missingFinalCounts(db, gameID, userID, pos, dealerIndex)
+	if err != nil {
+		log.Printf("refineCountingAction: missingFinalCounts failed: game_id=%d user_id=%d err=%v", gameID, userID, err)
+		return
+	}
+	if len(missing) == 0 {
+		return
+	}
+	pending.Action = PendingCount
+	pending.Missing = nil
+	for _, mt := range missing {
+		if mt == "count_crib_final" {
+			pending.Missing = append(pending.Missing, "crib")
+		} else {
+			pending.Missing = append(pending.Missing, "hand")
+		}
+	}
+	switch len(pending.Missing) {
+	case 2:
+		pending.Message = "Submit your hand and crib counts"
+	default:
+		pending.Message = fmt.Sprintf("Submit your %s count", pending.Missing[0])
+	}
+}
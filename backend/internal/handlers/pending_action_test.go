@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"testing"
+
+	"fifteen-thirty-one-go/backend/internal/game/cribbage"
+)
+
+func TestPendingDiscardNamesWhoseCrib(t *testing.T) {
+	tests := []struct {
+		players, pos int
+		want         string
+	}{
+		{2, 0, "Discard 2 cards to your crib"},
+		{2, 1, "Discard 2 cards to your opponent's crib"},
+		{3, 0, "Discard 1 card to your crib"},
+		{3, 2, "Discard 1 card to the dealer's crib"},
+		{4, 3, "Discard 1 card to the dealer's crib"},
+	}
+	for _, tt := range tests {
+		st := cribbage.NewState(tt.players)
+		if err := st.Deal(); err != nil {
+			t.Fatalf("%d players: deal: %v", tt.players, err)
+		}
+		st.DealerIndex = 0
+		got := pendingActionFor(st, tt.pos)
+		if got == nil || got.Action != PendingDiscard || got.Message != tt.want {
+			t.Errorf("%d players, seat %d: pending %+v, want discard %q", tt.players, tt.pos, got, tt.want)
+		}
+	}
+}
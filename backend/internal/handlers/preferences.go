@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"log"
 	"net/http"
@@ -32,7 +33,15 @@ func GetPreferencesHandler(db *sql.DB) gin.HandlerFunc {
 }
 
 type putPreferencesRequest struct {
-	AutoCountMode string `json:"auto_count_mode"`
+	AutoCountMode *string `json:"auto_count_mode"`
+	// QuietHours sets the quiet-hours window; an explicit null clears it, omitting it leaves it unchanged.
+	QuietHours json.RawMessage `json:"quiet_hours"`
+	// PeggingReveal is "immediate" or "sequence_end".
+	PeggingReveal *string `json:"pegging_reveal"`
+	// CutHints opts in to discard hints (GET /games/:id/discard_hints).
+	CutHints *bool `json:"cut_hints"`
+	// SuggestVerbosity is "score", "summary" or "detailed" (see countSuggestion).
+	SuggestVerbosity *string `json:"suggest_verbosity"`
 }
 
 func PutPreferencesHandler(db *sql.DB) gin.HandlerFunc {
@@ -51,14 +60,46 @@ func PutPreferencesHandler(db *sql.DB) gin.HandlerFunc {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid json"})
 			return
 		}
-		prefs, err := models.SetUserAutoCountModeAndGetPreferencesTx(db, userID, req.AutoCountMode)
+		var quiet *models.QuietHours
+		clearQuiet := false
+		if len(req.QuietHours) > 0 {
+			if string(req.QuietHours) == "null" {
+				clearQuiet = true
+			} else {
+				quiet = &models.QuietHours{}
+				if err := json.Unmarshal(req.QuietHours, quiet); err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "invalid quiet hours"})
+					return
+				}
+				if quiet.Timezone == "" {
+					quiet.Timezone = "UTC"
+				}
+			}
+		}
+		if req.AutoCountMode == nil && quiet == nil && !clearQuiet && req.PeggingReveal == nil && req.CutHints == nil && req.SuggestVerbosity == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid mode"})
+			return
+		}
+		prefs, err := models.UpdateUserPreferencesTx(db, userID, req.AutoCountMode, quiet, clearQuiet, req.PeggingReveal, req.CutHints, req.SuggestVerbosity)
 		if err != nil {
 			if errors.Is(err, models.ErrInvalidMode) {
 				log.Printf("PutPreferencesHandler invalid mode: user_id=%d err=%v", userID, err)
 				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid mode"})
 				return
 			}
-			log.Printf("SetUserAutoCountModeAndGetPreferencesTx failed: user_id=%d err=%v", userID, err)
+			if errors.Is(err, models.ErrInvalidPeggingReveal) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid pegging_reveal (expected immediate or sequence_end)"})
+				return
+			}
+			if errors.Is(err, models.ErrInvalidSuggestVerbosity) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid suggest_verbosity (expected score, summary or detailed)"})
+				return
+			}
+			if errors.Is(err, models.ErrInvalidQuietHours) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid quiet hours (expected HH:MM start/end and an IANA timezone)"})
+				return
+			}
+			log.Printf("UpdateUserPreferencesTx failed: user_id=%d err=%v", userID, err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
 			return
 		}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"fifteen-thirty-one-go/backend/internal/models"
+	"fifteen-thirty-one-go/backend/internal/tracing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RematchHandler replays a finished game: a new lobby with the same seat count and rules, the
+// same humans seated directly (unlike ChallengeHandler, which invites them) and the same bots by
+// difficulty. The caller hosts. One rematch exists per game; a second request, e.g. from the
+// opponent clicking at the same moment, gets the lobby the first one built. Clients still in the
+// old lobby room are told via lobby:rematch_ready.
+func RematchHandler(db *sql.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, span := tracing.StartSpan(c.Request.Context(), "handlers.RematchHandler")
+		defer span.End()
+
+		gameID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || gameID <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid game id"})
+			return
+		}
+		userID, ok := userIDFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		g, err := models.GetGameByID(db, gameID)
+		if err != nil {
+			writeAPIError(c, err)
+			return
+		}
+		if g.Status != "finished" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "game not finished"})
+			return
+		}
+		prevLobby, err := models.GetLobbyByID(db, g.LobbyID)
+		if err != nil {
+			writeAPIError(c, err)
+			return
+		}
+		players, err := models.ListGamePlayersByGame(db, gameID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		sort.Slice(players, func(i, j int) bool { return players[i].Position < players[j].Position })
+
+		isPlayer := false
+		for _, p := range players {
+			if p.UserID == userID {
+				isPlayer = !(p.IsBot && !p.Resigned && !p.BotTakeover)
+			}
+		}
+		if !isPlayer {
+			c.JSON(http.StatusForbidden, gin.H{"error": "not a player"})
+			return
+		}
+
+		claimed, existing, err := models.ClaimRematch(db, gameID, userID)
+		if err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				c.JSON(http.StatusConflict, gin.H{"error": "rematch in progress", "code": "rematch_in_progress"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		if !claimed {
+			if existing.LobbyID == nil || existing.GameID == nil {
+				c.JSON(http.StatusConflict, gin.H{"error": "rematch in progress", "code": "rematch_in_progress"})
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{"lobby_id": *existing.LobbyID, "game_id": *existing.GameID, "existing": true})
+			return
+		}
+
+		l, newGameID, err := buildRematch(db, gameID, prevLobby, userID, players)
+		if err != nil {
+			log.Printf("RematchHandler: game_id=%d err=%v", gameID, err)
+			if err := models.ReleaseRematch(db, gameID); err != nil {
+				log.Printf("RematchHandler: %v", err)
+			}
+			if errors.Is(err, errServerAtCapacity) {
+				writeAtCapacity(c)
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "unable to create rematch"})
+			return
+		}
+		if err := models.CompleteRematch(db, gameID, l.ID, newGameID); err != nil {
+			log.Printf("RematchHandler: %v", err)
+		}
+
+		if hub, ok := getHubProvider(); ok && hub != nil {
+			hub.Broadcast(fmt.Sprintf("lobby:%d", prevLobby.ID), "lobby:rematch_ready", gin.H{
+				"source_game_id": gameID,
+				"lobby_id":       l.ID,
+				"game_id":        newGameID,
+			})
+		}
+		broadcastGameUpdate(db, newGameID)
+		c.JSON(http.StatusCreated, gin.H{"lobby_id": l.ID, "game_id": newGameID})
+	}
+}
+
+// buildRematch creates the rematch lobby hosted by hostID and reseats the other previous
+// players in their old seat order: humans join directly, original bot seats get a fresh bot of
+// the same difficulty. Everything happens in one transaction, so a failed seat leaves no
+// half-filled lobby behind.
+func buildRematch(db *sql.DB, gameID int64, prevLobby *models.Lobby, hostID int64, players []models.GamePlayer) (*models.Lobby, int64, error) {
+	l, newGame, err := createLobbyWithGameThen(db, rematchLobbyName(prevLobby), hostID, previousGameRules(db, gameID, prevLobby.MaxPlayers), 0, previousAllowedBots(db, prevLobby.ID), 0,
+		func(tx *sql.Tx, lobbyID, newGameID int64) error {
+			if err := models.ContinueLobbySeriesTx(tx, lobbyID, prevLobby.ID); err != nil {
+				return err
+			}
+			for _, p := range players {
+				if p.UserID == hostID {
+					continue
+				}
+				if p.IsBot && !p.Resigned && !p.BotTakeover {
+					if _, _, _, _, err := addBotToLobbyTx(tx, lobbyID, prevLobby.MaxPlayers, string(seatBotDifficulty(p))); err != nil {
+						return fmt.Errorf("add bot (lobby_id=%d): %w", lobbyID, err)
+					}
+					continue
+				}
+				if _, err := seatJoiningPlayerTx(tx, lobbyID, newGameID, p.UserID); err != nil {
+					return fmt.Errorf("seat player (lobby_id=%d user_id=%d): %w", lobbyID, p.UserID, err)
+				}
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, 0, fmt.Errorf("createLobbyWithGameThen: %w", err)
+	}
+	startLobbyIfFull(db, l.ID)
+	return l, newGame.ID, nil
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"fifteen-thirty-one-go/backend/internal/models"
+)
+
+func TestRematchSeatsEveryoneAndDeals(t *testing.T) {
+	db := newTestDB(t)
+	gameID, users := newTestGame(t, db, "alice", "bob")
+	finishGame(t, gameID)
+	if err := maybeFinalizeGame(context.Background(), db, gameID); err != nil {
+		t.Fatalf("finalize: %v", err)
+	}
+
+	var out struct {
+		LobbyID int64 `json:"lobby_id"`
+		GameID  int64 `json:"game_id"`
+	}
+	path := fmt.Sprintf("/games/%d/rematch", gameID)
+	if code := doRequest(t, RematchHandler(db), http.MethodPost, "/games/:id/rematch", path, users[1], nil, &out); code != http.StatusCreated {
+		t.Fatalf("rematch: status %d, want 201", code)
+	}
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM game_players WHERE game_id = ?`, out.GameID); n != 2 {
+		t.Errorf("rematch seats %d players, want 2", n)
+	}
+	st, unlock, ok := defaultGameManager.GetLocked(out.GameID)
+	if !ok {
+		t.Fatalf("rematch game %d has no engine state", out.GameID)
+	}
+	stage := st.Stage
+	unlock()
+	if stage != "discard" {
+		t.Errorf("rematch in stage %q, want a full lobby dealt into discard", stage)
+	}
+}
+
+func TestRematchLeavesNothingBehindWhenASeatFails(t *testing.T) {
+	db := newTestDB(t)
+	gameID, users := newTestGame(t, db, "alice", "bob")
+	g, err := models.GetGameByID(db, gameID)
+	if err != nil {
+		t.Fatalf("get game: %v", err)
+	}
+	prevLobby, err := models.GetLobbyByID(db, g.LobbyID)
+	if err != nil {
+		t.Fatalf("get lobby: %v", err)
+	}
+	lobbies := queryInt(t, db, `SELECT COUNT(*) FROM lobbies`)
+
+	// The second seat belongs to a user that no longer exists, so seating it fails after the
+	// lobby and the host's seat were created.
+	players := []models.GamePlayer{{UserID: users[0], Position: 0}, {UserID: users[1] + 100, Position: 1}}
+	if _, _, err := buildRematch(db, gameID, prevLobby, users[0], players); err == nil {
+		t.Fatal("rematch with an unseatable player succeeded")
+	}
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM lobbies`); n != lobbies {
+		t.Errorf("%d lobbies after the failed rematch, want %d", n, lobbies)
+	}
+}
@@ -0,0 +1,556 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"fifteen-thirty-one-go/backend/internal/game/common"
+	"fifteen-thirty-one-go/backend/internal/game/cribbage"
+	"fifteen-thirty-one-go/backend/internal/models"
+	"fifteen-thirty-one-go/backend/internal/tracing"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// replayMaxMoves bounds how many recorded moves one replay reads; real games stay far below.
+	replayMaxMoves = 5000
+	// replayDefaultHands and replayMaxHands bound the hands per response (?hands=N), and
+	// replayMaxSteps the steps: a page ends early once it holds that many, after its first hand.
+	replayDefaultHands = 5
+	replayMaxHands     = 10
+	replayMaxSteps     = 400
+)
+
+// GameReplay is one page of a finished game's move-by-move replay.
+type GameReplay struct {
+	GameID     int64        `json:"game_id"`
+	Seats      []ReplaySeat `json:"seats"`
+	TotalHands int          `json:"total_hands"`
+	TotalMoves int          `json:"total_moves"`
+	Hands      []ReplayHand `json:"hands"`
+	// NextHand is the from_hand of the next page; unset on the last page. Pages selected by
+	// move range report NextMove (the next page's from) instead.
+	NextHand *int `json:"next_hand,omitempty"`
+	NextMove *int `json:"next_from,omitempty"`
+	// Truncated is set when the game had more moves than a replay reads.
+	Truncated bool `json:"truncated,omitempty"`
+	// Archived is set when the game's raw moves were pruned: its hands then carry their deal and
+	// result but no steps.
+	Archived bool `json:"archived,omitempty"`
+}
+
+// ReplaySeat maps a seat to its player.
+type ReplaySeat struct {
+	Seat     int    `json:"seat"`
+	UserID   int64  `json:"user_id"`
+	Username string `json:"username"`
+}
+
+// ReplayHand is one hand of a replay: its deal, as far as it is known, and a state per move.
+type ReplayHand struct {
+	Hand        int             `json:"hand"` // 1-based
+	DealerIndex int             `json:"dealer_index"`
+	Kept        [][]common.Card `json:"kept"`
+	Crib        []common.Card   `json:"crib"`
+	Cut         *common.Card    `json:"cut,omitempty"`
+	// Steps holds a full state per move; in diff mode it is empty and Deltas holds the moves.
+	Steps  []ReplayStep  `json:"steps"`
+	Deltas []ReplayDelta `json:"deltas,omitempty"`
+	// Incomplete is set when the hand could not be replayed to its end (Error says why); its
+	// steps stop at the move that failed.
+	Incomplete bool   `json:"incomplete,omitempty"`
+	Error      string `json:"error,omitempty"`
+	// ScoresAfter is the board once the hand was counted; only archived replays report it.
+	ScoresAfter []int `json:"scores_after,omitempty"`
+}
+
+// ReplayMove is a recorded move as a replay step reports it.
+type ReplayMove struct {
+	Move     int    `json:"move"` // 1-based position in the whole replay; what ?from/?to select
+	MoveID   int64  `json:"move_id"`
+	MoveType string `json:"move_type"`
+	Seat     int    `json:"seat"`
+	Card     string `json:"card,omitempty"`
+	// Points is what the move scored (or, for counts, what the hand was worth); Claimed is a
+	// count's claim, replaced by the value of a later correction when Corrected is set.
+	Points    *int64 `json:"points,omitempty"`
+	Claimed   *int64 `json:"claimed,omitempty"`
+	Corrected bool   `json:"corrected,omitempty"`
+}
+
+// ReplayStep is a recorded move and the game state right after it.
+type ReplayStep struct {
+	ReplayMove
+
+	Stage        string          `json:"stage"`
+	Scores       []int           `json:"scores"`
+	CurrentIndex int             `json:"current_index"`
+	PeggingTotal int             `json:"pegging_total"`
+	PeggingSeq   []common.Card   `json:"pegging_seq"`
+	Hands        [][]common.Card `json:"hands"` // cards still held
+}
+
+// ReplayDelta is a ReplayStep in diff mode (?diff=true): the move, plus only the state fields
+// that differ from the previous step of the same hand in the page. The first step of each hand
+// in a page carries every field. Hands lists only the seats whose cards changed.
+type ReplayDelta struct {
+	ReplayMove
+
+	Stage        *string               `json:"stage,omitempty"`
+	Scores       []int                 `json:"scores,omitempty"`
+	CurrentIndex *int                  `json:"current_index,omitempty"`
+	PeggingTotal *int                  `json:"pegging_total,omitempty"`
+	PeggingSeq   *[]common.Card        `json:"pegging_seq,omitempty"`
+	Hands        map[int][]common.Card `json:"hands,omitempty"`
+}
+
+// GameReplayHandler replays a finished game's recorded moves against a fresh engine, hand by
+// hand, for participants and spectators. Pages are selected by hand number, ?from_hand=N
+// (1-based) and ?hands=N, or by move range, ?from=N&to=M (1-based, inclusive, at most
+// replayMaxSteps moves). ?diff=true sends each step as a ReplayDelta instead of a full state.
+// Games whose raw moves were archived get a summary-only replay: each counted hand's deal and
+// result, without steps.
+func GameReplayHandler(db *sql.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, span := tracing.StartSpan(c.Request.Context(), "handlers.GameReplayHandler")
+		defer span.End()
+
+		gameID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || gameID <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid game id"})
+			return
+		}
+		userID, ok := userIDFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		fromHand, err := strconv.Atoi(c.DefaultQuery("from_hand", "1"))
+		if err != nil || fromHand < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from_hand"})
+			return
+		}
+		pageHands, err := strconv.Atoi(c.DefaultQuery("hands", strconv.Itoa(replayDefaultHands)))
+		if err != nil || pageHands < 1 || pageHands > replayMaxHands {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("hands must be between 1 and %d", replayMaxHands)})
+			return
+		}
+		byMove := c.Query("from") != "" || c.Query("to") != ""
+		if byMove && (c.Query("from_hand") != "" || c.Query("hands") != "") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "page by from/to or by from_hand/hands, not both"})
+			return
+		}
+		fromMove, err := strconv.Atoi(c.DefaultQuery("from", "1"))
+		if err != nil || fromMove < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from"})
+			return
+		}
+		toMove, err := strconv.Atoi(c.DefaultQuery("to", strconv.Itoa(fromMove+replayMaxSteps-1)))
+		if err != nil || toMove < fromMove || toMove-fromMove >= replayMaxSteps {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("to must be between from and from+%d", replayMaxSteps-1)})
+			return
+		}
+		diff, err := strconv.ParseBool(c.DefaultQuery("diff", "false"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "diff must be true or false"})
+			return
+		}
+		allowed, err := canViewGame(db, userID, gameID)
+		if err != nil {
+			log.Printf("GameReplayHandler: authorization check failed: game_id=%d user_id=%d err=%v", gameID, userID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		if !allowed {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+		g, err := models.GetGameByID(db, gameID)
+		if err != nil {
+			writeAPIError(c, err)
+			return
+		}
+		// Replays reveal every hand, so they wait until the game is over.
+		if g.Status != "finished" {
+			c.JSON(http.StatusConflict, gin.H{"error": "game not finished", "code": "replay_game_in_progress"})
+			return
+		}
+		archived, err := models.GameMovesArchived(db, gameID)
+		if err != nil {
+			writeAPIError(c, err)
+			return
+		}
+
+		final, err := replayFinalState(db, gameID)
+		if err != nil {
+			writeAPIError(c, err)
+			return
+		}
+		players, err := models.ListGamePlayersByGameContext(ctx, db, gameID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		var hands []ReplayHand
+		truncated := false
+		if archived {
+			hands = archivedReplayHands(final)
+		} else {
+			moves, err := models.ListMovesInOrder(ctx, db, gameID, replayMaxMoves)
+			if err != nil {
+				log.Printf("GameReplayHandler: ListMovesInOrder failed: game_id=%d err=%v", gameID, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+				return
+			}
+			hands = replayGame(final, players, moves)
+			truncated = len(moves) == replayMaxMoves
+		}
+		resp := GameReplay{
+			GameID:     gameID,
+			Seats:      make([]ReplaySeat, 0, len(players)),
+			TotalHands: len(hands),
+			Hands:      []ReplayHand{},
+			Truncated:  truncated,
+			Archived:   archived,
+		}
+		for _, h := range hands {
+			resp.TotalMoves += len(h.Steps)
+		}
+		for _, p := range players {
+			resp.Seats = append(resp.Seats, ReplaySeat{Seat: int(p.Position), UserID: p.UserID, Username: p.Username})
+		}
+		if byMove {
+			resp.Hands = replayMoveRange(hands, fromMove, toMove)
+			if toMove < resp.TotalMoves {
+				next := toMove + 1
+				resp.NextMove = &next
+			}
+		} else {
+			steps := 0
+			for i := fromHand - 1; i < len(hands) && len(resp.Hands) < pageHands; i++ {
+				if len(resp.Hands) > 0 && steps+len(hands[i].Steps) > replayMaxSteps {
+					break
+				}
+				resp.Hands = append(resp.Hands, hands[i])
+				steps += len(hands[i].Steps)
+			}
+			if next := fromHand + len(resp.Hands); next <= len(hands) {
+				resp.NextHand = &next
+			}
+		}
+		if diff {
+			for i := range resp.Hands {
+				resp.Hands[i].Deltas = replayDeltas(resp.Hands[i].Steps)
+				resp.Hands[i].Steps = []ReplayStep{}
+			}
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// replayMoveRange returns the hands holding moves from..to (1-based, inclusive), each cut down
+// to the steps in that range.
+func replayMoveRange(hands []ReplayHand, from, to int) []ReplayHand {
+	out := []ReplayHand{}
+	for _, h := range hands {
+		if len(h.Steps) == 0 || h.Steps[len(h.Steps)-1].Move < from || h.Steps[0].Move > to {
+			continue
+		}
+		steps := []ReplayStep{}
+		for _, st := range h.Steps {
+			if st.Move >= from && st.Move <= to {
+				steps = append(steps, st)
+			}
+		}
+		h.Steps = steps
+		out = append(out, h)
+	}
+	return out
+}
+
+// replayDeltas converts one hand's steps to diff mode: each delta keeps only what changed since
+// the step before it, and the first keeps everything.
+func replayDeltas(steps []ReplayStep) []ReplayDelta {
+	out := make([]ReplayDelta, 0, len(steps))
+	var prev *ReplayStep
+	for i := range steps {
+		cur := &steps[i]
+		d := ReplayDelta{ReplayMove: cur.ReplayMove}
+		if prev == nil || prev.Stage != cur.Stage {
+			d.Stage = &cur.Stage
+		}
+		if prev == nil || !slices.Equal(prev.Scores, cur.Scores) {
+			d.Scores = cur.Scores
+		}
+		if prev == nil || prev.CurrentIndex != cur.CurrentIndex {
+			d.CurrentIndex = &cur.CurrentIndex
+		}
+		if prev == nil || prev.PeggingTotal != cur.PeggingTotal {
+			d.PeggingTotal = &cur.PeggingTotal
+		}
+		if prev == nil || !slices.Equal(prev.PeggingSeq, cur.PeggingSeq) {
+			d.PeggingSeq = &cur.PeggingSeq
+		}
+		for seat, held := range cur.Hands {
+			if prev == nil || seat >= len(prev.Hands) || !slices.Equal(prev.Hands[seat], held) {
+				if d.Hands == nil {
+					d.Hands = map[int][]common.Card{}
+				}
+				d.Hands[seat] = held
+			}
+		}
+		out = append(out, d)
+		prev = cur
+	}
+	return out
+}
+
+// replayFinalState returns a copy of the game's engine state, from memory when loaded.
+func replayFinalState(db *sql.DB, gameID int64) (*cribbage.State, error) {
+	if st, unlock, ok := defaultGameManager.GetLocked(gameID); ok {
+		out := cloneStateDeep(st)
+		unlock()
+		return &out, nil
+	}
+	raw, _, ok, err := models.GetGameStateJSON(db, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, models.ErrGameStateMissing
+	}
+	var st cribbage.State
+	if err := json.Unmarshal([]byte(raw), &st); err != nil {
+		return nil, fmt.Errorf("replay: decode state (game_id=%d): %w", gameID, err)
+	}
+	return &st, nil
+}
+
+// archivedReplayHands summarizes each counted hand from the round history, for games whose moves
+// are gone: the deal and the board after the count, with no steps.
+func archivedReplayHands(final *cribbage.State) []ReplayHand {
+	out := make([]ReplayHand, 0, len(final.History))
+	for i := range final.History {
+		deal, _ := handDeal(final, i+1, false)
+		out = append(out, ReplayHand{
+			Hand:        i + 1,
+			DealerIndex: deal.dealer,
+			Kept:        deal.kept,
+			Crib:        deal.crib,
+			Cut:         deal.cut,
+			Steps:       []ReplayStep{},
+			ScoresAfter: final.History[i].ScoresAfter,
+		})
+	}
+	return out
+}
+
+// replayDeal is what a replay needs to know about a hand's deal.
+type replayDeal struct {
+	dealer int
+	kept   [][]common.Card
+	crib   []common.Card
+	cut    *common.Card
+}
+
+// handDeal returns the deal of hand (1-based): from the round history for counted hands, or
+// from the final state for a hand the game ended in.
+func handDeal(final *cribbage.State, hand int, last bool) (replayDeal, bool) {
+	if hand <= len(final.History) {
+		rs := final.History[hand-1]
+		d := replayDeal{dealer: rs.DealerIndex, kept: make([][]common.Card, final.Rules.MaxPlayers), crib: rs.CribCards, cut: rs.Cut}
+		for seat, cards := range rs.Kept {
+			if seat >= 0 && seat < len(d.kept) {
+				d.kept[seat] = cards
+			}
+		}
+		return d, true
+	}
+	if !last {
+		return replayDeal{}, false
+	}
+	d := replayDeal{dealer: final.DealerIndex, kept: make([][]common.Card, final.Rules.MaxPlayers), crib: final.Crib, cut: final.Cut}
+	for seat := range d.kept {
+		// Before the cut KeptHands is empty; Hands then holds what each seat kept (or was
+		// dealt, if it never discarded).
+		switch {
+		case seat < len(final.KeptHands) && len(final.KeptHands[seat]) > 0:
+			d.kept[seat] = final.KeptHands[seat]
+		case seat < len(final.Hands):
+			d.kept[seat] = final.Hands[seat]
+		}
+	}
+	return d, true
+}
+
+// replayGame splits moves into hands (each starting at its first discard) and replays them.
+func replayGame(final *cribbage.State, players []models.GamePlayer, moves []models.GameMove) []ReplayHand {
+	seatOf := make(map[int64]int, len(players))
+	for _, p := range players {
+		seatOf[p.UserID] = int(p.Position)
+	}
+	byID := make(map[int64]models.GameMove, len(moves))
+	for _, m := range moves {
+		byID[m.ID] = m
+	}
+	corrections := correctedClaims(moves)
+
+	var byHand [][]models.GameMove
+	var st *cribbage.State
+	discarded := map[int]bool{}
+	for _, m := range moves {
+		if strings.HasSuffix(m.MoveType, "_correct") {
+			continue // applied to the move it corrects
+		}
+		if m.MoveType == "discard" {
+			if seat := seatOf[m.PlayerID]; len(byHand) == 0 || discarded[seat] {
+				byHand = append(byHand, nil)
+				discarded = map[int]bool{}
+			}
+			discarded[seatOf[m.PlayerID]] = true
+		}
+		if len(byHand) == 0 {
+			continue
+		}
+		byHand[len(byHand)-1] = append(byHand[len(byHand)-1], m)
+	}
+
+	scores := make([]int, final.Rules.MaxPlayers)
+	out := make([]ReplayHand, 0, len(byHand))
+	moveNo := 0
+	for i, handMoves := range byHand {
+		hand := ReplayHand{Hand: i + 1, Steps: []ReplayStep{}}
+		deal, ok := handDeal(final, i+1, i == len(byHand)-1)
+		if !ok {
+			hand.Incomplete = true
+			hand.Error = "deal not recorded"
+			out = append(out, hand)
+			continue
+		}
+		hand.DealerIndex, hand.Kept, hand.Crib, hand.Cut = deal.dealer, deal.kept, deal.crib, deal.cut
+		st = cribbage.ReplayHand(final.Rules, deal.dealer, scores, deal.kept)
+		for _, m := range handMoves {
+			seat, seated := seatOf[m.PlayerID]
+			if !seated {
+				seat = -1
+			}
+			if err := replayMove(st, m, seat, deal, byID, seatOf); err != nil {
+				hand.Incomplete = true
+				hand.Error = fmt.Sprintf("move %d (%s): %v", m.ID, m.MoveType, err)
+				break
+			}
+			moveNo++
+			step := ReplayStep{
+				ReplayMove: ReplayMove{
+					Move:     moveNo,
+					MoveID:   m.ID,
+					MoveType: m.MoveType,
+					Seat:     seat,
+					Points:   m.ScoreVerified,
+					Claimed:  m.ScoreClaimed,
+				},
+				Stage:        st.Stage,
+				Scores:       append([]int(nil), st.Scores...),
+				CurrentIndex: st.CurrentIndex,
+				PeggingTotal: st.PeggingTotal,
+				PeggingSeq:   append([]common.Card{}, st.PeggingSeq...),
+				Hands:        make([][]common.Card, len(st.Hands)),
+			}
+			if m.CardPlayed != nil {
+				step.Card = *m.CardPlayed
+			}
+			if claim, ok := corrections[m.ID]; ok {
+				step.Claimed, step.Corrected = &claim, true
+			}
+			for h := range st.Hands {
+				step.Hands[h] = append([]common.Card{}, st.Hands[h]...)
+			}
+			hand.Steps = append(hand.Steps, step)
+		}
+		scores = append([]int(nil), st.Scores...)
+		if hand.Incomplete && i < len(final.History) && len(final.History[i].ScoresAfter) == len(scores) {
+			// Pick the next hand up from the recorded result rather than a half-replayed one.
+			scores = append([]int(nil), final.History[i].ScoresAfter...)
+		}
+		out = append(out, hand)
+	}
+	return out
+}
+
+// replayMove applies one recorded move to the replay state. Counts only record claims, so they
+// leave the board alone: the engine scores hands itself when pegging ends.
+func replayMove(st *cribbage.State, m models.GameMove, seat int, deal replayDeal, byID map[int64]models.GameMove, seatOf map[int64]int) error {
+	switch m.MoveType {
+	case "discard":
+		return st.ReplayDiscard(seat, deal.crib, deal.cut)
+	case "heels":
+		if m.ScoreVerified != nil {
+			st.ReplayHeels(int(*m.ScoreVerified))
+		}
+		return nil
+	case "play_card":
+		if m.CardPlayed == nil {
+			return models.ErrInvalidCard
+		}
+		card, err := common.ParseCard(*m.CardPlayed)
+		if err != nil {
+			return err
+		}
+		_, _, err = st.PlayPeggingCard(seat, card)
+		return err
+	case "go":
+		_, _, err := st.GoWithResolution(seat)
+		return err
+	case "muggins":
+		if m.RefMoveID == nil || m.ScoreVerified == nil {
+			return errors.New("muggins call without its count")
+		}
+		ref, ok := byID[*m.RefMoveID]
+		if !ok {
+			return errors.New("muggins call without its count")
+		}
+		claimant, ok := seatOf[ref.PlayerID]
+		if !ok {
+			return models.ErrInvalidPlayer
+		}
+		_, err := st.Muggins(seat, claimant, int(*m.ScoreVerified))
+		return err
+	default:
+		return nil
+	}
+}
+
+// correctedClaims maps each corrected move to the claim of the correction that replaced it.
+// Corrections name the move they correct; older ones did not, and are matched to the earliest
+// unmatched corrected move of their type recorded before them.
+func correctedClaims(moves []models.GameMove) map[int64]int64 {
+	out := map[int64]int64{}
+	for _, m := range moves {
+		base, ok := strings.CutSuffix(m.MoveType, "_correct")
+		if !ok || m.ScoreClaimed == nil {
+			continue
+		}
+		if m.RefMoveID != nil {
+			out[*m.RefMoveID] = *m.ScoreClaimed
+			continue
+		}
+		for _, orig := range moves {
+			if orig.ID >= m.ID {
+				break
+			}
+			if _, taken := out[orig.ID]; orig.IsCorrected && orig.MoveType == base && !taken {
+				out[orig.ID] = *m.ScoreClaimed
+				break
+			}
+		}
+	}
+	return out
+}
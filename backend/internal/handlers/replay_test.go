@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+
+	"fifteen-thirty-one-go/backend/internal/game/common"
+	"fifteen-thirty-one-go/backend/internal/game/cribbage"
+)
+
+func TestReplayOfArchivedGameIsSummaryOnly(t *testing.T) {
+	db := newTestDB(t)
+	gameID, users := newTestGame(t, db, "alice", "bob")
+
+	cut := common.Card{Rank: 5, Suit: common.Hearts}
+	history := []cribbage.RoundSummary{
+		{Round: 1, DealerIndex: 0, Cut: &cut, ScoresBefore: []int{0, 0}, ScoresAfter: []int{8, 12}},
+		{Round: 2, DealerIndex: 1, Cut: &cut, ScoresBefore: []int{8, 12}, ScoresAfter: []int{20, 15}},
+	}
+	st, unlock, ok := defaultGameManager.GetLocked(gameID)
+	if !ok {
+		t.Fatalf("game %d has no engine state", gameID)
+	}
+	st.History = history
+	st.Scores = []int{121, 90}
+	st.Stage = "finished"
+	unlock()
+	if _, err := db.Exec(`UPDATE games SET status = 'finished', moves_archived_at = CURRENT_TIMESTAMP WHERE id = ?`, gameID); err != nil {
+		t.Fatalf("archive game: %v", err)
+	}
+
+	var resp GameReplay
+	path := fmt.Sprintf("/games/%d/replay", gameID)
+	if code := doRequest(t, GameReplayHandler(db), http.MethodGet, "/games/:id/replay", path, users[0], nil, &resp); code != http.StatusOK {
+		t.Fatalf("replay of an archived game: status %d, want 200", code)
+	}
+	if !resp.Archived || resp.TotalMoves != 0 || resp.TotalHands != 2 || len(resp.Hands) != 2 {
+		t.Fatalf("archived %t, %d moves, %d hands (%d in page), want archived with 2 hands and no moves",
+			resp.Archived, resp.TotalMoves, resp.TotalHands, len(resp.Hands))
+	}
+	for i, h := range resp.Hands {
+		want := history[i]
+		if h.Hand != i+1 || h.DealerIndex != want.DealerIndex || len(h.Steps) != 0 {
+			t.Errorf("hand %d: number %d dealer %d with %d steps, want dealer %d and no steps", i+1, h.Hand, h.DealerIndex, len(h.Steps), want.DealerIndex)
+		}
+		if !slices.Equal(h.ScoresAfter, want.ScoresAfter) {
+			t.Errorf("hand %d: scores after %v, want %v", i+1, h.ScoresAfter, want.ScoresAfter)
+		}
+	}
+}
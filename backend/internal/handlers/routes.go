@@ -22,20 +22,34 @@ func RegisterLobbyRoutes(rg *gin.RouterGroup, db *sql.DB) {
 	rg.POST("/lobbies", CreateLobbyHandler(db))
 	rg.POST("/lobbies/:id/join", JoinLobbyHandler(db))
 	rg.POST("/lobbies/:id/add_bot", AddBotToLobbyHandler(db))
+	rg.POST("/lobbies/:id/start", StartLobbyHandler(db))
+
+	// Invitations (e.g. rematch challenges)
+	rg.GET("/me/invitations", ListInvitationsHandler(db))
+	rg.POST("/invitations/:token/accept", RespondInvitationHandler(db, true))
+	rg.POST("/invitations/:token/decline", RespondInvitationHandler(db, false))
 
 	// Lobby chat (Yahoo Games inspired)
 	rg.GET("/lobbies/:id/chat", GetLobbyChatHistory(db))
 	rg.POST("/lobbies/:id/chat", SendLobbyChatMessage(db, getHubProvider))
+	rg.GET("/lobbies/:id/chat/pinned", GetPinnedLobbyChatMessages(db))
+	rg.POST("/lobbies/:id/chat/:messageId/pin", PinLobbyChatMessage(db, getHubProvider, true))
+	rg.DELETE("/lobbies/:id/chat/:messageId/pin", PinLobbyChatMessage(db, getHubProvider, false))
 
 	// Spectator mode
 	rg.POST("/lobbies/:id/spectate", JoinAsSpectator(db, getHubProvider))
 	rg.DELETE("/lobbies/:id/spectate", LeaveAsSpectator(db, getHubProvider))
 	rg.GET("/lobbies/:id/spectators", GetSpectators(db))
+	rg.POST("/lobbies/:id/claim-seat", ClaimSeat(db, getHubProvider))
+	rg.GET("/spectate/available", ListSpectatableGames(db))
 
 	// User presence
 	rg.PUT("/users/presence", UpdatePresence(db, getHubProvider))
 	rg.POST("/users/presence/heartbeat", HeartbeatPresence(db))
 	rg.GET("/users/:id/presence", GetPresence(db))
+
+	// Account
+	rg.PUT("/users/username", ChangeUsernameHandler(db))
 }
 
 // getHubProvider returns the current websocket hub and a boolean indicating whether a hub provider
@@ -53,14 +67,33 @@ func RegisterGameRoutes(rg *gin.RouterGroup, db *sql.DB) {
 	rg.GET("/me/preferences", GetPreferencesHandler(db))
 	rg.PUT("/me/preferences", PutPreferencesHandler(db))
 
+	rg.GET("/rules/preview", RulesPreviewHandler())
 	rg.GET("/games/:id", GetGameHandler(db))
+	rg.GET("/games/:id/hand", PlayerHandHandler(db))
 	rg.GET("/games/:id/moves", GameMovesHandler(db))
+	rg.GET("/games/:id/scorecard", ScorecardHandler(db))
+	rg.GET("/games/:id/rules", GameRulesHandler(db))
+	rg.GET("/games/:id/hands/:handIndex/share", HandShareHandler(db))
+	rg.GET("/games/:id/discard_hints", DiscardHintsHandler(db))
+	rg.POST("/games/:id/whatif-cut", WhatIfCutHandler(db))
+	rg.GET("/games/:id/events", GameEventsHandler(db))
+	rg.GET("/games/:id/replay", GameReplayHandler(db))
+	rg.GET("/games/:id/spectate", SpectateGameHandler(db))
 	rg.POST("/games/:id/move", MoveHandler(db))
 	rg.POST("/games/:id/quit", QuitGameHandler(db))
+	rg.POST("/games/:id/resign", ResignGameHandler(db))
+	rg.POST("/games/:id/challenge", ChallengeHandler(db))
+	rg.POST("/games/:id/rematch", RematchHandler(db))
 	rg.POST("/games/:id/next_hand", NextHandHandler(db))
 	rg.POST("/games/:id/count", CountHandler(db))
 	rg.POST("/games/:id/correct", CorrectHandler(db))
+	rg.POST("/games/:id/muggins", MugginsHandler(db))
+	rg.POST("/games/:id/undo", UndoHandler(db))
+	rg.GET("/matches/:id", GetMatchHandler(db))
 	rg.GET("/scoreboard", ScoreboardHandler(db))
 	rg.GET("/scoreboard/:userId", UserStatsHandler(db))
+	rg.GET("/users/:id/best-hands", BestHandsHandler(db))
+	rg.POST("/users/batch", UsersBatchHandler(db))
 	rg.GET("/leaderboard", LeaderboardHandler(db))
+	rg.GET("/stats", GlobalStatsHandler(db))
 }
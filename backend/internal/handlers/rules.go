@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"fifteen-thirty-one-go/backend/internal/game/cribbage"
+	"fifteen-thirty-one-go/backend/internal/models"
+	"fifteen-thirty-one-go/backend/internal/tracing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GameRules is the effective rule set of a game, with defaults resolved so clients never have
+// to know what a zero value in the persisted Rules means.
+type GameRules struct {
+	GameID         int64  `json:"game_id,omitempty"` // unset in previews
+	Variant        string `json:"variant"`           // standard or five_card
+	MaxPlayers     int    `json:"max_players"`
+	TargetScore    int    `json:"target_score"`
+	LastCardPoints int    `json:"last_card_points"`
+	HandSize       int    `json:"hand_size"`
+	DiscardCount   int    `json:"discard_count"`
+	CribSize       int    `json:"crib_size"`
+	CutTiePolicy   string `json:"cut_tie_policy"`
+	// CribFlushRequiresFive is false in games where the crib may flush on its own four cards.
+	CribFlushRequiresFive bool `json:"crib_flush_requires_five"`
+	Muggins               bool `json:"muggins"`
+	// TurnTimeoutSeconds is the time allowed per discard or pegging turn; 0 means untimed.
+	TurnTimeoutSeconds int `json:"turn_timeout_seconds"`
+	// RevealHands is true in casual games that show every seat's discards during counting.
+	RevealHands bool `json:"reveal_hands"`
+	// PeggingFlush is true where same-suit cards in a row score during pegging.
+	PeggingFlush bool `json:"pegging_flush"`
+}
+
+func gameRulesView(gameID int64, r cribbage.Rules) GameRules {
+	return GameRules{
+		GameID:                gameID,
+		Variant:               r.VariantName(),
+		MaxPlayers:            r.MaxPlayers,
+		TargetScore:           r.WinningScore(),
+		LastCardPoints:        r.LastCardValue(),
+		HandSize:              r.HandSize(),
+		DiscardCount:          r.DiscardCount(),
+		CribSize:              r.CribSize(),
+		CutTiePolicy:          r.CutTieRule(),
+		CribFlushRequiresFive: r.CribFlushRequiresFive(),
+		Muggins:               r.Muggins,
+		TurnTimeoutSeconds:    r.TurnTimeoutSeconds,
+		RevealHands:           r.RevealHands,
+		PeggingFlush:          r.PeggingFlush,
+	}
+}
+
+// GameRulesHandler returns the game's effective rules to participants and spectators.
+func GameRulesHandler(db *sql.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, span := tracing.StartSpan(c.Request.Context(), "handlers.GameRulesHandler")
+		defer span.End()
+
+		gameID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || gameID <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid game id"})
+			return
+		}
+		userID, ok := userIDFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		allowed, err := canViewGame(db, userID, gameID)
+		if err != nil {
+			log.Printf("GameRulesHandler: authorization check failed: game_id=%d user_id=%d err=%v", gameID, userID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		if !allowed {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+
+		// Rules are fixed at lobby creation, so the persisted state is authoritative even when a
+		// write-behind flush is pending, and it exists before every seat is filled.
+		raw, _, ok, err := models.GetGameStateJSON(db, gameID)
+		if err != nil {
+			log.Printf("GameRulesHandler: GetGameStateJSON failed: game_id=%d err=%v", gameID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		if !ok {
+			c.JSON(http.StatusConflict, gin.H{"error": "game state unavailable; recreate lobby"})
+			return
+		}
+		var persisted struct {
+			Rules cribbage.Rules `json:"rules"`
+		}
+		if err := json.Unmarshal([]byte(raw), &persisted); err != nil {
+			log.Printf("GameRulesHandler: decode state failed: game_id=%d err=%v", gameID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		rules := persisted.Rules
+
+		c.JSON(http.StatusOK, gameRulesView(gameID, rules))
+	}
+}
+
+// RulesPreview is the outcome of previewing a variant before creating a lobby.
+type RulesPreview struct {
+	Valid  bool     `json:"valid"`
+	Errors []string `json:"errors,omitempty"`
+	// Rules, SkunkLine and DoubleSkunkLine are only set for valid variants.
+	Rules             *GameRules `json:"rules,omitempty"`
+	SkunkLine         int        `json:"skunk_line,omitempty"`
+	DoubleSkunkLine   int        `json:"double_skunk_line,omitempty"`
+	ValidPlayerCounts []int      `json:"valid_player_counts"`
+}
+
+// RulesPreviewHandler resolves a rule variant from query parameters (players, variant,
+// target_score, last_card_points, cut_tie_policy, crib_four_card_flush, muggins, turn_timeout_seconds,
+// reveal_hands, pegging_flush) without creating anything, so the lobby form can show hand size, crib size and
+// skunk lines before submitting. Invalid variants still return 200 with valid=false and the reasons; teams
+// are reported as unsupported.
+func RulesPreviewHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, span := tracing.StartSpan(c.Request.Context(), "handlers.RulesPreviewHandler")
+		defer span.End()
+
+		preview := RulesPreview{ValidPlayerCounts: []int{2, 3, 4}}
+		intParam := func(name string, def int) int {
+			raw := strings.TrimSpace(c.Query(name))
+			if raw == "" {
+				return def
+			}
+			v, err := strconv.Atoi(raw)
+			if err != nil {
+				preview.Errors = append(preview.Errors, name+" must be an integer")
+				return def
+			}
+			return v
+		}
+		rules := cribbage.Rules{
+			MaxPlayers:     intParam("players", 2),
+			TargetScore:    intParam("target_score", 0),
+			LastCardPoints: intParam("last_card_points", 0),
+			CutTiePolicy:   strings.TrimSpace(c.Query("cut_tie_policy")),
+			Variant:        strings.TrimSpace(c.Query("variant")),
+		}
+		rules.TurnTimeoutSeconds = intParam("turn_timeout_seconds", 0)
+		boolParam := func(name string) bool {
+			on, err := strconv.ParseBool(c.DefaultQuery(name, "false"))
+			if err != nil {
+				preview.Errors = append(preview.Errors, name+" must be true or false")
+			}
+			return on
+		}
+		rules.CribFourCardFlush = boolParam("crib_four_card_flush")
+		rules.Muggins = boolParam("muggins")
+		rules.RevealHands = boolParam("reveal_hands")
+		rules.PeggingFlush = boolParam("pegging_flush")
+		if boolParam("teams") {
+			preview.Errors = append(preview.Errors, "teams is not supported")
+		}
+		if err := rules.Validate(); err != nil {
+			preview.Errors = append(preview.Errors, err.Error())
+		}
+		if len(preview.Errors) == 0 {
+			view := gameRulesView(0, rules)
+			preview.Valid = true
+			preview.Rules = &view
+			preview.SkunkLine = rules.SkunkLine()
+			preview.DoubleSkunkLine = rules.DoubleSkunkLine()
+		}
+		c.JSON(http.StatusOK, preview)
+	}
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"sync"
+
+	"fifteen-thirty-one-go/backend/internal/config"
+)
+
+// runtimeCfg holds gameplay/server knobs that handlers consult at request time.
+// It is set by main at startup (like the websocket origin policy). Until then, and for
+// embedders that never call SetRuntimeConfig, the hand divergence check stays on as it does
+// by default in LoadFromEnv.
+var runtimeCfgMu sync.RWMutex
+var runtimeCfg = config.Config{StrictHandValidation: true}
+
+// SetRuntimeConfig installs the config consulted by gameplay handlers.
+func SetRuntimeConfig(cfg config.Config) {
+	runtimeCfgMu.Lock()
+	defer runtimeCfgMu.Unlock()
+	runtimeCfg = cfg
+}
+
+func currentConfig() config.Config {
+	runtimeCfgMu.RLock()
+	defer runtimeCfgMu.RUnlock()
+	return runtimeCfg
+}
@@ -52,3 +52,18 @@ func UserStatsHandler(db *sql.DB) gin.HandlerFunc {
 		c.JSON(http.StatusOK, stats)
 	}
 }
+
+// GlobalStatsHandler returns site-wide aggregates: currently game pacing from game_stats.
+func GlobalStatsHandler(db *sql.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, span := tracing.StartSpan(c.Request.Context(), "handlers.GlobalStatsHandler")
+		defer span.End()
+
+		pacing, err := models.GetGlobalPacingStats(ctx, db)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"pacing": pacing})
+	}
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"fifteen-thirty-one-go/backend/internal/game/cribbage"
+	"fifteen-thirty-one-go/backend/internal/models"
+	"fifteen-thirty-one-go/backend/internal/tracing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ScorecardEntry is one seat's line on the scorecard.
+type ScorecardEntry struct {
+	UserID      int64  `json:"user_id"`
+	Username    string `json:"username"`
+	Position    int64  `json:"position"` // seat
+	Rank        int    `json:"rank"`     // 1 = leading; tied scores share a rank
+	Score       int    `json:"score"`
+	PegsFromWin int    `json:"pegs_from_win"`
+	MaxThisHand int    `json:"max_this_hand"` // conservative upper bound, see cribbage.State.Outlook
+	IsDealer    bool   `json:"is_dealer"`
+	IsBot       bool   `json:"is_bot"`
+}
+
+// Scorecard is a condensed, hand-free projection of the game state for lightweight polling.
+type Scorecard struct {
+	GameID      int64            `json:"game_id"`
+	Stage       string           `json:"stage"`
+	TargetScore int              `json:"target_score"`
+	DealerIndex int              `json:"dealer_index"`
+	Players     []ScorecardEntry `json:"players"`
+}
+
+// ScorecardHandler returns running totals for participants and spectators. Responses carry a
+// content ETag so pollers can use If-None-Match. (Not every engine mutation bumps state_version,
+// so the version alone isn't a safe validator.)
+func ScorecardHandler(db *sql.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, span := tracing.StartSpan(c.Request.Context(), "handlers.ScorecardHandler")
+		defer span.End()
+
+		gameID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || gameID <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid game id"})
+			return
+		}
+		userID, ok := userIDFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		allowed, err := canViewGame(db, userID, gameID)
+		if err != nil {
+			log.Printf("ScorecardHandler: authorization check failed: game_id=%d user_id=%d err=%v", gameID, userID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		if !allowed {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+
+		players, err := models.ListGamePlayersByGame(db, gameID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		st, unlock, err := ensureGameStateLocked(db, gameID, players)
+		if err != nil {
+			if errors.Is(err, models.ErrGameStateMissing) {
+				c.JSON(http.StatusConflict, gin.H{"error": "game state unavailable; recreate lobby"})
+				return
+			}
+			log.Printf("ScorecardHandler: ensureGameStateLocked failed: game_id=%d err=%v", gameID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		card := Scorecard{
+			GameID:      gameID,
+			Stage:       st.Stage,
+			TargetScore: st.Rules.WinningScore(),
+			DealerIndex: st.DealerIndex,
+			Players:     make([]ScorecardEntry, 0, len(players)),
+		}
+		outlook := st.Outlook()
+		for _, p := range players {
+			score := 0
+			if int(p.Position) < len(st.Scores) {
+				score = st.Scores[p.Position]
+			}
+			card.Players = append(card.Players, ScorecardEntry{
+				UserID:      p.UserID,
+				Username:    p.Username,
+				Position:    p.Position,
+				Score:       score,
+				PegsFromWin: max(card.TargetScore-score, 0),
+				MaxThisHand: outlookFor(outlook, p.Position),
+				IsDealer:    int(p.Position) == st.DealerIndex,
+				IsBot:       p.IsBot && !p.BotTakeover,
+			})
+		}
+		unlock()
+
+		// Competition ranking: equal scores share a rank, the next rank skips.
+		order := make([]int, len(card.Players))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool {
+			return card.Players[order[a]].Score > card.Players[order[b]].Score
+		})
+		for i, idx := range order {
+			if i > 0 && card.Players[idx].Score == card.Players[order[i-1]].Score {
+				card.Players[idx].Rank = card.Players[order[i-1]].Rank
+			} else {
+				card.Players[idx].Rank = i + 1
+			}
+		}
+
+		body, err := json.Marshal(card)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		sum := sha256.Sum256(body)
+		etag := fmt.Sprintf(`"%x"`, sum[:12])
+		c.Header("ETag", etag)
+		c.Header("Cache-Control", "private, no-cache")
+		if c.GetHeader("If-None-Match") == etag {
+			c.Status(http.StatusNotModified)
+			return
+		}
+		c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+	}
+}
+
+func outlookFor(outlook []cribbage.Outlook, pos int64) int {
+	for _, o := range outlook {
+		if int64(o.Position) == pos {
+			return o.MaxThisHand
+		}
+	}
+	return 0
+}
+
+// canViewGame reports whether the user may read a game's public state: participants and
+// spectators both qualify.
+func canViewGame(db *sql.DB, userID, gameID int64) (bool, error) {
+	allowed, err := models.IsUserInGame(db, userID, gameID)
+	if err == nil && !allowed {
+		allowed, err = models.IsUserSpectatingGame(db, userID, gameID)
+	}
+	return allowed, err
+}
@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"fifteen-thirty-one-go/backend/internal/models"
+	ws "fifteen-thirty-one-go/backend/pkg/websocket"
+)
+
+// seatKey identifies a player's seat in a game.
+type seatKey struct {
+	gameID int64
+	userID int64
+}
+
+// seatPresence tracks which websocket clients are watching a game room so a human whose
+// last connection drops can be replaced by a bot (DISCONNECT_BOT_TAKEOVER).
+var seatPresence = struct {
+	mu      sync.Mutex
+	clients map[*ws.Client]int64 // client -> game id of the room it is in
+	counts  map[seatKey]int
+}{
+	clients: map[*ws.Client]int64{},
+	counts:  map[seatKey]int{},
+}
+
+// gameIDFromRoom parses "game:<id>" rooms; ok is false for any other room.
+func gameIDFromRoom(room string) (int64, bool) {
+	rest, found := strings.CutPrefix(room, "game:")
+	if !found {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(rest, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
+
+// seatPresenceEnter records that client is now in room. Only moving to a different game room
+// counts as a disconnect from the previous game; lobby and chat rooms leave the seat's presence
+// alone (see seatPresenceLeave for explicit leaves). Presence is only counted for players seated
+// in a game takeover applies to (see seatTakeoverApplies), so spectators never touch it. The
+// first connection to such a seat reclaims it if a bot holds it.
+func seatPresenceEnter(db *sql.DB, client *ws.Client, room string) {
+	gameID, isGame := gameIDFromRoom(room)
+	if !isGame {
+		return
+	}
+	if seatPresenceGame(client) == gameID {
+		return
+	}
+	seated := seatTakeoverApplies(db, gameID, client.UserID)
+
+	seatPresence.mu.Lock()
+	prev, hadPrev := seatPresence.clients[client]
+	if hadPrev && prev == gameID {
+		seatPresence.mu.Unlock()
+		return
+	}
+	var lastLeft bool
+	if hadPrev {
+		delete(seatPresence.clients, client)
+		lastLeft = decSeatLocked(seatKey{gameID: prev, userID: client.UserID})
+	}
+	var first bool
+	if seated {
+		seatPresence.clients[client] = gameID
+		k := seatKey{gameID: gameID, userID: client.UserID}
+		seatPresence.counts[k]++
+		first = seatPresence.counts[k] == 1
+	}
+	seatPresence.mu.Unlock()
+
+	if lastLeft {
+		scheduleSeatTakeover(db, prev, client.UserID)
+	}
+	if first {
+		reclaimSeat(db, gameID, client.UserID)
+	}
+}
+
+// seatTakeoverApplies reports whether userID holds a seat in gameID (their own, or one a bot
+// took over from them) that disconnect takeover covers: it must be enabled, and match games,
+// the rated format, also need DisconnectBotTakeoverMatches.
+func seatTakeoverApplies(db *sql.DB, gameID, userID int64) bool {
+	cfg := currentConfig()
+	if !cfg.DisconnectBotTakeover {
+		return false
+	}
+	players, err := models.ListGamePlayersByGame(db, gameID)
+	if err != nil {
+		log.Printf("seatTakeoverApplies: list players failed game_id=%d user_id=%d err=%v", gameID, userID, err)
+		return false
+	}
+	seated := false
+	for _, p := range players {
+		if p.UserID == userID && !p.Resigned && (!p.IsBot || p.BotTakeover) {
+			seated = true
+		}
+	}
+	if !seated || cfg.DisconnectBotTakeoverMatches {
+		return seated
+	}
+	if _, err := models.MatchIDForGame(db, gameID); err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			log.Printf("seatTakeoverApplies: match lookup failed game_id=%d err=%v", gameID, err)
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+// seatPresenceLeave records that client disconnected or explicitly left its game room.
+func seatPresenceLeave(db *sql.DB, client *ws.Client) {
+	seatPresence.mu.Lock()
+	gameID, ok := seatPresence.clients[client]
+	var lastLeft bool
+	if ok {
+		delete(seatPresence.clients, client)
+		lastLeft = decSeatLocked(seatKey{gameID: gameID, userID: client.UserID})
+	}
+	seatPresence.mu.Unlock()
+
+	if lastLeft {
+		scheduleSeatTakeover(db, gameID, client.UserID)
+	}
+}
+
+// decSeatLocked decrements the connection count for k and reports whether it reached zero.
+// Caller must hold seatPresence.mu.
+func decSeatLocked(k seatKey) bool {
+	n := seatPresence.counts[k] - 1
+	if n > 0 {
+		seatPresence.counts[k] = n
+		return false
+	}
+	delete(seatPresence.counts, k)
+	return true
+}
+
+// seatPresenceGame returns the game whose room client counts toward, or 0.
+func seatPresenceGame(client *ws.Client) int64 {
+	seatPresence.mu.Lock()
+	defer seatPresence.mu.Unlock()
+	return seatPresence.clients[client]
+}
+
+func seatConnected(gameID, userID int64) bool {
+	seatPresence.mu.Lock()
+	defer seatPresence.mu.Unlock()
+	return seatPresence.counts[seatKey{gameID: gameID, userID: userID}] > 0
+}
+
+func scheduleSeatTakeover(db *sql.DB, gameID, userID int64) {
+	cfg := currentConfig()
+	if !cfg.DisconnectBotTakeover {
+		return
+	}
+	time.AfterFunc(cfg.DisconnectBotGrace, func() {
+		if seatConnected(gameID, userID) {
+			return
+		}
+		takeOverSeat(db, gameID, userID, cfg.DisconnectBotDifficulty)
+	})
+}
+
+func takeOverSeat(db *sql.DB, gameID, userID int64, difficulty string) {
+	players, err := models.ListGamePlayersByGame(db, gameID)
+	if err != nil {
+		log.Printf("takeOverSeat: list players failed game_id=%d user_id=%d err=%v", gameID, userID, err)
+		return
+	}
+	// Never hand the last human seat to a bot: there would be nobody left to play against.
+	otherHumans := 0
+	for _, p := range players {
+		if p.UserID != userID && !p.IsBot && !p.Resigned {
+			otherHumans++
+		}
+	}
+	if otherHumans == 0 {
+		return
+	}
+	ok, err := models.TakeOverSeatWithBot(db, gameID, userID, difficulty)
+	if err != nil {
+		log.Printf("takeOverSeat: update failed game_id=%d user_id=%d err=%v", gameID, userID, err)
+		return
+	}
+	if !ok {
+		return
+	}
+	log.Printf("takeOverSeat: bot took over disconnected seat game_id=%d user_id=%d difficulty=%s", gameID, userID, difficulty)
+
+	if err := maybeRunBotTurns(db, gameID); err != nil {
+		log.Printf("takeOverSeat: maybeRunBotTurns failed game_id=%d err=%v", gameID, err)
+	}
+	if err := maybeFinalizeGame(context.Background(), db, gameID); err != nil {
+		log.Printf("takeOverSeat: maybeFinalizeGame failed game_id=%d err=%v", gameID, err)
+	}
+	broadcastSeatEvent(gameID, "game:seat_bot_takeover", userID)
+	broadcastGameUpdate(db, gameID)
+}
+
+func reclaimSeat(db *sql.DB, gameID, userID int64) {
+	cfg := currentConfig()
+	ok, err := models.ReclaimSeatFromBot(db, gameID, userID, cfg.DisconnectReclaimWindow)
+	if err != nil {
+		log.Printf("reclaimSeat: update failed game_id=%d user_id=%d err=%v", gameID, userID, err)
+		return
+	}
+	if !ok {
+		return
+	}
+	log.Printf("reclaimSeat: human reclaimed seat game_id=%d user_id=%d", gameID, userID)
+	broadcastSeatEvent(gameID, "game:seat_reclaimed", userID)
+	broadcastGameUpdate(db, gameID)
+}
+
+func broadcastSeatEvent(gameID int64, typ string, userID int64) {
+	if hubProvider == nil {
+		return
+	}
+	hub, ok := hubProvider()
+	if !ok || hub == nil {
+		return
+	}
+	hub.Broadcast("game:"+strconv.FormatInt(gameID, 10), typ, map[string]any{
+		"game_id": gameID,
+		"user_id": userID,
+	})
+}
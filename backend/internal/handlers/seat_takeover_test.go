@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"fifteen-thirty-one-go/backend/internal/config"
+	ws "fifteen-thirty-one-go/backend/pkg/websocket"
+)
+
+// enableSeatTakeover turns disconnect takeover on with a grace long enough that no timer fires
+// during the test; takeovers are driven through takeOverSeat directly.
+func enableSeatTakeover(t *testing.T, matches bool) {
+	t.Helper()
+	setTestConfig(t, func(cfg *config.Config) {
+		cfg.DisconnectBotTakeover = true
+		cfg.DisconnectBotTakeoverMatches = matches
+		cfg.DisconnectBotGrace = time.Hour
+		cfg.DisconnectReclaimWindow = 10 * time.Minute
+	})
+}
+
+func TestDisconnectedSeatIsTakenOverAndReclaimed(t *testing.T) {
+	db := newTestDB(t)
+	enableSeatTakeover(t, false)
+	gameID, users := newTestGame(t, db, "alice", "bob")
+	alice := users[0]
+	room := fmt.Sprintf("game:%d", gameID)
+
+	client := &ws.Client{UserID: alice}
+	seatPresenceEnter(db, client, room)
+	if !seatConnected(gameID, alice) {
+		t.Fatal("alice's seat not counted as connected after joining the game room")
+	}
+	seatPresenceLeave(db, client)
+	if seatConnected(gameID, alice) {
+		t.Fatal("alice's seat still connected after her last socket left")
+	}
+
+	takeOverSeat(db, gameID, alice, "easy")
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM game_players WHERE game_id = ? AND user_id = ? AND is_bot = 1 AND bot_takeover_at IS NOT NULL`, gameID, alice); n != 1 {
+		t.Fatal("alice's seat was not taken over by a bot")
+	}
+
+	reconnect := &ws.Client{UserID: alice}
+	seatPresenceEnter(db, reconnect, room)
+	defer seatPresenceLeave(db, reconnect)
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM game_players WHERE game_id = ? AND user_id = ? AND is_bot = 0 AND bot_takeover_at IS NULL`, gameID, alice); n != 1 {
+		t.Error("alice did not reclaim her seat on reconnect")
+	}
+}
+
+func TestLastHumanSeatIsNotTakenOver(t *testing.T) {
+	db := newTestDB(t)
+	enableSeatTakeover(t, false)
+	gameID, users := newTestGame(t, db, "alice", "bob")
+
+	takeOverSeat(db, gameID, users[0], "easy")
+	takeOverSeat(db, gameID, users[1], "easy")
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM game_players WHERE game_id = ? AND is_bot = 0`, gameID); n != 1 {
+		t.Errorf("%d human seats left, want the last one kept", n)
+	}
+}
+
+func TestSeatPresenceIgnoresSpectatorsAndRatedGames(t *testing.T) {
+	db := newTestDB(t)
+	enableSeatTakeover(t, false)
+	gameID, users := newTestGame(t, db, "alice", "bob")
+	matchID, matchUsers := newTestMatchGame(t, db, 121, "carol", "dave")
+	spectator := newTestUser(t, db, "erin")
+
+	for _, tc := range []struct {
+		name   string
+		gameID int64
+		userID int64
+	}{
+		{"spectator", gameID, spectator},
+		{"match game", matchID, matchUsers[0]},
+	} {
+		client := &ws.Client{UserID: tc.userID}
+		seatPresenceEnter(db, client, fmt.Sprintf("game:%d", tc.gameID))
+		if got := seatPresenceGame(client); got != 0 {
+			t.Errorf("%s: presence tracked in game %d, want untracked", tc.name, got)
+			seatPresenceLeave(db, client)
+		}
+	}
+
+	// Matches opt in separately.
+	enableSeatTakeover(t, true)
+	client := &ws.Client{UserID: matchUsers[0]}
+	seatPresenceEnter(db, client, fmt.Sprintf("game:%d", matchID))
+	if got := seatPresenceGame(client); got != matchID {
+		t.Errorf("match seat tracked in game %d with matches enabled, want %d", got, matchID)
+	}
+	seatPresenceLeave(db, client)
+
+	// With takeover off, joining a game room neither tracks presence nor touches the seat.
+	setTestConfig(t, func(cfg *config.Config) { cfg.DisconnectBotTakeover = false })
+	client = &ws.Client{UserID: users[0]}
+	seatPresenceEnter(db, client, fmt.Sprintf("game:%d", gameID))
+	if got := seatPresenceGame(client); got != 0 {
+		t.Errorf("presence tracked in game %d with takeover disabled, want untracked", got)
+		seatPresenceLeave(db, client)
+	}
+}
@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
@@ -9,6 +11,7 @@ import (
 	"strconv"
 	"time"
 
+	"fifteen-thirty-one-go/backend/internal/models"
 	"fifteen-thirty-one-go/backend/internal/tracing"
 	ws "fifteen-thirty-one-go/backend/pkg/websocket"
 
@@ -23,6 +26,11 @@ type SpectatorInfo struct {
 	AvatarURL *string   `json:"avatar_url,omitempty"`
 }
 
+type spectateRequest struct {
+	// Incognito spectators still receive room updates but are not listed or counted publicly.
+	Incognito bool `json:"incognito"`
+}
+
 // JoinAsSpectator handles POST /api/lobbies/:id/spectate and adds the authenticated user as a spectator.
 func JoinAsSpectator(db *sql.DB, hubProvider func() (*ws.Hub, bool)) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -51,6 +59,13 @@ func JoinAsSpectator(db *sql.DB, hubProvider func() (*ws.Hub, bool)) gin.Handler
 			return
 		}
 
+		var req spectateRequest
+		_ = c.ShouldBindJSON(&req) // optional body
+		if req.Incognito && !currentConfig().CanSpectateIncognito(userID) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "incognito spectating is not available", "code": "incognito_not_allowed"})
+			return
+		}
+
 		ctx := c.Request.Context()
 
 		// Check if lobby exists and allows spectators
@@ -87,7 +102,7 @@ func JoinAsSpectator(db *sql.DB, hubProvider func() (*ws.Hub, bool)) gin.Handler
 			SELECT COUNT(*)
 			FROM game_players gp
 			JOIN games g ON g.id = gp.game_id
-			WHERE g.lobby_id = ? AND gp.user_id = ? AND g.status IN ('waiting', 'in_progress')
+			WHERE g.lobby_id = ? AND gp.user_id = ? AND gp.resigned = 0 AND g.status IN ('waiting', 'in_progress')
 		`, lobbyID, userID).Scan(&playerCount)
 		if err != nil {
 			wrappedErr := fmt.Errorf("JoinAsSpectator: checking player status (lobby_id=%d user_id=%d): %w", lobbyID, userID, err)
@@ -111,12 +126,12 @@ func JoinAsSpectator(db *sql.DB, hubProvider func() (*ws.Hub, bool)) gin.Handler
 			return
 		}
 
-		// Insert spectator (ON CONFLICT DO NOTHING for idempotency)
+		// Insert spectator (idempotent; re-spectating only updates the visibility choice)
 		_, err = db.ExecContext(ctx, `
-			INSERT INTO lobby_spectators (lobby_id, user_id)
-			VALUES (?, ?)
-			ON CONFLICT(lobby_id, user_id) DO NOTHING
-		`, lobbyID, userID)
+			INSERT INTO lobby_spectators (lobby_id, user_id, hidden)
+			VALUES (?, ?, ?)
+			ON CONFLICT(lobby_id, user_id) DO UPDATE SET hidden = excluded.hidden
+		`, lobbyID, userID, req.Incognito)
 		if err != nil {
 			wrappedErr := fmt.Errorf("JoinAsSpectator: insert spectator (lobby_id=%d user_id=%d): %w", lobbyID, userID, err)
 			log.Printf("%v", wrappedErr)
@@ -125,7 +140,7 @@ func JoinAsSpectator(db *sql.DB, hubProvider func() (*ws.Hub, bool)) gin.Handler
 		}
 
 		var joinedAt time.Time
-		// If the spectator already existed (ON CONFLICT DO NOTHING), use the stored joined_at.
+		// If the spectator already existed (ON CONFLICT), use the stored joined_at.
 		// If this read fails unexpectedly, surface it rather than masking DB issues.
 		if err := db.QueryRowContext(ctx, `SELECT joined_at FROM lobby_spectators WHERE lobby_id = ? AND user_id = ?`, lobbyID, userID).Scan(&joinedAt); err != nil {
 			wrappedErr := fmt.Errorf("JoinAsSpectator: retrieve joined_at (lobby_id=%d user_id=%d): %w", lobbyID, userID, err)
@@ -143,17 +158,62 @@ func JoinAsSpectator(db *sql.DB, hubProvider func() (*ws.Hub, bool)) gin.Handler
 			spectator.AvatarURL = &avatarURL.String
 		}
 
-		// Broadcast spectator joined event
+		// Broadcast spectator joined event (incognito spectators join silently)
 		hub, ok := hubProvider()
-		if ok && hub != nil {
+		if ok && hub != nil && !req.Incognito {
 			hub.Broadcast(fmt.Sprintf("lobby:%d", lobbyID), "lobby:spectator_joined", spectator)
 
 			// Send system message
 			_ = SendSystemMessage(ctx, db, hub, lobbyID, fmt.Sprintf("%s is now spectating", username), "join")
 		}
 
-		c.JSON(http.StatusOK, gin.H{"success": true, "spectator": spectator})
+		c.JSON(http.StatusOK, gin.H{"success": true, "spectator": spectator, "incognito": req.Incognito})
+	}
+}
+
+// spectateAfterResign makes a player who resigned from a game that plays on a spectator of its
+// lobby, announced as if they had joined from the lobby list, and tells their clients to switch
+// to the spectator view. Their connections are already in the game room spectators share, so no
+// room move is needed. It is best-effort (the resignation stands either way) and reports whether
+// they are now spectating; lobbies that don't allow spectators are left alone.
+func spectateAfterResign(ctx context.Context, db *sql.DB, lobbyID, gameID, userID int64) bool {
+	var allowSpectators bool
+	if err := db.QueryRowContext(ctx, `SELECT allow_spectators FROM lobbies WHERE id = ?`, lobbyID).Scan(&allowSpectators); err != nil {
+		log.Printf("spectateAfterResign: checking lobby (lobby_id=%d): %v", lobbyID, err)
+		return false
+	}
+	if !allowSpectators {
+		return false
+	}
+	if _, err := db.ExecContext(ctx, `
+		INSERT INTO lobby_spectators (lobby_id, user_id, hidden)
+		VALUES (?, ?, 0)
+		ON CONFLICT(lobby_id, user_id) DO NOTHING
+	`, lobbyID, userID); err != nil {
+		log.Printf("spectateAfterResign: insert spectator (lobby_id=%d user_id=%d): %v", lobbyID, userID, err)
+		return false
+	}
+
+	var spectator SpectatorInfo
+	var avatarURL sql.NullString
+	if err := db.QueryRowContext(ctx, `
+		SELECT u.username, u.avatar_url, ls.joined_at
+		FROM lobby_spectators ls JOIN users u ON u.id = ls.user_id
+		WHERE ls.lobby_id = ? AND ls.user_id = ?
+	`, lobbyID, userID).Scan(&spectator.Username, &avatarURL, &spectator.JoinedAt); err != nil {
+		log.Printf("spectateAfterResign: get spectator (lobby_id=%d user_id=%d): %v", lobbyID, userID, err)
+		return true
+	}
+	spectator.UserID = userID
+	if avatarURL.Valid {
+		spectator.AvatarURL = &avatarURL.String
+	}
+	if hub, ok := getHubProvider(); ok && hub != nil {
+		hub.Broadcast(fmt.Sprintf("lobby:%d", lobbyID), "lobby:spectator_joined", spectator)
+		_ = SendSystemMessage(ctx, db, hub, lobbyID, fmt.Sprintf("%s resigned and is now spectating", spectator.Username), "join")
+		hub.SendToUser(userID, "game:spectating", gin.H{"game_id": gameID, "lobby_id": lobbyID})
 	}
+	return true
 }
 
 // LeaveAsSpectator handles DELETE /api/lobbies/:id/spectate and removes the authenticated user from spectators.
@@ -195,6 +255,19 @@ func LeaveAsSpectator(db *sql.DB, hubProvider func() (*ws.Hub, bool)) gin.Handle
 			return
 		}
 
+		var hidden bool
+		err = db.QueryRowContext(ctx, `SELECT hidden FROM lobby_spectators WHERE lobby_id = ? AND user_id = ?`, lobbyID, userID).Scan(&hidden)
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "you are not spectating this lobby"})
+			return
+		}
+		if err != nil {
+			wrappedErr := fmt.Errorf("LeaveAsSpectator: get spectator (lobby_id=%d user_id=%d): %w", lobbyID, userID, err)
+			log.Printf("%v", wrappedErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			return
+		}
+
 		// Delete spectator
 		result, err := db.ExecContext(ctx, `
 			DELETE FROM lobby_spectators
@@ -215,7 +288,7 @@ func LeaveAsSpectator(db *sql.DB, hubProvider func() (*ws.Hub, bool)) gin.Handle
 
 		// Broadcast spectator left event
 		hub, ok := hubProvider()
-		if ok && hub != nil {
+		if ok && hub != nil && !hidden {
 			hub.Broadcast(fmt.Sprintf("lobby:%d", lobbyID), "lobby:spectator_left", map[string]any{
 				"user_id":  userID,
 				"username": username,
@@ -247,7 +320,7 @@ func GetSpectators(db *sql.DB) gin.HandlerFunc {
 			SELECT ls.user_id, u.username, ls.joined_at, u.avatar_url
 			FROM lobby_spectators ls
 			JOIN users u ON u.id = ls.user_id
-			WHERE ls.lobby_id = ?
+			WHERE ls.lobby_id = ? AND ls.hidden = 0
 			ORDER BY ls.joined_at ASC
 		`, lobbyID)
 		if err != nil {
@@ -281,6 +354,256 @@ func GetSpectators(db *sql.DB) gin.HandlerFunc {
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{"spectators": spectators})
+		resp := gin.H{"spectators": spectators, "count": len(spectators)}
+		if userID, ok := userIDFromContext(c); ok && currentConfig().HiddenWatcherCountForHosts {
+			var hiddenCount int
+			err := db.QueryRowContext(ctx, `
+				SELECT COUNT(*)
+				FROM lobby_spectators ls
+				JOIN lobbies l ON l.id = ls.lobby_id
+				WHERE ls.lobby_id = ? AND ls.hidden = 1 AND l.host_id = ?
+			`, lobbyID, userID).Scan(&hiddenCount)
+			if err != nil {
+				log.Printf("GetSpectators: count hidden spectators (lobby_id=%d): %v", lobbyID, err)
+			} else if hiddenCount > 0 {
+				resp["hidden_count"] = hiddenCount
+			}
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// ClaimSeat handles POST /api/lobbies/:id/claim-seat: a current spectator of a waiting lobby
+// takes a free seat. Leaving the spectator list and joining happen in one transaction.
+func ClaimSeat(db *sql.DB, hubProvider func() (*ws.Hub, bool)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, span := tracing.StartSpan(c.Request.Context(), "handlers.ClaimSeat")
+		defer span.End()
+
+		userID, ok := userIDFromContext(c)
+		if !ok || userID <= 0 {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		lobbyID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || lobbyID <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid lobby id"})
+			return
+		}
+
+		ctx := c.Request.Context()
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			return
+		}
+		defer tx.Rollback()
+
+		gameID, gameStatus, err := models.LobbyGameTx(tx, lobbyID)
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "lobby not found"})
+			return
+		}
+		if err != nil {
+			log.Printf("ClaimSeat: find game (lobby_id=%d): %v", lobbyID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			return
+		}
+		if gameStatus != "waiting" {
+			c.JSON(http.StatusConflict, gin.H{"error": "game already started"})
+			return
+		}
+
+		var hidden bool
+		err = tx.QueryRowContext(ctx, `SELECT hidden FROM lobby_spectators WHERE lobby_id = ? AND user_id = ?`, lobbyID, userID).Scan(&hidden)
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "you are not spectating this lobby"})
+			return
+		}
+		if err != nil {
+			log.Printf("ClaimSeat: get spectator (lobby_id=%d user_id=%d): %v", lobbyID, userID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			return
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM lobby_spectators WHERE lobby_id = ? AND user_id = ?`, lobbyID, userID); err != nil {
+			log.Printf("ClaimSeat: delete spectator (lobby_id=%d user_id=%d): %v", lobbyID, userID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			return
+		}
+
+		j, err := seatJoiningPlayerTx(tx, lobbyID, gameID, userID)
+		if err != nil {
+			writeJoinError(c, "ClaimSeat", lobbyID, userID, err)
+			return
+		}
+		var username string
+		if err := tx.QueryRowContext(ctx, `SELECT username FROM users WHERE id = ?`, userID).Scan(&username); err != nil {
+			log.Printf("ClaimSeat: get username (user_id=%d): %v", userID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			return
+		}
+		if err := tx.Commit(); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			return
+		}
+
+		resp := gin.H{"lobby": j.lobby, "game_id": gameID, "position": j.position, "realtime_sync": "ok"}
+		if err := syncRuntimeStateFromDB(gameID, int(j.position), j.stateVersion, j.stateJSON, j.handJSON); err != nil {
+			log.Printf("ClaimSeat: runtime state sync failed after commit (best-effort): game_id=%d user_id=%d pos=%d err=%v", gameID, userID, j.position, err)
+			resp["realtime_sync"] = "failed"
+		}
+
+		if hub, ok := hubProvider(); ok && hub != nil {
+			room := fmt.Sprintf("lobby:%d", lobbyID)
+			if !hidden {
+				hub.Broadcast(room, "lobby:spectator_left", map[string]any{"user_id": userID, "username": username})
+			}
+			hub.Broadcast(room, "lobby:seat_claimed", map[string]any{"user_id": userID, "username": username, "position": j.position})
+			_ = SendSystemMessage(ctx, db, hub, lobbyID, fmt.Sprintf("%s took a seat", username), "join")
+		}
+		startLobbyIfFull(db, lobbyID)
+		broadcastGameUpdate(db, gameID)
+
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// SpectatablePlayer is one seat of a game listed for spectating.
+type SpectatablePlayer struct {
+	Username string `json:"username"`
+	Position int64  `json:"position"`
+	Score    int    `json:"score"`
+	IsBot    bool   `json:"is_bot"`
+}
+
+// SpectatableGameInfo is one entry of GET /api/spectate/available.
+type SpectatableGameInfo struct {
+	models.SpectatableGame
+	Players []SpectatablePlayer `json:"players"`
+}
+
+// ListSpectatableGames handles GET /api/spectate/available: games underway in lobbies that allow
+// spectators, most watched first, with public seat info and live scores.
+func ListSpectatableGames(db *sql.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, span := tracing.StartSpan(c.Request.Context(), "handlers.ListSpectatableGames")
+		defer span.End()
+
+		limit, offset, ok := parseLimitOffset(c)
+		if !ok {
+			return
+		}
+		games, err := models.ListSpectatableGames(db, limit, offset)
+		if err != nil {
+			log.Printf("ListSpectatableGames: list games: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			return
+		}
+
+		out := make([]SpectatableGameInfo, 0, len(games))
+		for _, g := range games {
+			players, err := models.ListGamePlayersByGame(db, g.GameID)
+			if err != nil {
+				log.Printf("ListSpectatableGames: list players (game_id=%d): %v", g.GameID, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+				return
+			}
+			scores := liveScores(db, g.GameID)
+			info := SpectatableGameInfo{SpectatableGame: g, Players: make([]SpectatablePlayer, 0, len(players))}
+			for _, p := range players {
+				sp := SpectatablePlayer{Username: p.Username, Position: p.Position, IsBot: p.IsBot && !p.BotTakeover}
+				if int(p.Position) < len(scores) {
+					sp.Score = scores[p.Position]
+				}
+				info.Players = append(info.Players, sp)
+			}
+			out = append(out, info)
+		}
+		c.JSON(http.StatusOK, gin.H{"games": out})
+	}
+}
+
+// SpectateGameHandler handles GET /api/games/:id/spectate: the public snapshot of a game (see
+// BuildGameSnapshotPublic) for a registered spectator of its lobby. No seat's cards are
+// included. Games whose lobby does not allow spectators are reported as not found. With
+// ?follow=<user id> the snapshot also carries a SpectatorFocus on that player.
+func SpectateGameHandler(db *sql.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, span := tracing.StartSpan(c.Request.Context(), "handlers.SpectateGameHandler")
+		defer span.End()
+
+		gameID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || gameID <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid game id"})
+			return
+		}
+		userID, ok := userIDFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		var allowSpectators bool
+		err = db.QueryRowContext(ctx, `
+			SELECT l.allow_spectators
+			FROM games g
+			JOIN lobbies l ON l.id = g.lobby_id
+			WHERE g.id = ?
+		`, gameID).Scan(&allowSpectators)
+		if errors.Is(err, sql.ErrNoRows) || (err == nil && !allowSpectators) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "game not found"})
+			return
+		}
+		if err != nil {
+			log.Printf("SpectateGameHandler: checking lobby (game_id=%d): %v", gameID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			return
+		}
+		spectating, err := models.IsUserSpectatingGame(db, userID, gameID)
+		if err != nil {
+			log.Printf("SpectateGameHandler: checking spectator (game_id=%d user_id=%d): %v", gameID, userID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			return
+		}
+		if !spectating {
+			c.JSON(http.StatusForbidden, gin.H{"error": "you are not spectating this game"})
+			return
+		}
+
+		snap, err := BuildGameSnapshotPublic(db, gameID)
+		if err != nil {
+			if errors.Is(err, models.ErrGameStateMissing) {
+				c.JSON(http.StatusConflict, gin.H{"error": "game state unavailable; recreate lobby"})
+				return
+			}
+			log.Printf("SpectateGameHandler: BuildGameSnapshotPublic failed (game_id=%d): %v", gameID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			return
+		}
+		if !applySpectatorFollow(c, snap) {
+			return
+		}
+		c.JSON(http.StatusOK, snap)
+	}
+}
+
+// liveScores returns per-seat scores from the in-memory engine when the game is loaded,
+// otherwise from the persisted state. It never loads a game into memory just to list it.
+func liveScores(db *sql.DB, gameID int64) []int {
+	if st, unlock, ok := defaultGameManager.GetLocked(gameID); ok {
+		scores := append([]int(nil), st.Scores...)
+		unlock()
+		return scores
+	}
+	raw, _, ok, err := models.GetGameStateJSON(db, gameID)
+	if err != nil || !ok {
+		return nil
+	}
+	var persisted struct {
+		Scores []int `json:"scores"`
+	}
+	if err := json.Unmarshal([]byte(raw), &persisted); err != nil {
+		return nil
 	}
+	return persisted.Scores
 }
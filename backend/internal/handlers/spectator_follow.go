@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"fifteen-thirty-one-go/backend/internal/game/common"
+	"fifteen-thirty-one-go/backend/internal/game/cribbage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SpectatorFocus centres a spectator snapshot on one player (?follow=<user id>). Everything in it
+// is read from the already-redacted snapshot, so it reveals nothing the rest of the snapshot
+// does not: the kept hand and count breakdowns only appear once the hand is being counted.
+type SpectatorFocus struct {
+	UserID   int64  `json:"user_id"`
+	Username string `json:"username"`
+	Position int    `json:"position"`
+	// SeatOrder lists every seat starting with the followed one, so a client can draw the table
+	// from their side.
+	SeatOrder []int             `json:"seat_order"`
+	Score     int               `json:"score"`
+	HandCount *int64            `json:"hand_count,omitempty"`
+	IsDealer  bool              `json:"is_dealer"`
+	ToAct     bool              `json:"to_act"` // still to discard, or on turn while pegging
+	Outlook   *cribbage.Outlook `json:"outlook,omitempty"`
+	// Revealed during counting and once the game is finished.
+	KeptHand  []common.Card            `json:"kept_hand,omitempty"`
+	HandScore *cribbage.ScoreBreakdown `json:"hand_score,omitempty"`
+	CribScore *cribbage.ScoreBreakdown `json:"crib_score,omitempty"` // the followed player's own crib
+	Pegged    *int                     `json:"pegged,omitempty"`     // points pegged this hand
+}
+
+// applySpectatorFollow sets snap.Focus from the request's follow parameter, if present. It
+// writes a 400 and returns false when follow is not the user id of a seated player.
+func applySpectatorFollow(c *gin.Context, snap *GameSnapshot) bool {
+	raw := c.Query("follow")
+	if raw == "" {
+		return true
+	}
+	userID, err := strconv.ParseInt(raw, 10, 64)
+	if err == nil {
+		if focus := spectatorFocus(snap, userID); focus != nil {
+			snap.Focus = focus
+			return true
+		}
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": "follow must be the user id of a player in this game"})
+	return false
+}
+
+func spectatorFocus(snap *GameSnapshot, userID int64) *SpectatorFocus {
+	st := &snap.State
+	for _, p := range snap.Players {
+		if p.UserID != userID {
+			continue
+		}
+		pos := int(p.Position)
+		seats := st.Rules.MaxPlayers
+		if pos < 0 || pos >= seats {
+			return nil
+		}
+		f := &SpectatorFocus{
+			UserID:    p.UserID,
+			Username:  p.Username,
+			Position:  pos,
+			SeatOrder: make([]int, seats),
+			HandCount: p.HandCount,
+			IsDealer:  pos == st.DealerIndex,
+		}
+		for i := range f.SeatOrder {
+			f.SeatOrder[i] = (pos + i) % seats
+		}
+		if pos < len(st.Scores) {
+			f.Score = st.Scores[pos]
+		}
+		switch st.Stage {
+		case "discard":
+			f.ToAct = pos < len(st.DiscardCompleted) && !st.DiscardCompleted[pos]
+		case "pegging":
+			f.ToAct = pos == st.CurrentIndex
+		}
+		for i := range snap.Outlook {
+			if snap.Outlook[i].Position == pos {
+				o := snap.Outlook[i]
+				f.Outlook = &o
+			}
+		}
+		if pos < len(st.KeptHands) {
+			f.KeptHand = st.KeptHands[pos]
+		}
+		if cs := st.CountSummary; cs != nil {
+			if b, ok := cs.Hands[pos]; ok {
+				f.HandScore = &b
+			}
+			if f.IsDealer {
+				f.CribScore = cs.Crib
+			}
+			if pos < len(cs.Pegging) {
+				n := cs.Pegging[pos]
+				f.Pegged = &n
+			}
+		}
+		return f
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"fifteen-thirty-one-go/backend/internal/game/cribbage"
+	"fifteen-thirty-one-go/backend/internal/models"
+)
+
+// staleGameBatch caps how many games one janitor pass closes.
+const staleGameBatch = 100
+
+// staleLobbyClosed is the janitor's outcome for a lobby that never started its game.
+const staleLobbyClosed = "lobby_closed"
+
+// RunStaleGameJanitor closes games that have had no move or lobby chat for StaleGameTimeout,
+// checking hourly (or more often for short timeouts) until ctx is cancelled.
+func RunStaleGameJanitor(ctx context.Context, db *sql.DB) {
+	cfg := currentConfig()
+	if !cfg.StaleGameJanitor {
+		return
+	}
+	interval := time.Hour
+	if q := cfg.StaleGameTimeout / 4; q < interval {
+		interval = q
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		closeStaleGames(ctx, db, cfg.StaleGameTimeout)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func closeStaleGames(ctx context.Context, db *sql.DB, idle time.Duration) {
+	ids, err := models.ListStaleGames(db, idle, staleGameBatch)
+	if err != nil {
+		log.Printf("RunStaleGameJanitor: err=%v", err)
+		return
+	}
+	for _, gameID := range ids {
+		outcome, err := closeStaleGame(ctx, db, gameID, idle)
+		if err != nil {
+			log.Printf("RunStaleGameJanitor: game_id=%d err=%v", gameID, err)
+			continue
+		}
+		if outcome == "" {
+			continue
+		}
+		log.Printf("RunStaleGameJanitor: closed idle game game_id=%d outcome=%s", gameID, outcome)
+		// Persist any unsaved state for history, then drop the runtime copy.
+		if err := flushGameState(db, gameID); err != nil {
+			log.Printf("RunStaleGameJanitor: flushGameState failed: game_id=%d err=%v", gameID, err)
+		}
+		defaultGameManager.Delete(gameID)
+		broadcastGameUpdate(db, gameID)
+	}
+}
+
+// closeStaleGame records final standings for an idle game and marks it finished. With exactly
+// one active human the game is a forfeit: humans who never moved lose to them, or, in a game
+// against bots, the lone human forfeits to the bots. Otherwise it is abandoned, which keeps the
+// standings for history without counting toward games played or won. A lobby still waiting for
+// players never had a game to score, so it is just closed (staleLobbyClosed) with no scoreboard
+// rows. It returns the outcome, or "" if the game saw activity in the meantime and was left alone.
+func closeStaleGame(ctx context.Context, db *sql.DB, gameID int64, idle time.Duration) (string, error) {
+	var lobbyID int64
+	var status string
+	if err := db.QueryRowContext(ctx, `SELECT lobby_id, status FROM games WHERE id = ?`, gameID).Scan(&lobbyID, &status); err != nil {
+		return "", fmt.Errorf("query game: %w", err)
+	}
+	waiting := status == "waiting"
+
+	outcome := staleLobbyClosed
+	var players []models.GamePlayer
+	var scores []int
+	losers := map[int64]bool{}
+	if !waiting {
+		var err error
+		players, err = models.ListGamePlayersByGameContext(ctx, db, gameID)
+		if err != nil {
+			return "", err
+		}
+		if len(players) == 0 {
+			return "", nil
+		}
+		active, err := models.ActiveHumanIDs(db, gameID)
+		if err != nil {
+			return "", err
+		}
+		outcome = models.OutcomeAbandoned
+		if len(active) == 1 {
+			outcome = models.OutcomeForfeit
+			for _, p := range players {
+				human := (!p.IsBot || p.BotTakeover) && !p.Resigned
+				if human && p.UserID != active[0] {
+					losers[p.UserID] = true
+				}
+			}
+			if len(losers) == 0 {
+				losers[active[0]] = true
+			}
+		}
+		scores = liveScores(db, gameID)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	stale, err := models.GameIsStaleTx(tx, gameID, idle)
+	if err != nil {
+		return "", err
+	}
+	if !stale {
+		return "", nil
+	}
+	if !waiting {
+		if err := recordStandingsTx(ctx, tx, gameID, players, scores, losers, outcome); err != nil {
+			return "", err
+		}
+	}
+	if err := models.SetGameStatusTx(tx, gameID, "finished"); err != nil {
+		return "", err
+	}
+	if err := models.SetLobbyStatusTx(tx, lobbyID, "finished"); err != nil {
+		return "", err
+	}
+	if err := models.LeaveLobbySeriesTx(tx, lobbyID); err != nil {
+		return "", err
+	}
+	if err := NewMatchManager(db).AbandonTx(tx, gameID); err != nil {
+		return "", err
+	}
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+	activeLobbies.invalidate()
+	return outcome, nil
+}
+
+// recordStandingsTx writes the scoreboard of a game that ended off the board (forfeit or
+// abandoned), unless one is already recorded. Resigned players and those in losers rank last;
+// everyone else ranks by score. Only forfeits count toward games played, with the top row won.
+func recordStandingsTx(ctx context.Context, tx *sql.Tx, gameID int64, players []models.GamePlayer, scores []int, losers map[int64]bool, outcome string) error {
+	type row struct {
+		userID int64
+		pos    int64
+		score  int64
+		last   bool
+	}
+	rows := make([]row, 0, len(players))
+	for _, p := range players {
+		var sc int64
+		if pos := int(p.Position); pos >= 0 && pos < len(scores) {
+			sc = int64(scores[pos])
+		}
+		rows = append(rows, row{userID: p.UserID, pos: p.Position, score: sc, last: p.Resigned || losers[p.UserID]})
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].last != rows[j].last {
+			return !rows[i].last
+		}
+		if rows[i].score != rows[j].score {
+			return rows[i].score > rows[j].score
+		}
+		return rows[i].pos < rows[j].pos
+	})
+
+	var existing int64
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM scoreboard WHERE game_id = ?`, gameID).Scan(&existing); err != nil {
+		return fmt.Errorf("query existing scoreboard rows: %w", err)
+	}
+	if existing > 0 {
+		return nil
+	}
+	for i, r := range rows {
+		rank := int64(i + 1)
+		inserted, err := models.InsertScoreboardRowTx(ctx, tx, r.userID, gameID, r.score, rank, outcome, cribbage.NoSkunk)
+		if err != nil {
+			return fmt.Errorf("insert scoreboard row (user_id=%d rank=%d): %w", r.userID, rank, err)
+		}
+		if !inserted || outcome != models.OutcomeForfeit {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE users SET games_played = games_played + 1 WHERE id = ?`, r.userID); err != nil {
+			return fmt.Errorf("update games_played (user_id=%d): %w", r.userID, err)
+		}
+		if i == 0 {
+			if _, err := tx.ExecContext(ctx, `UPDATE users SET games_won = games_won + 1 WHERE id = ?`, r.userID); err != nil {
+				return fmt.Errorf("update games_won (winner_id=%d): %w", r.userID, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"fifteen-thirty-one-go/backend/internal/game/cribbage"
+	"fifteen-thirty-one-go/backend/internal/models"
+)
+
+func TestStaleJanitorClosesWaitingLobbyWithoutScoreboard(t *testing.T) {
+	db := newTestDB(t)
+	host := newTestUser(t, db, "host")
+	l, g, err := createLobbyWithGame(db, "idle", host, cribbage.DefaultRules(2), 0, nil, 0)
+	if err != nil {
+		t.Fatalf("create lobby: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE games SET created_at = datetime('now', '-1 day') WHERE id = ?`, g.ID); err != nil {
+		t.Fatalf("backdate: %v", err)
+	}
+
+	outcome, err := closeStaleGame(context.Background(), db, g.ID, time.Hour)
+	if err != nil {
+		t.Fatalf("closeStaleGame: %v", err)
+	}
+	if outcome != staleLobbyClosed {
+		t.Errorf("outcome %q, want %q", outcome, staleLobbyClosed)
+	}
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM scoreboard WHERE game_id = ?`, g.ID); n != 0 {
+		t.Errorf("%d scoreboard rows for a lobby that never started, want 0", n)
+	}
+	lobby, err := models.GetLobbyByID(db, l.ID)
+	if err != nil {
+		t.Fatalf("get lobby: %v", err)
+	}
+	game, err := models.GetGameByID(db, g.ID)
+	if err != nil {
+		t.Fatalf("get game: %v", err)
+	}
+	if lobby.Status != "finished" || game.Status != "finished" {
+		t.Errorf("lobby %q game %q, want both finished", lobby.Status, game.Status)
+	}
+}
+
+func TestStaleJanitorAbandonsStartedGame(t *testing.T) {
+	db := newTestDB(t)
+	gameID, users := newTestGame(t, db, "alice", "bob")
+	if _, err := db.Exec(`UPDATE games SET created_at = datetime('now', '-1 day') WHERE id = ?`, gameID); err != nil {
+		t.Fatalf("backdate: %v", err)
+	}
+
+	outcome, err := closeStaleGame(context.Background(), db, gameID, time.Hour)
+	if err != nil {
+		t.Fatalf("closeStaleGame: %v", err)
+	}
+	if outcome != models.OutcomeAbandoned {
+		t.Errorf("outcome %q, want %q", outcome, models.OutcomeAbandoned)
+	}
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM scoreboard WHERE game_id = ? AND outcome = ?`, gameID, models.OutcomeAbandoned); n != int64(len(users)) {
+		t.Errorf("%d abandoned scoreboard rows, want one per player (%d)", n, len(users))
+	}
+	if n := queryInt(t, db, `SELECT COALESCE(SUM(games_played), 0) FROM users`); n != 0 {
+		t.Errorf("abandoned game counted %d games played, want 0", n)
+	}
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"fifteen-thirty-one-go/backend/internal/models"
+)
+
+// dirtyStates tracks games whose in-memory engine state is ahead of games.state_json when
+// STATE_DURABILITY=batched. In durable mode nothing is ever marked.
+var dirtyStates = struct {
+	mu    sync.Mutex
+	games map[int64]struct{}
+}{games: map[int64]struct{}{}}
+
+func stateWriteBehind() bool {
+	return currentConfig().StateDurability == "batched"
+}
+
+// markStateDirty records that gameID needs a state flush, flushing everything early once the
+// pending threshold is reached.
+func markStateDirty(db *sql.DB, gameID int64) {
+	dirtyStates.mu.Lock()
+	dirtyStates.games[gameID] = struct{}{}
+	n := int64(len(dirtyStates.games))
+	dirtyStates.mu.Unlock()
+	if n >= currentConfig().StateFlushMaxPending {
+		go FlushPendingState(db)
+	}
+}
+
+// flushGameState persists gameID's in-memory state if it is dirty. The game lock is only held
+// to snapshot the state; the DB write happens after it is released. Callers that CAS against
+// state_version outside the engine commit path flush first, so write-behind state lands before
+// the compare.
+func flushGameState(db *sql.DB, gameID int64) error {
+	dirtyStates.mu.Lock()
+	_, dirty := dirtyStates.games[gameID]
+	delete(dirtyStates.games, gameID)
+	dirtyStates.mu.Unlock()
+	if !dirty {
+		return nil
+	}
+
+	st, unlock, ok := defaultGameManager.GetLocked(gameID)
+	if !ok {
+		return nil
+	}
+	version := st.Version
+	b, err := json.Marshal(st)
+	unlock()
+	if err != nil {
+		return err
+	}
+	if _, err := models.UpdateGameStateIfNewer(db, gameID, version, string(b)); err != nil {
+		// Keep it dirty so the next flush retries.
+		dirtyStates.mu.Lock()
+		dirtyStates.games[gameID] = struct{}{}
+		dirtyStates.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// FlushPendingState persists every dirty game. Call it on shutdown after the HTTP server has
+// stopped so write-behind state isn't lost.
+func FlushPendingState(db *sql.DB) {
+	dirtyStates.mu.Lock()
+	ids := make([]int64, 0, len(dirtyStates.games))
+	for id := range dirtyStates.games {
+		ids = append(ids, id)
+	}
+	dirtyStates.mu.Unlock()
+	for _, id := range ids {
+		if err := flushGameState(db, id); err != nil {
+			log.Printf("FlushPendingState: flush failed game_id=%d err=%v", id, err)
+		}
+	}
+}
+
+// RunStateFlusher periodically flushes write-behind state until ctx is cancelled. It is a
+// no-op in durable mode.
+func RunStateFlusher(ctx context.Context, db *sql.DB) {
+	if !stateWriteBehind() {
+		return
+	}
+	ticker := time.NewTicker(currentConfig().StateFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			FlushPendingState(db)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"fifteen-thirty-one-go/backend/internal/config"
+	"fifteen-thirty-one-go/backend/internal/game/cribbage"
+	"fifteen-thirty-one-go/backend/internal/models"
+)
+
+// persistedState decodes the game's state_json and returns it with its state_version.
+func persistedState(t *testing.T, db *sql.DB, gameID int64) (*cribbage.State, int64) {
+	t.Helper()
+	raw, version, ok, err := models.GetGameStateJSON(db, gameID)
+	if err != nil || !ok {
+		t.Fatalf("load state_json: ok=%t err=%v", ok, err)
+	}
+	var st cribbage.State
+	if err := json.Unmarshal([]byte(raw), &st); err != nil {
+		t.Fatalf("decode state_json: %v", err)
+	}
+	return &st, version
+}
+
+func memoryVersion(t *testing.T, gameID int64) int64 {
+	t.Helper()
+	st, unlock, ok := defaultGameManager.GetLocked(gameID)
+	if !ok {
+		t.Fatalf("game %d not in memory", gameID)
+	}
+	defer unlock()
+	return st.Version
+}
+
+func TestWriteBehindDefersStateUntilFlush(t *testing.T) {
+	db := newTestDB(t)
+	setTestConfig(t, func(c *config.Config) { c.StateDurability = "batched" })
+	gameID, users := newTestGame(t, db, "alice", "bob")
+	_, before := persistedState(t, db, gameID)
+
+	hand := seatHand(t, db, gameID, users[1])
+	if _, err := ApplyMove(db, gameID, users[1], moveRequest{Type: "discard", Cards: hand[:2]}); err != nil {
+		t.Fatalf("discard: %v", err)
+	}
+
+	// The move row is written at once; the engine state waits for a flush.
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM game_moves WHERE game_id = ? AND move_type = 'discard'`, gameID); n != 1 {
+		t.Fatalf("%d discard moves recorded, want 1", n)
+	}
+	if _, v := persistedState(t, db, gameID); v != before {
+		t.Fatalf("state_version %d before the flush, want it unchanged at %d", v, before)
+	}
+	if got := memoryVersion(t, gameID); got != before+1 {
+		t.Fatalf("memory version %d, want %d", got, before+1)
+	}
+
+	FlushPendingState(db)
+	st, v := persistedState(t, db, gameID)
+	if v != before+1 || !st.DiscardCompleted[1] {
+		t.Fatalf("after flush: state_version %d discard_completed %v, want %d with seat 1 done", v, st.DiscardCompleted, before+1)
+	}
+
+	// A restart restores the flushed state, not the deal.
+	defaultGameManager = NewGameManager()
+	players, err := models.ListGamePlayersByGame(db, gameID)
+	if err != nil {
+		t.Fatalf("list players: %v", err)
+	}
+	restored, unlock, err := ensureGameStateLocked(db, gameID, players)
+	if err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	done, version := restored.DiscardCompleted[1], restored.Version
+	unlock()
+	if !done || version != before+1 {
+		t.Errorf("restored discard_completed[1]=%t version %d, want true and %d", done, version, before+1)
+	}
+}
+
+func TestWriteBehindFlushNeverRollsBack(t *testing.T) {
+	db := newTestDB(t)
+	gameID, _ := newTestGame(t, db, "alice", "bob")
+	raw, version, _, err := models.GetGameStateJSON(db, gameID)
+	if err != nil {
+		t.Fatalf("load state: %v", err)
+	}
+
+	for _, stale := range []int64{version, version - 1} {
+		ok, err := models.UpdateGameStateIfNewer(db, gameID, stale, `{"stale":true}`)
+		if err != nil || ok {
+			t.Errorf("snapshot at version %d over %d: updated=%t err=%v, want a no-op", stale, version, ok, err)
+		}
+	}
+	if got, v, _, _ := models.GetGameStateJSON(db, gameID); got != raw || v != version {
+		t.Errorf("stale snapshots changed the row: version %d", v)
+	}
+	if ok, err := models.UpdateGameStateIfNewer(db, gameID, version+1, raw); err != nil || !ok {
+		t.Errorf("newer snapshot: updated=%t err=%v, want written", ok, err)
+	}
+}
+
+func TestDurableModePersistsEveryMove(t *testing.T) {
+	db := newTestDB(t)
+	gameID, users := newTestGame(t, db, "alice", "bob")
+	_, before := persistedState(t, db, gameID)
+
+	hand := seatHand(t, db, gameID, users[1])
+	if _, err := ApplyMove(db, gameID, users[1], moveRequest{Type: "discard", Cards: hand[:2]}); err != nil {
+		t.Fatalf("discard: %v", err)
+	}
+	st, v := persistedState(t, db, gameID)
+	if v != before+1 || v != memoryVersion(t, gameID) || !st.DiscardCompleted[1] {
+		t.Errorf("state_version %d (memory %d) discard_completed %v, want %d with seat 1 done", v, memoryVersion(t, gameID), st.DiscardCompleted, before+1)
+	}
+	dirtyStates.mu.Lock()
+	dirty := len(dirtyStates.games)
+	dirtyStates.mu.Unlock()
+	if dirty != 0 {
+		t.Errorf("%d games marked dirty in durable mode, want 0", dirty)
+	}
+}
@@ -33,6 +33,8 @@ func cloneStateForView(st *cribbage.State) cribbage.State {
 	if st.PeggingPassed != nil {
 		view.PeggingPassed = append([]bool(nil), st.PeggingPassed...)
 	}
+	// Per-player discard flags are non-secret: they tell the UI who it is still waiting on,
+	// while the discarded cards themselves stay in the (hidden) crib until counting.
 	if st.DiscardCompleted != nil {
 		view.DiscardCompleted = append([]bool(nil), st.DiscardCompleted...)
 	}
@@ -42,11 +44,30 @@ func cloneStateForView(st *cribbage.State) cribbage.State {
 	if st.PeggingSeq != nil {
 		view.PeggingSeq = append([]common.Card(nil), st.PeggingSeq...)
 	}
+	// The turn clock and timeout runs are public so every client can show them.
+	if st.TurnDeadline != nil {
+		d := *st.TurnDeadline
+		view.TurnDeadline = &d
+	}
+	if st.TurnTimeouts != nil {
+		view.TurnTimeouts = append([]int(nil), st.TurnTimeouts...)
+	}
 
 	// History is safe to expose at all times (it contains only past, non-secret info).
 	if st.History != nil {
 		view.History = append([]cribbage.RoundSummary(nil), st.History...)
 	}
+	// Events are public by construction. Snapshots carry only the current hand's; the retained
+	// log is served by GET /games/:id/events. Scoring in the open pegging sequence is withheld
+	// here, since a room-wide snapshot also reaches sequence_end viewers; per-user snapshots
+	// restore it for everyone else.
+	if len(st.Events) > 0 {
+		openSeq := -1
+		if seq, ok := st.OpenSequenceSeq(); ok {
+			openSeq = seq
+		}
+		view.Events, _ = withholdOpenSequence(st.HandEvents(), openSeq)
+	}
 
 	// Deep copy hands slice headers (but leave cards empty; filled selectively by caller).
 	view.Hands = make([][]common.Card, len(st.Hands))
@@ -68,6 +89,13 @@ func cloneStateForView(st *cribbage.State) cribbage.State {
 			view.Crib = append([]common.Card(nil), st.Crib...)
 		}
 		view.CountSummary = st.CountSummary
+		// Casual games may also show who threw what to the crib.
+		if st.Rules.RevealHands && st.Discards != nil {
+			view.Discards = make([][]common.Card, len(st.Discards))
+			for i := range st.Discards {
+				view.Discards[i] = append([]common.Card(nil), st.Discards[i]...)
+			}
+		}
 	} else {
 		view.KeptHands = nil
 		view.Crib = nil
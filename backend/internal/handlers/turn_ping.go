@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"fifteen-thirty-one-go/backend/internal/models"
+)
+
+// lastTurnPing remembers the last turn signature pinged per game so repeated snapshots
+// (e.g. other players' discards) don't re-ping someone who is already on the clock. A game's
+// entry is dropped once nobody is waiting to act, which includes when it finishes.
+var lastTurnPing = struct {
+	mu  sync.Mutex
+	sig map[int64]string
+}{sig: map[int64]string{}}
+
+// suppressedTurnPings counts pings skipped for quiet hours since startup; each skip is also
+// logged with the running total so a "I never got pinged" report can be checked.
+var suppressedTurnPings atomic.Int64
+
+// pushTurnNotifications sends "game:your_turn" to each human who now has to act in snap:
+// everyone still discarding during discard, or the current player during pegging. It reads the
+// caller's snapshot rather than the engine, so it takes no game lock of its own.
+//
+// Pings are skipped for users inside their quiet hours; the turn itself is unaffected and
+// visible in the snapshot. Skipped pings are only logged and counted, not stored: nobody is
+// pinged later for a turn that started during their quiet hours.
+func pushTurnNotifications(db *sql.DB, snap *GameSnapshot) {
+	if snap == nil || snap.Game == nil || hubProvider == nil {
+		return
+	}
+	hub, ok := hubProvider()
+	if !ok || hub == nil {
+		return
+	}
+
+	gameID := snap.Game.ID
+	st := &snap.State
+	stage := st.Stage
+	hand := len(st.History)
+	var positions []int
+	var sig string
+	if snap.Game.Status != "finished" {
+		switch stage {
+		case "discard":
+			for i, done := range st.DiscardCompleted {
+				if !done {
+					positions = append(positions, i)
+				}
+			}
+			// Discards only shrink the waiting set; a new hand is what should re-ping.
+			sig = fmt.Sprintf("discard:%d", hand)
+		case "pegging":
+			positions = []int{st.CurrentIndex}
+			sig = fmt.Sprintf("pegging:%d:%d:%d", hand, st.CurrentIndex, len(st.PeggingSeq))
+		}
+	}
+
+	lastTurnPing.mu.Lock()
+	if len(positions) == 0 || lastTurnPing.sig[gameID] == sig {
+		if len(positions) == 0 {
+			delete(lastTurnPing.sig, gameID)
+		}
+		lastTurnPing.mu.Unlock()
+		return
+	}
+	lastTurnPing.sig[gameID] = sig
+	lastTurnPing.mu.Unlock()
+
+	var userIDs []int64
+	for _, pos := range positions {
+		for _, p := range snap.Players {
+			if int(p.Position) == pos && !p.IsBot {
+				userIDs = append(userIDs, p.UserID)
+			}
+		}
+	}
+	if len(userIDs) == 0 {
+		return
+	}
+	prefs, err := models.ListUserPreferences(db, userIDs)
+	if err != nil {
+		log.Printf("pushTurnNotifications: load preferences failed game_id=%d err=%v", gameID, err)
+		return
+	}
+	now := time.Now()
+	for _, userID := range userIDs {
+		if prefs[userID].InQuietHours(now) {
+			n := suppressedTurnPings.Add(1)
+			log.Printf("pushTurnNotifications: ping suppressed by quiet hours game_id=%d user_id=%d stage=%s suppressed_total=%d", gameID, userID, stage, n)
+			continue
+		}
+		hub.SendToUser(userID, "game:your_turn", map[string]any{
+			"game_id": gameID,
+			"stage":   stage,
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"sort"
+	"strconv"
+	"time"
+
+	"fifteen-thirty-one-go/backend/internal/game/common"
+	"fifteen-thirty-one-go/backend/internal/models"
+)
+
+// turnTimerInterval is how often loaded games are checked for expired turns.
+const turnTimerInterval = time.Second
+
+// RunTurnTimer plays timed-out turns in games created with a turn timeout until ctx is
+// cancelled. Only games loaded in memory are checked; a game restored after a restart gets a
+// fresh clock when it is next loaded.
+func RunTurnTimer(ctx context.Context, db *sql.DB) {
+	defaultGameManager.RunTurnTimer(ctx, turnTimerInterval, func(gameID int64) {
+		expireTurn(ctx, db, gameID)
+	})
+}
+
+// timedOutTurn is a move the turn timer makes for a human who ran out of time.
+type timedOutTurn struct {
+	userID int64
+	pos    int
+	req    moveRequest
+}
+
+// expireTurn plays for every human the game is waiting on once its turn clock runs out: the
+// lowest legal card (or a go) while pegging, the lowest cards during discard. Bots are exempt;
+// maybeRunBotTurns already moves them. A player whose turn ran out TurnTimeoutForfeitAfter
+// times in a row forfeits the game.
+func expireTurn(ctx context.Context, db *sql.DB, gameID int64) {
+	g, err := models.GetGameByID(db, gameID)
+	if err != nil && !errors.Is(err, models.ErrNotFound) {
+		log.Printf("expireTurn: load game failed: game_id=%d err=%v", gameID, err)
+		return
+	}
+	if err != nil || g.Status == "finished" {
+		// Gone, or ended by a quit or forfeit before the engine finished: nobody is on the clock.
+		if st, unlock, ok := defaultGameManager.GetLocked(gameID); ok {
+			st.TurnDeadline = nil
+			unlock()
+		}
+		return
+	}
+	players, err := models.ListGamePlayersByGameContext(ctx, db, gameID)
+	if err != nil {
+		log.Printf("expireTurn: list players failed: game_id=%d err=%v", gameID, err)
+		return
+	}
+	byPos := make(map[int]models.GamePlayer, len(players))
+	for _, p := range players {
+		byPos[int(p.Position)] = p
+	}
+
+	st, unlock, ok := defaultGameManager.GetLocked(gameID)
+	if !ok {
+		return
+	}
+	if !st.TurnExpired(time.Now()) {
+		unlock()
+		return
+	}
+	if len(players) < st.Rules.MaxPlayers {
+		// The clock only runs once every seat is filled.
+		st.RestartTurnClock()
+		unlock()
+		return
+	}
+	var turns []timedOutTurn
+	owed := func(pos int) (models.GamePlayer, bool) {
+		p, ok := byPos[pos]
+		return p, ok && !p.IsBot && pos < len(st.Hands)
+	}
+	switch st.Stage {
+	case "discard":
+		for pos, done := range st.DiscardCompleted {
+			if p, human := owed(pos); human && !done {
+				cards := lowestCards(st.Hands[pos], st.Rules.DiscardCount())
+				turns = append(turns, timedOutTurn{userID: p.UserID, pos: pos, req: moveRequest{Type: "discard", Cards: cards, timedOut: true}})
+			}
+		}
+	case "pegging":
+		if p, human := owed(st.CurrentIndex); human {
+			req := moveRequest{Type: "go", timedOut: true}
+			if card, ok := lowestPlayable(st.Hands[st.CurrentIndex], st.PeggingTotal); ok {
+				req = moveRequest{Type: "play_card", Card: card, timedOut: true}
+			}
+			turns = append(turns, timedOutTurn{userID: p.UserID, pos: st.CurrentIndex, req: req})
+		}
+	}
+	if len(turns) == 0 {
+		// Nobody human is on the clock (a bot owes the move); let the bot loop have it.
+		st.RestartTurnClock()
+	}
+	limit := currentConfig().TurnTimeoutForfeitAfter
+	unlock()
+
+	var forfeit *timedOutTurn
+	for i, t := range turns {
+		if _, err := applyMove(db, gameID, t.userID, t.req, true); err != nil {
+			log.Printf("expireTurn: timed-out %s failed: game_id=%d user_id=%d err=%v", t.req.Type, gameID, t.userID, err)
+			// Don't retry every tick; the player gets a fresh clock instead.
+			if st, unlock, ok := defaultGameManager.GetLocked(gameID); ok {
+				st.RestartTurnClock()
+				unlock()
+			}
+			continue
+		}
+		log.Printf("expireTurn: played timed-out %s: game_id=%d user_id=%d", t.req.Type, gameID, t.userID)
+		if st, unlock, ok := defaultGameManager.GetLocked(gameID); ok {
+			// A timed-out play that pegged out won the game; there is nothing left to forfeit.
+			if st.Stage != "finished" && t.pos < len(st.TurnTimeouts) && st.TurnTimeouts[t.pos] >= limit && forfeit == nil {
+				forfeit = &turns[i]
+			}
+			unlock()
+		}
+	}
+	if forfeit != nil {
+		err := forfeitTimedOutPlayer(ctx, db, gameID, forfeit.userID)
+		if err == nil {
+			return
+		}
+		if !errors.Is(err, models.ErrGameFinished) {
+			log.Printf("expireTurn: forfeit failed: game_id=%d user_id=%d err=%v", gameID, forfeit.userID, err)
+		}
+	}
+	if len(turns) > 0 {
+		if err := maybeRunBotTurns(db, gameID); err != nil {
+			log.Printf("expireTurn: maybeRunBotTurns failed: game_id=%d err=%v", gameID, err)
+		}
+	}
+	if err := maybeFinalizeGame(ctx, db, gameID); err != nil {
+		log.Printf("expireTurn: maybeFinalizeGame failed: game_id=%d err=%v", gameID, err)
+	}
+	broadcastGameUpdate(db, gameID)
+}
+
+// lowestCards returns the n lowest cards of hand (by rank, then suit) as card codes.
+func lowestCards(hand []common.Card, n int) []string {
+	sorted := append([]common.Card(nil), hand...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Rank != sorted[j].Rank {
+			return sorted[i].Rank < sorted[j].Rank
+		}
+		return sorted[i].Suit < sorted[j].Suit
+	})
+	if n > len(sorted) {
+		n = len(sorted)
+	}
+	return cardCodes(sorted[:n])
+}
+
+// lowestPlayable returns the lowest card of hand that keeps the pegging count at or under 31.
+func lowestPlayable(hand []common.Card, total int) (string, bool) {
+	for _, code := range lowestCards(hand, len(hand)) {
+		c, err := common.ParseCard(code)
+		if err == nil && total+c.Value15() <= 31 {
+			return code, true
+		}
+	}
+	return "", false
+}
+
+// forfeitTimedOutPlayer ends the game with userID forfeiting it (and any match it is part of):
+// they rank last and everyone else by score, as when the stale-game janitor records a forfeit.
+// It holds the finalize lock and commits through resignSeat, so it can't interleave with
+// maybeFinalizeGame or a move.
+func forfeitTimedOutPlayer(ctx context.Context, db *sql.DB, gameID, userID int64) error {
+	defer lockFinalize(gameID)()
+
+	g, err := models.GetGameByID(db, gameID)
+	if err != nil {
+		return err
+	}
+	if g.Status == "finished" {
+		return models.ErrGameFinished
+	}
+	players, err := models.ListGamePlayersByGameContext(ctx, db, gameID)
+	if err != nil {
+		return err
+	}
+	if err := resignSeat(ctx, db, g, userID, players, nil); err != nil {
+		return err
+	}
+	activeLobbies.invalidate()
+	log.Printf("forfeitTimedOutPlayer: player forfeited after repeated turn timeouts: game_id=%d user_id=%d", gameID, userID)
+
+	// Drop in-memory runtime state so a future game doesn't accidentally reuse it.
+	if err := flushGameState(db, gameID); err != nil {
+		log.Printf("forfeitTimedOutPlayer: flushGameState failed: game_id=%d err=%v", gameID, err)
+	}
+	defaultGameManager.Delete(gameID)
+
+	if hub, ok := getHubProvider(); ok && hub != nil {
+		hub.Broadcast("game:"+strconv.FormatInt(gameID, 10), "game:turn_forfeit", map[string]any{"game_id": gameID, "user_id": userID})
+	}
+	broadcastGameUpdate(db, gameID)
+	return nil
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"fifteen-thirty-one-go/backend/internal/config"
+	"fifteen-thirty-one-go/backend/internal/game/common"
+	"fifteen-thirty-one-go/backend/internal/models"
+)
+
+// expireClock puts gameID on a turn timer whose deadline has already passed.
+func expireClock(t *testing.T, gameID int64) {
+	t.Helper()
+	st, unlock, ok := defaultGameManager.GetLocked(gameID)
+	if !ok {
+		t.Fatalf("game %d has no engine state", gameID)
+	}
+	st.Rules.TurnTimeoutSeconds = 30
+	past := time.Now().Add(-time.Second)
+	st.TurnDeadline = &past
+	unlock()
+}
+
+func turnTimeouts(t *testing.T, gameID int64) []int {
+	t.Helper()
+	st, unlock, ok := defaultGameManager.GetLocked(gameID)
+	if !ok {
+		t.Fatalf("game %d has no engine state", gameID)
+	}
+	defer unlock()
+	out := make([]int, st.Rules.MaxPlayers)
+	copy(out, st.TurnTimeouts)
+	return out
+}
+
+func TestExpiredTurnDiscardsForEveryHuman(t *testing.T) {
+	db := newTestDB(t)
+	gameID, _ := newTestGame(t, db, "alice", "bob")
+	expireClock(t, gameID)
+
+	expireTurn(context.Background(), db, gameID)
+
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM game_moves WHERE game_id = ? AND move_type = 'discard'`, gameID); n != 2 {
+		t.Errorf("%d discards after the clock ran out, want one per player", n)
+	}
+	if got := turnTimeouts(t, gameID); got[0] != 1 || got[1] != 1 {
+		t.Errorf("turn timeouts %v, want [1 1]", got)
+	}
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM games WHERE id = ? AND status = 'finished'`, gameID); n != 0 {
+		t.Error("game finished after a first timeout")
+	}
+}
+
+func TestExpiredTurnSkipsBots(t *testing.T) {
+	db := newTestDB(t)
+	gameID, users := newTestGame(t, db, "alice", "bob")
+	if _, err := db.Exec(`UPDATE game_players SET is_bot = 1, bot_difficulty = 'easy' WHERE game_id = ? AND user_id = ?`, gameID, users[1]); err != nil {
+		t.Fatalf("make bob a bot: %v", err)
+	}
+	expireClock(t, gameID)
+
+	expireTurn(context.Background(), db, gameID)
+
+	if got := turnTimeouts(t, gameID); got[0] != 1 || got[1] != 0 {
+		t.Errorf("turn timeouts %v, want only alice's counted", got)
+	}
+	// The bot still discards, on its own turn.
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM game_moves WHERE game_id = ? AND player_id = ? AND move_type = 'discard'`, gameID, users[1]); n != 1 {
+		t.Errorf("bot made %d discards, want 1", n)
+	}
+}
+
+func TestRepeatedTimeoutsForfeitTheGame(t *testing.T) {
+	db := newTestDB(t)
+	setTestConfig(t, func(cfg *config.Config) { cfg.TurnTimeoutForfeitAfter = 1 })
+	gameID, users := newTestGame(t, db, "alice", "bob")
+	alice := users[0]
+	expireClock(t, gameID)
+
+	expireTurn(context.Background(), db, gameID)
+
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM games WHERE id = ? AND status = 'finished'`, gameID); n != 1 {
+		t.Fatal("game not finished after the forfeit")
+	}
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM scoreboard WHERE game_id = ? AND outcome = ?`, gameID, models.OutcomeForfeit); n != 2 {
+		t.Errorf("%d forfeit scoreboard rows, want 2", n)
+	}
+	if pos := queryInt(t, db, `SELECT position FROM scoreboard WHERE game_id = ? AND user_id = ?`, gameID, alice); pos != 2 {
+		t.Errorf("alice, who timed out first, ranked %d, want last", pos)
+	}
+}
+
+func TestTimedOutPlayThatPegsOutWinsInsteadOfForfeiting(t *testing.T) {
+	db := newTestDB(t)
+	setTestConfig(t, func(cfg *config.Config) { cfg.TurnTimeoutForfeitAfter = 1 })
+	gameID, users := newTestGame(t, db, "alice", "bob")
+	alice := users[0]
+
+	parse := func(s string) common.Card {
+		c, err := common.ParseCard(s)
+		if err != nil {
+			t.Fatalf("parse %s: %v", s, err)
+		}
+		return c
+	}
+	st, unlock, _ := defaultGameManager.GetLocked(gameID)
+	cut := parse("2C")
+	st.Stage = "pegging"
+	st.Cut = &cut
+	st.DiscardCompleted = []bool{true, true}
+	st.Hands = [][]common.Card{{parse("5H")}, {parse("KS")}}
+	st.PeggingSeq = []common.Card{parse("10C")}
+	st.PeggingTotal = 10
+	st.CurrentIndex = 0
+	// The fifteen takes alice from 119 past 121.
+	st.Scores = []int{119, 0}
+	unlock()
+	if _, err := db.Exec(`UPDATE game_players SET hand = '[{"rank":5,"suit":"H"}]' WHERE game_id = ? AND user_id = ?`, gameID, alice); err != nil {
+		t.Fatalf("set hand: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE game_players SET hand = '[]' WHERE game_id = ? AND user_id = ?`, gameID, users[1]); err != nil {
+		t.Fatalf("set hand: %v", err)
+	}
+	expireClock(t, gameID)
+
+	expireTurn(context.Background(), db, gameID)
+
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM scoreboard WHERE game_id = ? AND outcome = ?`, gameID, models.OutcomeForfeit); n != 0 {
+		t.Errorf("%d forfeit rows for a game alice won", n)
+	}
+	if pos := queryInt(t, db, `SELECT position FROM scoreboard WHERE game_id = ? AND user_id = ?`, gameID, alice); pos != 1 {
+		t.Errorf("alice ranked %d after pegging out, want 1", pos)
+	}
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"fifteen-thirty-one-go/backend/internal/models"
+	"fifteen-thirty-one-go/backend/internal/tracing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UndoHandler lets a player take back their last pegging card while nobody has acted since: the
+// card returns to their hand, the count, scores and turn are restored (see
+// cribbage.State.UndoPeggingPlay) and the play_card move is deleted. Plays that ended a sequence
+// (31, the end of pegging or a win) can't be undone. The undo commits against the state version
+// it was computed from, so it loses cleanly against a move by the next player.
+func UndoHandler(db *sql.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, span := tracing.StartSpan(c.Request.Context(), "handlers.UndoHandler")
+		defer span.End()
+
+		gameID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || gameID <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid game id"})
+			return
+		}
+		userID, ok := userIDFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		players, err := models.ListGamePlayersByGame(db, gameID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		pos := -1
+		for _, p := range players {
+			if p.UserID == userID && !p.Resigned {
+				pos = int(p.Position)
+			}
+		}
+		if pos < 0 {
+			c.JSON(http.StatusForbidden, gin.H{"error": "not a player"})
+			return
+		}
+
+		st, unlock, err := ensureGameStateLocked(db, gameID, players)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "game not ready"})
+			return
+		}
+		baseVersion := st.Version
+		eventsBefore := st.LastEventSeq()
+		working := cloneStateDeep(st)
+		working.Version = baseVersion
+		unlock()
+		if working.PeggingUndo == nil {
+			writeAPIError(c, models.ErrUndoUnavailable)
+			return
+		}
+		card := working.PeggingUndo.Card
+		if err := working.UndoPeggingPlay(pos); err != nil {
+			writeAPIError(c, err)
+			return
+		}
+		hand, err := json.Marshal(working.Hands[pos])
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+
+		tx, err := db.Begin()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		committed := false
+		defer func() {
+			if !committed {
+				_ = tx.Rollback()
+			}
+		}()
+		if err := models.DeleteLatestPlayMoveTx(tx, gameID, userID, card.String()); err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				writeAPIError(c, models.ErrUndoUnavailable)
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		if err := models.UpdatePlayerHandTx(tx, gameID, userID, string(hand)); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		applied, err := commitStateTx(db, tx, gameID, baseVersion, &working)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		if !applied {
+			// The next player moved first; the play stands.
+			writeAPIError(c, models.ErrUndoUnavailable)
+			return
+		}
+		committed = true
+
+		broadcastGameEvents(db, gameID, newGameEventBatch(&working, eventsBefore))
+		broadcastGameUpdate(db, gameID)
+		c.JSON(http.StatusOK, gin.H{"card": card.String(), "total": working.PeggingTotal, "scores": working.Scores})
+	}
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+
+	"fifteen-thirty-one-go/backend/internal/config"
+	"fifteen-thirty-one-go/backend/internal/game/common"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setPegging puts a two-player test game mid-pegging: seq has been played so far, hands are
+// what each seat still holds, and alice (seat 0) is on turn. The persisted hands match.
+func setPegging(t *testing.T, db *sql.DB, gameID int64, users []int64, seq []string, hands ...[]string) {
+	t.Helper()
+	parse := func(codes []string) []common.Card {
+		out := make([]common.Card, len(codes))
+		for i, s := range codes {
+			c, err := common.ParseCard(s)
+			if err != nil {
+				t.Fatalf("parse %s: %v", s, err)
+			}
+			out[i] = c
+		}
+		return out
+	}
+	st, unlock, _ := defaultGameManager.GetLocked(gameID)
+	cut := parse([]string{"2C"})[0]
+	st.Stage = "pegging"
+	st.Cut = &cut
+	st.DealerIndex = 1
+	st.CurrentIndex = 0
+	st.DiscardCompleted = make([]bool, len(hands))
+	st.PeggingPassed = make([]bool, len(hands))
+	st.Scores = make([]int, len(hands))
+	st.PeggingSeq = parse(seq)
+	st.PeggingTotal = 0
+	for _, c := range st.PeggingSeq {
+		st.PeggingTotal += c.Value15()
+	}
+	st.LastPlayIndex = -1
+	if len(seq) > 0 {
+		st.LastPlayIndex = 1
+	}
+	st.PeggingUndo = nil
+	for i, h := range hands {
+		st.Hands[i] = parse(h)
+		st.KeptHands[i] = parse(h)
+	}
+	unlock()
+	for i, h := range hands {
+		raw, err := json.Marshal(parse(h))
+		if err != nil {
+			t.Fatalf("encode hand: %v", err)
+		}
+		if _, err := db.Exec(`UPDATE game_players SET hand = ? WHERE game_id = ? AND user_id = ?`, string(raw), gameID, users[i]); err != nil {
+			t.Fatalf("set hand: %v", err)
+		}
+	}
+}
+
+func playCard(t *testing.T, db *sql.DB, gameID, userID int64, card string) {
+	t.Helper()
+	path := fmt.Sprintf("/games/%d/move", gameID)
+	if code := doRequest(t, MoveHandler(db), http.MethodPost, "/games/:id/move", path, userID, gin.H{"type": "play_card", "card": card}, nil); code != http.StatusOK {
+		t.Fatalf("user %d plays %s: status %d, want 200", userID, card, code)
+	}
+}
+
+func undo(t *testing.T, db *sql.DB, gameID, userID int64) (int, string) {
+	t.Helper()
+	var out struct {
+		Card string `json:"card"`
+		Code string `json:"code"`
+	}
+	path := fmt.Sprintf("/games/%d/undo", gameID)
+	code := doRequest(t, UndoHandler(db), http.MethodPost, "/games/:id/undo", path, userID, nil, &out)
+	if code == http.StatusOK {
+		return code, out.Card
+	}
+	return code, out.Code
+}
+
+func TestUndoReturnsTheLastPlay(t *testing.T) {
+	db := newTestDB(t)
+	gameID, users := newTestGame(t, db, "alice", "bob")
+	alice := users[0]
+	setPegging(t, db, gameID, users, nil, []string{"5H", "9C"}, []string{"KS", "QD"})
+
+	playCard(t, db, gameID, alice, "5H")
+	if code, card := undo(t, db, gameID, alice); code != http.StatusOK || card != "5H" {
+		t.Fatalf("undo: status %d card %q, want 200 and 5H", code, card)
+	}
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM game_moves WHERE game_id = ? AND move_type = 'play_card'`, gameID); n != 0 {
+		t.Errorf("%d plays recorded after the undo, want none", n)
+	}
+	if hand := seatHand(t, db, gameID, alice); !slices.Contains(hand, "5H") {
+		t.Errorf("persisted hand %v after the undo, want 5H back", hand)
+	}
+	st, unlock, _ := defaultGameManager.GetLocked(gameID)
+	total, current, held := st.PeggingTotal, st.CurrentIndex, len(st.Hands[0])
+	unlock()
+	if total != 0 || current != 0 || held != 2 {
+		t.Errorf("after the undo total %d, seat %d on turn, alice holds %d; want 0, 0 and 2", total, current, held)
+	}
+}
+
+func TestUndoAfterTheOpponentPlayedIsRefused(t *testing.T) {
+	db := newTestDB(t)
+	gameID, users := newTestGame(t, db, "alice", "bob")
+	alice, bob := users[0], users[1]
+	setPegging(t, db, gameID, users, nil, []string{"5H", "9C"}, []string{"KS", "QD"})
+
+	playCard(t, db, gameID, alice, "5H")
+	playCard(t, db, gameID, bob, "KS")
+	// The latest play is now bob's, so alice's is out of reach.
+	if code, errCode := undo(t, db, gameID, alice); code != http.StatusForbidden || errCode != "undo_not_your_play" {
+		t.Errorf("undo after bob played: status %d code %q, want 403 undo_not_your_play", code, errCode)
+	}
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM game_moves WHERE game_id = ? AND move_type = 'play_card'`, gameID); n != 2 {
+		t.Errorf("%d plays recorded, want both to stand", n)
+	}
+}
+
+func TestUndoOfA31IsUnavailable(t *testing.T) {
+	db := newTestDB(t)
+	gameID, users := newTestGame(t, db, "alice", "bob")
+	alice := users[0]
+	setPegging(t, db, gameID, users, []string{"10C", "10D", "QH"}, []string{"AS", "2D"}, []string{"KS", "QD"})
+
+	playCard(t, db, gameID, alice, "AS")
+	if code, errCode := undo(t, db, gameID, alice); code != http.StatusConflict || errCode != "undo_unavailable" {
+		t.Errorf("undo of a 31: status %d code %q, want 409 undo_unavailable", code, errCode)
+	}
+}
+
+func TestUndoOfSomeoneElsesPlayIsForbidden(t *testing.T) {
+	db := newTestDB(t)
+	gameID, users := newTestGame(t, db, "alice", "bob")
+	alice, bob := users[0], users[1]
+	setPegging(t, db, gameID, users, nil, []string{"5H", "9C"}, []string{"KS", "QD"})
+
+	playCard(t, db, gameID, alice, "5H")
+	if code, errCode := undo(t, db, gameID, bob); code != http.StatusForbidden || errCode != "undo_not_your_play" {
+		t.Errorf("bob undoes alice's play: status %d code %q, want 403 undo_not_your_play", code, errCode)
+	}
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM game_moves WHERE game_id = ? AND move_type = 'play_card'`, gameID); n != 1 {
+		t.Errorf("%d plays recorded, want alice's to stand", n)
+	}
+}
+
+func TestUndoCommitLosesToAMoveThatLandedFirst(t *testing.T) {
+	for _, durability := range []string{"durable", "batched"} {
+		t.Run(durability, func(t *testing.T) {
+			db := newTestDB(t)
+			setTestConfig(t, func(c *config.Config) { c.StateDurability = durability })
+			gameID, users := newTestGame(t, db, "alice", "bob")
+			alice, bob := users[0], users[1]
+			setPegging(t, db, gameID, users, nil, []string{"5H", "9C"}, []string{"KS", "QD"})
+			playCard(t, db, gameID, alice, "5H")
+
+			// Alice's undo is computed from the state right after her play...
+			st, unlock, _ := defaultGameManager.GetLocked(gameID)
+			baseVersion := st.Version
+			working := cloneStateDeep(st)
+			working.Version = baseVersion
+			unlock()
+			if err := working.UndoPeggingPlay(0); err != nil {
+				t.Fatalf("undo: %v", err)
+			}
+			// ...but bob's play commits before it does.
+			playCard(t, db, gameID, bob, "KS")
+
+			tx, err := db.Begin()
+			if err != nil {
+				t.Fatalf("begin: %v", err)
+			}
+			defer tx.Rollback()
+			applied, err := commitStateTx(db, tx, gameID, baseVersion, &working)
+			if err != nil || applied {
+				t.Fatalf("stale undo commit: applied %t err %v, want rejected", applied, err)
+			}
+			st, unlock, _ = defaultGameManager.GetLocked(gameID)
+			seq := len(st.PeggingSeq)
+			unlock()
+			if seq != 2 {
+				t.Errorf("%d cards in the sequence after the stale undo, want both plays kept", seq)
+			}
+		})
+	}
+}
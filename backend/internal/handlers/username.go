@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"fifteen-thirty-one-go/backend/internal/auth"
+	"fifteen-thirty-one-go/backend/internal/models"
+	"fifteen-thirty-one-go/backend/internal/tracing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ChangeUsernameHandler handles PUT /api/users/username. The new name passes the registration
+// checks (3-32 characters, unique ignoring case), and changes are limited to one per
+// UsernameChangeInterval. Old names go to username_history; lobby chat is rewritten to the new
+// name, while access_log keeps the name each request was made under. The response carries a
+// fresh token (the old one names the old username), and the rename is broadcast as
+// user:renamed to the global lobby and to the rooms of the user's unfinished games.
+func ChangeUsernameHandler(db *sql.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, span := tracing.StartSpan(c.Request.Context(), "handlers.ChangeUsernameHandler")
+		defer span.End()
+
+		cfg := currentConfig()
+		if !cfg.UsernameChanges {
+			c.JSON(http.StatusForbidden, gin.H{"error": "username changes are disabled", "code": "username_changes_disabled"})
+			return
+		}
+		userID, ok := userIDFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		var req struct {
+			Username string `json:"username"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid json"})
+			return
+		}
+		username, ok := normalizeUsername(req.Username)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "username must be 3-32 characters"})
+			return
+		}
+
+		old, nextAt, err := models.ChangeUsername(db, userID, username, cfg.UsernameChangeInterval)
+		switch {
+		case errors.Is(err, models.ErrUsernameTaken):
+			c.JSON(http.StatusConflict, gin.H{"error": "username already taken"})
+			return
+		case errors.Is(err, models.ErrUsernameChangeTooSoon):
+			c.Header("Retry-After", fmt.Sprintf("%d", int(time.Until(nextAt).Seconds())+1))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "username was changed too recently", "code": "username_change_too_soon", "next_change_at": nextAt.UTC()})
+			return
+		case errors.Is(err, models.ErrNotFound):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found or unauthorized"})
+			return
+		case err != nil:
+			log.Printf("ChangeUsernameHandler: user_id=%d err=%v", userID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+
+		u, err := models.GetUserByID(db, userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		token, err := auth.GenerateToken(u.ID, u.Username, cfg)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "token error"})
+			return
+		}
+		setAuthCookie(c, cfg, token)
+
+		if old != u.Username {
+			broadcastRename(db, userID, old, u.Username)
+		}
+		c.JSON(http.StatusOK, authResponse{Token: token, User: u})
+	}
+}
+
+// broadcastRename tells the global lobby and every room of userID's unfinished games that
+// they are now username.
+func broadcastRename(db *sql.DB, userID int64, old, username string) {
+	hub, ok := getHubProvider()
+	if !ok || hub == nil {
+		return
+	}
+	payload := gin.H{"user_id": userID, "old_username": old, "username": username}
+	hub.Broadcast("lobby:global", "user:renamed", payload)
+	lobbyIDs, gameIDs, err := models.ActiveRoomsForUser(db, userID)
+	if err != nil {
+		log.Printf("broadcastRename: user_id=%d err=%v", userID, err)
+		return
+	}
+	for i := range lobbyIDs {
+		hub.Broadcast(fmt.Sprintf("lobby:%d", lobbyIDs[i]), "user:renamed", payload)
+		hub.Broadcast(fmt.Sprintf("game:%d", gameIDs[i]), "user:renamed", payload)
+	}
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+
+	"fifteen-thirty-one-go/backend/internal/models"
+	"fifteen-thirty-one-go/backend/internal/tracing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type usersBatchRequest struct {
+	IDs []int64 `json:"ids"`
+}
+
+// UsersBatchHandler resolves up to UsersBatchMax user ids to public profiles (id, username,
+// avatar_url) in one query, so scoreboards and move logs needn't look users up one by one.
+// Duplicate ids are collapsed and unknown ones are left out of the response.
+func UsersBatchHandler(db *sql.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, span := tracing.StartSpan(c.Request.Context(), "handlers.UsersBatchHandler")
+		defer span.End()
+
+		var req usersBatchRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid json"})
+			return
+		}
+		seen := make(map[int64]bool, len(req.IDs))
+		ids := make([]int64, 0, len(req.IDs))
+		for _, id := range req.IDs {
+			if id <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+				return
+			}
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		if limit := currentConfig().UsersBatchMax; len(ids) > limit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("at most %d ids per lookup", limit), "code": "users_batch_too_large"})
+			return
+		}
+		users, err := models.ListPublicUsersByIDs(ctx, db, ids)
+		if err != nil {
+			log.Printf("UsersBatchHandler: ListPublicUsersByIDs failed: ids=%d err=%v", len(ids), err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"users": users})
+	}
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -14,6 +15,7 @@ import (
 
 	"fifteen-thirty-one-go/backend/internal/auth"
 	"fifteen-thirty-one-go/backend/internal/config"
+	"fifteen-thirty-one-go/backend/internal/models"
 	"fifteen-thirty-one-go/backend/internal/tracing"
 	ws "fifteen-thirty-one-go/backend/pkg/websocket"
 
@@ -88,6 +90,13 @@ func isLocalhostOrigin(origin string) bool {
 // WebSocketHandler upgrades the connection and registers the client.
 // Full message routing is implemented in Phase 4.
 func WebSocketHandler(hubProvider func() (*ws.Hub, bool), db *sql.DB, cfg config.Config) gin.HandlerFunc {
+	up := upgrader
+	if cfg.WSReadBufferSize > 0 {
+		up.ReadBufferSize = cfg.WSReadBufferSize
+	}
+	if cfg.WSWriteBufferSize > 0 {
+		up.WriteBufferSize = cfg.WSWriteBufferSize
+	}
 	return func(c *gin.Context) {
 		_, span := tracing.StartSpan(c.Request.Context(), "handlers.WebSocketHandler")
 		defer span.End()
@@ -116,7 +125,8 @@ func WebSocketHandler(hubProvider func() (*ws.Hub, bool), db *sql.DB, cfg config
 			return
 		}
 
-		conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+		conn, err := up.Upgrade(c.Writer, c.Request, nil)
+		recordAccess(db, c, "ws_upgrade", err == nil, &claims.UserID, claims.Username)
 		if err != nil {
 			log.Printf("WebSocketHandler upgrade failed: method=%s path=%s remote=%s origin=%q err=%v",
 				c.Request.Method, c.Request.URL.Path, c.ClientIP(), c.Request.Header.Get("Origin"), err,
@@ -149,10 +159,31 @@ func WebSocketHandler(hubProvider func() (*ws.Hub, bool), db *sql.DB, cfg config
 			}
 			return
 		}
-		client, err := ws.NewClient(conn, hub, room, claims.UserID)
+		// Resume a recent session (brief network blip) by restoring its last room; otherwise start a new one.
+		sessionID := strings.TrimSpace(c.Query("session_id"))
+		resumed := false
+		if prevRoom, ok := hub.ResumeSession(sessionID, claims.UserID); ok {
+			room = prevRoom
+			resumed = true
+		} else {
+			sessionID, err = hub.NewSession(claims.UserID, room, cfg.WSSessionTTL)
+			if err != nil {
+				// Non-fatal: the connection works, it just can't be resumed later.
+				log.Printf("WebSocketHandler NewSession failed: user_id=%d err=%v", claims.UserID, err)
+				sessionID = ""
+			}
+		}
+
+		client, err := ws.NewClient(conn, hub, room, claims.UserID, ws.ClientOptions{
+			MaxMessageSize: cfg.WSReadLimit,
+			SendQueue:      cfg.WSSendQueue,
+			PongWait:       cfg.WSPongWait,
+			PingPeriod:     cfg.WSPingPeriod,
+		})
 		if err != nil {
 			wrappedErr := fmt.Errorf("ws.NewClient failed (user_id=%d room=%q): %w", claims.UserID, room, err)
 			log.Printf("WebSocketHandler: %v", wrappedErr)
+			hub.ReleaseSession(sessionID)
 			// Best-effort: send a close control message so the peer sees a clean disconnect.
 			if closeErr := conn.WriteControl(
 				websocket.CloseMessage,
@@ -164,17 +195,24 @@ func WebSocketHandler(hubProvider func() (*ws.Hub, bool), db *sql.DB, cfg config
 			_ = conn.Close()
 			return
 		}
+		client.SessionID = sessionID
 		hub.Register(client)
 
 		go client.WritePump()
-		go client.ReadPump(func(msg []byte) {
-			handleWSMessage(hub, client, db, msg)
-		})
+		seatPresenceEnter(db, client, room)
+		go func() {
+			client.ReadPump(func(msg []byte) {
+				handleWSMessage(hub, client, db, msg)
+			})
+			seatPresenceLeave(db, client)
+		}()
 
 		// Send a direct "connected" ack.
 		if err := sendDirect(client, "connected", map[string]any{
-			"user_id": client.UserID,
-			"room":    room,
+			"user_id":    client.UserID,
+			"room":       room,
+			"session_id": sessionID,
+			"resumed":    resumed,
 		}); err != nil {
 			log.Printf("sendDirect failed (connected): err=%v", err)
 			client.Close()
@@ -212,11 +250,35 @@ func handleWSMessage(hub *ws.Hub, client *ws.Client, db *sql.DB, msg []byte) {
 		}
 		room := strings.TrimSpace(p.Room)
 		hub.Join(client, room)
+		seatPresenceEnter(db, client, room)
 		if err := sendDirect(client, "joined_room", map[string]any{"room": room}); err != nil {
 			log.Printf("sendDirect failed (joined_room): err=%v", err)
 			client.Close()
 			return
 		}
+	case "leave_room":
+		var p struct {
+			Room string `json:"room"`
+		}
+		if err := json.Unmarshal(in.Payload, &p); err != nil || strings.TrimSpace(p.Room) == "" {
+			if err := sendDirect(client, "error", map[string]any{"error": "invalid room"}); err != nil {
+				log.Printf("sendDirect failed (invalid_room): err=%v", err)
+				client.Close()
+			}
+			return
+		}
+		// Leaving a game room is the explicit way to give up seat presence without
+		// disconnecting; the socket falls back to the global lobby.
+		room := strings.TrimSpace(p.Room)
+		if gameID, ok := gameIDFromRoom(room); ok && seatPresenceGame(client) == gameID {
+			seatPresenceLeave(db, client)
+		}
+		hub.Join(client, "lobby:global")
+		if err := sendDirect(client, "left_room", map[string]any{"room": room}); err != nil {
+			log.Printf("sendDirect failed (left_room): err=%v", err)
+			client.Close()
+			return
+		}
 	case "move":
 		var p struct {
 			GameID int64       `json:"game_id"`
@@ -232,7 +294,12 @@ func handleWSMessage(hub *ws.Hub, client *ws.Client, db *sql.DB, msg []byte) {
 		resp, err := ApplyMove(db, p.GameID, client.UserID, p.Move)
 		if err != nil {
 			// Avoid leaking internal details; ApplyMove errors are mapped in HTTP handlers only.
-			if err := sendDirect(client, "error", map[string]any{"error": "invalid move"}); err != nil {
+			// Raced/duplicate "go" is the exception: tell the client to just refresh.
+			payload := map[string]any{"error": "invalid move"}
+			if errors.Is(err, models.ErrTurnAlreadyAdvanced) {
+				payload = map[string]any{"error": "the turn already moved on", "code": "turn_already_advanced"}
+			}
+			if err := sendDirect(client, "error", payload); err != nil {
 				log.Printf("sendDirect failed (move_error): err=%v", err)
 				client.Close()
 			}
@@ -252,9 +319,14 @@ func handleWSMessage(hub *ws.Hub, client *ws.Client, db *sql.DB, msg []byte) {
 		snap, err := BuildGameSnapshotPublic(db, p.GameID)
 		if err == nil {
 			hub.Broadcast("game:"+strconv.FormatInt(p.GameID, 10), "game_update", snap)
+			pushTurnNotifications(db, snap)
 		} else {
 			log.Printf("BuildGameSnapshotPublic failed: game_id=%d err=%v", p.GameID, err)
 		}
+	case "resume":
+		handleResumeWS(hub, client, db, in.Payload)
+	case "refresh_snapshot":
+		handleRefreshSnapshotWS(client, db, in.Payload)
 	case "lobby:send_message":
 		handleLobbyChatWS(hub, client, db, in.Payload)
 	default:
@@ -265,6 +337,75 @@ func handleWSMessage(hub *ws.Hub, client *ws.Client, db *sql.DB, msg []byte) {
 	}
 }
 
+// handleResumeWS serves a reconnecting client: it joins the socket to the game room, so later
+// broadcasts reach it, and answers with the caller's own snapshot as a direct game_update, so
+// the client needn't fetch GET /api/games/:id. Participants and spectators may resume.
+func handleResumeWS(hub *ws.Hub, client *ws.Client, db *sql.DB, raw json.RawMessage) {
+	gameID, ok := authorizeSnapshotWS(client, db, raw, "resume")
+	if !ok {
+		return
+	}
+	room := "game:" + strconv.FormatInt(gameID, 10)
+	hub.Join(client, room)
+	seatPresenceEnter(db, client, room)
+	sendSnapshotWS(client, db, gameID, "resume")
+}
+
+// handleRefreshSnapshotWS re-sends the caller's current snapshot as a direct game_update, e.g.
+// after a UI reload on a socket that is already in the game room. Unlike resume it leaves room
+// membership and presence alone.
+func handleRefreshSnapshotWS(client *ws.Client, db *sql.DB, raw json.RawMessage) {
+	gameID, ok := authorizeSnapshotWS(client, db, raw, "refresh")
+	if !ok {
+		return
+	}
+	sendSnapshotWS(client, db, gameID, "refresh")
+}
+
+// authorizeSnapshotWS reads the game_id of a snapshot request and checks the caller may view
+// that game. Failures are reported to the client with codes prefixed by kind.
+func authorizeSnapshotWS(client *ws.Client, db *sql.DB, raw json.RawMessage, kind string) (int64, bool) {
+	var p struct {
+		GameID int64 `json:"game_id"`
+	}
+	if err := json.Unmarshal(raw, &p); err != nil || p.GameID <= 0 {
+		if err := sendDirect(client, "error", map[string]any{"error": "invalid " + kind + " payload"}); err != nil {
+			log.Printf("sendDirect failed (invalid_%s_payload): err=%v", kind, err)
+			client.Close()
+		}
+		return 0, false
+	}
+	allowed, err := canViewGame(db, client.UserID, p.GameID)
+	if err != nil || !allowed {
+		if err != nil {
+			log.Printf("authorizeSnapshotWS: authorization check failed: kind=%s game_id=%d user_id=%d err=%v", kind, p.GameID, client.UserID, err)
+		}
+		if err := sendDirect(client, "error", map[string]any{"error": "access denied", "code": kind + "_denied"}); err != nil {
+			log.Printf("sendDirect failed (%s_denied): err=%v", kind, err)
+			client.Close()
+		}
+		return 0, false
+	}
+	return p.GameID, true
+}
+
+// sendSnapshotWS sends the caller's own view of the game as a direct game_update.
+func sendSnapshotWS(client *ws.Client, db *sql.DB, gameID int64, kind string) {
+	snap, err := BuildGameSnapshotForUser(db, gameID, client.UserID)
+	if err != nil {
+		log.Printf("sendSnapshotWS: BuildGameSnapshotForUser failed: kind=%s game_id=%d user_id=%d err=%v", kind, gameID, client.UserID, err)
+		if err := sendDirect(client, "error", map[string]any{"error": "game unavailable", "code": kind + "_failed"}); err != nil {
+			log.Printf("sendDirect failed (%s_failed): err=%v", kind, err)
+			client.Close()
+		}
+		return
+	}
+	if err := sendDirect(client, "game_update", snap); err != nil {
+		log.Printf("sendDirect failed (%s game_update): err=%v", kind, err)
+		client.Close()
+	}
+}
+
 func sendDirect(c *ws.Client, typ string, payload any) error {
 	msg := map[string]any{
 		"type":      typ,
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"fifteen-thirty-one-go/backend/internal/game/common"
+	"fifteen-thirty-one-go/backend/internal/game/cribbage"
+	"fifteen-thirty-one-go/backend/internal/models"
+	"fifteen-thirty-one-go/backend/internal/tracing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type whatIfCutRequest struct {
+	Cut string `json:"cut"` // e.g. "5H"
+}
+
+// WhatIfCutHandler scores the caller's own kept hand against a hypothetical cut card. Only the
+// caller's hand is ever scored. Outside training mode (WhatIfCutTraining) it is limited to the
+// counting and finished stages, when the real cut and every kept hand are already on the table;
+// in training mode it also answers once the caller has discarded.
+func WhatIfCutHandler(db *sql.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, span := tracing.StartSpan(c.Request.Context(), "handlers.WhatIfCutHandler")
+		defer span.End()
+
+		gameID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || gameID <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid game id"})
+			return
+		}
+		userID, ok := userIDFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		var req whatIfCutRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid json"})
+			return
+		}
+		cut, err := common.ParseCard(strings.TrimSpace(req.Cut))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cut card"})
+			return
+		}
+
+		players, err := models.ListGamePlayersByGame(db, gameID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		pos := -1
+		for _, p := range players {
+			if p.UserID == userID {
+				pos = int(p.Position)
+			}
+		}
+		if pos < 0 {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+		st, unlock, err := ensureGameStateLocked(db, gameID, players)
+		if err != nil {
+			if errors.Is(err, models.ErrGameStateMissing) {
+				c.JSON(http.StatusConflict, gin.H{"error": "game state unavailable; recreate lobby"})
+				return
+			}
+			log.Printf("WhatIfCutHandler: ensureGameStateLocked failed: game_id=%d err=%v", gameID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		var kept []common.Card
+		training := currentConfig().WhatIfCutTraining
+		switch {
+		case st.Stage == "counting" || st.Stage == "finished":
+			if pos < len(st.KeptHands) {
+				kept = append(kept, st.KeptHands[pos]...)
+			}
+		case training && st.Stage == "pegging":
+			if pos < len(st.KeptHands) {
+				kept = append(kept, st.KeptHands[pos]...)
+			}
+		case training && st.Stage == "discard" && pos < len(st.DiscardCompleted) && st.DiscardCompleted[pos]:
+			// Until everyone has discarded, the kept hand is still the dealt hand.
+			kept = append(kept, st.Hands[pos]...)
+		}
+		handSize := st.Rules.HandSize() - st.Rules.DiscardCount()
+		unlock()
+		if len(kept) != handSize {
+			msg := "what-if cuts are only available while hands are counted or after the game"
+			if training {
+				msg = "what-if cuts are only available after you discard"
+			}
+			c.JSON(http.StatusConflict, gin.H{"error": msg})
+			return
+		}
+		for _, k := range kept {
+			if k == cut {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "the cut cannot be a card in your hand"})
+				return
+			}
+		}
+
+		breakdown := cribbage.ScoreHand(kept, cut, false)
+		c.JSON(http.StatusOK, gin.H{"game_id": gameID, "hand": cardCodes(kept), "cut": cut.String(), "breakdown": breakdown})
+	}
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"net/http"
+
+	"fifteen-thirty-one-go/backend/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireAdmin must run after RequireAuth; it only lets through users listed in ADMIN_USER_IDS.
+func RequireAdmin(cfg config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		v, ok := c.Get("userID")
+		userID, isInt := v.(int64)
+		if !ok || !isInt {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		if !cfg.IsAdmin(userID) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "admin only"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"fifteen-thirty-one-go/backend/internal/config"
+	"fifteen-thirty-one-go/backend/internal/database"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	requestsInFlight atomic.Int64
+	requestsStarted  atomic.Int64
+)
+
+// QueryStats logs requests that ran more than cfg.DBDebugMaxQueries statements or took longer
+// than cfg.DBDebugSlowRequest, tagged with the route, handler and request id (X-Request-ID,
+// generated when absent). Statement counts come from the process-wide counter in
+// database.QueryStats, so a request that overlapped another is logged with approx=true; the
+// count may then include the other request's (or a background job's) queries. It is a
+// diagnostic for development and staging, enabled by DB_QUERY_DEBUG.
+func QueryStats(cfg config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// WebSocket connections stay open for the whole session; they would mark every other
+		// request as overlapping while adding nothing useful themselves.
+		if strings.EqualFold(c.GetHeader("Upgrade"), "websocket") {
+			c.Next()
+			return
+		}
+		requestID := c.GetHeader("X-Request-ID")
+		if requestID == "" {
+			var b [8]byte
+			_, _ = rand.Read(b[:])
+			requestID = hex.EncodeToString(b[:])
+		}
+		c.Header("X-Request-ID", requestID)
+
+		seq := requestsStarted.Add(1)
+		overlapped := requestsInFlight.Add(1) > 1
+		queries0, dbTime0 := database.QueryStats()
+		start := time.Now()
+
+		c.Next()
+
+		latency := time.Since(start)
+		queries1, dbTime1 := database.QueryStats()
+		overlapped = overlapped || requestsStarted.Load() != seq
+		requestsInFlight.Add(-1)
+
+		queries := queries1 - queries0
+		if queries <= int64(cfg.DBDebugMaxQueries) && latency <= cfg.DBDebugSlowRequest {
+			return
+		}
+		log.Printf("db debug: request_id=%s route=%q handler=%s status=%d queries=%d db_time=%s latency=%s approx=%t",
+			requestID, c.Request.Method+" "+c.FullPath(), c.HandlerName(), c.Writer.Status(), queries,
+			dbTime1-dbTime0, latency, overlapped)
+	}
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// AccessLogEntry is one row of the security audit trail.
+type AccessLogEntry struct {
+	Event     string // login|ws_upgrade
+	Success   bool
+	UserID    *int64
+	Username  string
+	IP        string
+	Origin    string
+	UserAgent string
+}
+
+func InsertAccessLog(db *sql.DB, e AccessLogEntry) error {
+	_, err := db.Exec(
+		`INSERT INTO access_log(event, success, user_id, username, ip, origin, user_agent) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		e.Event, boolToInt(e.Success), e.UserID, e.Username, e.IP, e.Origin, e.UserAgent,
+	)
+	if err != nil {
+		return fmt.Errorf("insert access log: event=%s: %w", e.Event, err)
+	}
+	return nil
+}
+
+// DeleteAccessLogOlderThan prunes audit rows older than age and returns how many were removed.
+func DeleteAccessLogOlderThan(db *sql.DB, age time.Duration) (int64, error) {
+	res, err := db.Exec(
+		`DELETE FROM access_log WHERE created_at < datetime('now', ?)`,
+		fmt.Sprintf("-%d seconds", int64(age/time.Second)),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("delete access log: %w", err)
+	}
+	return res.RowsAffected()
+}
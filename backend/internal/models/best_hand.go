@@ -0,0 +1,67 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// BestHand is a player's highest-scoring counted hand in one completed game.
+type BestHand struct {
+	GameID    int64           `json:"game_id"`
+	UserID    int64           `json:"user_id"`
+	Round     int             `json:"round"`
+	Cards     []string        `json:"cards"`
+	Cut       string          `json:"cut"`
+	Points    int64           `json:"points"`
+	Breakdown json.RawMessage `json:"breakdown"`
+	PlayedAt  time.Time       `json:"played_at"` // when the game was finalized
+}
+
+// InsertBestHandTx records h unless the player's best hand for the game is already stored.
+func InsertBestHandTx(ctx context.Context, tx *sql.Tx, h BestHand) error {
+	cards, err := json.Marshal(h.Cards)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO best_hands(game_id, user_id, round, cards, cut, points, breakdown) VALUES (?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(game_id, user_id) DO NOTHING`,
+		h.GameID, h.UserID, h.Round, string(cards), h.Cut, h.Points, string(h.Breakdown),
+	); err != nil {
+		return fmt.Errorf("insert best hand (game_id=%d user_id=%d): %w", h.GameID, h.UserID, err)
+	}
+	return nil
+}
+
+// ListBestHands returns the user's top limit hands across games, highest first; ties go to the
+// earlier hand.
+func ListBestHands(ctx context.Context, db *sql.DB, userID int64, limit int) ([]BestHand, error) {
+	rows, err := db.QueryContext(ctx,
+		`SELECT game_id, user_id, round, cards, cut, points, breakdown, created_at
+		 FROM best_hands WHERE user_id = ?
+		 ORDER BY points DESC, created_at ASC, game_id ASC
+		 LIMIT ?`,
+		userID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list best hands (user_id=%d): %w", userID, err)
+	}
+	defer rows.Close()
+	out := []BestHand{}
+	for rows.Next() {
+		var h BestHand
+		var cards, breakdown string
+		if err := rows.Scan(&h.GameID, &h.UserID, &h.Round, &cards, &h.Cut, &h.Points, &breakdown, &h.PlayedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(cards), &h.Cards); err != nil {
+			return nil, fmt.Errorf("decode best hand cards (game_id=%d): %w", h.GameID, err)
+		}
+		h.Breakdown = json.RawMessage(breakdown)
+		out = append(out, h)
+	}
+	return out, rows.Err()
+}
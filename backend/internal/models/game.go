@@ -13,25 +13,55 @@ var ErrInvalidGameStatus = errors.New("invalid game status")
 type Game struct {
 	ID              int64      `json:"id"`
 	LobbyID         int64      `json:"lobby_id"`
-	Status          string     `json:"status"` // waiting|playing|finished
+	Status          string     `json:"status"` // waiting|in_progress|finished
 	CurrentPlayerID *int64     `json:"current_player_id,omitempty"`
 	DealerID        *int64     `json:"dealer_id,omitempty"`
 	CreatedAt       time.Time  `json:"created_at"`
 	FinishedAt      *time.Time `json:"finished_at,omitempty"`
 }
 
+// CreateGame creates the lobby's game. A lobby has at most one active (waiting or in_progress)
+// game; a second one fails with ErrLobbyHasActiveGame.
 func CreateGame(db *sql.DB, lobbyID int64) (*Game, error) {
-	res, err := db.Exec(`INSERT INTO games(lobby_id, status) VALUES (?, 'waiting')`, lobbyID)
+	tx, err := db.Begin()
 	if err != nil {
 		return nil, err
 	}
-	id, err := res.LastInsertId()
+	defer tx.Rollback()
+	id, err := CreateGameTx(tx, lobbyID)
 	if err != nil {
 		return nil, err
 	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 	return GetGameByID(db, id)
 }
 
+func CreateGameTx(tx *sql.Tx, lobbyID int64) (int64, error) {
+	res, err := tx.Exec(`INSERT INTO games(lobby_id, status) VALUES (?, 'waiting')`, lobbyID)
+	if err != nil {
+		if IsUniqueConstraint(err) {
+			return 0, ErrLobbyHasActiveGame
+		}
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// LobbyGameTx returns the lobby's current game: the active one when it exists, otherwise the
+// most recent finished one. It returns sql.ErrNoRows for a lobby without games.
+func LobbyGameTx(tx *sql.Tx, lobbyID int64) (gameID int64, status string, err error) {
+	err = tx.QueryRow(
+		`SELECT id, status FROM games
+		 WHERE lobby_id = ?
+		 ORDER BY CASE WHEN status IN ('waiting', 'in_progress') THEN 0 ELSE 1 END, id DESC
+		 LIMIT 1`,
+		lobbyID,
+	).Scan(&gameID, &status)
+	return gameID, status, err
+}
+
 func GetGameByID(db *sql.DB, id int64) (*Game, error) {
 	var g Game
 	var current sql.NullInt64
@@ -63,11 +93,11 @@ func GetGameByID(db *sql.DB, id int64) (*Game, error) {
 }
 
 // SetGameStatus updates a game's status to the specified value.
-// Valid status values are "waiting", "playing", and "finished".
+// Valid status values are "waiting", "in_progress", and "finished".
 // When status is "finished", it also sets finished_at to CURRENT_TIMESTAMP.
 // Returns ErrGameNotFound if the game does not exist, or ErrInvalidGameStatus for invalid status values.
 func SetGameStatus(db *sql.DB, gameID int64, status string) error {
-	if status != "waiting" && status != "playing" && status != "finished" {
+	if status != "waiting" && status != "in_progress" && status != "finished" {
 		return fmt.Errorf("invalid game status %q: %w", status, ErrInvalidGameStatus)
 	}
 	if status == "finished" {
@@ -114,11 +144,11 @@ func SetGameStatus(db *sql.DB, gameID int64, status string) error {
 }
 
 // SetGameStatusTx updates a game's status within the provided transaction.
-// Valid status values are "waiting", "playing", and "finished".
+// Valid status values are "waiting", "in_progress", and "finished".
 // When status is "finished", it also sets finished_at to CURRENT_TIMESTAMP.
 // Returns ErrGameNotFound if the game does not exist.
 func SetGameStatusTx(tx *sql.Tx, gameID int64, status string) error {
-	if status != "waiting" && status != "playing" && status != "finished" {
+	if status != "waiting" && status != "in_progress" && status != "finished" {
 		return fmt.Errorf("invalid game status %q: %w", status, ErrInvalidGameStatus)
 	}
 	if status == "finished" {
@@ -241,6 +271,17 @@ func GetGameStateJSON(db *sql.DB, gameID int64) (stateJSON string, stateVersion
 	return s.String, stateVersion, true, nil
 }
 
+// HasGameStateTx reports whether the game has a persisted engine state.
+func HasGameStateTx(tx *sql.Tx, gameID int64) (bool, error) {
+	var s sql.NullString
+	if err := tx.QueryRow(`SELECT state_json FROM games WHERE id = ?`, gameID).Scan(&s); errors.Is(err, sql.ErrNoRows) {
+		return false, ErrNotFound
+	} else if err != nil {
+		return false, err
+	}
+	return s.Valid && strings.TrimSpace(s.String) != "", nil
+}
+
 func UpdateGameStateTx(tx *sql.Tx, gameID int64, stateJSON string) error {
 	res, err := tx.Exec(`UPDATE games SET state_json = ?, state_version = state_version + 1 WHERE id = ?`, stateJSON, gameID)
 	if err != nil {
@@ -285,3 +326,20 @@ func UpdateGameStateTxCAS(tx *sql.Tx, gameID int64, expectedVersion int64, state
 	}
 	return nil
 }
+
+// UpdateGameStateIfNewer writes a write-behind snapshot taken at the given in-memory version.
+// Older snapshots never overwrite newer ones; it reports whether the row was updated.
+func UpdateGameStateIfNewer(db *sql.DB, gameID int64, version int64, stateJSON string) (bool, error) {
+	res, err := db.Exec(
+		`UPDATE games SET state_json = ?, state_version = ? WHERE id = ? AND state_version < ?`,
+		stateJSON, version, gameID, version,
+	)
+	if err != nil {
+		return false, err
+	}
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return ra > 0, nil
+}
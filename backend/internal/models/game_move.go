@@ -1,6 +1,7 @@
 package models
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"strings"
@@ -16,13 +17,14 @@ type GameMove struct {
 	ScoreClaimed  *int64    `json:"score_claimed,omitempty"`
 	ScoreVerified *int64    `json:"score_verified,omitempty"`
 	IsCorrected   bool      `json:"is_corrected"`
+	RefMoveID     *int64    `json:"ref_move_id,omitempty"` // the move a correction or muggins call refers to
 	CreatedAt     time.Time `json:"created_at"`
 }
 
 func InsertMove(db *sql.DB, m GameMove) (*GameMove, error) {
 	res, err := db.Exec(
-		`INSERT INTO game_moves(game_id, player_id, move_type, card_played, score_claimed, score_verified, is_corrected) VALUES (?, ?, ?, ?, ?, ?, ?)`,
-		m.GameID, m.PlayerID, m.MoveType, m.CardPlayed, m.ScoreClaimed, m.ScoreVerified, boolToInt(m.IsCorrected),
+		`INSERT INTO game_moves(game_id, player_id, move_type, card_played, score_claimed, score_verified, is_corrected, ref_move_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		m.GameID, m.PlayerID, m.MoveType, m.CardPlayed, m.ScoreClaimed, m.ScoreVerified, boolToInt(m.IsCorrected), m.RefMoveID,
 	)
 	if err != nil {
 		return nil, err
@@ -36,8 +38,8 @@ func InsertMove(db *sql.DB, m GameMove) (*GameMove, error) {
 
 func InsertMoveTx(tx *sql.Tx, m GameMove) error {
 	_, err := tx.Exec(
-		`INSERT INTO game_moves(game_id, player_id, move_type, card_played, score_claimed, score_verified, is_corrected) VALUES (?, ?, ?, ?, ?, ?, ?)`,
-		m.GameID, m.PlayerID, m.MoveType, m.CardPlayed, m.ScoreClaimed, m.ScoreVerified, boolToInt(m.IsCorrected),
+		`INSERT INTO game_moves(game_id, player_id, move_type, card_played, score_claimed, score_verified, is_corrected, ref_move_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		m.GameID, m.PlayerID, m.MoveType, m.CardPlayed, m.ScoreClaimed, m.ScoreVerified, boolToInt(m.IsCorrected), m.RefMoveID,
 	)
 	return err
 }
@@ -47,11 +49,12 @@ func GetMoveByID(db *sql.DB, id int64) (*GameMove, error) {
 	var card sql.NullString
 	var sc sql.NullInt64
 	var sv sql.NullInt64
+	var ref sql.NullInt64
 	var isCorrVal any
 	err := db.QueryRow(
-		`SELECT id, game_id, player_id, move_type, card_played, score_claimed, score_verified, is_corrected, created_at FROM game_moves WHERE id = ?`,
+		`SELECT id, game_id, player_id, move_type, card_played, score_claimed, score_verified, is_corrected, ref_move_id, created_at FROM game_moves WHERE id = ?`,
 		id,
-	).Scan(&m.ID, &m.GameID, &m.PlayerID, &m.MoveType, &card, &sc, &sv, &isCorrVal, &m.CreatedAt)
+	).Scan(&m.ID, &m.GameID, &m.PlayerID, &m.MoveType, &card, &sc, &sv, &isCorrVal, &ref, &m.CreatedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrNotFound
@@ -70,6 +73,10 @@ func GetMoveByID(db *sql.DB, id int64) (*GameMove, error) {
 		v := sv.Int64
 		m.ScoreVerified = &v
 	}
+	if ref.Valid {
+		v := ref.Int64
+		m.RefMoveID = &v
+	}
 	m.IsCorrected = parseSQLiteBool(isCorrVal)
 	return &m, nil
 }
@@ -79,13 +86,30 @@ func ListMovesByGame(db *sql.DB, gameID int64, limit int64) ([]GameMove, error)
 		limit = 200
 	}
 	rows, err := db.Query(
-		`SELECT id, game_id, player_id, move_type, card_played, score_claimed, score_verified, is_corrected, created_at
+		`SELECT id, game_id, player_id, move_type, card_played, score_claimed, score_verified, is_corrected, ref_move_id, created_at
 		 FROM game_moves WHERE game_id = ? ORDER BY created_at DESC LIMIT ?`,
 		gameID, limit,
 	)
 	if err != nil {
 		return nil, err
 	}
+	return scanMoves(rows)
+}
+
+// ListMovesInOrder returns up to limit of the game's moves, oldest first by id.
+func ListMovesInOrder(ctx context.Context, db *sql.DB, gameID int64, limit int64) ([]GameMove, error) {
+	rows, err := db.QueryContext(ctx,
+		`SELECT id, game_id, player_id, move_type, card_played, score_claimed, score_verified, is_corrected, ref_move_id, created_at
+		 FROM game_moves WHERE game_id = ? ORDER BY id ASC LIMIT ?`,
+		gameID, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanMoves(rows)
+}
+
+func scanMoves(rows *sql.Rows) ([]GameMove, error) {
 	defer rows.Close()
 
 	var out []GameMove
@@ -94,8 +118,9 @@ func ListMovesByGame(db *sql.DB, gameID int64, limit int64) ([]GameMove, error)
 		var card sql.NullString
 		var sc sql.NullInt64
 		var sv sql.NullInt64
+		var ref sql.NullInt64
 		var isCorrVal any
-		if err := rows.Scan(&m.ID, &m.GameID, &m.PlayerID, &m.MoveType, &card, &sc, &sv, &isCorrVal, &m.CreatedAt); err != nil {
+		if err := rows.Scan(&m.ID, &m.GameID, &m.PlayerID, &m.MoveType, &card, &sc, &sv, &isCorrVal, &ref, &m.CreatedAt); err != nil {
 			return nil, err
 		}
 		if card.Valid {
@@ -110,6 +135,10 @@ func ListMovesByGame(db *sql.DB, gameID int64, limit int64) ([]GameMove, error)
 			v := sv.Int64
 			m.ScoreVerified = &v
 		}
+		if ref.Valid {
+			v := ref.Int64
+			m.RefMoveID = &v
+		}
 		m.IsCorrected = parseSQLiteBool(isCorrVal)
 		out = append(out, m)
 	}
@@ -156,6 +185,130 @@ func HasUncorrectedMoveType(db *sql.DB, gameID, playerID int64, moveType string)
 	return true, nil
 }
 
+// HasMoveTypeAfter returns true if the game has a move of the given type recorded after moveID.
+// Move IDs are monotonically increasing, so this orders moves without relying on timestamps.
+func HasMoveTypeAfter(db *sql.DB, gameID int64, moveType string, moveID int64) (bool, error) {
+	var one int
+	err := db.QueryRow(
+		`SELECT 1 FROM game_moves WHERE game_id = ? AND move_type = ? AND id > ? LIMIT 1`,
+		gameID, moveType, moveID,
+	).Scan(&one)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// LatestMoveIDOfType returns the id of the game's most recent move of the given type, or 0 if
+// there is none.
+func LatestMoveIDOfType(db *sql.DB, gameID int64, moveType string) (int64, error) {
+	var id sql.NullInt64
+	err := db.QueryRow(
+		`SELECT MAX(id) FROM game_moves WHERE game_id = ? AND move_type = ?`,
+		gameID, moveType,
+	).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+	return id.Int64, nil
+}
+
+// HasUncorrectedMoveTypeSince is HasUncorrectedMoveType restricted to moves recorded after moveID.
+func HasUncorrectedMoveTypeSince(db *sql.DB, gameID, playerID int64, moveType string, moveID int64) (bool, error) {
+	var one int
+	err := db.QueryRow(
+		`SELECT 1
+		 FROM game_moves
+		 WHERE game_id = ? AND player_id = ? AND move_type = ? AND is_corrected = 0 AND id > ?
+		 LIMIT 1`,
+		gameID, playerID, moveType, moveID,
+	).Scan(&one)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// LatestUncorrectedMoveSince returns the player's most recent uncorrected move of the given type
+// recorded after moveID, or ErrNotFound if there is none.
+func LatestUncorrectedMoveSince(db *sql.DB, gameID, playerID int64, moveType string, moveID int64) (*GameMove, error) {
+	var id int64
+	err := db.QueryRow(
+		`SELECT id
+		 FROM game_moves
+		 WHERE game_id = ? AND player_id = ? AND move_type = ? AND is_corrected = 0 AND id > ?
+		 ORDER BY id DESC
+		 LIMIT 1`,
+		gameID, playerID, moveType, moveID,
+	).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return GetMoveByID(db, id)
+}
+
+// InsertMugginsMoveTx records callerID's muggins call on the under-claimed count refMoveID,
+// worth points. It returns ErrMugginsAlreadyCalled if that count was already called.
+func InsertMugginsMoveTx(tx *sql.Tx, gameID, callerID, refMoveID, points int64) error {
+	_, err := tx.Exec(
+		`INSERT INTO game_moves(game_id, player_id, move_type, score_verified, is_corrected, ref_move_id) VALUES (?, ?, 'muggins', ?, 0, ?)`,
+		gameID, callerID, points, refMoveID,
+	)
+	if IsUniqueConstraint(err) {
+		return ErrMugginsAlreadyCalled
+	}
+	return err
+}
+
+// DeleteLatestPlayMoveTx removes the game's most recent move when it is playerID's play_card of
+// card, for a pegging play that was taken back. It returns ErrNotFound when the latest move is
+// anything else.
+func DeleteLatestPlayMoveTx(tx *sql.Tx, gameID, playerID int64, card string) error {
+	var (
+		id       int64
+		moveType string
+		mover    int64
+		played   sql.NullString
+	)
+	err := tx.QueryRow(
+		`SELECT id, move_type, player_id, card_played FROM game_moves WHERE game_id = ? ORDER BY id DESC LIMIT 1`,
+		gameID,
+	).Scan(&id, &moveType, &mover, &played)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if moveType != "play_card" || mover != playerID || played.String != card {
+		return ErrNotFound
+	}
+	_, err = tx.Exec(`DELETE FROM game_moves WHERE id = ?`, id)
+	return err
+}
+
+// GameHasMovesTx reports whether any move has been recorded for the game.
+func GameHasMovesTx(tx *sql.Tx, gameID int64) (bool, error) {
+	var one int
+	err := tx.QueryRow(`SELECT 1 FROM game_moves WHERE game_id = ? LIMIT 1`, gameID).Scan(&one)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 func MarkMoveAsCorrected(db *sql.DB, moveID int64) error {
 	res, err := db.Exec(`UPDATE game_moves SET is_corrected = 1 WHERE id = ?`, moveID)
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 )
 
 type GamePlayer struct {
@@ -18,6 +19,8 @@ type GamePlayer struct {
 	CribCards     *string `json:"crib_cards,omitempty"`
 	IsBot         bool    `json:"is_bot"`
 	BotDifficulty *string `json:"bot_difficulty,omitempty"`
+	Resigned      bool    `json:"resigned"`
+	BotTakeover   bool    `json:"bot_takeover"` // human seat temporarily played by a bot after a disconnect
 }
 
 func AddGamePlayer(db *sql.DB, gameID, userID int64, position int64, isBot bool, botDifficulty *string) error {
@@ -28,6 +31,14 @@ func AddGamePlayer(db *sql.DB, gameID, userID int64, position int64, isBot bool,
 	return err
 }
 
+func AddGamePlayerTx(tx *sql.Tx, gameID, userID int64, position int64, isBot bool, botDifficulty *string) error {
+	_, err := tx.Exec(
+		`INSERT INTO game_players(game_id, user_id, position, is_bot, bot_difficulty) VALUES (?, ?, ?, ?, ?)`,
+		gameID, userID, position, boolToInt(isBot), botDifficulty,
+	)
+	return err
+}
+
 func AddGamePlayerAutoPosition(db *sql.DB, gameID, userID, maxPlayers int64, isBot bool, botDifficulty *string) (int64, error) {
 	// Retry on unique position collision (due to concurrent joins).
 	//
@@ -108,7 +119,8 @@ func ListGamePlayersByGame(db *sql.DB, gameID int64) ([]GamePlayer, error) {
 func ListGamePlayersByGameContext(ctx context.Context, db *sql.DB, gameID int64) ([]GamePlayer, error) {
 	rows, err := db.QueryContext(
 		ctx,
-		`SELECT gp.game_id, gp.user_id, COALESCE(u.username, '') AS username, gp.position, gp.score, gp.hand, gp.crib_cards, gp.is_bot, gp.bot_difficulty
+		`SELECT gp.game_id, gp.user_id, COALESCE(u.username, '') AS username, gp.position, gp.score, gp.hand, gp.crib_cards, gp.is_bot, gp.bot_difficulty, gp.resigned,
+		        gp.bot_takeover_at IS NOT NULL AS bot_takeover
 		 FROM game_players gp
 		 LEFT JOIN users u ON u.id = gp.user_id
 		 WHERE gp.game_id = ? ORDER BY gp.position ASC`,
@@ -125,7 +137,9 @@ func ListGamePlayersByGameContext(ctx context.Context, db *sql.DB, gameID int64)
 		var crib sql.NullString
 		var isBotVal any
 		var botDiff sql.NullString
-		if err := rows.Scan(&gp.GameID, &gp.UserID, &gp.Username, &gp.Position, &gp.Score, &gp.Hand, &crib, &isBotVal, &botDiff); err != nil {
+		var resignedVal any
+		var takeoverVal any
+		if err := rows.Scan(&gp.GameID, &gp.UserID, &gp.Username, &gp.Position, &gp.Score, &gp.Hand, &crib, &isBotVal, &botDiff, &resignedVal, &takeoverVal); err != nil {
 			return nil, fmt.Errorf("ListGamePlayersByGameContext: scan game player (game_id=%d): %w", gameID, err)
 		}
 		if crib.Valid {
@@ -137,6 +151,8 @@ func ListGamePlayersByGameContext(ctx context.Context, db *sql.DB, gameID int64)
 			v := botDiff.String
 			gp.BotDifficulty = &v
 		}
+		gp.Resigned = parseSQLiteBool(resignedVal)
+		gp.BotTakeover = parseSQLiteBool(takeoverVal)
 		out = append(out, gp)
 	}
 	return out, rows.Err()
@@ -213,6 +229,74 @@ func UpdatePlayerScore(db *sql.DB, gameID, userID int64, score int64) error {
 	return nil
 }
 
+// ResignPlayerTx marks a player as resigned. When botDifficulty is non-nil the seat is also
+// handed to a bot of that difficulty so the remaining players can keep playing.
+func ResignPlayerTx(tx *sql.Tx, gameID, userID int64, botDifficulty *string) error {
+	var res sql.Result
+	var err error
+	if botDifficulty != nil {
+		res, err = tx.Exec(
+			`UPDATE game_players SET resigned = 1, is_bot = 1, bot_difficulty = ? WHERE game_id = ? AND user_id = ?`,
+			*botDifficulty, gameID, userID,
+		)
+	} else {
+		res, err = tx.Exec(`UPDATE game_players SET resigned = 1 WHERE game_id = ? AND user_id = ?`, gameID, userID)
+	}
+	if err != nil {
+		return fmt.Errorf("resign player (tx): game_id=%d user_id=%d: %w", gameID, userID, err)
+	}
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("resign player rows affected (tx): game_id=%d user_id=%d: %w", gameID, userID, err)
+	}
+	if ra == 0 {
+		return ErrPlayerNotInGame
+	}
+	return nil
+}
+
+// TakeOverSeatWithBot hands a disconnected human's seat to a bot of the given difficulty.
+// It only applies to human, non-resigned seats in unfinished games and reports whether
+// the seat was converted.
+func TakeOverSeatWithBot(db *sql.DB, gameID, userID int64, botDifficulty string) (bool, error) {
+	res, err := db.Exec(
+		`UPDATE game_players
+		 SET is_bot = 1, bot_difficulty = ?, bot_takeover_at = CURRENT_TIMESTAMP
+		 WHERE game_id = ? AND user_id = ? AND is_bot = 0 AND resigned = 0
+		   AND EXISTS (SELECT 1 FROM games g WHERE g.id = game_players.game_id AND g.status != 'finished')`,
+		botDifficulty, gameID, userID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("take over seat: game_id=%d user_id=%d: %w", gameID, userID, err)
+	}
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("take over seat rows affected: game_id=%d user_id=%d: %w", gameID, userID, err)
+	}
+	return ra > 0, nil
+}
+
+// ReclaimSeatFromBot returns a bot-taken-over seat to its human owner when the takeover
+// happened within the given window. It reports whether the seat was reclaimed.
+func ReclaimSeatFromBot(db *sql.DB, gameID, userID int64, window time.Duration) (bool, error) {
+	res, err := db.Exec(
+		`UPDATE game_players
+		 SET is_bot = 0, bot_difficulty = NULL, bot_takeover_at = NULL
+		 WHERE game_id = ? AND user_id = ? AND resigned = 0
+		   AND bot_takeover_at IS NOT NULL
+		   AND bot_takeover_at >= datetime('now', ?)`,
+		gameID, userID, fmt.Sprintf("-%d seconds", int64(window/time.Second)),
+	)
+	if err != nil {
+		return false, fmt.Errorf("reclaim seat: game_id=%d user_id=%d: %w", gameID, userID, err)
+	}
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("reclaim seat rows affected: game_id=%d user_id=%d: %w", gameID, userID, err)
+	}
+	return ra > 0, nil
+}
+
 func boolToInt(b bool) int {
 	if b {
 		return 1
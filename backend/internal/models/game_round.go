@@ -0,0 +1,86 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// GameRound is one completed round of a game: the deal through the hand and crib counts.
+type GameRound struct {
+	GameID      int64           `json:"game_id"`
+	Round       int             `json:"round"`
+	DealerIndex int             `json:"dealer_index"`
+	Cut         string          `json:"cut"`
+	CribPoints  *int64          `json:"crib_points,omitempty"` // nil when the game ended before the crib count
+	Hands       []GameRoundHand `json:"hands,omitempty"`
+	Summary     json.RawMessage `json:"summary"` // the engine's RoundSummary
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// GameRoundHand is one seat's counted hand in a GameRound.
+type GameRoundHand struct {
+	Seat      int             `json:"seat"`
+	UserID    int64           `json:"user_id"`
+	Points    int64           `json:"points"`
+	Breakdown json.RawMessage `json:"breakdown"`
+}
+
+// InsertGameRoundTx records a completed round and its counted hands. A round already stored for
+// the game is left unchanged, so retried moves cannot duplicate it.
+func InsertGameRoundTx(tx *sql.Tx, r GameRound) error {
+	res, err := tx.Exec(
+		`INSERT INTO game_rounds(game_id, round, dealer_index, cut, crib_points, summary) VALUES (?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(game_id, round) DO NOTHING`,
+		r.GameID, r.Round, r.DealerIndex, r.Cut, r.CribPoints, string(r.Summary),
+	)
+	if err != nil {
+		return fmt.Errorf("insert game round (game_id=%d round=%d): %w", r.GameID, r.Round, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return nil
+	}
+	for _, h := range r.Hands {
+		if _, err := tx.Exec(
+			`INSERT INTO game_round_hands(game_id, round, seat, user_id, points, breakdown) VALUES (?, ?, ?, ?, ?, ?)`,
+			r.GameID, r.Round, h.Seat, h.UserID, h.Points, string(h.Breakdown),
+		); err != nil {
+			return fmt.Errorf("insert game round hand (game_id=%d round=%d seat=%d): %w", r.GameID, r.Round, h.Seat, err)
+		}
+	}
+	return nil
+}
+
+// ListGameRounds returns the game's completed rounds in order. Hands are not loaded; the
+// summary carries every seat's breakdown.
+func ListGameRounds(ctx context.Context, db *sql.DB, gameID int64) ([]GameRound, error) {
+	rows, err := db.QueryContext(ctx,
+		`SELECT game_id, round, dealer_index, cut, crib_points, summary, created_at
+		 FROM game_rounds WHERE game_id = ? ORDER BY round ASC`,
+		gameID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list game rounds (game_id=%d): %w", gameID, err)
+	}
+	defer rows.Close()
+	out := []GameRound{}
+	for rows.Next() {
+		var r GameRound
+		var crib sql.NullInt64
+		var summary string
+		if err := rows.Scan(&r.GameID, &r.Round, &r.DealerIndex, &r.Cut, &crib, &summary, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		if crib.Valid {
+			v := crib.Int64
+			r.CribPoints = &v
+		}
+		r.Summary = json.RawMessage(summary)
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// LongPauseThreshold marks a turn long enough that the game was probably abandoned and resumed.
+const LongPauseThreshold = 10 * time.Minute
+
+// GlobalPacingStats aggregates game_stats across all recorded games.
+type GlobalPacingStats struct {
+	Games              int64   `json:"games"`
+	AvgDurationSeconds float64 `json:"avg_duration_seconds"`
+	AvgHands           float64 `json:"avg_hands"`
+	AvgTurnSeconds     float64 `json:"avg_turn_seconds"`
+	LongestTurnSeconds int64   `json:"longest_turn_seconds"`
+	LongPauseGames     int64   `json:"long_pause_games"` // games with a turn >= LongPauseThreshold
+}
+
+// RecordGameStatsTx computes pacing metrics from the game's moves and stores them in game_stats.
+// A turn is a discard, card play or go; its length is the gap since the previous turn. The clock
+// starts at the first discard rather than at game creation, which would charge the time a lobby
+// spent filling to the first turn; that first discard is counted but not timed. Hands are
+// counted as discard rounds. Move timestamps have one-second resolution, so sub-second turns
+// count as zero. It is a no-op if stats already exist for the game.
+func RecordGameStatsTx(ctx context.Context, tx *sql.Tx, gameID int64, players int) error {
+	rows, err := tx.QueryContext(ctx,
+		`SELECT move_type, created_at FROM game_moves
+		 WHERE game_id = ? AND move_type IN ('discard', 'play_card', 'go')
+		 ORDER BY id`,
+		gameID,
+	)
+	if err != nil {
+		return fmt.Errorf("RecordGameStatsTx: query moves (game_id=%d): %w", gameID, err)
+	}
+	defer rows.Close()
+
+	var turns, timed, discards int64
+	var total, longest time.Duration
+	var started, prev, last time.Time
+	for rows.Next() {
+		var mt string
+		var at time.Time
+		if err := rows.Scan(&mt, &at); err != nil {
+			return fmt.Errorf("RecordGameStatsTx: scan move (game_id=%d): %w", gameID, err)
+		}
+		if turns == 0 {
+			started = at
+		} else {
+			gap := max(at.Sub(prev), 0)
+			total += gap
+			longest = max(longest, gap)
+			timed++
+		}
+		turns++
+		if mt == "discard" {
+			discards++
+		}
+		prev, last = at, at
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("RecordGameStatsTx: iterate moves (game_id=%d): %w", gameID, err)
+	}
+
+	var hands int64
+	if players > 0 {
+		hands = (discards + int64(players) - 1) / int64(players)
+	}
+	var avg float64
+	if timed > 0 {
+		avg = total.Seconds() / float64(timed)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO game_stats(game_id, duration_seconds, hands, turns, avg_turn_seconds, longest_turn_seconds)
+		 VALUES (?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(game_id) DO NOTHING`,
+		gameID, int64(max(last.Sub(started), 0).Seconds()), hands, turns, avg, int64(longest.Seconds()),
+	); err != nil {
+		return fmt.Errorf("RecordGameStatsTx: insert (game_id=%d): %w", gameID, err)
+	}
+	return nil
+}
+
+// GetGlobalPacingStats aggregates pacing metrics over every game with recorded stats.
+func GetGlobalPacingStats(ctx context.Context, db *sql.DB) (*GlobalPacingStats, error) {
+	var s GlobalPacingStats
+	err := db.QueryRowContext(ctx,
+		`SELECT COUNT(*),
+		        COALESCE(AVG(duration_seconds), 0),
+		        COALESCE(AVG(hands), 0),
+		        COALESCE(SUM(avg_turn_seconds * turns) / NULLIF(SUM(turns), 0), 0),
+		        COALESCE(MAX(longest_turn_seconds), 0),
+		        COALESCE(SUM(CASE WHEN longest_turn_seconds >= ? THEN 1 ELSE 0 END), 0)
+		 FROM game_stats`,
+		int64(LongPauseThreshold.Seconds()),
+	).Scan(&s.Games, &s.AvgDurationSeconds, &s.AvgHands, &s.AvgTurnSeconds, &s.LongestTurnSeconds, &s.LongPauseGames)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"fifteen-thirty-one-go/backend/internal/database"
+)
+
+func TestRecordGameStatsStartsAtFirstDiscard(t *testing.T) {
+	db, err := database.OpenAndMigrate(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	u, err := CreateUser(db, "alice", "x")
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	res, err := db.Exec(`INSERT INTO lobbies(name, host_id, max_players) VALUES ('stats', ?, 2)`, u.ID)
+	if err != nil {
+		t.Fatalf("create lobby: %v", err)
+	}
+	lobbyID, _ := res.LastInsertId()
+	// The lobby sat waiting for an hour before the first discard.
+	res, err = db.Exec(`INSERT INTO games(lobby_id, created_at) VALUES (?, '2026-01-01 09:00:00')`, lobbyID)
+	if err != nil {
+		t.Fatalf("create game: %v", err)
+	}
+	gameID, _ := res.LastInsertId()
+	for _, m := range []struct{ typ, at string }{
+		{"discard", "2026-01-01 10:00:00"},
+		{"discard", "2026-01-01 10:00:20"},
+		{"heels", "2026-01-01 10:00:20"}, // not a turn
+		{"play_card", "2026-01-01 10:00:30"},
+		{"go", "2026-01-01 10:01:00"},
+	} {
+		if _, err := db.Exec(`INSERT INTO game_moves(game_id, player_id, move_type, created_at) VALUES (?, ?, ?, ?)`, gameID, u.ID, m.typ, m.at); err != nil {
+			t.Fatalf("insert move: %v", err)
+		}
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	if err := RecordGameStatsTx(context.Background(), tx, gameID, 2); err != nil {
+		t.Fatalf("RecordGameStatsTx: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	var duration, hands, turns, longest int64
+	var avg float64
+	if err := db.QueryRow(
+		`SELECT duration_seconds, hands, turns, avg_turn_seconds, longest_turn_seconds FROM game_stats WHERE game_id = ?`, gameID,
+	).Scan(&duration, &hands, &turns, &avg, &longest); err != nil {
+		t.Fatalf("read stats: %v", err)
+	}
+	// Timed turns are the gaps after the first discard: 20s, 10s and 30s.
+	if duration != 60 || hands != 1 || turns != 4 || avg != 20 || longest != 30 {
+		t.Errorf("stats duration=%d hands=%d turns=%d avg=%v longest=%d, want 60 1 4 20 30", duration, hands, turns, avg, longest)
+	}
+}
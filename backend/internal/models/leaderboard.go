@@ -25,6 +25,8 @@ type LeaderboardPlayer struct {
 	GamesPlayed int64                 `json:"games_played"` // all-time (from scoreboard)
 	GamesWon    int64                 `json:"games_won"`    // all-time (from scoreboard)
 	WinRate     float64               `json:"win_rate"`     // all-time [0..1]
+	Skunks      int64                 `json:"skunks"`       // all-time wins with an opponent skunked
+	Skunked     int64                 `json:"skunked"`      // all-time losses by a skunk
 	Series      []LeaderboardDayPoint `json:"series"`
 }
 
@@ -34,115 +36,54 @@ type LeaderboardResponse struct {
 	Items []LeaderboardPlayer `json:"items"`
 }
 
-// BuildLeaderboard constructs a leaderboard response containing player statistics for the specified
-// time window. The days parameter is normalized to [1, 365]. Returns an error if database queries fail.
-func BuildLeaderboard(ctx context.Context, db *sql.DB, days int64) (*LeaderboardResponse, error) {
+// NormalizeLeaderboardDays clamps a requested window to [1, 365], defaulting to 30.
+func NormalizeLeaderboardDays(days int64) int64 {
 	if days <= 0 {
-		days = 30
+		return 30
 	}
 	if days > 365 {
-		days = 365
+		return 365
 	}
+	return days
+}
 
-	type u struct {
-		id       int64
-		username string
-	}
-	users := make([]u, 0)
-	{
-		rows, err := db.QueryContext(ctx, `SELECT id, username FROM users ORDER BY username COLLATE NOCASE ASC`)
-		if err != nil {
-			return nil, fmt.Errorf("BuildLeaderboard: querying users: %w", err)
-		}
-		defer rows.Close()
-		for rows.Next() {
-			var id int64
-			var username string
-			if err := rows.Scan(&id, &username); err != nil {
-				return nil, fmt.Errorf("BuildLeaderboard: scanning user row: %w", err)
-			}
-			users = append(users, u{id: id, username: username})
-		}
-		if err := rows.Err(); err != nil {
-			return nil, fmt.Errorf("BuildLeaderboard: iterating user rows: %w", err)
-		}
-	}
+// BuildLeaderboard constructs a leaderboard response containing player statistics for the specified
+// time window. The days parameter is normalized to [1, 365]. Returns an error if database queries fail.
+func BuildLeaderboard(ctx context.Context, db *sql.DB, days int64) (*LeaderboardResponse, error) {
+	days = NormalizeLeaderboardDays(days)
 
-	type totals struct {
-		played int64
-		won    int64
-	}
-	byUserTotals := map[int64]totals{}
-	{
-		rows, err := db.QueryContext(
-			ctx,
-			`SELECT user_id,
-			        COUNT(*) AS games_played,
-			        SUM(CASE WHEN position = 1 THEN 1 ELSE 0 END) AS games_won
-			 FROM scoreboard
-			 GROUP BY user_id`,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("BuildLeaderboard: querying totals from scoreboard: %w", err)
-		}
-		defer rows.Close()
-		for rows.Next() {
-			var userID, played, won int64
-			if err := rows.Scan(&userID, &played, &won); err != nil {
-				return nil, fmt.Errorf("BuildLeaderboard: scanning totals row: %w", err)
-			}
-			byUserTotals[userID] = totals{played: played, won: won}
-		}
-		if err := rows.Err(); err != nil {
-			return nil, fmt.Errorf("BuildLeaderboard: iterating totals rows: %w", err)
-		}
+	out := make([]LeaderboardPlayer, 0)
+	if err := StreamLeaderboard(ctx, db, days, func(p LeaderboardPlayer) error {
+		out = append(out, p)
+		return nil
+	}); err != nil {
+		return nil, err
 	}
 
-	type dayAgg struct {
-		played int64
-		won    int64
-	}
-	byUserDay := map[int64]map[string]dayAgg{}
-	{
-		since := fmt.Sprintf("-%d days", days-1)
-		rows, err := db.QueryContext(
-			ctx,
-			`SELECT user_id,
-			        DATE(created_at) AS day,
-			        COUNT(*) AS games_played,
-			        SUM(CASE WHEN position = 1 THEN 1 ELSE 0 END) AS games_won
-			 FROM scoreboard
-			 WHERE created_at >= DATE('now', ?)
-			 GROUP BY user_id, DATE(created_at)
-			 ORDER BY day ASC`,
-			since,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("BuildLeaderboard: querying daily aggregates from scoreboard: %w", err)
+	sort.SliceStable(out, func(i, j int) bool {
+		// Players with games come first.
+		if (out[i].GamesPlayed == 0) != (out[j].GamesPlayed == 0) {
+			return out[i].GamesPlayed > 0
 		}
-		defer rows.Close()
-		for rows.Next() {
-			var userID, played, won int64
-			var day string
-			if err := rows.Scan(&userID, &day, &played, &won); err != nil {
-				return nil, fmt.Errorf("BuildLeaderboard: scanning daily aggregate row: %w", err)
-			}
-			m := byUserDay[userID]
-			if m == nil {
-				m = map[string]dayAgg{}
-				byUserDay[userID] = m
-			}
-			m[day] = dayAgg{played: played, won: won}
+		if out[i].WinRate != out[j].WinRate {
+			return out[i].WinRate > out[j].WinRate
 		}
-		if err := rows.Err(); err != nil {
-			return nil, fmt.Errorf("BuildLeaderboard: iterating daily aggregate rows: %w", err)
+		if out[i].GamesPlayed != out[j].GamesPlayed {
+			return out[i].GamesPlayed > out[j].GamesPlayed
 		}
-	}
+		return out[i].Username < out[j].Username
+	})
 
-	// Respect cancellations before expensive in-memory processing.
-	if err := ctx.Err(); err != nil {
-		return nil, fmt.Errorf("BuildLeaderboard: context cancelled: %w", err)
-	}
+	return &LeaderboardResponse{Days: days, Items: out}, nil
+}
+
+// StreamLeaderboard computes the same per-player statistics as BuildLeaderboard but hands each
+// player to emit as soon as it is built (in username order) instead of collecting and ranking
+// them. It walks a single cursor of user rows joined with their totals and daily aggregates, so
+// only the current player is held in memory. It stops early if emit returns an error or ctx is
+// done. days is normalized to [1, 365].
+func StreamLeaderboard(ctx context.Context, db *sql.DB, days int64, emit func(LeaderboardPlayer) error) error {
+	days = NormalizeLeaderboardDays(days)
 
 	// Build the date list (oldest -> newest) as YYYY-MM-DD in UTC to match SQLite DATE('now', ...).
 	start := time.Now().UTC().AddDate(0, 0, -int(days)+1)
@@ -152,66 +93,101 @@ func BuildLeaderboard(ctx context.Context, db *sql.DB, days int64) (*Leaderboard
 		dates = append(dates, d.Format("2006-01-02"))
 	}
 
-	out := make([]LeaderboardPlayer, 0, len(users))
-	for _, usr := range users {
-		if err := ctx.Err(); err != nil {
-			return nil, fmt.Errorf("BuildLeaderboard: context cancelled: %w", err)
+	// One row per (user, day with games in the window); users without any get a single row
+	// with a NULL day.
+	rows, err := db.QueryContext(
+		ctx,
+		`WITH totals AS (
+		   SELECT s.user_id,
+		          COUNT(*) AS games_played,
+		          SUM(CASE WHEN s.position = 1 THEN 1 ELSE 0 END) AS games_won,
+		          SUM(CASE WHEN s.position = 1 AND EXISTS (SELECT 1 FROM scoreboard l WHERE l.game_id = s.game_id AND l.skunk_level >= 1) THEN 1 ELSE 0 END) AS skunks,
+		          SUM(CASE WHEN s.skunk_level >= 1 THEN 1 ELSE 0 END) AS skunked
+		   FROM scoreboard s
+		   WHERE s.outcome != 'abandoned'
+		   GROUP BY s.user_id
+		 ), daily AS (
+		   SELECT user_id,
+		          DATE(created_at) AS day,
+		          COUNT(*) AS games_played,
+		          SUM(CASE WHEN position = 1 THEN 1 ELSE 0 END) AS games_won
+		   FROM scoreboard
+		   WHERE created_at >= DATE('now', ?) AND outcome != 'abandoned'
+		   GROUP BY user_id, DATE(created_at)
+		 )
+		 SELECT u.id, u.username,
+		        COALESCE(t.games_played, 0), COALESCE(t.games_won, 0), COALESCE(t.skunks, 0), COALESCE(t.skunked, 0),
+		        d.day, COALESCE(d.games_played, 0), COALESCE(d.games_won, 0)
+		 FROM users u
+		 LEFT JOIN totals t ON t.user_id = u.id
+		 LEFT JOIN daily d ON d.user_id = u.id
+		 ORDER BY u.username COLLATE NOCASE ASC, u.id ASC, d.day ASC`,
+		fmt.Sprintf("-%d days", days-1),
+	)
+	if err != nil {
+		return fmt.Errorf("StreamLeaderboard: querying players: %w", err)
+	}
+	defer rows.Close()
+
+	type dayAgg struct {
+		played int64
+		won    int64
+	}
+	var cur *LeaderboardPlayer
+	byDay := map[string]dayAgg{}
+	flush := func() error {
+		if cur == nil {
+			return nil
 		}
-		t := byUserTotals[usr.id]
-		var allTimeRate float64
-		if t.played > 0 {
-			allTimeRate = float64(t.won) / float64(t.played)
+		if cur.GamesPlayed > 0 {
+			cur.WinRate = float64(cur.GamesWon) / float64(cur.GamesPlayed)
 		}
-
-		series := make([]LeaderboardDayPoint, 0, len(dates))
+		cur.Series = make([]LeaderboardDayPoint, 0, len(dates))
 		cumPlayed := int64(0)
 		cumWon := int64(0)
-		dayMap := byUserDay[usr.id]
 		for _, day := range dates {
-			if err := ctx.Err(); err != nil {
-				return nil, fmt.Errorf("BuildLeaderboard: context cancelled: %w", err)
-			}
-			da := dayAgg{}
-			if dayMap != nil {
-				da = dayMap[day]
-			}
+			da := byDay[day]
 			cumPlayed += da.played
 			cumWon += da.won
 			var wr float64
 			if cumPlayed > 0 {
 				wr = float64(cumWon) / float64(cumPlayed)
 			}
-			series = append(series, LeaderboardDayPoint{
+			cur.Series = append(cur.Series, LeaderboardDayPoint{
 				Date:        day,
 				GamesPlayed: da.played,
 				GamesWon:    da.won,
 				WinRate:     wr,
 			})
 		}
-
-		out = append(out, LeaderboardPlayer{
-			UserID:      usr.id,
-			Username:    usr.username,
-			GamesPlayed: t.played,
-			GamesWon:    t.won,
-			WinRate:     allTimeRate,
-			Series:      series,
-		})
+		p := *cur
+		cur = nil
+		clear(byDay)
+		return emit(p)
 	}
 
-	sort.SliceStable(out, func(i, j int) bool {
-		// Players with games come first.
-		if (out[i].GamesPlayed == 0) != (out[j].GamesPlayed == 0) {
-			return out[i].GamesPlayed > 0
+	for rows.Next() {
+		var p LeaderboardPlayer
+		var day sql.NullString
+		var dayPlayed, dayWon int64
+		if err := rows.Scan(&p.UserID, &p.Username, &p.GamesPlayed, &p.GamesWon, &p.Skunks, &p.Skunked, &day, &dayPlayed, &dayWon); err != nil {
+			return fmt.Errorf("StreamLeaderboard: scanning player row: %w", err)
 		}
-		if out[i].WinRate != out[j].WinRate {
-			return out[i].WinRate > out[j].WinRate
+		if cur == nil || cur.UserID != p.UserID {
+			if err := ctx.Err(); err != nil {
+				return fmt.Errorf("StreamLeaderboard: context cancelled: %w", err)
+			}
+			if err := flush(); err != nil {
+				return err
+			}
+			cur = &p
 		}
-		if out[i].GamesPlayed != out[j].GamesPlayed {
-			return out[i].GamesPlayed > out[j].GamesPlayed
+		if day.Valid {
+			byDay[day.String] = dayAgg{played: dayPlayed, won: dayWon}
 		}
-		return out[i].Username < out[j].Username
-	})
-
-	return &LeaderboardResponse{Days: days, Items: out}, nil
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("StreamLeaderboard: iterating player rows: %w", err)
+	}
+	return flush()
 }
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"fifteen-thirty-one-go/backend/internal/database"
+)
+
+func TestStreamLeaderboardEmitsEachPlayerOnce(t *testing.T) {
+	db, err := database.OpenAndMigrate(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	ids := map[string]int64{}
+	for _, name := range []string{"carol", "Bob", "alice"} {
+		u, err := CreateUser(db, name, "x")
+		if err != nil {
+			t.Fatalf("create user %s: %v", name, err)
+		}
+		ids[name] = u.ID
+	}
+	// Alice beat carol today and yesterday; a third game was abandoned and doesn't count.
+	for _, g := range []struct {
+		at      string
+		outcome string
+	}{
+		{"+0 days", "completed"},
+		{"-1 day", "completed"},
+		{"+0 days", "abandoned"},
+	} {
+		res, err := db.Exec(`INSERT INTO lobbies(name, host_id, max_players) VALUES ('lb', ?, 2)`, ids["alice"])
+		if err != nil {
+			t.Fatalf("create lobby: %v", err)
+		}
+		lobbyID, _ := res.LastInsertId()
+		res, err = db.Exec(`INSERT INTO games(lobby_id) VALUES (?)`, lobbyID)
+		if err != nil {
+			t.Fatalf("create game: %v", err)
+		}
+		gameID, _ := res.LastInsertId()
+		for pos, name := range []string{"alice", "carol"} {
+			if _, err := db.Exec(
+				`INSERT INTO scoreboard(user_id, game_id, final_score, position, outcome, created_at) VALUES (?, ?, ?, ?, ?, datetime('now', ?))`,
+				ids[name], gameID, 121-pos*30, pos+1, g.outcome, g.at,
+			); err != nil {
+				t.Fatalf("insert scoreboard: %v", err)
+			}
+		}
+	}
+
+	var got []LeaderboardPlayer
+	if err := StreamLeaderboard(context.Background(), db, 7, func(p LeaderboardPlayer) error {
+		got = append(got, p)
+		return nil
+	}); err != nil {
+		t.Fatalf("StreamLeaderboard: %v", err)
+	}
+	if len(got) != 3 || got[0].Username != "alice" || got[1].Username != "Bob" || got[2].Username != "carol" {
+		t.Fatalf("players %+v, want alice, Bob, carol once each", got)
+	}
+	alice, bob, carol := got[0], got[1], got[2]
+	if alice.GamesPlayed != 2 || alice.GamesWon != 2 || alice.WinRate != 1 {
+		t.Errorf("alice played %d won %d rate %v, want 2, 2 and 1", alice.GamesPlayed, alice.GamesWon, alice.WinRate)
+	}
+	if carol.GamesPlayed != 2 || carol.GamesWon != 0 {
+		t.Errorf("carol played %d won %d, want 2 and 0", carol.GamesPlayed, carol.GamesWon)
+	}
+	if bob.GamesPlayed != 0 || len(bob.Series) != 7 {
+		t.Errorf("bob played %d with %d series days, want 0 and 7", bob.GamesPlayed, len(bob.Series))
+	}
+	if len(alice.Series) != 7 {
+		t.Fatalf("alice has %d series days, want 7", len(alice.Series))
+	}
+	yesterday, today := alice.Series[5], alice.Series[6]
+	if yesterday.GamesPlayed != 1 || today.GamesPlayed != 1 || today.WinRate != 1 {
+		t.Errorf("alice's last two days %+v %+v, want one win each", yesterday, today)
+	}
+}
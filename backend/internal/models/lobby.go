@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -48,6 +49,15 @@ func GetLobbyByID(db *sql.DB, id int64) (*Lobby, error) {
 	return &l, nil
 }
 
+// CountActiveLobbies returns how many lobbies are not finished.
+func CountActiveLobbies(db *sql.DB) (int64, error) {
+	var n int64
+	if err := db.QueryRow(`SELECT COUNT(*) FROM lobbies WHERE status != 'finished'`).Scan(&n); err != nil {
+		return 0, fmt.Errorf("CountActiveLobbies: %w", err)
+	}
+	return n, nil
+}
+
 func ListLobbies(db *sql.DB, limit, offset int64) ([]Lobby, error) {
 	// Defensive defaults/caps to prevent unbounded reads.
 	if limit <= 0 {
@@ -150,6 +160,108 @@ func JoinLobbyTx(tx *sql.Tx, lobbyID int64) (*Lobby, error) {
 	return &l, nil
 }
 
+// LobbyAllowedBotDifficulties returns the bot difficulties the lobby is restricted to, or nil
+// when any difficulty may be added.
+func LobbyAllowedBotDifficulties(db *sql.DB, lobbyID int64) ([]string, error) {
+	var raw sql.NullString
+	err := db.QueryRow(`SELECT allowed_bot_difficulties FROM lobbies WHERE id = ?`, lobbyID).Scan(&raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !raw.Valid || strings.TrimSpace(raw.String) == "" {
+		return nil, nil
+	}
+	return strings.Split(raw.String, ","), nil
+}
+
+// IdleLobbySeat is a human seated in a lobby that is still filling, with idle kicking on, who
+// has shown no presence activity for the lobby's idle_kick_seconds.
+type IdleLobbySeat struct {
+	LobbyID  int64
+	GameID   int64
+	HostID   int64
+	UserID   int64
+	Position int64
+}
+
+// ListIdleLobbySeats returns, in lobby and seat order, the humans in lobbies that are still
+// filling whose last presence activity (a heartbeat or a presence update) is older than the
+// lobby allows. A full lobby is playing (its status stays waiting), so it is never listed.
+func ListIdleLobbySeats(db *sql.DB) ([]IdleLobbySeat, error) {
+	rows, err := db.Query(
+		`SELECT l.id, g.id, l.host_id, gp.user_id, gp.position
+		 FROM lobbies l
+		 JOIN games g ON g.lobby_id = l.id AND g.status = 'waiting'
+		 JOIN game_players gp ON gp.game_id = g.id AND gp.is_bot = 0
+		 LEFT JOIN user_presence up ON up.user_id = gp.user_id
+		 WHERE l.status = 'waiting' AND l.current_players < l.max_players AND l.idle_kick_seconds IS NOT NULL
+		   AND COALESCE(up.last_active, l.created_at) < datetime('now', '-' || l.idle_kick_seconds || ' seconds')
+		 ORDER BY l.id, gp.position`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ListIdleLobbySeats: %w", err)
+	}
+	defer rows.Close()
+	var out []IdleLobbySeat
+	for rows.Next() {
+		var s IdleLobbySeat
+		if err := rows.Scan(&s.LobbyID, &s.GameID, &s.HostID, &s.UserID, &s.Position); err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+// RemoveWaitingSeatTx frees userID's seat in a lobby that is still filling: the lobby's player
+// count drops, the seat row goes, and later seats move down one so positions stay contiguous
+// for the next join. It returns ErrLobbyNotJoinable once the lobby is full (play has begun) or
+// has left waiting.
+func RemoveWaitingSeatTx(tx *sql.Tx, lobbyID, gameID, userID, position int64) error {
+	res, err := tx.Exec(
+		`UPDATE lobbies SET current_players = current_players - 1
+		 WHERE id = ? AND status = 'waiting' AND current_players > 0 AND current_players < max_players`,
+		lobbyID,
+	)
+	if err != nil {
+		return fmt.Errorf("RemoveWaitingSeatTx: update lobby (lobby_id=%d): %w", lobbyID, err)
+	}
+	if ra, err := res.RowsAffected(); err != nil {
+		return err
+	} else if ra == 0 {
+		return ErrLobbyNotJoinable
+	}
+	res, err = tx.Exec(`DELETE FROM game_players WHERE game_id = ? AND user_id = ? AND position = ?`, gameID, userID, position)
+	if err != nil {
+		return fmt.Errorf("RemoveWaitingSeatTx: delete seat (game_id=%d user_id=%d): %w", gameID, userID, err)
+	}
+	if ra, err := res.RowsAffected(); err != nil {
+		return err
+	} else if ra == 0 {
+		return ErrNotFound
+	}
+	// Two steps through negative positions: SQLite checks the unique (game_id, position) index
+	// row by row, so shifting in place could collide mid-update.
+	if _, err := tx.Exec(`UPDATE game_players SET position = -position WHERE game_id = ? AND position > ?`, gameID, position); err != nil {
+		return fmt.Errorf("RemoveWaitingSeatTx: shift seats (game_id=%d): %w", gameID, err)
+	}
+	if _, err := tx.Exec(`UPDATE game_players SET position = -position - 1 WHERE game_id = ? AND position < 0`, gameID); err != nil {
+		return fmt.Errorf("RemoveWaitingSeatTx: shift seats (game_id=%d): %w", gameID, err)
+	}
+	return nil
+}
+
+// SetLobbyHostTx hands the lobby to hostID.
+func SetLobbyHostTx(tx *sql.Tx, lobbyID, hostID int64) error {
+	if _, err := tx.Exec(`UPDATE lobbies SET host_id = ? WHERE id = ?`, hostID, lobbyID); err != nil {
+		return fmt.Errorf("SetLobbyHostTx (lobby_id=%d): %w", lobbyID, err)
+	}
+	return nil
+}
+
 // DecrementLobbyCurrentPlayers decrements current_players by 1, but never below 0.
 // Used as a compensating action when a join flow fails after incrementing.
 func DecrementLobbyCurrentPlayers(db *sql.DB, lobbyID int64) error {
@@ -215,3 +327,50 @@ func SetLobbyStatusTx(tx *sql.Tx, lobbyID int64, status string) error {
 	}
 	return nil
 }
+
+// SpectatableGame is a lobby whose game is underway and open to spectators.
+type SpectatableGame struct {
+	LobbyID    int64  `json:"lobby_id"`
+	LobbyName  string `json:"lobby_name"`
+	GameID     int64  `json:"game_id"`
+	MaxPlayers int64  `json:"max_players"`
+	Spectators int64  `json:"spectators"` // visible spectators only
+}
+
+// ListSpectatableGames returns games open to spectators whose seats are all filled, most
+// watched first. Hidden (incognito) spectators are not counted.
+func ListSpectatableGames(db *sql.DB, limit, offset int64) ([]SpectatableGame, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 200 {
+		limit = 200
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	rows, err := db.Query(
+		`SELECT l.id, l.name, g.id, l.max_players,
+		        (SELECT COUNT(*) FROM lobby_spectators ls WHERE ls.lobby_id = l.id AND ls.hidden = 0) AS spectators
+		 FROM lobbies l
+		 JOIN games g ON g.lobby_id = l.id AND g.status IN ('waiting', 'in_progress')
+		 WHERE l.allow_spectators = 1 AND l.status != 'finished' AND l.current_players >= l.max_players
+		 ORDER BY spectators DESC, g.id DESC
+		 LIMIT ? OFFSET ?`,
+		limit, offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]SpectatableGame, 0)
+	for rows.Next() {
+		var s SpectatableGame
+		if err := rows.Scan(&s.LobbyID, &s.LobbyName, &s.GameID, &s.MaxPlayers, &s.Spectators); err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
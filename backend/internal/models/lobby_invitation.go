@@ -0,0 +1,93 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type LobbyInvitation struct {
+	ID        int64     `json:"id"`
+	LobbyID   int64     `json:"lobby_id"`
+	InviterID int64     `json:"inviter_id"`
+	InviteeID int64     `json:"invitee_id"`
+	Token     string    `json:"token"`
+	Status    string    `json:"status"` // pending|accepted|declined
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func CreateLobbyInvitationTx(tx *sql.Tx, lobbyID, inviterID, inviteeID int64, token string) (*LobbyInvitation, error) {
+	res, err := tx.Exec(
+		`INSERT INTO lobby_invitations(lobby_id, inviter_id, invitee_id, token) VALUES (?, ?, ?, ?)`,
+		lobbyID, inviterID, inviteeID, token,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create lobby invitation: lobby_id=%d invitee_id=%d: %w", lobbyID, inviteeID, err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	return &LobbyInvitation{
+		ID: id, LobbyID: lobbyID, InviterID: inviterID, InviteeID: inviteeID,
+		Token: token, Status: "pending", CreatedAt: time.Now().UTC(),
+	}, nil
+}
+
+func GetLobbyInvitationByToken(db *sql.DB, token string) (*LobbyInvitation, error) {
+	var inv LobbyInvitation
+	err := db.QueryRow(
+		`SELECT id, lobby_id, inviter_id, invitee_id, token, status, created_at FROM lobby_invitations WHERE token = ?`,
+		token,
+	).Scan(&inv.ID, &inv.LobbyID, &inv.InviterID, &inv.InviteeID, &inv.Token, &inv.Status, &inv.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
+
+// ListPendingInvitationsForUser returns the user's pending invitations to lobbies that are still waiting.
+func ListPendingInvitationsForUser(db *sql.DB, userID int64) ([]LobbyInvitation, error) {
+	rows, err := db.Query(
+		`SELECT i.id, i.lobby_id, i.inviter_id, i.invitee_id, i.token, i.status, i.created_at
+		 FROM lobby_invitations i
+		 JOIN lobbies l ON l.id = i.lobby_id
+		 WHERE i.invitee_id = ? AND i.status = 'pending' AND l.status = 'waiting'
+		 ORDER BY i.created_at DESC`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []LobbyInvitation{}
+	for rows.Next() {
+		var inv LobbyInvitation
+		if err := rows.Scan(&inv.ID, &inv.LobbyID, &inv.InviterID, &inv.InviteeID, &inv.Token, &inv.Status, &inv.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, inv)
+	}
+	return out, rows.Err()
+}
+
+// SetLobbyInvitationStatus moves a pending invitation to accepted/declined. It returns
+// ErrNotFound if the invitation is not pending anymore.
+func SetLobbyInvitationStatus(db *sql.DB, id int64, status string) error {
+	res, err := db.Exec(`UPDATE lobby_invitations SET status = ? WHERE id = ? AND status = 'pending'`, status, id)
+	if err != nil {
+		return err
+	}
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if ra == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
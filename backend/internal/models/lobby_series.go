@@ -0,0 +1,147 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// LobbySeries is the casual win tally shared by a lobby and the rematch lobbies started from it.
+type LobbySeries struct {
+	ID          int64               `json:"id"`
+	GamesPlayed int64               `json:"games_played"`
+	Standings   []SeriesStandingRow `json:"standings"` // most wins first
+}
+
+// SeriesStandingRow is one player's wins in a series.
+type SeriesStandingRow struct {
+	UserID   int64  `json:"user_id"`
+	Username string `json:"username"`
+	Wins     int64  `json:"wins"`
+}
+
+// RecordSeriesWinTx counts gameID, a finished game of the lobby, towards its series, starting a
+// series for the lobby if it has none, and returns the series id. Each game counts once: if it
+// was already recorded, recorded is false and the tally is left alone.
+func RecordSeriesWinTx(tx *sql.Tx, lobbyID, gameID, winnerID int64) (seriesID int64, recorded bool, err error) {
+	err = tx.QueryRow(`SELECT series_id FROM lobby_series_games WHERE game_id = ?`, gameID).Scan(&seriesID)
+	if err == nil {
+		return seriesID, false, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, false, fmt.Errorf("lobby series game (game_id=%d): %w", gameID, err)
+	}
+	var current sql.NullInt64
+	if err := tx.QueryRow(`SELECT series_id FROM lobbies WHERE id = ?`, lobbyID).Scan(&current); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, false, ErrNotFound
+		}
+		return 0, false, fmt.Errorf("lobby series (lobby_id=%d): %w", lobbyID, err)
+	}
+	id := current.Int64
+	if !current.Valid {
+		res, err := tx.Exec(`INSERT INTO lobby_series DEFAULT VALUES`)
+		if err != nil {
+			return 0, false, fmt.Errorf("create lobby series (lobby_id=%d): %w", lobbyID, err)
+		}
+		if id, err = res.LastInsertId(); err != nil {
+			return 0, false, err
+		}
+		if _, err := tx.Exec(`UPDATE lobbies SET series_id = ? WHERE id = ?`, id, lobbyID); err != nil {
+			return 0, false, fmt.Errorf("attach lobby series (lobby_id=%d series_id=%d): %w", lobbyID, id, err)
+		}
+	}
+	res, err := tx.Exec(
+		`INSERT INTO lobby_series_games(game_id, series_id, winner_id) VALUES (?, ?, ?) ON CONFLICT(game_id) DO NOTHING`,
+		gameID, id, winnerID,
+	)
+	if err != nil {
+		return 0, false, fmt.Errorf("record series game (series_id=%d game_id=%d): %w", id, gameID, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return 0, false, err
+	} else if n == 0 {
+		return id, false, nil
+	}
+	if _, err := tx.Exec(
+		`UPDATE lobby_series SET games_played = games_played + 1, updated_at = CURRENT_TIMESTAMP WHERE id = ?`,
+		id,
+	); err != nil {
+		return 0, false, fmt.Errorf("count series game (series_id=%d): %w", id, err)
+	}
+	if _, err := tx.Exec(
+		`INSERT INTO lobby_series_wins(series_id, user_id, wins) VALUES (?, ?, 1)
+		 ON CONFLICT(series_id, user_id) DO UPDATE SET wins = wins + 1`,
+		id, winnerID,
+	); err != nil {
+		return 0, false, fmt.Errorf("count series win (series_id=%d user_id=%d): %w", id, winnerID, err)
+	}
+	return id, true, nil
+}
+
+// LeaveLobbySeriesTx detaches the lobby from its series, so its next game starts a fresh tally.
+// Other lobbies of the series keep theirs.
+func LeaveLobbySeriesTx(tx *sql.Tx, lobbyID int64) error {
+	if _, err := tx.Exec(`UPDATE lobbies SET series_id = NULL WHERE id = ?`, lobbyID); err != nil {
+		return fmt.Errorf("leave lobby series (lobby_id=%d): %w", lobbyID, err)
+	}
+	return nil
+}
+
+// LeaveLobbySeries is LeaveLobbySeriesTx outside a transaction.
+func LeaveLobbySeries(db *sql.DB, lobbyID int64) error {
+	if _, err := db.Exec(`UPDATE lobbies SET series_id = NULL WHERE id = ?`, lobbyID); err != nil {
+		return fmt.Errorf("leave lobby series (lobby_id=%d): %w", lobbyID, err)
+	}
+	return nil
+}
+
+// ContinueLobbySeriesTx puts a rematch lobby in the series of the lobby it was started from. It
+// is a no-op when that lobby has no series yet.
+func ContinueLobbySeriesTx(tx *sql.Tx, lobbyID, fromLobbyID int64) error {
+	if _, err := tx.Exec(
+		`UPDATE lobbies SET series_id = (SELECT series_id FROM lobbies WHERE id = ?) WHERE id = ?`,
+		fromLobbyID, lobbyID,
+	); err != nil {
+		return fmt.Errorf("continue lobby series (lobby_id=%d from=%d): %w", lobbyID, fromLobbyID, err)
+	}
+	return nil
+}
+
+// GetLobbySeries returns the lobby's current series with its standings, or ErrNotFound when the
+// lobby has none.
+func GetLobbySeries(ctx context.Context, db *sql.DB, lobbyID int64) (*LobbySeries, error) {
+	var s LobbySeries
+	err := db.QueryRowContext(ctx,
+		`SELECT s.id, s.games_played FROM lobbies l JOIN lobby_series s ON s.id = l.series_id WHERE l.id = ?`,
+		lobbyID,
+	).Scan(&s.ID, &s.GamesPlayed)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get lobby series (lobby_id=%d): %w", lobbyID, err)
+	}
+	rows, err := db.QueryContext(ctx,
+		`SELECT w.user_id, u.username, w.wins FROM lobby_series_wins w JOIN users u ON u.id = w.user_id
+		 WHERE w.series_id = ? ORDER BY w.wins DESC, u.username ASC`,
+		s.ID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list series wins (series_id=%d): %w", s.ID, err)
+	}
+	defer rows.Close()
+	s.Standings = []SeriesStandingRow{}
+	for rows.Next() {
+		var r SeriesStandingRow
+		if err := rows.Scan(&r.UserID, &r.Username, &r.Wins); err != nil {
+			return nil, err
+		}
+		s.Standings = append(s.Standings, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
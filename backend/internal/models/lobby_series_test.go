@@ -0,0 +1,70 @@
+package models
+
+import (
+	"path/filepath"
+	"testing"
+
+	"fifteen-thirty-one-go/backend/internal/database"
+)
+
+func TestRecordSeriesWinCountsEachGameOnce(t *testing.T) {
+	db, err := database.OpenAndMigrate(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	u, err := CreateUser(db, "alice", "x")
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	l, err := CreateLobby(db, "series", u.ID, 2)
+	if err != nil {
+		t.Fatalf("create lobby: %v", err)
+	}
+	record := func(gameID int64) bool {
+		t.Helper()
+		tx, err := db.Begin()
+		if err != nil {
+			t.Fatalf("begin: %v", err)
+		}
+		defer tx.Rollback()
+		_, recorded, err := RecordSeriesWinTx(tx, l.ID, gameID, u.ID)
+		if err != nil {
+			t.Fatalf("RecordSeriesWinTx(game %d): %v", gameID, err)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("commit: %v", err)
+		}
+		return recorded
+	}
+
+	first, err := CreateGame(db, l.ID)
+	if err != nil {
+		t.Fatalf("create game: %v", err)
+	}
+	if !record(first.ID) {
+		t.Fatal("first finalization of the game was not recorded")
+	}
+	if record(first.ID) {
+		t.Error("second finalization of the same game was recorded again")
+	}
+	if err := SetGameStatus(db, first.ID, "finished"); err != nil {
+		t.Fatalf("finish game: %v", err)
+	}
+	second, err := CreateGame(db, l.ID)
+	if err != nil {
+		t.Fatalf("create game: %v", err)
+	}
+	if !record(second.ID) {
+		t.Error("the lobby's next game was not recorded")
+	}
+
+	s, err := GetLobbySeries(t.Context(), db, l.ID)
+	if err != nil {
+		t.Fatalf("GetLobbySeries: %v", err)
+	}
+	if s.GamesPlayed != 2 || len(s.Standings) != 1 || s.Standings[0].Wins != 2 {
+		t.Errorf("series played %d with standings %+v, want 2 games and 2 wins", s.GamesPlayed, s.Standings)
+	}
+}
@@ -0,0 +1,206 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Match statuses.
+const (
+	MatchInProgress = "in_progress"
+	MatchFinished   = "finished"
+	MatchForfeited  = "forfeited"
+	MatchAbandoned  = "abandoned"
+)
+
+// Match is a series of games in one lobby played until someone reaches TargetPoints game points.
+type Match struct {
+	ID           int64       `json:"id"`
+	LobbyID      int64       `json:"lobby_id"`
+	TargetPoints int64       `json:"target_points"`
+	Status       string      `json:"status"` // in_progress|finished|forfeited|abandoned
+	WinnerID     *int64      `json:"winner_id,omitempty"`
+	ForfeitedBy  *int64      `json:"forfeited_by,omitempty"`
+	Games        []MatchGame `json:"games"`
+	CreatedAt    time.Time   `json:"created_at"`
+	FinishedAt   *time.Time  `json:"finished_at,omitempty"`
+}
+
+// MatchGame is one game of a match. WinnerID is nil until the game is finalized.
+type MatchGame struct {
+	GameNumber int64  `json:"game_number"`
+	GameID     int64  `json:"game_id"`
+	WinnerID   *int64 `json:"winner_id,omitempty"`
+	SkunkLevel int    `json:"skunk_level"`
+	Points     int64  `json:"points"`
+}
+
+// CreateMatchTx starts a match for the lobby with its first game.
+func CreateMatchTx(tx *sql.Tx, lobbyID, firstGameID, targetPoints int64) (int64, error) {
+	res, err := tx.Exec(`INSERT INTO matches(lobby_id, target_points) VALUES (?, ?)`, lobbyID, targetPoints)
+	if err != nil {
+		return 0, fmt.Errorf("create match (lobby_id=%d): %w", lobbyID, err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	if err := AddMatchGameTx(tx, id, 1, firstGameID); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// AddMatchGameTx appends a game to the match as its gameNumber'th game.
+func AddMatchGameTx(tx *sql.Tx, matchID, gameNumber, gameID int64) error {
+	if _, err := tx.Exec(
+		`INSERT INTO match_games(match_id, game_number, game_id) VALUES (?, ?, ?)`,
+		matchID, gameNumber, gameID,
+	); err != nil {
+		return fmt.Errorf("add match game (match_id=%d game_id=%d): %w", matchID, gameID, err)
+	}
+	return nil
+}
+
+// ActiveMatchForGameTx returns the in-progress match the game belongs to, with its games. It
+// returns ErrNotFound when the game is not part of an in-progress match.
+func ActiveMatchForGameTx(tx *sql.Tx, gameID int64) (*Match, error) {
+	var matchID int64
+	err := tx.QueryRow(
+		`SELECT m.id FROM matches m JOIN match_games mg ON mg.match_id = m.id
+		 WHERE mg.game_id = ? AND m.status = 'in_progress'`,
+		gameID,
+	).Scan(&matchID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("active match for game (game_id=%d): %w", gameID, err)
+	}
+	return getMatch(context.Background(), tx, matchID)
+}
+
+// RecordMatchGameResultTx stores the result of a match game. It reports false when the result
+// was already recorded, so a retried finalization cannot score the game twice.
+func RecordMatchGameResultTx(tx *sql.Tx, gameID, winnerID int64, skunkLevel int, points int64) (bool, error) {
+	res, err := tx.Exec(
+		`UPDATE match_games SET winner_id = ?, skunk_level = ?, points = ? WHERE game_id = ? AND winner_id IS NULL`,
+		winnerID, skunkLevel, points, gameID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("record match game result (game_id=%d): %w", gameID, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// EndMatchTx moves an in-progress match to status. winnerID and forfeitedBy may be nil.
+func EndMatchTx(tx *sql.Tx, matchID int64, status string, winnerID, forfeitedBy *int64) error {
+	switch status {
+	case MatchFinished, MatchForfeited, MatchAbandoned:
+	default:
+		return fmt.Errorf("end match: invalid status %q", status)
+	}
+	if _, err := tx.Exec(
+		`UPDATE matches SET status = ?, winner_id = ?, forfeited_by = ?, finished_at = CURRENT_TIMESTAMP
+		 WHERE id = ? AND status = 'in_progress'`,
+		status, winnerID, forfeitedBy, matchID,
+	); err != nil {
+		return fmt.Errorf("end match (match_id=%d): %w", matchID, err)
+	}
+	return nil
+}
+
+// MatchIDForGame returns the match the game belongs to, or ErrNotFound.
+func MatchIDForGame(db *sql.DB, gameID int64) (int64, error) {
+	var id int64
+	err := db.QueryRow(`SELECT match_id FROM match_games WHERE game_id = ?`, gameID).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("match for game (game_id=%d): %w", gameID, err)
+	}
+	return id, nil
+}
+
+// GetMatch returns the match with its games in order, or ErrNotFound.
+func GetMatch(ctx context.Context, db *sql.DB, matchID int64) (*Match, error) {
+	return getMatch(ctx, db, matchID)
+}
+
+type querier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+func getMatch(ctx context.Context, q querier, matchID int64) (*Match, error) {
+	var m Match
+	var winner, forfeitedBy sql.NullInt64
+	var finished sql.NullTime
+	err := q.QueryRowContext(ctx,
+		`SELECT id, lobby_id, target_points, status, winner_id, forfeited_by, created_at, finished_at FROM matches WHERE id = ?`,
+		matchID,
+	).Scan(&m.ID, &m.LobbyID, &m.TargetPoints, &m.Status, &winner, &forfeitedBy, &m.CreatedAt, &finished)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get match (match_id=%d): %w", matchID, err)
+	}
+	if winner.Valid {
+		v := winner.Int64
+		m.WinnerID = &v
+	}
+	if forfeitedBy.Valid {
+		v := forfeitedBy.Int64
+		m.ForfeitedBy = &v
+	}
+	if finished.Valid {
+		v := finished.Time
+		m.FinishedAt = &v
+	}
+
+	rows, err := q.QueryContext(ctx,
+		`SELECT game_number, game_id, winner_id, skunk_level, points FROM match_games WHERE match_id = ? ORDER BY game_number ASC`,
+		matchID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list match games (match_id=%d): %w", matchID, err)
+	}
+	defer rows.Close()
+	m.Games = []MatchGame{}
+	for rows.Next() {
+		var g MatchGame
+		var w sql.NullInt64
+		if err := rows.Scan(&g.GameNumber, &g.GameID, &w, &g.SkunkLevel, &g.Points); err != nil {
+			return nil, err
+		}
+		if w.Valid {
+			v := w.Int64
+			g.WinnerID = &v
+		}
+		m.Games = append(m.Games, g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Points sums the match points each user has won so far.
+func (m *Match) Points() map[int64]int64 {
+	out := map[int64]int64{}
+	for _, g := range m.Games {
+		if g.WinnerID != nil {
+			out[*g.WinnerID] += g.Points
+		}
+	}
+	return out
+}
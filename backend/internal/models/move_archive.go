@@ -0,0 +1,134 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MoveSummary aggregates one player's uncorrected moves of one type in an archived game.
+type MoveSummary struct {
+	PlayerID       int64  `json:"player_id"`
+	MoveType       string `json:"move_type"`
+	Moves          int64  `json:"moves"`
+	PointsVerified int64  `json:"points_verified"`
+}
+
+// ListArchivableGames returns up to limit finished games whose moves have not been archived and
+// that finished more than age ago.
+func ListArchivableGames(db *sql.DB, age time.Duration, limit int) ([]int64, error) {
+	rows, err := db.Query(
+		`SELECT id FROM games
+		 WHERE status = 'finished' AND moves_archived_at IS NULL
+		   AND finished_at IS NOT NULL AND finished_at < datetime('now', ?)
+		 ORDER BY id LIMIT ?`,
+		idleModifier(age), limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list archivable games: %w", err)
+	}
+	defer rows.Close()
+	var out []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
+
+// ArchiveGameMoves summarizes a finished game's moves into game_move_summaries (and game_stats,
+// if pacing stats were never recorded), then deletes the raw moves. It returns the number of
+// moves pruned; a game that is not finished or is already archived is left alone.
+func ArchiveGameMoves(ctx context.Context, db *sql.DB, gameID int64) (int64, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var status string
+	var archivedAt sql.NullTime
+	err = tx.QueryRowContext(ctx, `SELECT status, moves_archived_at FROM games WHERE id = ?`, gameID).Scan(&status, &archivedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("ArchiveGameMoves: query game (game_id=%d): %w", gameID, err)
+	}
+	if status != "finished" || archivedAt.Valid {
+		return 0, nil
+	}
+
+	var players int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM game_players WHERE game_id = ?`, gameID).Scan(&players); err != nil {
+		return 0, fmt.Errorf("ArchiveGameMoves: count players (game_id=%d): %w", gameID, err)
+	}
+	if err := RecordGameStatsTx(ctx, tx, gameID, players); err != nil {
+		return 0, err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO game_move_summaries(game_id, player_id, move_type, moves, points_verified)
+		 SELECT game_id, player_id, move_type, COUNT(*), COALESCE(SUM(score_verified), 0)
+		 FROM game_moves WHERE game_id = ? AND is_corrected = 0
+		 GROUP BY player_id, move_type
+		 ON CONFLICT(game_id, player_id, move_type) DO NOTHING`,
+		gameID,
+	); err != nil {
+		return 0, fmt.Errorf("ArchiveGameMoves: summarize moves (game_id=%d): %w", gameID, err)
+	}
+	res, err := tx.ExecContext(ctx, `DELETE FROM game_moves WHERE game_id = ?`, gameID)
+	if err != nil {
+		return 0, fmt.Errorf("ArchiveGameMoves: prune moves (game_id=%d): %w", gameID, err)
+	}
+	pruned, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE games SET moves_archived_at = CURRENT_TIMESTAMP WHERE id = ?`, gameID); err != nil {
+		return 0, fmt.Errorf("ArchiveGameMoves: mark archived (game_id=%d): %w", gameID, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return pruned, nil
+}
+
+// GameMovesArchived reports whether the game's raw moves have been pruned by archival.
+func GameMovesArchived(db *sql.DB, gameID int64) (bool, error) {
+	var archivedAt sql.NullTime
+	err := db.QueryRow(`SELECT moves_archived_at FROM games WHERE id = ?`, gameID).Scan(&archivedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, ErrNotFound
+	}
+	if err != nil {
+		return false, err
+	}
+	return archivedAt.Valid, nil
+}
+
+// ListMoveSummaries returns the archived move summary of a game.
+func ListMoveSummaries(db *sql.DB, gameID int64) ([]MoveSummary, error) {
+	rows, err := db.Query(
+		`SELECT player_id, move_type, moves, points_verified
+		 FROM game_move_summaries WHERE game_id = ? ORDER BY player_id, move_type`,
+		gameID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []MoveSummary{}
+	for rows.Next() {
+		var s MoveSummary
+		if err := rows.Scan(&s.PlayerID, &s.MoveType, &s.Moves, &s.PointsVerified); err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
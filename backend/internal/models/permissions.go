@@ -19,3 +19,20 @@ func IsUserInGame(db *sql.DB, userID int64, gameID int64) (bool, error) {
 	}
 	return true, nil
 }
+
+// IsUserSpectatingGame reports whether userID is a spectator of the lobby that owns gameID.
+func IsUserSpectatingGame(db *sql.DB, userID int64, gameID int64) (bool, error) {
+	var exists int
+	err := db.QueryRow(
+		`SELECT 1 FROM lobby_spectators ls JOIN games g ON g.lobby_id = ls.lobby_id
+		 WHERE g.id = ? AND ls.user_id = ? LIMIT 1`,
+		gameID, userID,
+	).Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
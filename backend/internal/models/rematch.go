@@ -0,0 +1,77 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// Rematch links a finished game to the lobby started to play it again. LobbyID and GameID are
+// nil while the rematch is still being set up.
+type Rematch struct {
+	SourceGameID int64
+	LobbyID      *int64
+	GameID       *int64
+	CreatedBy    int64
+}
+
+// ClaimRematch reserves the rematch of sourceGameID for userID. When someone already claimed it,
+// it returns false with their rematch, or ErrNotFound if that claim was released meanwhile.
+func ClaimRematch(db *sql.DB, sourceGameID, userID int64) (bool, *Rematch, error) {
+	res, err := db.Exec(
+		`INSERT INTO game_rematches(source_game_id, created_by) VALUES (?, ?) ON CONFLICT(source_game_id) DO NOTHING`,
+		sourceGameID, userID,
+	)
+	if err != nil {
+		return false, nil, fmt.Errorf("claim rematch (game_id=%d): %w", sourceGameID, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, nil, err
+	}
+	if n == 1 {
+		return true, nil, nil
+	}
+	var r Rematch
+	var lobbyID, gameID sql.NullInt64
+	err = db.QueryRow(
+		`SELECT source_game_id, lobby_id, game_id, created_by FROM game_rematches WHERE source_game_id = ?`,
+		sourceGameID,
+	).Scan(&r.SourceGameID, &lobbyID, &gameID, &r.CreatedBy)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Released between our insert and this read; the caller can simply retry.
+		return false, nil, ErrNotFound
+	}
+	if err != nil {
+		return false, nil, fmt.Errorf("get rematch (game_id=%d): %w", sourceGameID, err)
+	}
+	if lobbyID.Valid {
+		r.LobbyID = &lobbyID.Int64
+	}
+	if gameID.Valid {
+		r.GameID = &gameID.Int64
+	}
+	return false, &r, nil
+}
+
+// CompleteRematch records the lobby and game a claimed rematch was set up in.
+func CompleteRematch(db *sql.DB, sourceGameID, lobbyID, gameID int64) error {
+	if _, err := db.Exec(
+		`UPDATE game_rematches SET lobby_id = ?, game_id = ? WHERE source_game_id = ?`,
+		lobbyID, gameID, sourceGameID,
+	); err != nil {
+		return fmt.Errorf("complete rematch (game_id=%d): %w", sourceGameID, err)
+	}
+	return nil
+}
+
+// ReleaseRematch drops an unfinished claim so the rematch can be tried again.
+func ReleaseRematch(db *sql.DB, sourceGameID int64) error {
+	if _, err := db.Exec(
+		`DELETE FROM game_rematches WHERE source_game_id = ? AND lobby_id IS NULL`,
+		sourceGameID,
+	); err != nil {
+		return fmt.Errorf("release rematch (game_id=%d): %w", sourceGameID, err)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"time"
@@ -12,6 +13,8 @@ type ScoreboardEntry struct {
 	GameID     int64     `json:"game_id"`
 	FinalScore int64     `json:"final_score"`
 	Position   int64     `json:"position"`
+	Outcome    string    `json:"outcome"`     // completed|forfeit|abandoned
+	SkunkLevel int       `json:"skunk_level"` // 0 none, 1 skunked, 2 double skunked (losers only)
 	CreatedAt  time.Time `json:"created_at"`
 }
 
@@ -19,6 +22,31 @@ type UserStats struct {
 	UserID      int64 `json:"user_id"`
 	GamesPlayed int64 `json:"games_played"`
 	GamesWon    int64 `json:"games_won"`
+	// Skunks counts wins in which an opponent was skunked (double skunks included);
+	// Skunked counts games the user lost by a skunk. Only completed games are scored.
+	Skunks        int64 `json:"skunks"`
+	DoubleSkunks  int64 `json:"double_skunks"`
+	Skunked       int64 `json:"skunked"`
+	DoubleSkunked int64 `json:"double_skunked"`
+}
+
+// InsertScoreboardRowTx records one player's final standing. It reports false, without error,
+// when the game already has a row for that player or rank: another finalization got there
+// first, and the caller must not count the game toward the player's stats again.
+func InsertScoreboardRowTx(ctx context.Context, tx *sql.Tx, userID, gameID, finalScore, position int64, outcome string, skunkLevel int) (bool, error) {
+	res, err := tx.ExecContext(ctx,
+		`INSERT INTO scoreboard(user_id, game_id, final_score, position, outcome, skunk_level) VALUES (?, ?, ?, ?, ?, ?)
+		 ON CONFLICT DO NOTHING`,
+		userID, gameID, finalScore, position, outcome, skunkLevel,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
 }
 
 func InsertScoreboardEntry(db *sql.DB, userID, gameID, finalScore, position int64) (*ScoreboardEntry, error) {
@@ -35,9 +63,9 @@ func InsertScoreboardEntry(db *sql.DB, userID, gameID, finalScore, position int6
 	}
 	var e ScoreboardEntry
 	if err := db.QueryRow(
-		`SELECT id, user_id, game_id, final_score, position, created_at FROM scoreboard WHERE id = ?`,
+		`SELECT id, user_id, game_id, final_score, position, outcome, skunk_level, created_at FROM scoreboard WHERE id = ?`,
 		id,
-	).Scan(&e.ID, &e.UserID, &e.GameID, &e.FinalScore, &e.Position, &e.CreatedAt); err != nil {
+	).Scan(&e.ID, &e.UserID, &e.GameID, &e.FinalScore, &e.Position, &e.Outcome, &e.SkunkLevel, &e.CreatedAt); err != nil {
 		return nil, err
 	}
 	return &e, nil
@@ -48,7 +76,7 @@ func ListScoreboard(db *sql.DB, limit int64) ([]ScoreboardEntry, error) {
 		limit = 50
 	}
 	rows, err := db.Query(
-		`SELECT id, user_id, game_id, final_score, position, created_at FROM scoreboard ORDER BY created_at DESC LIMIT ?`,
+		`SELECT id, user_id, game_id, final_score, position, outcome, skunk_level, created_at FROM scoreboard ORDER BY created_at DESC LIMIT ?`,
 		limit,
 	)
 	if err != nil {
@@ -59,7 +87,7 @@ func ListScoreboard(db *sql.DB, limit int64) ([]ScoreboardEntry, error) {
 	var out []ScoreboardEntry
 	for rows.Next() {
 		var e ScoreboardEntry
-		if err := rows.Scan(&e.ID, &e.UserID, &e.GameID, &e.FinalScore, &e.Position, &e.CreatedAt); err != nil {
+		if err := rows.Scan(&e.ID, &e.UserID, &e.GameID, &e.FinalScore, &e.Position, &e.Outcome, &e.SkunkLevel, &e.CreatedAt); err != nil {
 			return nil, err
 		}
 		out = append(out, e)
@@ -76,5 +104,17 @@ func GetUserStats(db *sql.DB, userID int64) (*UserStats, error) {
 		}
 		return nil, err
 	}
+	// A win is a skunk when any loser in the same game carries a skunk level.
+	if err := db.QueryRow(
+		`SELECT
+		   COALESCE(SUM(CASE WHEN s.position = 1 AND EXISTS (SELECT 1 FROM scoreboard l WHERE l.game_id = s.game_id AND l.skunk_level >= 1) THEN 1 ELSE 0 END), 0),
+		   COALESCE(SUM(CASE WHEN s.position = 1 AND EXISTS (SELECT 1 FROM scoreboard l WHERE l.game_id = s.game_id AND l.skunk_level >= 2) THEN 1 ELSE 0 END), 0),
+		   COALESCE(SUM(CASE WHEN s.skunk_level >= 1 THEN 1 ELSE 0 END), 0),
+		   COALESCE(SUM(CASE WHEN s.skunk_level >= 2 THEN 1 ELSE 0 END), 0)
+		 FROM scoreboard s WHERE s.user_id = ?`,
+		userID,
+	).Scan(&s.Skunks, &s.DoubleSkunks, &s.Skunked, &s.DoubleSkunked); err != nil {
+		return nil, err
+	}
 	return &s, nil
 }
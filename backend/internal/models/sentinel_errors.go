@@ -7,6 +7,7 @@ var (
 	ErrInvalidCard             = errors.New("invalid card")
 	ErrNotAPlayer              = errors.New("not a player")
 	ErrNotYourTurn             = errors.New("not your turn")
+	ErrTurnAlreadyAdvanced     = errors.New("turn already advanced")
 	ErrNotInPeggingStage       = errors.New("not in pegging stage")
 	ErrWouldExceed31           = errors.New("would exceed 31")
 	ErrCardNotInHand           = errors.New("card not in hand")
@@ -17,6 +18,7 @@ var (
 	ErrUnknownMoveType         = errors.New("unknown move type")
 	ErrHasLegalPlay            = errors.New("you have a legal play")
 	ErrInvalidDiscardCount     = errors.New("invalid discard count")
+	ErrInvalidCribSize         = errors.New("invalid crib size")
 	ErrInvalidPlayer           = errors.New("invalid player")
 	ErrLobbyFull               = errors.New("lobby full")
 	ErrLobbyNotJoinable        = errors.New("lobby not joinable")
@@ -24,4 +26,15 @@ var (
 	ErrGameStateConflict       = errors.New("game state conflict")
 	ErrPlayerNotInGame         = errors.New("player not in game")
 	ErrGameNotFound            = errors.New("game not found")
+	ErrHandStateMismatch       = errors.New("hand state mismatch")
+	ErrPlayerResigned          = errors.New("player resigned")
+	ErrLobbyHasActiveGame      = errors.New("lobby already has an active game")
+	ErrNotInCountingStage      = errors.New("not in counting stage")
+	ErrMugginsAlreadyCalled    = errors.New("muggins already called")
+	ErrMugginsDisabled         = errors.New("muggins not enabled")
+	ErrUndoUnavailable         = errors.New("nothing to undo")
+	ErrUndoNotYourPlay         = errors.New("undo of another player's play")
+	ErrGameNotStarted          = errors.New("game not started")
+	ErrGameFinished            = errors.New("game finished")
+	ErrMatchGameRecorded       = errors.New("match game already recorded")
 )
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Scoreboard outcomes.
+const (
+	OutcomeCompleted = "completed"
+	OutcomeForfeit   = "forfeit"
+	OutcomeAbandoned = "abandoned"
+)
+
+// staleGameCondition matches active games with no move or lobby chat in the idle window. The
+// window is bound three times as a datetime('now', ?) modifier.
+const staleGameCondition = `g.status IN ('waiting', 'in_progress')
+	   AND g.created_at < datetime('now', ?)
+	   AND NOT EXISTS (SELECT 1 FROM game_moves m WHERE m.game_id = g.id AND m.created_at >= datetime('now', ?))
+	   AND NOT EXISTS (SELECT 1 FROM lobby_messages lm WHERE lm.lobby_id = g.lobby_id AND lm.created_at >= datetime('now', ?))`
+
+func idleModifier(idle time.Duration) string {
+	return fmt.Sprintf("-%d seconds", int64(idle/time.Second))
+}
+
+// ListStaleGames returns up to limit active games that have had no move or chat for idle.
+func ListStaleGames(db *sql.DB, idle time.Duration, limit int) ([]int64, error) {
+	mod := idleModifier(idle)
+	rows, err := db.Query(
+		`SELECT g.id FROM games g WHERE `+staleGameCondition+` ORDER BY g.id LIMIT ?`,
+		mod, mod, mod, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list stale games: %w", err)
+	}
+	defer rows.Close()
+	var out []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
+
+// GameIsStaleTx re-checks ListStaleGames' condition for one game inside tx, so a game that saw
+// activity after it was listed is left alone.
+func GameIsStaleTx(tx *sql.Tx, gameID int64, idle time.Duration) (bool, error) {
+	mod := idleModifier(idle)
+	var one int
+	err := tx.QueryRow(
+		`SELECT 1 FROM games g WHERE g.id = ? AND `+staleGameCondition,
+		gameID, mod, mod, mod,
+	).Scan(&one)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ActiveHumanIDs returns the human players (not resigned) who recorded at least one move.
+func ActiveHumanIDs(db *sql.DB, gameID int64) ([]int64, error) {
+	rows, err := db.Query(
+		`SELECT DISTINCT gp.user_id
+		 FROM game_players gp
+		 JOIN game_moves m ON m.game_id = gp.game_id AND m.player_id = gp.user_id
+		 WHERE gp.game_id = ? AND gp.resigned = 0 AND (gp.is_bot = 0 OR gp.bot_takeover_at IS NOT NULL)
+		 ORDER BY gp.user_id`,
+		gameID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
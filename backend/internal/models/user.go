@@ -1,11 +1,17 @@
 package models
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 )
 
+var ErrUsernameTaken = errors.New("username already taken")
+var ErrUsernameChangeTooSoon = errors.New("username changed too recently")
+
 type User struct {
 	ID           int64     `json:"id"`
 	Username     string    `json:"username"`
@@ -59,3 +65,148 @@ func GetUserByUsername(db *sql.DB, username string) (*User, error) {
 	}
 	return &u, nil
 }
+
+// PublicUser is the part of a user's profile anyone may see.
+type PublicUser struct {
+	ID        int64   `json:"id"`
+	Username  string  `json:"username"`
+	AvatarURL *string `json:"avatar_url,omitempty"`
+}
+
+// ListPublicUsersByIDs returns the public profiles of the given users in one query, ordered by
+// id. Unknown ids are skipped.
+func ListPublicUsersByIDs(ctx context.Context, db *sql.DB, ids []int64) ([]PublicUser, error) {
+	out := []PublicUser{}
+	if len(ids) == 0 {
+		return out, nil
+	}
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	rows, err := db.QueryContext(ctx,
+		`SELECT id, username, avatar_url FROM users WHERE id IN (?`+strings.Repeat(", ?", len(ids)-1)+`) ORDER BY id`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var u PublicUser
+		var avatar sql.NullString
+		if err := rows.Scan(&u.ID, &u.Username, &avatar); err != nil {
+			return nil, err
+		}
+		if avatar.Valid && avatar.String != "" {
+			v := avatar.String
+			u.AvatarURL = &v
+		}
+		out = append(out, u)
+	}
+	return out, rows.Err()
+}
+
+// UsernameTaken reports whether another user holds username, ignoring case.
+func UsernameTaken(db *sql.DB, username string, exceptUserID int64) (bool, error) {
+	var n int64
+	if err := db.QueryRow(
+		`SELECT COUNT(*) FROM users WHERE username = ? COLLATE NOCASE AND id != ?`,
+		username, exceptUserID,
+	).Scan(&n); err != nil {
+		return false, fmt.Errorf("UsernameTaken: %w", err)
+	}
+	return n > 0, nil
+}
+
+// ChangeUsername renames userID to username, records the old name in username_history and
+// rewrites the name on their lobby chat messages. It fails with ErrUsernameTaken when another
+// user holds the name in any case, and with ErrUsernameChangeTooSoon, plus the time the next
+// change is allowed, when the last change was under minInterval ago. Renaming to the current
+// name changes nothing and returns it as old.
+func ChangeUsername(db *sql.DB, userID int64, username string, minInterval time.Duration) (old string, nextAt time.Time, err error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	defer tx.Rollback()
+
+	if err := tx.QueryRow(`SELECT username FROM users WHERE id = ?`, userID).Scan(&old); errors.Is(err, sql.ErrNoRows) {
+		return "", time.Time{}, ErrNotFound
+	} else if err != nil {
+		return "", time.Time{}, fmt.Errorf("ChangeUsername: get user (user_id=%d): %w", userID, err)
+	}
+	if old == username {
+		return old, time.Time{}, nil
+	}
+	if minInterval > 0 {
+		var last time.Time
+		err := tx.QueryRow(
+			`SELECT changed_at FROM username_history WHERE user_id = ? ORDER BY changed_at DESC, id DESC LIMIT 1`,
+			userID,
+		).Scan(&last)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return "", time.Time{}, fmt.Errorf("ChangeUsername: last change (user_id=%d): %w", userID, err)
+		}
+		if err == nil {
+			if next := last.Add(minInterval); time.Now().Before(next) {
+				return "", next, ErrUsernameChangeTooSoon
+			}
+		}
+	}
+	var taken int64
+	if err := tx.QueryRow(
+		`SELECT COUNT(*) FROM users WHERE username = ? COLLATE NOCASE AND id != ?`,
+		username, userID,
+	).Scan(&taken); err != nil {
+		return "", time.Time{}, fmt.Errorf("ChangeUsername: check taken: %w", err)
+	}
+	if taken > 0 {
+		return "", time.Time{}, ErrUsernameTaken
+	}
+	if _, err := tx.Exec(`UPDATE users SET username = ? WHERE id = ?`, username, userID); err != nil {
+		if IsUniqueConstraint(err) {
+			return "", time.Time{}, ErrUsernameTaken
+		}
+		return "", time.Time{}, fmt.Errorf("ChangeUsername: update user (user_id=%d): %w", userID, err)
+	}
+	if _, err := tx.Exec(
+		`INSERT INTO username_history(user_id, old_username, new_username) VALUES (?, ?, ?)`,
+		userID, old, username,
+	); err != nil {
+		return "", time.Time{}, fmt.Errorf("ChangeUsername: record history (user_id=%d): %w", userID, err)
+	}
+	// Chat shows who someone is now; username_history keeps who they were.
+	if _, err := tx.Exec(`UPDATE lobby_messages SET username = ? WHERE user_id = ?`, username, userID); err != nil {
+		return "", time.Time{}, fmt.Errorf("ChangeUsername: update chat (user_id=%d): %w", userID, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return "", time.Time{}, err
+	}
+	return old, time.Time{}, nil
+}
+
+// ActiveRoomsForUser returns the lobby and game ids of the unfinished games userID plays in
+// or spectates.
+func ActiveRoomsForUser(db *sql.DB, userID int64) (lobbyIDs, gameIDs []int64, err error) {
+	rows, err := db.Query(
+		`SELECT g.lobby_id, g.id FROM games g
+		 WHERE g.status IN ('waiting', 'in_progress')
+		   AND (EXISTS (SELECT 1 FROM game_players gp WHERE gp.game_id = g.id AND gp.user_id = ?)
+		     OR EXISTS (SELECT 1 FROM lobby_spectators ls WHERE ls.lobby_id = g.lobby_id AND ls.user_id = ?))`,
+		userID, userID,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ActiveRoomsForUser: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var lobbyID, gameID int64
+		if err := rows.Scan(&lobbyID, &gameID); err != nil {
+			return nil, nil, err
+		}
+		lobbyIDs = append(lobbyIDs, lobbyID)
+		gameIDs = append(gameIDs, gameID)
+	}
+	return lobbyIDs, gameIDs, rows.Err()
+}
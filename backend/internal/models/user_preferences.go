@@ -4,28 +4,200 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 )
 
 var ErrInvalidMode = errors.New("invalid mode")
+var ErrInvalidQuietHours = errors.New("invalid quiet hours")
+var ErrInvalidPeggingReveal = errors.New("invalid pegging reveal")
+var ErrInvalidSuggestVerbosity = errors.New("invalid suggest verbosity")
 
 type UserPreferences struct {
-	UserID        int64     `json:"user_id"`
-	AutoCountMode string    `json:"auto_count_mode"` // off|suggest|auto
-	UpdatedAt     time.Time `json:"updated_at"`
+	UserID          int64     `json:"user_id"`
+	AutoCountMode   string    `json:"auto_count_mode"`             // off|suggest|auto
+	QuietHoursStart *string   `json:"quiet_hours_start,omitempty"` // HH:MM in Timezone
+	QuietHoursEnd   *string   `json:"quiet_hours_end,omitempty"`   // HH:MM in Timezone
+	Timezone        string    `json:"timezone"`                    // IANA name, e.g. America/Chicago
+	PeggingReveal   string    `json:"pegging_reveal"`              // immediate|sequence_end
+	CutHints        bool      `json:"cut_hints"`                   // discard EV breakdown over cuts
+	UpdatedAt       time.Time `json:"updated_at"`
+	// SuggestVerbosity controls how much a count suggestion explains in suggest auto-count mode.
+	SuggestVerbosity string `json:"suggest_verbosity"` // score|summary|detailed
 }
 
-func GetUserPreferences(db *sql.DB, userID int64) (*UserPreferences, error) {
+// QuietHours is a daily window during which turn pings are not pushed. A window whose end is
+// before its start wraps past midnight (e.g. 22:00-07:00).
+type QuietHours struct {
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	Timezone string `json:"timezone"`
+}
+
+// Defaults applied to new users and to users whose preferences row is missing.
+const (
+	DefaultAutoCountMode    = "suggest"
+	DefaultTimezone         = "UTC"
+	DefaultPeggingReveal    = PeggingRevealImmediate
+	DefaultSuggestVerbosity = SuggestVerbositySummary
+)
+
+// Pegging reveal modes: itemized points on every play, or a running total with the breakdown
+// deferred to the end of each pegging sequence.
+const (
+	PeggingRevealImmediate   = "immediate"
+	PeggingRevealSequenceEnd = "sequence_end"
+)
+
+// Suggest verbosity levels: the verified score alone, the score with a per-category summary,
+// or every scoring combination itemized.
+const (
+	SuggestVerbosityScore    = "score"
+	SuggestVerbositySummary  = "summary"
+	SuggestVerbosityDetailed = "detailed"
+)
+
+const userPreferencesColumns = `user_id, auto_count_mode, quiet_hours_start, quiet_hours_end, timezone, pegging_reveal, cut_hints, suggest_verbosity, updated_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanUserPreferences(row rowScanner) (*UserPreferences, error) {
 	var p UserPreferences
-	err := db.QueryRow(`SELECT user_id, auto_count_mode, updated_at FROM user_preferences WHERE user_id = ?`, userID).
-		Scan(&p.UserID, &p.AutoCountMode, &p.UpdatedAt)
+	var start, end sql.NullString
+	if err := row.Scan(&p.UserID, &p.AutoCountMode, &start, &end, &p.Timezone, &p.PeggingReveal, &p.CutHints, &p.SuggestVerbosity, &p.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if start.Valid {
+		v := start.String
+		p.QuietHoursStart = &v
+	}
+	if end.Valid {
+		v := end.String
+		p.QuietHoursEnd = &v
+	}
+	return &p, nil
+}
+
+func GetUserPreferences(db *sql.DB, userID int64) (*UserPreferences, error) {
+	p, err := scanUserPreferences(db.QueryRow(`SELECT `+userPreferencesColumns+` FROM user_preferences WHERE user_id = ?`, userID))
 	if errors.Is(err, sql.ErrNoRows) {
-		return &UserPreferences{UserID: userID, AutoCountMode: "suggest", UpdatedAt: time.Now().UTC()}, nil
+		return defaultUserPreferences(userID), nil
 	}
 	if err != nil {
 		return nil, err
 	}
-	return &p, nil
+	return p, nil
+}
+
+// ListUserPreferences loads the preferences of every user in userIDs in one query. Users
+// without a stored row get the defaults, as from GetUserPreferences.
+func ListUserPreferences(db *sql.DB, userIDs []int64) (map[int64]*UserPreferences, error) {
+	out := make(map[int64]*UserPreferences, len(userIDs))
+	if len(userIDs) == 0 {
+		return out, nil
+	}
+	args := make([]any, len(userIDs))
+	for i, id := range userIDs {
+		args[i] = id
+	}
+	rows, err := db.Query(
+		`SELECT `+userPreferencesColumns+` FROM user_preferences WHERE user_id IN (?`+strings.Repeat(", ?", len(userIDs)-1)+`)`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		p, err := scanUserPreferences(rows)
+		if err != nil {
+			return nil, err
+		}
+		out[p.UserID] = p
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for _, id := range userIDs {
+		if out[id] == nil {
+			out[id] = defaultUserPreferences(id)
+		}
+	}
+	return out, nil
+}
+
+func defaultUserPreferences(userID int64) *UserPreferences {
+	return &UserPreferences{UserID: userID, AutoCountMode: DefaultAutoCountMode, Timezone: DefaultTimezone, PeggingReveal: DefaultPeggingReveal, SuggestVerbosity: DefaultSuggestVerbosity, UpdatedAt: time.Now().UTC()}
+}
+
+// InitDefaultPreferences seeds every preference default for userID in one transaction. It is
+// idempotent: an existing row is left untouched, so it is safe to call for existing users.
+func InitDefaultPreferences(db *sql.DB, userID int64) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := initDefaultPreferencesTx(tx, userID); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func initDefaultPreferencesTx(tx *sql.Tx, userID int64) error {
+	_, err := tx.Exec(
+		`INSERT INTO user_preferences(user_id, auto_count_mode, timezone, pegging_reveal) VALUES (?, ?, ?, ?)
+		 ON CONFLICT(user_id) DO NOTHING`,
+		userID, DefaultAutoCountMode, DefaultTimezone, DefaultPeggingReveal,
+	)
+	return err
+}
+
+// parseClock parses an HH:MM wall-clock time into minutes after midnight.
+func parseClock(s string) (int, bool) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+// ValidateQuietHours checks the HH:MM bounds and timezone, returning ErrInvalidQuietHours on failure.
+func ValidateQuietHours(q QuietHours) error {
+	start, ok1 := parseClock(q.Start)
+	end, ok2 := parseClock(q.End)
+	if !ok1 || !ok2 || start == end {
+		return ErrInvalidQuietHours
+	}
+	if _, err := time.LoadLocation(q.Timezone); err != nil || q.Timezone == "" {
+		return ErrInvalidQuietHours
+	}
+	return nil
+}
+
+// InQuietHours reports whether now falls inside the user's quiet-hours window.
+// Unset or unparsable settings never suppress anything.
+func (p *UserPreferences) InQuietHours(now time.Time) bool {
+	if p == nil || p.QuietHoursStart == nil || p.QuietHoursEnd == nil {
+		return false
+	}
+	start, ok1 := parseClock(*p.QuietHoursStart)
+	end, ok2 := parseClock(*p.QuietHoursEnd)
+	if !ok1 || !ok2 {
+		return false
+	}
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	local := now.In(loc)
+	m := local.Hour()*60 + local.Minute()
+	if start < end {
+		return m >= start && m < end
+	}
+	return m >= start || m < end
 }
 
 func SetUserAutoCountMode(db *sql.DB, userID int64, mode string) error {
@@ -43,43 +215,99 @@ func SetUserAutoCountMode(db *sql.DB, userID int64, mode string) error {
 // SetUserAutoCountModeAndGetPreferencesTx updates the user's auto-count preference and
 // then returns the updated preferences, atomically.
 func SetUserAutoCountModeAndGetPreferencesTx(db *sql.DB, userID int64, mode string) (*UserPreferences, error) {
-	if mode != "off" && mode != "suggest" && mode != "auto" {
+	return UpdateUserPreferencesTx(db, userID, &mode, nil, false, nil, nil, nil)
+}
+
+// UpdateUserPreferencesTx applies the given changes and returns the updated preferences, atomically.
+// A nil mode leaves auto-count unchanged. quiet sets the quiet-hours window; clearQuiet removes it.
+// A nil reveal leaves the pegging reveal mode unchanged, a nil cutHints the discard hints opt-in,
+// and a nil verbosity the count suggestion verbosity.
+func UpdateUserPreferencesTx(db *sql.DB, userID int64, mode *string, quiet *QuietHours, clearQuiet bool, reveal *string, cutHints *bool, verbosity *string) (*UserPreferences, error) {
+	if mode != nil && *mode != "off" && *mode != "suggest" && *mode != "auto" {
 		return nil, ErrInvalidMode
 	}
+	if reveal != nil && *reveal != PeggingRevealImmediate && *reveal != PeggingRevealSequenceEnd {
+		return nil, ErrInvalidPeggingReveal
+	}
+	if verbosity != nil && *verbosity != SuggestVerbosityScore && *verbosity != SuggestVerbositySummary && *verbosity != SuggestVerbosityDetailed {
+		return nil, ErrInvalidSuggestVerbosity
+	}
+	if quiet != nil {
+		if err := ValidateQuietHours(*quiet); err != nil {
+			return nil, err
+		}
+	}
 	tx, err := db.Begin()
 	if err != nil {
 		return nil, err
 	}
 
-	if _, err := tx.Exec(
-		`INSERT INTO user_preferences(user_id, auto_count_mode) VALUES (?, ?)
-		 ON CONFLICT(user_id) DO UPDATE SET auto_count_mode = excluded.auto_count_mode, updated_at = datetime('now','utc')`,
-		userID, mode,
-	); err != nil {
+	// Ensure the row exists so the updates below always apply.
+	if _, err := tx.Exec(`INSERT INTO user_preferences(user_id) VALUES (?) ON CONFLICT(user_id) DO NOTHING`, userID); err != nil {
 		_ = tx.Rollback()
 		return nil, err
 	}
+	if mode != nil {
+		if _, err := tx.Exec(
+			`UPDATE user_preferences SET auto_count_mode = ?, updated_at = datetime('now','utc') WHERE user_id = ?`,
+			*mode, userID,
+		); err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+	}
+	if reveal != nil {
+		if _, err := tx.Exec(
+			`UPDATE user_preferences SET pegging_reveal = ?, updated_at = datetime('now','utc') WHERE user_id = ?`,
+			*reveal, userID,
+		); err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+	}
+	if cutHints != nil {
+		if _, err := tx.Exec(
+			`UPDATE user_preferences SET cut_hints = ?, updated_at = datetime('now','utc') WHERE user_id = ?`,
+			boolToInt(*cutHints), userID,
+		); err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+	}
+	if verbosity != nil {
+		if _, err := tx.Exec(
+			`UPDATE user_preferences SET suggest_verbosity = ?, updated_at = datetime('now','utc') WHERE user_id = ?`,
+			*verbosity, userID,
+		); err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+	}
+	if quiet != nil {
+		if _, err := tx.Exec(
+			`UPDATE user_preferences SET quiet_hours_start = ?, quiet_hours_end = ?, timezone = ?, updated_at = datetime('now','utc') WHERE user_id = ?`,
+			quiet.Start, quiet.End, quiet.Timezone, userID,
+		); err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+	} else if clearQuiet {
+		if _, err := tx.Exec(
+			`UPDATE user_preferences SET quiet_hours_start = NULL, quiet_hours_end = NULL, updated_at = datetime('now','utc') WHERE user_id = ?`,
+			userID,
+		); err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+	}
 
-	var p UserPreferences
-	err = tx.QueryRow(`SELECT user_id, auto_count_mode, updated_at FROM user_preferences WHERE user_id = ?`, userID).
-		Scan(&p.UserID, &p.AutoCountMode, &p.UpdatedAt)
+	p, err := scanUserPreferences(tx.QueryRow(`SELECT `+userPreferencesColumns+` FROM user_preferences WHERE user_id = ?`, userID))
 	if err != nil {
-		// Extremely defensive: after an upsert, the row should exist.
-		// Preserve GetUserPreferences semantics if it somehow doesn't.
-		if errors.Is(err, sql.ErrNoRows) {
-			// Preserve the upsert; commit even though the SELECT returned no rows.
-			// (This should be unreachable, but keeps DB changes consistent with caller intent.)
-			if err := tx.Commit(); err != nil {
-				return nil, fmt.Errorf("commit user_preferences tx: %w", err)
-			}
-			return &UserPreferences{UserID: userID, AutoCountMode: mode, UpdatedAt: time.Now().UTC()}, nil
-		}
 		_ = tx.Rollback()
-		return nil, err
+		return nil, fmt.Errorf("read back user_preferences: %w", err)
 	}
-
 	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
-	return &p, nil
+	return p, nil
 }
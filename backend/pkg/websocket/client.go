@@ -10,12 +10,25 @@ import (
 )
 
 const (
-	writeWait      = 10 * time.Second
-	pongWait       = 60 * time.Second
-	pingPeriod     = (pongWait * 9) / 10
-	maxMessageSize = 64 * 1024
+	writeWait = 10 * time.Second
+
+	// Defaults apply when ClientOptions leaves a field zero.
+	DefaultMaxMessageSize = 64 * 1024
+	DefaultSendQueue      = 256
+	DefaultPongWait       = 60 * time.Second
+	DefaultPingPeriod     = (DefaultPongWait * 9) / 10
 )
 
+// ClientOptions tunes per-connection limits. Zero fields use the defaults above.
+type ClientOptions struct {
+	MaxMessageSize int64 // read limit for a single inbound message
+	SendQueue      int   // capacity of the outbound Send channel
+	// PongWait is how long the peer may stay silent before the connection is considered dead;
+	// PingPeriod is how often we ping. PingPeriod must be shorter than PongWait.
+	PongWait   time.Duration
+	PingPeriod time.Duration
+}
+
 // Client is a single websocket connection registered to a room.
 type Client struct {
 	Conn *websocket.Conn
@@ -24,14 +37,21 @@ type Client struct {
 	Room   string
 	UserID int64
 
+	// SessionID identifies the resumable session this connection belongs to (see Hub.NewSession).
+	SessionID string
+
 	CloseOnce     sync.Once
 	SendCloseOnce sync.Once
 	Send          chan []byte
+
+	maxMessageSize int64
+	pongWait       time.Duration
+	pingPeriod     time.Duration
 }
 
 // NewClient creates a new websocket Client for the given connection, hub, room, and user.
 // Returns an error if required parameters are invalid.
-func NewClient(conn *websocket.Conn, hub *Hub, room string, userID int64) (*Client, error) {
+func NewClient(conn *websocket.Conn, hub *Hub, room string, userID int64, opts ClientOptions) (*Client, error) {
 	if conn == nil {
 		return nil, fmt.Errorf("NewClient: conn cannot be nil")
 	}
@@ -44,12 +64,33 @@ func NewClient(conn *websocket.Conn, hub *Hub, room string, userID int64) (*Clie
 	if userID <= 0 {
 		return nil, fmt.Errorf("NewClient: userID must be positive")
 	}
+	if opts.MaxMessageSize < 0 || opts.SendQueue < 0 || opts.PongWait < 0 || opts.PingPeriod < 0 {
+		return nil, fmt.Errorf("NewClient: options must not be negative")
+	}
+	if opts.MaxMessageSize == 0 {
+		opts.MaxMessageSize = DefaultMaxMessageSize
+	}
+	if opts.SendQueue == 0 {
+		opts.SendQueue = DefaultSendQueue
+	}
+	if opts.PongWait == 0 {
+		opts.PongWait = DefaultPongWait
+	}
+	if opts.PingPeriod == 0 {
+		opts.PingPeriod = DefaultPingPeriod
+	}
+	if opts.PingPeriod >= opts.PongWait {
+		return nil, fmt.Errorf("NewClient: ping period %v must be shorter than pong wait %v", opts.PingPeriod, opts.PongWait)
+	}
 	return &Client{
-		Conn:   conn,
-		Hub:    hub,
-		Room:   room,
-		UserID: userID,
-		Send:   make(chan []byte, 256),
+		Conn:           conn,
+		Hub:            hub,
+		Room:           room,
+		UserID:         userID,
+		Send:           make(chan []byte, opts.SendQueue),
+		maxMessageSize: opts.MaxMessageSize,
+		pongWait:       opts.PongWait,
+		pingPeriod:     opts.PingPeriod,
 	}, nil
 }
 
@@ -77,7 +118,16 @@ func (c *Client) ReadPump(onMessage func([]byte)) {
 		c.Close()
 	}()
 
-	c.Conn.SetReadLimit(maxMessageSize)
+	limit := c.maxMessageSize
+	if limit <= 0 {
+		// Client built without NewClient; never run without a read limit.
+		limit = DefaultMaxMessageSize
+	}
+	c.Conn.SetReadLimit(limit)
+	pongWait := c.pongWait
+	if pongWait <= 0 {
+		pongWait = DefaultPongWait
+	}
 	_ = c.Conn.SetReadDeadline(time.Now().Add(pongWait))
 	c.Conn.SetPongHandler(func(string) error {
 		_ = c.Conn.SetReadDeadline(time.Now().Add(pongWait))
@@ -97,6 +147,10 @@ func (c *Client) ReadPump(onMessage func([]byte)) {
 }
 
 func (c *Client) WritePump() {
+	pingPeriod := c.pingPeriod
+	if pingPeriod <= 0 {
+		pingPeriod = DefaultPingPeriod
+	}
 	ticker := time.NewTicker(pingPeriod)
 	defer func() {
 		ticker.Stop()
@@ -1,21 +1,29 @@
 package websocket
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"log"
+	"sort"
 	"sync"
 	"time"
 )
 
 // Hub manages websocket clients and room-based broadcasts.
 type Hub struct {
-	register   chan *Client
-	unregister chan *Client
-	join       chan joinReq
-	broadcast  chan Broadcast
+	register     chan *Client
+	unregister   chan *Client
+	join         chan joinReq
+	broadcast    chan Broadcast
+	broadcastAll chan Broadcast
+	toUser       chan userBroadcast
+	roomQuery    chan roomQuery
 
 	rooms map[string]map[*Client]bool
 
+	sessions sessionStore
+
 	stopOnce sync.Once
 	stop     chan struct{}
 }
@@ -25,20 +33,55 @@ type joinReq struct {
 	Room   string
 }
 
+// userBroadcast targets every connection of a single user, regardless of room.
+type userBroadcast struct {
+	UserID  int64
+	Type    string
+	Payload any
+}
+
+// roomQuery asks the Run loop for a snapshot of one room's clients.
+type roomQuery struct {
+	Room  string
+	Reply chan RoomSnapshot
+}
+
+// RoomUser is one user's connection count within a room.
+type RoomUser struct {
+	UserID      int64 `json:"user_id"`
+	Connections int   `json:"connections"`
+}
+
+// RoomSnapshot describes who is connected to a room at the moment it was taken.
+type RoomSnapshot struct {
+	Room        string     `json:"room"`
+	Connections int        `json:"connections"`
+	Users       []RoomUser `json:"users"`
+}
+
+// ErrHubUnavailable is returned by queries when the hub is stopped or does not answer in time.
+var ErrHubUnavailable = errors.New("websocket hub unavailable")
+
 type Broadcast struct {
 	Room    string
 	Type    string
 	Payload any
+	// ByUser overrides Payload for the listed users' clients (see BroadcastTailored).
+	ByUser map[int64]any
 }
 
 func NewHub() *Hub {
 	return &Hub{
-		register:   make(chan *Client),
-		unregister: make(chan *Client),
-		join:       make(chan joinReq),
-		broadcast:  make(chan Broadcast, 256),
-		rooms:      map[string]map[*Client]bool{},
-		stop:       make(chan struct{}),
+		register:     make(chan *Client),
+		unregister:   make(chan *Client),
+		join:         make(chan joinReq),
+		broadcast:    make(chan Broadcast, 256),
+		broadcastAll: make(chan Broadcast, 16),
+		toUser:       make(chan userBroadcast, 256),
+		roomQuery:    make(chan roomQuery),
+		rooms:        map[string]map[*Client]bool{},
+		sessions:     sessionStore{sessions: map[string]*session{}},
+		stop:         make(chan struct{}),
 	}
 }
 
@@ -55,12 +98,21 @@ func (h *Hub) Run() {
 				h.rooms[c.Room] = map[*Client]bool{}
 			}
 			h.rooms[c.Room][c] = true
+			h.attachSession(c)
 		case c := <-h.unregister:
 			h.removeClient(c)
 		case jr := <-h.join:
 			h.moveClientToRoom(jr.Client, jr.Room)
 		case b := <-h.broadcast:
-			h.broadcastToRoom(b.Room, b.Type, b.Payload)
+			h.broadcastToRoom(b.Room, b.Type, b.Payload, b.ByUser)
+		case b := <-h.broadcastAll:
+			for room := range h.rooms {
+				h.broadcastToRoom(room, b.Type, b.Payload, nil)
+			}
+		case u := <-h.toUser:
+			h.sendToUser(u.UserID, u.Type, u.Payload)
+		case q := <-h.roomQuery:
+			q.Reply <- h.snapshotRoom(q.Room)
 		}
 	}
 }
@@ -119,6 +171,81 @@ func (h *Hub) Broadcast(room, typ string, payload any) {
 	}
 }
 
+// BroadcastTailored is Broadcast with a per-user payload: clients of users in byUser get their
+// entry, everyone else in the room (including anyone who joins after byUser was built) gets
+// payload. Callers use it when viewers may see the same event differently.
+func (h *Hub) BroadcastTailored(room, typ string, payload any, byUser map[int64]any) {
+	select {
+	case <-h.stop:
+		return
+	case h.broadcast <- Broadcast{Room: room, Type: typ, Payload: payload, ByUser: byUser}:
+		return
+	default:
+		return
+	}
+}
+
+// BroadcastAll delivers a message to every connected client in every room. Rooms are walked
+// on the Run goroutine, so it never races with joins or leaves. Like Broadcast, it drops the
+// message rather than block when the hub is stopped or backed up.
+func (h *Hub) BroadcastAll(typ string, payload any) {
+	select {
+	case <-h.stop:
+		return
+	case h.broadcastAll <- Broadcast{Type: typ, Payload: payload}:
+		return
+	default:
+		return
+	}
+}
+
+// SendToUser delivers a message to all of a user's connections. Like Broadcast, it drops the
+// message rather than block when the hub is stopped or backed up.
+func (h *Hub) SendToUser(userID int64, typ string, payload any) {
+	select {
+	case <-h.stop:
+		return
+	case h.toUser <- userBroadcast{UserID: userID, Type: typ, Payload: payload}:
+		return
+	default:
+		return
+	}
+}
+
+// RoomClients snapshots a room's connected users. The snapshot is taken on the Run goroutine,
+// so it never races with registrations; it fails with ErrHubUnavailable if the hub is stopped
+// or ctx ends first.
+func (h *Hub) RoomClients(ctx context.Context, room string) (RoomSnapshot, error) {
+	q := roomQuery{Room: room, Reply: make(chan RoomSnapshot, 1)}
+	select {
+	case <-h.stop:
+		return RoomSnapshot{}, ErrHubUnavailable
+	case <-ctx.Done():
+		return RoomSnapshot{}, ErrHubUnavailable
+	case h.roomQuery <- q:
+	}
+	select {
+	case snap := <-q.Reply:
+		return snap, nil
+	case <-ctx.Done():
+		return RoomSnapshot{}, ErrHubUnavailable
+	}
+}
+
+func (h *Hub) snapshotRoom(room string) RoomSnapshot {
+	snap := RoomSnapshot{Room: room, Users: []RoomUser{}}
+	counts := map[int64]int{}
+	for c := range h.rooms[room] {
+		counts[c.UserID]++
+		snap.Connections++
+	}
+	for id, n := range counts {
+		snap.Users = append(snap.Users, RoomUser{UserID: id, Connections: n})
+	}
+	sort.Slice(snap.Users, func(i, j int) bool { return snap.Users[i].UserID < snap.Users[j].UserID })
+	return snap
+}
+
 func (h *Hub) removeClient(c *Client) {
 	if c == nil {
 		return
@@ -129,6 +256,7 @@ func (h *Hub) removeClient(c *Client) {
 			delete(h.rooms, c.Room)
 		}
 	}
+	h.detachSession(c)
 	c.SendCloseOnce.Do(func() { close(c.Send) })
 }
 
@@ -147,33 +275,40 @@ func (h *Hub) moveClientToRoom(c *Client, room string) {
 		}
 	}
 	c.Room = room
+	h.setSessionRoom(c.SessionID, room)
 	if h.rooms[room] == nil {
 		h.rooms[room] = map[*Client]bool{}
 	}
 	h.rooms[room][c] = true
 }
 
-func (h *Hub) broadcastToRoom(room, typ string, payload any) {
+func (h *Hub) broadcastToRoom(room, typ string, payload any, byUser map[int64]any) {
 	clients := h.rooms[room]
 	if len(clients) == 0 {
 		return
 	}
 
-	msg := map[string]any{
-		"type":      typ,
-		"payload":   payload,
-		"timestamp": time.Now().UTC().Format(time.RFC3339Nano),
-	}
-	data, err := json.Marshal(msg)
+	data, err := encodeMessage(typ, payload)
 	if err != nil {
 		log.Printf("ws broadcast marshal error: room=%s type=%s err=%v", room, typ, err)
 		return
 	}
+	userData := map[int64][]byte{}
 
 	var deadClients []*Client
 	for c := range clients {
+		msg := data
+		if p, ok := byUser[c.UserID]; ok {
+			if msg, ok = userData[c.UserID]; !ok {
+				if msg, err = encodeMessage(typ, p); err != nil {
+					log.Printf("ws broadcast marshal error: room=%s type=%s user_id=%d err=%v", room, typ, c.UserID, err)
+					continue
+				}
+				userData[c.UserID] = msg
+			}
+		}
 		select {
-		case c.Send <- data:
+		case c.Send <- msg:
 		default:
 			// Backpressure / dead client.
 			deadClients = append(deadClients, c)
@@ -183,3 +318,38 @@ func (h *Hub) broadcastToRoom(room, typ string, payload any) {
 		h.removeClient(c)
 	}
 }
+
+func (h *Hub) sendToUser(userID int64, typ string, payload any) {
+	var data []byte
+	var deadClients []*Client
+	for _, clients := range h.rooms {
+		for c := range clients {
+			if c.UserID != userID {
+				continue
+			}
+			if data == nil {
+				var err error
+				if data, err = encodeMessage(typ, payload); err != nil {
+					log.Printf("ws user send marshal error: user_id=%d type=%s err=%v", userID, typ, err)
+					return
+				}
+			}
+			select {
+			case c.Send <- data:
+			default:
+				deadClients = append(deadClients, c)
+			}
+		}
+	}
+	for _, c := range deadClients {
+		h.removeClient(c)
+	}
+}
+
+func encodeMessage(typ string, payload any) ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"type":      typ,
+		"payload":   payload,
+		"timestamp": time.Now().UTC().Format(time.RFC3339Nano),
+	})
+}
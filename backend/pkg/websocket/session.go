@@ -0,0 +1,128 @@
+package websocket
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// session remembers the room a user's connection was in so a reconnect after a brief
+// network blip can be restored without the client re-issuing join_room.
+type session struct {
+	userID    int64
+	room      string
+	ttl       time.Duration
+	connected bool
+	owner     *Client   // connection currently holding the session, set on Register
+	expiresAt time.Time // only meaningful while disconnected
+}
+
+// sessionStore is guarded by its own mutex (not the Run loop) so handlers can create and
+// resume sessions synchronously during the upgrade.
+type sessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]*session
+}
+
+func newSessionID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b[:]), nil
+}
+
+// NewSession creates a resumable session for userID starting in room. After the client
+// disconnects the session stays resumable for ttl.
+func (h *Hub) NewSession(userID int64, room string, ttl time.Duration) (string, error) {
+	id, err := newSessionID()
+	if err != nil {
+		return "", err
+	}
+	h.sessions.mu.Lock()
+	defer h.sessions.mu.Unlock()
+	h.pruneSessionsLocked(time.Now())
+	h.sessions.sessions[id] = &session{userID: userID, room: room, ttl: ttl, connected: true}
+	return id, nil
+}
+
+// ResumeSession reattaches a disconnected, unexpired session owned by userID and returns
+// the room it was last in. A session can only be resumed by one connection at a time; callers
+// that fail to build that connection hand the session back with ReleaseSession.
+func (h *Hub) ResumeSession(id string, userID int64) (string, bool) {
+	if id == "" {
+		return "", false
+	}
+	h.sessions.mu.Lock()
+	defer h.sessions.mu.Unlock()
+	h.pruneSessionsLocked(time.Now())
+	s, ok := h.sessions.sessions[id]
+	if !ok || s.userID != userID || s.connected {
+		return "", false
+	}
+	s.connected = true
+	return s.room, true
+}
+
+// ReleaseSession hands back a session claimed by NewSession or ResumeSession when its connection
+// could not be built. The TTL starts as on a disconnect, rather than the session staying claimed
+// (and unresumable) forever.
+func (h *Hub) ReleaseSession(id string) {
+	if id == "" {
+		return
+	}
+	h.sessions.mu.Lock()
+	defer h.sessions.mu.Unlock()
+	if s, ok := h.sessions.sessions[id]; ok && s.connected && s.owner == nil {
+		s.connected = false
+		s.expiresAt = time.Now().Add(s.ttl)
+	}
+}
+
+// pruneSessionsLocked drops disconnected sessions whose TTL has passed. Caller must hold
+// h.sessions.mu.
+func (h *Hub) pruneSessionsLocked(now time.Time) {
+	for k, s := range h.sessions.sessions {
+		if !s.connected && now.After(s.expiresAt) {
+			delete(h.sessions.sessions, k)
+		}
+	}
+}
+
+func (h *Hub) setSessionRoom(id, room string) {
+	if id == "" {
+		return
+	}
+	h.sessions.mu.Lock()
+	defer h.sessions.mu.Unlock()
+	if s, ok := h.sessions.sessions[id]; ok {
+		s.room = room
+	}
+}
+
+func (h *Hub) attachSession(c *Client) {
+	if c.SessionID == "" {
+		return
+	}
+	h.sessions.mu.Lock()
+	defer h.sessions.mu.Unlock()
+	if s, ok := h.sessions.sessions[c.SessionID]; ok {
+		s.owner = c
+	}
+}
+
+// detachSession starts the resume TTL once the owning connection goes away. Removals of a
+// stale connection (e.g. a late unregister after the session was resumed) are ignored.
+func (h *Hub) detachSession(c *Client) {
+	if c.SessionID == "" {
+		return
+	}
+	h.sessions.mu.Lock()
+	defer h.sessions.mu.Unlock()
+	if s, ok := h.sessions.sessions[c.SessionID]; ok && s.connected && s.owner == c {
+		s.connected = false
+		s.owner = nil
+		s.expiresAt = time.Now().Add(s.ttl)
+	}
+}
@@ -4,11 +4,15 @@ import type {
   AuthResponse,
   Game,
   GameMove,
+  GameReplay,
   GameSnapshot,
   LeaderboardResponse,
   Lobby,
   LobbyChatMessage,
+  Match,
+  PlayerHand,
   PresenceStatus,
+  PublicUser,
   SpectatorInfo,
   User,
   UserStats,
@@ -17,7 +21,7 @@ import type {
 type AuthCredentials = { username: string; password: string }
 export type RegisterRequest = AuthCredentials
 export type LoginRequest = AuthCredentials
-export type CreateLobbyRequest = { name: string; max_players: number }
+export type CreateLobbyRequest = { name: string; max_players: number; target_score?: 121 | 61; match_points?: number; turn_timeout_seconds?: number; reveal_hands?: boolean; allowed_bot_difficulties?: ('easy' | 'medium' | 'hard')[]; idle_kick_seconds?: number; variant?: 'standard' | 'five_card' }
 export type GameMoveRequest =
   | { type: 'discard'; cards: string[] }
   | { type: 'play_card'; card: string }
@@ -25,6 +29,7 @@ export type GameMoveRequest =
 export type AddBotRequest = { difficulty?: 'easy' | 'medium' | 'hard' }
 export type SendChatMessageRequest = { message: string }
 export type UpdatePresenceRequest = { status: 'online' | 'away' | 'in_game' | 'offline' }
+export type ChangeUsernameRequest = { username: string }
 
 const UNEXPECTED_EMPTY_RESPONSE_STATUS = 599
 
@@ -60,7 +65,7 @@ export const api = {
     return res
   },
   async createLobby(req: CreateLobbyRequest) {
-    const res = await apiFetch<{ lobby: Lobby; game: Game }>(`${apiBaseUrl()}/api/lobbies`, {
+    const res = await apiFetch<{ lobby: Lobby; game: Game; allowed_bot_difficulties?: ('easy' | 'medium' | 'hard')[] }>(`${apiBaseUrl()}/api/lobbies`, {
       method: 'POST',
       body: req,
     })
@@ -90,16 +95,59 @@ export const api = {
     if (!res) throw new ApiError('Unexpected empty response', UNEXPECTED_EMPTY_RESPONSE_STATUS)
     return res
   },
+  async startLobby(lobbyId: number) {
+    const res = await apiFetch<{ lobby: Lobby; game_id: number }>(`${apiBaseUrl()}/api/lobbies/${lobbyId}/start`, {
+      method: 'POST',
+    })
+    if (!res) throw new ApiError('Unexpected empty response', UNEXPECTED_EMPTY_RESPONSE_STATUS)
+    return res
+  },
   async getGame(gameId: number) {
     const res = await apiFetch<GameSnapshot>(`${apiBaseUrl()}/api/games/${gameId}`)
     if (!res) throw new ApiError('Unexpected empty response', UNEXPECTED_EMPTY_RESPONSE_STATUS)
     return res
   },
+  async getPlayerHand(gameId: number) {
+    const res = await apiFetch<PlayerHand>(`${apiBaseUrl()}/api/games/${gameId}/hand`)
+    if (!res) throw new ApiError('Unexpected empty response', UNEXPECTED_EMPTY_RESPONSE_STATUS)
+    return res
+  },
+  async getMatch(matchId: number) {
+    const res = await apiFetch<Match>(`${apiBaseUrl()}/api/matches/${matchId}`)
+    if (!res) throw new ApiError('Unexpected empty response', UNEXPECTED_EMPTY_RESPONSE_STATUS)
+    return res
+  },
+  async getGameReplay(
+    gameId: number,
+    page: { fromHand?: number; hands?: number } | { from: number; to?: number } = {},
+    opts: { diff?: boolean } = {},
+  ) {
+    const params = new URLSearchParams()
+    if ('from' in page) {
+      params.set('from', String(page.from))
+      if (page.to) params.set('to', String(page.to))
+    } else {
+      params.set('from_hand', String(page.fromHand ?? 1))
+      if (page.hands) params.set('hands', String(page.hands))
+    }
+    if (opts.diff) params.set('diff', 'true')
+    const res = await apiFetch<GameReplay>(`${apiBaseUrl()}/api/games/${gameId}/replay?${params}`)
+    if (!res) throw new ApiError('Unexpected empty response', UNEXPECTED_EMPTY_RESPONSE_STATUS)
+    return res
+  },
   async getUserStats(userId: number) {
     const res = await apiFetch<UserStats>(`${apiBaseUrl()}/api/scoreboard/${userId}`)
     if (!res) throw new ApiError('Unexpected empty response', UNEXPECTED_EMPTY_RESPONSE_STATUS)
     return res
   },
+  async getUsersBatch(ids: number[]) {
+    const res = await apiFetch<{ users: PublicUser[] }>(`${apiBaseUrl()}/api/users/batch`, {
+      method: 'POST',
+      body: { ids },
+    })
+    if (!res) throw new ApiError('Unexpected empty response', UNEXPECTED_EMPTY_RESPONSE_STATUS)
+    return res.users
+  },
   async getLeaderboard(days = 30) {
     const qs = new URLSearchParams()
     qs.set('days', String(days))
@@ -127,6 +175,20 @@ export const api = {
     if (res === undefined) throw new ApiError('Unexpected empty response', UNEXPECTED_EMPTY_RESPONSE_STATUS)
     return res
   },
+  async undoPeggingPlay(gameId: number) {
+    const res = await apiFetch<{ card: string; total: number; scores: number[] }>(`${apiBaseUrl()}/api/games/${gameId}/undo`, {
+      method: 'POST',
+    })
+    if (!res) throw new ApiError('Unexpected empty response', UNEXPECTED_EMPTY_RESPONSE_STATUS)
+    return res
+  },
+  async rematchGame(gameId: number) {
+    const res = await apiFetch<{ lobby_id: number; game_id: number; existing?: boolean }>(`${apiBaseUrl()}/api/games/${gameId}/rematch`, {
+      method: 'POST',
+    })
+    if (!res) throw new ApiError('Unexpected empty response', UNEXPECTED_EMPTY_RESPONSE_STATUS)
+    return res
+  },
 
   // Lobby chat
   async getLobbyChatHistory(lobbyId: number, limit = 100) {
@@ -142,6 +204,18 @@ export const api = {
     if (!res) throw new ApiError('Unexpected empty response', UNEXPECTED_EMPTY_RESPONSE_STATUS)
     return res
   },
+  async getPinnedLobbyChatMessages(lobbyId: number) {
+    const res = await apiFetch<{ messages: LobbyChatMessage[] }>(`${apiBaseUrl()}/api/lobbies/${lobbyId}/chat/pinned`)
+    if (!res) throw new ApiError('Unexpected empty response', UNEXPECTED_EMPTY_RESPONSE_STATUS)
+    return res
+  },
+  async setLobbyChatMessagePinned(lobbyId: number, messageId: number, pinned: boolean) {
+    const res = await apiFetch<LobbyChatMessage>(`${apiBaseUrl()}/api/lobbies/${lobbyId}/chat/${messageId}/pin`, {
+      method: pinned ? 'POST' : 'DELETE',
+    })
+    if (!res) throw new ApiError('Unexpected empty response', UNEXPECTED_EMPTY_RESPONSE_STATUS)
+    return res
+  },
 
   // Spectators
   async joinAsSpectator(lobbyId: number) {
@@ -158,6 +232,12 @@ export const api = {
     if (!res) throw new ApiError('Unexpected empty response', UNEXPECTED_EMPTY_RESPONSE_STATUS)
     return res
   },
+  async getSpectatorSnapshot(gameId: number, followUserId?: number) {
+    const qs = followUserId ? `?follow=${followUserId}` : ''
+    const res = await apiFetch<GameSnapshot>(`${apiBaseUrl()}/api/games/${gameId}/spectate${qs}`)
+    if (!res) throw new ApiError('Unexpected empty response', UNEXPECTED_EMPTY_RESPONSE_STATUS)
+    return res
+  },
   async getSpectators(lobbyId: number) {
     const res = await apiFetch<{ spectators: SpectatorInfo[] }>(`${apiBaseUrl()}/api/lobbies/${lobbyId}/spectators`)
     if (!res) throw new ApiError('Unexpected empty response', UNEXPECTED_EMPTY_RESPONSE_STATUS)
@@ -185,5 +265,13 @@ export const api = {
     if (!res) throw new ApiError('Unexpected empty response', UNEXPECTED_EMPTY_RESPONSE_STATUS)
     return res
   },
+  async changeUsername(req: ChangeUsernameRequest) {
+    const res = await apiFetch<AuthResponse>(`${apiBaseUrl()}/api/users/username`, {
+      method: 'PUT',
+      body: req,
+    })
+    if (!res) throw new ApiError('Unexpected empty response', UNEXPECTED_EMPTY_RESPONSE_STATUS)
+    return res
+  },
 }
 
@@ -41,10 +41,20 @@ export type GamePlayer = {
   bot_difficulty?: string
 }
 
+export type PublicUser = {
+  id: number
+  username: string
+  avatar_url?: string
+}
+
 export type UserStats = {
   user_id: number
   games_played: number
   games_won: number
+  skunks: number // wins with an opponent skunked (double skunks included)
+  double_skunks: number
+  skunked: number // losses by a skunk (double skunks included)
+  double_skunked: number
 }
 
 export type LobbyChatMessage = {
@@ -54,6 +64,7 @@ export type LobbyChatMessage = {
   username: string
   message: string
   message_type: 'chat' | 'system' | 'join' | 'leave'
+  pinned?: boolean
   created_at: string
 }
 
@@ -86,6 +97,8 @@ export type LeaderboardPlayer = {
   games_played: number
   games_won: number
   win_rate: number // all-time [0..1]
+  skunks: number // all-time wins with an opponent skunked
+  skunked: number // all-time losses by a skunk
   series: LeaderboardDayPoint[]
 }
 
@@ -101,6 +114,11 @@ export type Card = {
 
 export type CribbageRules = {
   max_players: number
+  target_score?: number // 121 when omitted; 61 for a short game
+  turn_timeout_seconds?: number // untimed when omitted
+  reveal_hands?: boolean // casual: discards are shown while each hand is counted
+  pegging_flush?: boolean // house variant: same-suit cards in a row score while pegging
+  variant?: 'standard' | 'five_card' // standard when omitted
 }
 
 export type CribbageStage = 'dealing' | 'discard' | 'pegging' | 'counting' | 'finished'
@@ -114,11 +132,14 @@ export type CribbageState = {
   hands: Card[][] // other players are [] (hidden); your hand is populated
   kept_hands?: Card[][] // revealed during counting/finished
   crib?: Card[] // revealed during counting/finished
+  discards?: Card[][] // per seat; revealed during counting/finished when rules.reveal_hands is set
   pegging_total: number
   pegging_seq: Card[]
   pegging_passed: boolean[]
   discard_completed: boolean[]
   ready_next_hand?: boolean[]
+  turn_deadline?: string // when the current discard or pegging turn is played for the player
+  turn_timeouts?: number[] // consecutive timed-out turns per seat
   scores: number[]
   stage: CribbageStage
   count_summary?: {
@@ -128,6 +149,7 @@ export type CribbageState = {
       { total: number; fifteens: number; pairs: number; runs: number; flush: number; nobs: number; reasons?: Record<string, number> }
     >
     crib?: { total: number; fifteens: number; pairs: number; runs: number; flush: number; nobs: number; reasons?: Record<string, number> }
+    pegging?: number[] // per seat, points pegged this hand
   }
 
   history?: Array<{
@@ -139,15 +161,145 @@ export type CribbageState = {
       { total: number; fifteens: number; pairs: number; runs: number; flush: number; nobs: number; reasons?: Record<string, number> }
     >
     crib?: { total: number; fifteens: number; pairs: number; runs: number; flush: number; nobs: number; reasons?: Record<string, number> }
+    scored_zero?: Record<string, boolean> // hands that scored nothing, collapsible in the UI
+    crib_scored_zero?: boolean
     scores_before?: number[]
     scores_after?: number[]
+    pegging?: number[] // per seat, points pegged that hand
   }>
+  events?: GameEvent[] // current hand only; /games/:id/events keeps the previous hand too
+}
+
+// GET /api/games/:id/hand: just the caller's seat, for refreshing cards after a reconnect.
+export type PlayerHand = {
+  game_id: number
+  position: number
+  score: number
+  hand: Card[]
+  stage: CribbageStage
+  your_turn: boolean
+}
+
+export type GameEvent = {
+  seq: number
+  type: 'deal' | 'discard' | 'cut' | 'play' | 'undo' | 'go' | 'sequence_reset' | 'hand_counted' | 'muggins' | 'game_over'
+  player: number // seat, -1 when not tied to one
+  card?: Card
+  cards?: Card[]
+  count?: number
+  points?: number
+  reasons?: string[]
+  total?: number // play: count after the card; undo: the restored count
+  crib?: boolean
+  from?: number // muggins: the seat whose missed points moved
+}
+
+export type PlayerOutlook = {
+  position: number
+  needed: number
+  max_this_hand: number
+  can_win_this_hand: boolean
 }
 
 export type GameSnapshot = {
   game: Game
   players: GamePlayer[]
   state: CribbageState
+  outlook?: PlayerOutlook[]
+  pending_action?: PendingAction
+  counting_order?: number[] // user ids: left of dealer first, dealer last (then the crib)
+  next_to_count?: { user_id: number; kind: 'hand' | 'crib' }
+  count_progress?: CountProgress // your own counts this hand (counting stage only)
+  match_id?: number // set when the game is part of a match
+  series?: LobbySeries // the lobby's running win tally across rematches
+  focus?: SpectatorFocus // spectator snapshots fetched with ?follow=<user id>
+}
+
+// The followed player's side of a spectator snapshot; card fields appear only once revealed.
+export type SpectatorFocus = {
+  user_id: number
+  username: string
+  position: number
+  seat_order: number[] // every seat, starting with the followed one
+  score: number
+  hand_count?: number
+  is_dealer: boolean
+  to_act: boolean
+  outlook?: PlayerOutlook
+  kept_hand?: Card[]
+  hand_score?: { total: number; fifteens: number; pairs: number; runs: number; flush: number; nobs: number; reasons?: Record<string, number> }
+  crib_score?: { total: number; fifteens: number; pairs: number; runs: number; flush: number; nobs: number; reasons?: Record<string, number> }
+  pegged?: number
+}
+
+export type LobbySeries = {
+  id: number
+  games_played: number
+  standings: { user_id: number; username: string; wins: number }[] // most wins first
+}
+
+export type Match = {
+  id: number
+  lobby_id: number
+  target_points: number
+  status: 'in_progress' | 'finished' | 'forfeited' | 'abandoned'
+  winner_id?: number
+  forfeited_by?: number
+  games: { game_number: number; game_id: number; winner_id?: number; skunk_level: number; points: number }[]
+  scores: { user_id: number; username: string; points: number }[] // match points per player
+  created_at: string
+  finished_at?: string
+}
+
+export type CountProgress = {
+  manual: boolean // false when your counts are recorded automatically
+  duties: { kind: 'hand' | 'crib'; finalized: boolean; claimed?: number; verified?: number }[]
+  done: boolean
+}
+
+export type PendingAction = {
+  action: 'discard' | 'play' | 'go' | 'count' | 'ready' | 'wait'
+  message: string
+  cards?: number
+  missing?: ('hand' | 'crib')[]
+}
+
+export type GameRules = {
+  game_id: number
+  variant: 'standard' | 'five_card' // five_card: the 2-player game dealt five cards, to 61
+  max_players: number
+  target_score: number
+  last_card_points: number
+  hand_size: number
+  discard_count: number
+  crib_size: number
+  cut_tie_policy: 'recut' | 'suit'
+  crib_flush_requires_five: boolean // false: the crib may flush on its own four cards
+  muggins: boolean // opponents may claim points missed in final counts
+  turn_timeout_seconds: number // 0: untimed
+  reveal_hands: boolean // casual: discards are shown while each hand is counted
+  pegging_flush: boolean // house variant: same-suit cards in a row score while pegging
+}
+
+export type HandShare = {
+  game_id: number
+  hand_index: number
+  seat: number
+  username: string
+  is_crib: boolean
+  cards: string[]
+  cut: string
+  total: number
+  items: { reason: string; points: number }[]
+}
+
+export type DiscardHint = {
+  keep: string[]
+  discard: string[]
+  average: number
+  best: number
+  worst: number
+  summary: string
 }
 
 export type GameMove = {
@@ -159,6 +311,59 @@ export type GameMove = {
   score_claimed?: number
   score_verified?: number
   is_corrected: boolean
+  ref_move_id?: number // the move a correction or muggins call refers to
   created_at: string
 }
 
+export type ReplayStep = {
+  move: number // 1-based position in the whole replay
+  move_id: number
+  move_type: string
+  seat: number
+  card?: string
+  points?: number
+  claimed?: number // count claims, after corrections
+  corrected?: boolean
+  stage: CribbageStage
+  scores: number[]
+  current_index: number
+  pegging_total: number
+  pegging_seq: Card[]
+  hands: Card[][] // cards still held
+}
+
+// A ReplayStep in diff mode: state fields are present only when they changed since the
+// previous step of the hand, and hands lists only the seats whose cards changed.
+export type ReplayDelta = Pick<
+  ReplayStep,
+  'move' | 'move_id' | 'move_type' | 'seat' | 'card' | 'points' | 'claimed' | 'corrected'
+> &
+  Partial<Pick<ReplayStep, 'stage' | 'scores' | 'current_index' | 'pegging_total' | 'pegging_seq'>> & {
+    hands?: Record<number, Card[]>
+  }
+
+export type ReplayHand = {
+  hand: number // 1-based
+  dealer_index: number
+  kept: Card[][]
+  crib: Card[]
+  cut?: Card
+  steps: ReplayStep[] // empty in diff mode and for archived games
+  deltas?: ReplayDelta[] // diff mode only
+  incomplete?: boolean
+  error?: string
+  scores_after?: number[] // archived games only
+}
+
+export type GameReplay = {
+  game_id: number
+  seats: { seat: number; user_id: number; username: string }[]
+  total_hands: number
+  total_moves: number
+  hands: ReplayHand[]
+  next_hand?: number // from_hand of the next page
+  next_from?: number // from of the next page, when paging by move range
+  truncated?: boolean
+  archived?: boolean // moves pruned: hands carry their deal and result, no steps
+}
+
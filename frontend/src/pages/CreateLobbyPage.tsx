@@ -9,6 +9,8 @@ export function CreateLobbyPage() {
   const nav = useNavigate()
   const [name, setName] = useState('Lobby')
   const [maxPlayers, setMaxPlayers] = useState(2)
+  const [targetScore, setTargetScore] = useState<121 | 61>(121)
+  const [revealHands, setRevealHands] = useState(false)
   const [err, setErr] = useState<string | null>(null)
   const [busy, setBusy] = useState(false)
 
@@ -26,7 +28,7 @@ export function CreateLobbyPage() {
     }
     setBusy(true)
     try {
-      const res = await api.createLobby({ name: trimmed, max_players: maxPlayers })
+      const res = await api.createLobby({ name: trimmed, max_players: maxPlayers, target_score: targetScore, reveal_hands: revealHands })
       nav(`/games/${res.game.id}`, { replace: true })
     } catch (e: unknown) {
       setErr(e instanceof Error ? e.message : 'failed to create lobby')
@@ -60,6 +62,24 @@ export function CreateLobbyPage() {
             <option value={3}>3</option>
             <option value={4}>4</option>
         </select>
+        <label htmlFor="lobby_target_score">Play to</label>
+        <select
+          id="lobby_target_score"
+          value={targetScore}
+          onChange={(e) => setTargetScore(Number(e.target.value) === 61 ? 61 : 121)}
+        >
+            <option value={121}>121 (standard)</option>
+            <option value={61}>61 (once around)</option>
+        </select>
+        <label htmlFor="lobby_reveal_hands" style={{ display: 'block', marginTop: 8 }}>
+          <input
+            id="lobby_reveal_hands"
+            type="checkbox"
+            checked={revealHands}
+            onChange={(e) => setRevealHands(e.target.checked)}
+          />{' '}
+          Show everyone's discards after each hand (casual)
+        </label>
         {err && <div style={{ color: 'crimson', marginTop: 8 }}>{err}</div>}
         <button disabled={busy} style={{ marginTop: 12 }}>
           {busy ? 'Creating…' : 'Create'}
@@ -454,11 +454,13 @@ function feltPanelStyle(): React.CSSProperties {
 function PegTrack({
   players,
   scores,
+  target,
 }: {
   players: GameSnapshot['players']
   scores: number[] | undefined
+  target: number
 }) {
-  const max = 121
+  const max = target
   const endPad = 18
   const sorted = players.slice().sort((a, b) => a.position - b.position)
   const colors = ['#2563eb', '#dc2626', '#16a34a', '#7c3aed']
@@ -490,7 +492,7 @@ function PegTrack({
           overflow: 'hidden',
         }}
       >
-        {/* Endcaps so 0/121 don't feel cramped */}
+        {/* Endcaps so 0 and the target don't feel cramped */}
         <div style={{ position: 'absolute', left: 0, top: 0, bottom: 0, width: endPad, background: '#eef2ff' }} />
         <div
           style={{
@@ -508,9 +510,9 @@ function PegTrack({
             textShadow: '0 1px 0 rgba(0,0,0,0.25)',
             fontSize: 12,
           }}
-          title="121"
+          title={String(max)}
         >
-          121
+          {max}
         </div>
 
         {/* Inner lane where ticks/pegs are positioned (gives padding at the ends) */}
@@ -704,11 +706,15 @@ export function GamePage() {
       void fetchSnapshot()
       void fetchMoves()
     })
+    const offSeries = ws.on('lobby:series_update', () => {
+      void fetchSnapshot()
+    })
     return () => {
       cancelled = true
       offOpen()
       offClose()
       offUpdate()
+      offSeries()
       ws.disconnect()
     }
   }, [user, gameId, isValidId, ws])
@@ -864,6 +870,16 @@ export function GamePage() {
         <div style={{ marginTop: 16, opacity: 0.8 }}>{loading ? 'Loading…' : 'No snapshot yet.'}</div>
       ) : (
         <div style={{ marginTop: 16 }}>
+          {snap.series && snap.series.games_played > 0 ? (
+            <div style={{ marginBottom: 12, fontWeight: 700 }}>
+              Series after {snap.series.games_played} {snap.series.games_played === 1 ? 'game' : 'games'}:{' '}
+              {snap.players
+                .slice()
+                .sort((a, b) => a.position - b.position)
+                .map((p) => `${p.username} ${snap.series?.standings.find((s) => s.user_id === p.user_id)?.wins ?? 0}`)
+                .join(' – ')}
+            </div>
+          ) : null}
           <div style={{ marginBottom: 16 }}>
             <div style={{ fontWeight: 900, marginBottom: 8, opacity: 0.95 }}>Player profiles</div>
             <div style={{ display: 'grid', gridTemplateColumns: 'repeat(auto-fit, minmax(220px, 1fr))', gap: 12 }}>
@@ -925,7 +941,7 @@ export function GamePage() {
                 {/* Board */}
                 <div style={{ display: 'flex', alignItems: 'flex-start', gap: 14, flexWrap: 'wrap' }}>
                   <div style={{ flex: '1 1 520px', minWidth: 360 }}>
-                    <PegTrack players={snap.players} scores={state?.scores} />
+                    <PegTrack players={snap.players} scores={state?.scores} target={state?.rules?.target_score || 121} />
                   </div>
                   <div style={{ flex: '0 0 auto' }}>
                     <div style={{ fontWeight: 900, marginBottom: 6, opacity: 0.9 }}>Cut</div>
@@ -1030,6 +1046,11 @@ export function GamePage() {
                                       <CardIcon key={`kh:${idx}:${j}:${cardToCode(c)}`} card={c} disabled title={cardToCode(c)} />
                                     ))}
                                   </div>
+                                  {state?.discards?.[idx]?.length ? (
+                                    <div style={{ marginTop: 6, fontSize: 12, opacity: 0.85 }}>
+                                      Threw to crib: {state.discards[idx].map(cardToCode).join(' ')}
+                                    </div>
+                                  ) : null}
                                 </div>
                               )
                             })}